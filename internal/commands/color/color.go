@@ -0,0 +1,409 @@
+package color
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/color"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/material"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// outputFormat prints bare values in one format instead of the table
+	outputFormat string
+	// jsonOutput prints machine readable output
+	jsonOutput bool
+)
+
+// defaultTones are the HCT tones listed by shades
+var defaultTones = []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 95, 99, 100}
+
+// colorJSON is the JSON shape of a color
+type colorJSON struct {
+	Input string  `json:"input,omitempty"`
+	Hex   string  `json:"hex"`
+	RGB   string  `json:"rgb"`
+	HSL   string  `json:"hsl"`
+	OKLCH string  `json:"oklch"`
+	Tone  *int    `json:"tone,omitempty"`
+	Name  string  `json:"name,omitempty"`
+	Delta float64 `json:"delta,omitempty"`
+}
+
+// Command creates the color command
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "color",
+		Short: "Color conversion and color math",
+		Long: `Convert colors and do color math with the same code the scheme
+generator uses.
+
+Colors can be given as hex (#rgb, #rrggbb), CSS rgb(), hsl() or
+oklch(), a CSS color name, or a key of the current scheme such as
+primary or surfaceContainer.
+
+Examples:
+  heimdall color convert '#7aa2f7' 'rgb(255 0 0)'   # Every format with swatches
+  heimdall color convert primary --format oklch     # Bare value for scripts
+  heimdall color mix '#1e1e2e' '#cba6f7' --ratio 0.2
+  heimdall color contrast onSurface surface
+  heimdall color shades '#7aa2f7'                   # Material tones 0-100
+  heimdall color name '#7aa2f7'                     # Nearest CSS color name`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if outputFormat == "" {
+				return nil
+			}
+			for _, format := range color.Formats {
+				if outputFormat == format {
+					return nil
+				}
+			}
+			return fmt.Errorf("unknown color format: %s (must be one of %s)", outputFormat, strings.Join(color.Formats, ", "))
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&outputFormat, "format", "f", "", "Print bare values in one format: hex, rgb, hsl or oklch")
+	cmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	cmd.AddCommand(convertCommand())
+	cmd.AddCommand(mixCommand())
+	cmd.AddCommand(contrastCommand())
+	cmd.AddCommand(shadesCommand())
+	cmd.AddCommand(nameCommand())
+
+	return cmd
+}
+
+// convertCommand creates the color convert subcommand
+func convertCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "convert <color...>",
+		Short: "Show colors in every format",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			colors, err := resolveColors(args)
+			if err != nil {
+				return err
+			}
+
+			entries := make([]colorJSON, len(colors))
+			for i, c := range colors {
+				entries[i] = describe(c)
+				entries[i].Input = args[i]
+			}
+			return printColors(entries)
+		},
+	}
+}
+
+// mixCommand creates the color mix subcommand
+func mixCommand() *cobra.Command {
+	var (
+		ratio float64
+		steps int
+		rgb   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mix <color> <color>",
+		Short: "Blend two colors",
+		Long: `Blend two colors in OKLab. --ratio is how much of the second color to
+use; --steps prints a gradient from the first color to the second instead.
+--rgb blends channel by channel in sRGB like the old behaviour.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			colors, err := resolveColors(args)
+			if err != nil {
+				return err
+			}
+			if ratio < 0 || ratio > 1 {
+				return fmt.Errorf("invalid ratio: %g (must be between 0 and 1)", ratio)
+			}
+
+			ratios := []float64{ratio}
+			if steps > 0 {
+				if steps < 2 {
+					return fmt.Errorf("invalid steps: %d (must be at least 2)", steps)
+				}
+				ratios = make([]float64, steps)
+				for i := range ratios {
+					ratios[i] = float64(i) / float64(steps-1)
+				}
+			}
+
+			entries := make([]colorJSON, len(ratios))
+			for i, r := range ratios {
+				if rgb {
+					entries[i] = describe(color.Blend(colors[0], colors[1], r))
+				} else {
+					entries[i] = describe(color.Mix(colors[0], colors[1], r))
+				}
+			}
+			return printColors(entries)
+		},
+	}
+
+	cmd.Flags().Float64VarP(&ratio, "ratio", "r", 0.5, "Amount of the second color (0-1)")
+	cmd.Flags().IntVarP(&steps, "steps", "s", 0, "Print a gradient with this many colors")
+	cmd.Flags().BoolVar(&rgb, "rgb", false, "Blend in sRGB instead of OKLab")
+
+	return cmd
+}
+
+// contrastResult is the JSON shape of color contrast
+type contrastResult struct {
+	Foreground string  `json:"foreground"`
+	Background string  `json:"background"`
+	Ratio      float64 `json:"ratio"`
+	AA         bool    `json:"aa"`
+	AALarge    bool    `json:"aa_large"`
+	AAA        bool    `json:"aaa"`
+	AAALarge   bool    `json:"aaa_large"`
+}
+
+// contrastCommand creates the color contrast subcommand
+func contrastCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "contrast <foreground> <background>",
+		Short: "Show the WCAG contrast ratio of two colors",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			colors, err := resolveColors(args)
+			if err != nil {
+				return err
+			}
+
+			result := contrastOf(colors[0], colors[1])
+
+			if jsonOutput {
+				return printJSON(result)
+			}
+			if outputFormat != "" {
+				fmt.Printf("%.2f\n", result.Ratio)
+				return nil
+			}
+
+			fg, bg := colors[0].RGB, colors[1].RGB
+			fmt.Printf("\033[36;1mContrast\033[0m\n")
+			fmt.Printf("━━━━━━━━\n")
+			fmt.Printf("\033[38;2;%d;%d;%dm\033[48;2;%d;%d;%dm  Sample text  \033[0m  %s on %s\n\n",
+				fg.R, fg.G, fg.B, bg.R, bg.G, bg.B, result.Foreground, result.Background)
+			fmt.Printf("Ratio:      %.2f:1\n", result.Ratio)
+			fmt.Printf("AA:         %s\n", passFail(result.AA))
+			fmt.Printf("AA large:   %s\n", passFail(result.AALarge))
+			fmt.Printf("AAA:        %s\n", passFail(result.AAA))
+			fmt.Printf("AAA large:  %s\n", passFail(result.AAALarge))
+			return nil
+		},
+	}
+}
+
+// shadesCommand creates the color shades subcommand
+func shadesCommand() *cobra.Command {
+	var tones []int
+
+	cmd := &cobra.Command{
+		Use:   "shades <color>",
+		Short: "Show the Material tones of a color",
+		Long: `Show the tonal palette of a color: the same hue and chroma at
+different tones, as used for every role of a generated scheme.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			colors, err := resolveColors(args)
+			if err != nil {
+				return err
+			}
+
+			for _, tone := range tones {
+				if tone < 0 || tone > 100 {
+					return fmt.Errorf("invalid tone: %d (must be between 0 and 100)", tone)
+				}
+			}
+
+			entries := make([]colorJSON, len(tones))
+			for i, c := range shades(colors[0], tones) {
+				entries[i] = describe(c)
+				entries[i].Tone = &tones[i]
+			}
+			return printColors(entries)
+		},
+	}
+
+	cmd.Flags().IntSliceVarP(&tones, "tones", "t", defaultTones, "Tones to list (0-100)")
+
+	return cmd
+}
+
+// nameCommand creates the color name subcommand
+func nameCommand() *cobra.Command {
+	var quiet bool
+
+	cmd := &cobra.Command{
+		Use:   "name <color...>",
+		Short: "Find the nearest CSS color name",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			colors, err := resolveColors(args)
+			if err != nil {
+				return err
+			}
+
+			entries := make([]colorJSON, len(colors))
+			for i, c := range colors {
+				entries[i] = describe(c)
+				entries[i].Input = args[i]
+				entries[i].Name, entries[i].Delta = color.NearestName(c)
+			}
+
+			if quiet && !jsonOutput {
+				for _, e := range entries {
+					fmt.Println(e.Name)
+				}
+				return nil
+			}
+			return printColors(entries)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Print only the names")
+
+	return cmd
+}
+
+// resolveColors parses color arguments, falling back to keys of the
+// current scheme
+func resolveColors(args []string) ([]*color.Color, error) {
+	var current *scheme.Scheme
+
+	colors := make([]*color.Color, len(args))
+	for i, arg := range args {
+		c, err := color.Parse(arg)
+		if err == nil {
+			colors[i] = c
+			continue
+		}
+
+		if current == nil {
+			current, err = scheme.NewManager().GetCurrent()
+			if err != nil {
+				return nil, fmt.Errorf("invalid color %q: %w", arg, err)
+			}
+		}
+
+		hex, ok := current.Colours[arg]
+		if !ok {
+			return nil, fmt.Errorf("invalid color %q: not a color or a key of scheme %s", arg, current.Name)
+		}
+		c, err = color.NewFromHex(hex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color %q in scheme %s: %w", hex, current.Name, err)
+		}
+		colors[i] = c
+	}
+	return colors, nil
+}
+
+// describe renders a color in every output format
+func describe(c *color.Color) colorJSON {
+	entry := colorJSON{}
+	entry.Hex, _ = c.Format(color.FormatHex)
+	entry.RGB, _ = c.Format(color.FormatRGB)
+	entry.HSL, _ = c.Format(color.FormatHSL)
+	entry.OKLCH, _ = c.Format(color.FormatOKLCH)
+	return entry
+}
+
+// shades returns the color at each HCT tone, keeping its hue and chroma
+func shades(c *color.Color, tones []int) []*color.Color {
+	argb := 0xFF000000 | uint32(c.RGB.R)<<16 | uint32(c.RGB.G)<<8 | uint32(c.RGB.B)
+	hct := material.HctFromARGB(argb)
+
+	result := make([]*color.Color, len(tones))
+	for i, tone := range tones {
+		shade := hct.WithTone(float64(tone)).ARGB()
+		result[i] = color.NewFromRGB(uint8(shade>>16), uint8(shade>>8), uint8(shade))
+	}
+	return result
+}
+
+// contrastOf computes the contrast ratio and the WCAG 2 levels it passes
+func contrastOf(fg, bg *color.Color) contrastResult {
+	ratio := color.Contrast(fg, bg)
+	return contrastResult{
+		Foreground: fg.Hex,
+		Background: bg.Hex,
+		Ratio:      ratio,
+		AA:         ratio >= 4.5,
+		AALarge:    ratio >= 3,
+		AAA:        ratio >= 7,
+		AAALarge:   ratio >= 4.5,
+	}
+}
+
+// printColors prints colors as JSON, bare values in --format, or a table
+// with swatches
+func printColors(entries []colorJSON) error {
+	if jsonOutput {
+		return printJSON(entries)
+	}
+
+	if outputFormat != "" {
+		for _, e := range entries {
+			value, err := formatEntry(e, outputFormat)
+			if err != nil {
+				return err
+			}
+			fmt.Println(value)
+		}
+		return nil
+	}
+
+	for _, e := range entries {
+		c, _ := color.NewFromHex(e.Hex)
+		line := fmt.Sprintf("%-8s  %-19s  %-24s  %s", e.Hex, e.RGB, e.HSL, e.OKLCH)
+		if e.Tone != nil {
+			line = fmt.Sprintf("%3d  %s", *e.Tone, line)
+		}
+		if e.Name != "" {
+			line += fmt.Sprintf("  %s (ΔE %.3f)", e.Name, e.Delta)
+		}
+		fmt.Printf("\033[48;2;%d;%d;%dm    \033[0m %s\n", c.RGB.R, c.RGB.G, c.RGB.B, line)
+	}
+	return nil
+}
+
+// formatEntry picks one format of a described color
+func formatEntry(e colorJSON, format string) (string, error) {
+	switch format {
+	case color.FormatHex:
+		return e.Hex, nil
+	case color.FormatRGB:
+		return e.RGB, nil
+	case color.FormatHSL:
+		return e.HSL, nil
+	case color.FormatOKLCH:
+		return e.OKLCH, nil
+	}
+	return "", fmt.Errorf("unknown color format: %s (must be one of %s)", format, strings.Join(color.Formats, ", "))
+}
+
+// printJSON prints v as indented JSON
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// passFail renders a WCAG level result
+func passFail(pass bool) string {
+	if pass {
+		return "\033[32mpass\033[0m"
+	}
+	return "\033[31mfail\033[0m"
+}
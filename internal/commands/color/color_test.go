@@ -0,0 +1,61 @@
+package color
+
+import (
+	"testing"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/color"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/material"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveColors(t *testing.T) {
+	colors, err := resolveColors([]string{"#7aa2f7", "rgb(0 0 0)", "white"})
+	require.NoError(t, err)
+	assert.Equal(t, "#7AA2F7", colors[0].Hex)
+	assert.Equal(t, "#000000", colors[1].Hex)
+	assert.Equal(t, "#FFFFFF", colors[2].Hex)
+}
+
+func TestContrastOf(t *testing.T) {
+	black := color.NewFromRGB(0, 0, 0)
+	white := color.NewFromRGB(255, 255, 255)
+
+	result := contrastOf(black, white)
+	assert.InDelta(t, 21, result.Ratio, 0.01)
+	assert.True(t, result.AAA)
+
+	// 3.0 to 4.5 only passes for large text
+	gray := color.NewFromRGB(0x94, 0x94, 0x94)
+	result = contrastOf(gray, white)
+	assert.False(t, result.AA)
+	assert.True(t, result.AALarge)
+	assert.False(t, result.AAALarge)
+}
+
+func TestShades(t *testing.T) {
+	seed, err := color.Parse("#7aa2f7")
+	require.NoError(t, err)
+
+	tones := []int{0, 40, 100}
+	result := shades(seed, tones)
+	require.Len(t, result, 3)
+
+	assert.Equal(t, "#000000", result[0].Hex)
+	assert.Equal(t, "#FFFFFF", result[2].Hex)
+
+	hct := material.HctFromARGB(0xFF000000 | uint32(result[1].RGB.R)<<16 | uint32(result[1].RGB.G)<<8 | uint32(result[1].RGB.B))
+	assert.InDelta(t, 40, hct.Tone, 0.5)
+	assert.InDelta(t, material.HctFromARGB(0xFF7AA2F7).Hue, hct.Hue, 2)
+}
+
+func TestFormatEntry(t *testing.T) {
+	entry := describe(color.NewFromRGB(255, 0, 0))
+
+	value, err := formatEntry(entry, color.FormatOKLCH)
+	require.NoError(t, err)
+	assert.Equal(t, "oklch(62.8% 0.2577 29.23)", value)
+
+	_, err = formatEntry(entry, "cmyk")
+	assert.Error(t, err)
+}
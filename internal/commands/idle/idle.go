@@ -3,7 +3,6 @@ package idle
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"strings"
@@ -11,6 +10,7 @@ import (
 	"time"
 
 	"github.com/arthur404dev/heimdall-cli/internal/commands/idle/manager"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/daemon"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/notify"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
@@ -192,39 +192,22 @@ func handleStart(mgr *manager.Manager, reason string, duration time.Duration, pr
 
 func handleStop(mgr *manager.Manager, stopAll bool) error {
 	// First check for daemon process
-	pidFile := filepath.Join(paths.StateDir, "heimdall-idle.pid")
-	if pidData, err := os.ReadFile(pidFile); err == nil {
-		var pid int
-		if _, err := fmt.Sscanf(string(pidData), "%d", &pid); err == nil {
-			// Try to stop the daemon
-			if proc, err := os.FindProcess(pid); err == nil {
-				if err := proc.Signal(syscall.SIGTERM); err == nil {
-					fmt.Printf("✓ Stopped daemon process (PID: %d)\n", pid)
-					// Remove PID file
-					os.Remove(pidFile)
-
-					// Send notification
-					notifier := notify.NewNotifier()
-					notifier.Send(&notify.Notification{
-						Summary: "Idle Prevention Stopped",
-						Body:    "Daemon process terminated",
-						Urgency: notify.UrgencyNormal,
-					})
-					return nil
-				}
-			}
-			// Process not found or couldn't signal, remove stale PID file
-			os.Remove(pidFile)
-		}
+	if pid, err := idleDaemon().Stop(); err == nil {
+		fmt.Printf("✓ Stopped daemon process (PID: %d)\n", pid)
+
+		// Send notification
+		notifier := notify.NewNotifier()
+		notifier.Send(&notify.Notification{
+			Summary: "Idle Prevention Stopped",
+			Body:    "Daemon process terminated",
+			Urgency: notify.UrgencyNormal,
+		})
+		return nil
 	}
 
 	// Handle regular session stop
 	if stopAll {
 		if err := mgr.StopAll(); err != nil {
-			// No sessions in current process, but that's okay if we stopped a daemon
-			if pidFile != "" {
-				return nil
-			}
 			return fmt.Errorf("failed to stop sessions: %w", err)
 		}
 		fmt.Println("✓ All idle prevention sessions stopped")
@@ -271,20 +254,8 @@ func handleStatus(mgr *manager.Manager) error {
 	active, sessions, providers := mgr.GetStatus()
 
 	// Check for daemon process
-	daemonActive := false
-	var daemonPID int
-	pidFile := filepath.Join(paths.StateDir, "heimdall-idle.pid")
-	if pidData, err := os.ReadFile(pidFile); err == nil {
-		if _, err := fmt.Sscanf(string(pidData), "%d", &daemonPID); err == nil {
-			// Check if process is still running
-			if proc, err := os.FindProcess(daemonPID); err == nil {
-				// Try sending signal 0 to check if process exists
-				if err := proc.Signal(syscall.Signal(0)); err == nil {
-					daemonActive = true
-				}
-			}
-		}
-	}
+	daemonPID := idleDaemon().PID()
+	daemonActive := daemonPID != 0
 
 	if active || daemonActive {
 		fmt.Println("✓ Idle prevention is ACTIVE")
@@ -378,44 +349,38 @@ func setupSignalHandler(mgr *manager.Manager) {
 	}()
 }
 
+// idleDaemonEnv is set in the environment of the background idle process
+const idleDaemonEnv = "HEIMDALL_IDLE_DAEMON"
+
+// idleDaemon returns the background idle prevention process, which runs
+// the current command again, -d included
+func idleDaemon() daemon.Process {
+	return daemon.Process{
+		Name:    "idle prevention daemon",
+		PIDFile: filepath.Join(paths.StateDir, "heimdall-idle.pid"),
+		LogFile: filepath.Join(paths.StateDir, "heimdall-idle.log"),
+		Args:    os.Args[1:],
+		Env:     []string{idleDaemonEnv + "=1"},
+	}
+}
+
 func runDaemon(mgr *manager.Manager, reason string, duration time.Duration, provider string) error {
 	// Fork to background
-	if os.Getppid() != 1 {
+	if os.Getenv(idleDaemonEnv) != "1" {
 		// We are the parent process
-		args := os.Args[1:]
-		cmd := exec.Command(os.Args[0], args...)
-
-		// Detach from parent
-		cmd.SysProcAttr = &syscall.SysProcAttr{
-			Setsid: true,
-		}
-
-		// Redirect output to /dev/null or log file
-		logFile := filepath.Join(paths.StateDir, "heimdall-idle.log")
-		if output, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); err == nil {
-			cmd.Stdout = output
-			cmd.Stderr = output
-		}
-
-		// Start the daemon process
-		if err := cmd.Start(); err != nil {
-			return fmt.Errorf("failed to start daemon: %w", err)
-		}
-
-		// Write PID file
-		pidFile := filepath.Join(paths.StateDir, "heimdall-idle.pid")
-		if err := os.WriteFile(pidFile, []byte(fmt.Sprintf("%d", cmd.Process.Pid)), 0644); err != nil {
-			logger.Error("Failed to write PID file", "error", err)
+		pid, err := idleDaemon().Start()
+		if err != nil {
+			return err
 		}
 
-		fmt.Printf("✓ Idle prevention daemon started (PID: %d)\n", cmd.Process.Pid)
+		fmt.Printf("✓ Idle prevention daemon started (PID: %d)\n", pid)
 		fmt.Printf("  Session will run in background\n")
 		if duration > 0 {
 			fmt.Printf("  Duration: %s\n", manager.FormatDuration(duration))
 		} else {
 			fmt.Printf("  Duration: unlimited\n")
 		}
-		fmt.Printf("  To stop: heimdall idle --stop or kill %d\n", cmd.Process.Pid)
+		fmt.Printf("  To stop: heimdall idle --stop or kill %d\n", pid)
 
 		return nil
 	}
@@ -442,8 +407,7 @@ func runDaemon(mgr *manager.Manager, reason string, duration time.Duration, prov
 		logger.Info("Daemon session expired", "session", session.ID[:8])
 
 		// Clean up PID file
-		pidFile := filepath.Join(paths.StateDir, "heimdall-idle.pid")
-		os.Remove(pidFile)
+		idleDaemon().RemovePID()
 	} else {
 		// Run indefinitely
 		select {}
@@ -3,14 +3,11 @@ package pip
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strconv"
 	"strings"
-	"syscall"
-	"time"
 
 	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/daemon"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/hypr"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/notify"
@@ -60,52 +57,41 @@ like video players and browsers.`,
 	return cmd
 }
 
+// pipDaemonEnv is set in the environment of the background PIP process
+const pipDaemonEnv = "PIP_DAEMON"
+
+// pipDaemon returns the background PIP process, which runs heimdall pip
+func pipDaemon() daemon.Process {
+	return daemon.Process{
+		Name:    "PIP daemon",
+		PIDFile: filepath.Join(paths.StateDir, "pip.pid"),
+		Args:    []string{"pip"},
+		Env:     []string{pipDaemonEnv + "=1"},
+	}
+}
+
 // startDaemon starts the PIP daemon
 func startDaemon() error {
-	pidFile := filepath.Join(paths.StateDir, "pip.pid")
-
-	// Check if already running
-	if isDaemonRunning(pidFile) {
-		return fmt.Errorf("PIP daemon is already running")
+	// We are the daemon process
+	if os.Getenv(pipDaemonEnv) == "1" {
+		return runDaemon()
 	}
 
 	// Fork to background
-	if os.Getenv("PIP_DAEMON") != "1" {
-		// Re-execute ourselves with daemon flag
-		cmd := exec.Command(os.Args[0], "pip")
-		cmd.Env = append(os.Environ(), "PIP_DAEMON=1")
-
-		// Detach from parent
-		cmd.SysProcAttr = &syscall.SysProcAttr{
-			Setpgid: true,
-		}
-
-		// Start daemon
-		if err := cmd.Start(); err != nil {
-			return fmt.Errorf("failed to start daemon: %w", err)
-		}
-
-		// Write PID file
-		pid := cmd.Process.Pid
-		if err := os.WriteFile(pidFile, []byte(strconv.Itoa(pid)), 0644); err != nil {
-			cmd.Process.Kill()
-			return fmt.Errorf("failed to write PID file: %w", err)
-		}
-
-		// Send notification
-		notifier := notify.NewNotifier()
-		notifier.Send(&notify.Notification{
-			Summary: "PIP Daemon",
-			Body:    "Picture-in-picture daemon started",
-			Urgency: notify.UrgencyNormal,
-		})
-
-		fmt.Println("PIP daemon started")
-		return nil
+	if _, err := pipDaemon().Start(); err != nil {
+		return err
 	}
 
-	// We are the daemon process
-	return runDaemon()
+	// Send notification
+	notifier := notify.NewNotifier()
+	notifier.Send(&notify.Notification{
+		Summary: "PIP Daemon",
+		Body:    "Picture-in-picture daemon started",
+		Urgency: notify.UrgencyNormal,
+	})
+
+	fmt.Println("PIP daemon started")
+	return nil
 }
 
 // runDaemon runs the main daemon loop
@@ -262,47 +248,10 @@ func enablePIP(client *hypr.Client, windowClass string) error {
 
 // stopDaemon stops the running PIP daemon
 func stopDaemon() error {
-	pidFile := filepath.Join(paths.StateDir, "pip.pid")
-
-	// Read PID
-	data, err := os.ReadFile(pidFile)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("PIP daemon is not running")
-		}
-		return fmt.Errorf("failed to read PID file: %w", err)
+	if _, err := pipDaemon().Stop(); err != nil {
+		return err
 	}
 
-	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
-	if err != nil {
-		return fmt.Errorf("invalid PID in file: %w", err)
-	}
-
-	// Find process
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return fmt.Errorf("failed to find process: %w", err)
-	}
-
-	// Send SIGTERM
-	if err := process.Signal(syscall.SIGTERM); err != nil {
-		return fmt.Errorf("failed to stop daemon: %w", err)
-	}
-
-	// Wait a bit for graceful shutdown
-	time.Sleep(100 * time.Millisecond)
-
-	// Check if still running and force kill if necessary
-	if err := process.Signal(syscall.Signal(0)); err == nil {
-		// Still running, force kill
-		if err := process.Kill(); err != nil {
-			return fmt.Errorf("failed to kill daemon: %w", err)
-		}
-	}
-
-	// Remove PID file
-	os.Remove(pidFile)
-
 	// Send notification
 	notifier := notify.NewNotifier()
 	notifier.Send(&notify.Notification{
@@ -317,44 +266,12 @@ func stopDaemon() error {
 
 // showStatus shows the daemon status
 func showStatus() error {
-	pidFile := filepath.Join(paths.StateDir, "pip.pid")
-
-	if !isDaemonRunning(pidFile) {
+	pid := pipDaemon().PID()
+	if pid == 0 {
 		fmt.Println("PIP daemon is not running")
 		return nil
 	}
 
-	// Read PID
-	data, err := os.ReadFile(pidFile)
-	if err != nil {
-		return fmt.Errorf("failed to read PID file: %w", err)
-	}
-
-	pid := strings.TrimSpace(string(data))
-	fmt.Printf("PIP daemon is running (PID: %s)\n", pid)
-
+	fmt.Printf("PIP daemon is running (PID: %d)\n", pid)
 	return nil
 }
-
-// isDaemonRunning checks if the daemon is already running
-func isDaemonRunning(pidFile string) bool {
-	data, err := os.ReadFile(pidFile)
-	if err != nil {
-		return false
-	}
-
-	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
-	if err != nil {
-		return false
-	}
-
-	// Check if process exists
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-
-	// Send signal 0 to check if process is alive
-	err = process.Signal(syscall.Signal(0))
-	return err == nil
-}
@@ -206,9 +206,13 @@ func TestIsVideoWindowWithDefaults(t *testing.T) {
 	}
 }
 
-func TestIsDaemonRunning(t *testing.T) {
+func TestDaemonRunning(t *testing.T) {
 	tempDir := t.TempDir()
-	pidFile := filepath.Join(tempDir, "test.pid")
+	originalStateDir := paths.StateDir
+	paths.StateDir = tempDir
+	defer func() { paths.StateDir = originalStateDir }()
+
+	pidFile := filepath.Join(tempDir, "pip.pid")
 
 	tests := []struct {
 		name     string
@@ -257,9 +261,9 @@ func TestIsDaemonRunning(t *testing.T) {
 				t.Fatalf("Setup failed: %v", err)
 			}
 
-			result := isDaemonRunning(pidFile)
+			result := pipDaemon().Running()
 			if result != tt.expected {
-				t.Errorf("isDaemonRunning() = %v, expected %v", result, tt.expected)
+				t.Errorf("Running() = %v, expected %v", result, tt.expected)
 			}
 		})
 	}
@@ -358,7 +362,7 @@ func TestStopDaemon(t *testing.T) {
 				return os.WriteFile(pidFile, []byte("invalid"), 0644)
 			},
 			expectError: true,
-			errorMsg:    "invalid PID",
+			errorMsg:    "not running",
 		},
 		{
 			name: "handles non-existent process gracefully",
@@ -366,7 +370,7 @@ func TestStopDaemon(t *testing.T) {
 				return os.WriteFile(pidFile, []byte("99999"), 0644)
 			},
 			expectError: true,
-			errorMsg:    "failed to stop daemon",
+			errorMsg:    "not running",
 		},
 	}
 
@@ -589,9 +593,13 @@ func BenchmarkIsVideoWindow(b *testing.B) {
 	}
 }
 
-func BenchmarkIsDaemonRunning(b *testing.B) {
+func BenchmarkDaemonRunning(b *testing.B) {
 	tempDir := b.TempDir()
-	pidFile := filepath.Join(tempDir, "test.pid")
+	originalStateDir := paths.StateDir
+	paths.StateDir = tempDir
+	defer func() { paths.StateDir = originalStateDir }()
+
+	pidFile := filepath.Join(tempDir, "pip.pid")
 
 	// Create a valid pid file
 	pid := os.Getpid()
@@ -599,7 +607,7 @@ func BenchmarkIsDaemonRunning(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		isDaemonRunning(pidFile)
+		pipDaemon().Running()
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/arthur404dev/heimdall-cli/internal/commands/clipboard"
+	"github.com/arthur404dev/heimdall-cli/internal/commands/color"
 	"github.com/arthur404dev/heimdall-cli/internal/commands/config"
 	"github.com/arthur404dev/heimdall-cli/internal/commands/emoji"
 	"github.com/arthur404dev/heimdall-cli/internal/commands/idle"
@@ -165,6 +166,9 @@ func addCommands() {
 	// Add wallpaper command
 	rootCmd.AddCommand(wallpaper.Command())
 
+	// Add color command
+	rootCmd.AddCommand(color.Command())
+
 	// Add pip command
 	rootCmd.AddCommand(pip.Command())
 
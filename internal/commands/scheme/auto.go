@@ -0,0 +1,284 @@
+package scheme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/theme"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/notify"
+	"github.com/spf13/cobra"
+)
+
+// autoCommand creates the scheme auto subcommand
+func autoCommand() *cobra.Command {
+	var (
+		daemon       bool
+		once         bool
+		stop         bool
+		status       bool
+		jsonOutput   bool
+		enableNotify bool
+		apps         string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "auto",
+		Short: "Switch between light and dark mode automatically",
+		Long: `Switch the current scheme between its light and dark mode on a schedule.
+
+The schedule is read from the scheme.auto section of the configuration:
+  trigger    - 'time' for fixed times or 'sun' for local sunrise/sunset
+  light_time - Time (HH:MM) to switch to light mode (trigger 'time')
+  dark_time  - Time (HH:MM) to switch to dark mode (trigger 'time')
+  latitude   - Latitude for sunrise/sunset (trigger 'sun')
+  longitude  - Longitude for sunrise/sunset (trigger 'sun')
+
+Sunrise and sunset are computed offline. Switching requires scheme.auto_mode
+to be enabled and the current scheme to provide both modes. A mode chosen
+manually is kept until the next scheduled transition.
+
+Examples:
+  heimdall scheme auto              # Run in the foreground
+  heimdall scheme auto -d           # Run as a background daemon
+  heimdall scheme auto --once       # Apply the mode for the current time and exit
+  heimdall scheme auto --status     # Show the schedule and next transition
+  heimdall scheme auto --stop       # Stop the background daemon`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if stop {
+				pid, err := schemeDaemon("auto").Stop()
+				if err != nil {
+					return err
+				}
+				fmt.Printf("✓ Stopped auto mode daemon (PID: %d)\n", pid)
+				return nil
+			}
+
+			cfg := config.Get()
+			schedule, err := scheme.NewAutoSchedule(cfg.Scheme.Auto)
+			if err != nil {
+				return fmt.Errorf("invalid auto mode schedule: %w", err)
+			}
+
+			if status {
+				return showAutoStatus(schedule, jsonOutput)
+			}
+
+			if !cfg.Scheme.AutoMode {
+				return fmt.Errorf("auto mode is disabled (set scheme.auto_mode to true)")
+			}
+
+			selectedApps := splitApps(apps)
+
+			if once {
+				mode, next := schedule.ModeAt(time.Now())
+				switched, err := switchSchemeMode(mode, enableNotify, selectedApps)
+				if err != nil {
+					return err
+				}
+				recordAutoState(schedule, mode, next, false)
+				if switched {
+					fmt.Printf("Switched to %s mode\n", mode)
+				} else {
+					fmt.Printf("Already in %s mode\n", mode)
+				}
+				if !next.At.IsZero() {
+					fmt.Printf("Next: %s at %s\n", next.Mode, next.At.Format("2006-01-02 15:04"))
+				}
+				return nil
+			}
+
+			if daemon {
+				pid, err := schemeDaemon("auto").Start()
+				if err != nil {
+					return err
+				}
+				fmt.Printf("✓ Auto mode daemon started (PID: %d)\n", pid)
+				fmt.Printf("  To stop: heimdall scheme auto --stop\n")
+				return nil
+			}
+
+			return runAutoLoop(schedule, enableNotify, selectedApps)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&daemon, "daemon", "d", false, "Run in the background")
+	cmd.Flags().BoolVar(&once, "once", false, "Apply the mode for the current time and exit")
+	cmd.Flags().BoolVar(&stop, "stop", false, "Stop the background daemon")
+	cmd.Flags().BoolVar(&status, "status", false, "Show the schedule and next transition")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output status as JSON")
+	cmd.Flags().BoolVar(&enableNotify, "notify", false, "Send a desktop notification on each switch")
+	cmd.Flags().StringVar(&apps, "apps", "", "Comma-separated list of apps to theme (e.g., 'gtk,qt,discord')")
+
+	return cmd
+}
+
+// runAutoLoop switches modes at each transition until interrupted
+func runAutoLoop(schedule *scheme.AutoSchedule, shouldNotify bool, selectedApps []string) error {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	logger.Info("Auto mode started", "schedule", schedule.Describe())
+
+	// Only the mode the schedule asks for is tracked, so manual changes
+	// survive until the next transition
+	lastMode := ""
+	for {
+		mode, next := schedule.ModeAt(time.Now())
+		if mode != lastMode {
+			if _, err := switchSchemeMode(mode, shouldNotify, selectedApps); err != nil {
+				logger.Error("Failed to switch mode", "mode", mode, "error", err)
+			}
+			lastMode = mode
+			recordAutoState(schedule, mode, next, true)
+		}
+
+		wait := daemonCheckInterval
+		if !next.At.IsZero() {
+			if until := time.Until(next.At); until < wait {
+				wait = until
+			}
+		}
+
+		select {
+		case <-sigChan:
+			logger.Info("Auto mode stopped")
+			recordAutoState(schedule, mode, next, false)
+			schemeDaemon("auto").RemovePID()
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// switchSchemeMode loads the current scheme in the given mode and applies it.
+// Returns false when the scheme is already in that mode.
+func switchSchemeMode(mode string, shouldNotify bool, selectedApps []string) (bool, error) {
+	manager := scheme.NewManager()
+
+	current, err := manager.GetCurrent()
+	if err != nil {
+		return false, fmt.Errorf("failed to get current scheme: %w", err)
+	}
+	if current.Mode == mode {
+		return false, nil
+	}
+
+	newScheme, err := manager.LoadSchemeWithFallback(current.Name, current.Flavour, mode)
+	if err != nil {
+		return false, fmt.Errorf("scheme %s/%s has no %s mode: %w", current.Name, current.Flavour, mode, err)
+	}
+
+	if err := manager.SetScheme(newScheme); err != nil {
+		return false, fmt.Errorf("failed to set scheme: %w", err)
+	}
+
+	stateManager := theme.NewStateManager()
+	stateManager.SetCurrent(theme.CurrentTheme{
+		Name:    current.Name,
+		Flavour: current.Flavour,
+		Mode:    mode,
+		Variant: current.Variant,
+		Source:  newScheme.Source,
+	})
+
+	if err := applyThemeWithOptions(newScheme, selectedApps); err != nil {
+		return false, fmt.Errorf("failed to apply theme: %w", err)
+	}
+
+	logger.Info("Auto mode switched scheme",
+		"scheme", current.Name,
+		"flavour", current.Flavour,
+		"mode", mode)
+
+	if shouldNotify {
+		notifier := notify.NewNotifier()
+		notifier.Send(&notify.Notification{
+			Summary: "Scheme Mode Changed",
+			Body:    fmt.Sprintf("Switched %s/%s to %s mode", current.Name, current.Flavour, mode),
+			Urgency: notify.UrgencyLow,
+		})
+	}
+
+	return true, nil
+}
+
+// recordAutoState persists the schedule and next transition
+func recordAutoState(schedule *scheme.AutoSchedule, mode string, next scheme.ModeTransition, active bool) {
+	stateManager := theme.NewStateManager()
+	if err := stateManager.SetAutoMode(theme.AutoModeInfo{
+		Active:         active,
+		Trigger:        schedule.Trigger,
+		Schedule:       schedule.Describe(),
+		CurrentMode:    mode,
+		NextMode:       next.Mode,
+		NextTransition: next.At,
+	}); err != nil {
+		logger.Error("Failed to save auto mode state", "error", err)
+	}
+}
+
+// autoStatus is the JSON form of the auto mode status
+type autoStatus struct {
+	Enabled        bool      `json:"enabled"`
+	Running        bool      `json:"running"`
+	PID            int       `json:"pid,omitempty"`
+	Trigger        string    `json:"trigger"`
+	Schedule       string    `json:"schedule"`
+	Mode           string    `json:"mode"`
+	NextMode       string    `json:"next_mode,omitempty"`
+	NextTransition time.Time `json:"next_transition,omitempty"`
+}
+
+// showAutoStatus prints the schedule, daemon state and next transition
+func showAutoStatus(schedule *scheme.AutoSchedule, jsonOutput bool) error {
+	mode, next := schedule.ModeAt(time.Now())
+	pid := schemeDaemon("auto").PID()
+
+	status := autoStatus{
+		Enabled:        config.Get().Scheme.AutoMode,
+		Running:        pid != 0,
+		PID:            pid,
+		Trigger:        schedule.Trigger,
+		Schedule:       schedule.Describe(),
+		Mode:           mode,
+		NextMode:       next.Mode,
+		NextTransition: next.At,
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("\033[36;1mAuto Mode\033[0m\n")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("Enabled:  %v\n", status.Enabled)
+	if status.Running {
+		fmt.Printf("Daemon:   running (PID: %d)\n", pid)
+	} else {
+		fmt.Printf("Daemon:   not running\n")
+	}
+	fmt.Printf("Schedule: %s\n", status.Schedule)
+	fmt.Printf("Mode now: %s\n", mode)
+	if next.At.IsZero() {
+		fmt.Printf("Next:     none in the next two days\n")
+	} else {
+		fmt.Printf("Next:     %s at %s (in %s)\n", next.Mode, next.At.Format("2006-01-02 15:04"),
+			time.Until(next.At).Round(time.Minute))
+	}
+
+	return nil
+}
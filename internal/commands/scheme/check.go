@@ -0,0 +1,176 @@
+package scheme
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/theme"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+	"github.com/spf13/cobra"
+)
+
+// checkResult is the JSON shape of a scheme check
+type checkResult struct {
+	Scheme    string                   `json:"scheme"`
+	Version   int                      `json:"schema_version"`
+	Supported int                      `json:"supported_version"`
+	Missing   []string                 `json:"missing,omitempty"`
+	Derived   []string                 `json:"derived,omitempty"`
+	Unknown   []string                 `json:"unknown,omitempty"`
+	Templates []theme.TemplateKeyUsage `json:"templates,omitempty"`
+}
+
+// checkCommand creates the scheme check subcommand
+func checkCommand() *cobra.Command {
+	var (
+		jsonOutput bool
+		strict     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check [scheme] [flavour] [mode]",
+		Short: "Check a scheme against the color key schema",
+		Long: `Check a scheme against the versioned color key schema.
+
+Reports required keys the scheme is missing, keys filled in by the
+derivation layer, keys the schema does not know about, and templates
+that reference keys the scheme cannot provide.
+
+Schemes declaring "schema_version" fail to load when required keys are
+missing; unversioned schemes are loaded leniently. Use --strict to hold
+an unversioned scheme to the current schema.
+
+Examples:
+  heimdall scheme check                       # Check the current scheme
+  heimdall scheme check rosepine main dark    # Check a specific scheme
+  heimdall scheme check --strict my-scheme    # Fail on missing required keys
+  heimdall scheme check --json                # Output the report as JSON`,
+		Args: cobra.RangeArgs(0, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager := scheme.NewManager()
+
+			target, err := resolveCheckTarget(manager, args)
+			if err != nil {
+				return err
+			}
+
+			// Schemes read from state have not been through the load pipeline
+			report := target.Schema
+			if report == nil {
+				report, err = scheme.EnforceSchema(target)
+				if report == nil {
+					return fmt.Errorf("scheme %s: %w", target.Name, err)
+				}
+			}
+
+			result := checkResult{
+				Scheme:    fmt.Sprintf("%s/%s/%s", target.Name, target.Flavour, target.Mode),
+				Version:   report.Version,
+				Supported: scheme.CurrentSchemaVersion,
+				Missing:   report.Missing,
+				Derived:   report.Derived,
+				Unknown:   report.Unknown,
+				Templates: theme.TemplateKeyReport(target.Colours, filepath.Join(paths.DataDir, "templates")),
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(result, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal report: %w", err)
+				}
+				fmt.Println(string(data))
+			} else {
+				printCheckResult(result)
+			}
+
+			if strict && len(result.Missing) > 0 {
+				return fmt.Errorf("scheme %s is missing required keys: %s", result.Scheme, strings.Join(result.Missing, ", "))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail when required keys are missing, even for unversioned schemes")
+
+	return cmd
+}
+
+// resolveCheckTarget loads the scheme named by args, or the current scheme
+func resolveCheckTarget(manager *scheme.Manager, args []string) (*scheme.Scheme, error) {
+	if len(args) == 0 {
+		current, err := manager.GetCurrent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current scheme: %w", err)
+		}
+		return current, nil
+	}
+
+	name := args[0]
+	flavour := ""
+	mode := "dark"
+	if len(args) > 1 {
+		flavour = args[1]
+	}
+	if len(args) > 2 {
+		mode = args[2]
+	}
+
+	if flavour == "" {
+		flavours, err := manager.ListFlavours(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list flavours: %w", err)
+		}
+		flavour = flavours[0]
+	}
+
+	loaded, err := manager.LoadSchemeWithFallback(name, flavour, mode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scheme: %w", err)
+	}
+	return loaded, nil
+}
+
+// printCheckResult renders a check result for humans
+func printCheckResult(result checkResult) {
+	fmt.Printf("\033[36;1mScheme Check\033[0m\n")
+	fmt.Printf("━━━━━━━━━━━━\n")
+	fmt.Printf("Scheme:   %s\n", result.Scheme)
+	if result.Version == 0 {
+		fmt.Printf("Schema:   unversioned (current is v%d)\n", result.Supported)
+	} else {
+		fmt.Printf("Schema:   v%d\n", result.Version)
+	}
+
+	if len(result.Missing) > 0 {
+		fmt.Printf("\n\033[31;1mMissing required keys\033[0m\n")
+		for _, key := range result.Missing {
+			fmt.Printf("  - %s\n", key)
+		}
+	} else {
+		fmt.Printf("\n\033[32m✓ All required keys present\033[0m\n")
+	}
+
+	if len(result.Derived) > 0 {
+		fmt.Printf("\n\033[33;1mDerived keys\033[0m (%d)\n", len(result.Derived))
+		fmt.Printf("  %s\n", strings.Join(result.Derived, ", "))
+	}
+
+	if len(result.Unknown) > 0 {
+		fmt.Printf("\n\033[34;1mKeys outside the schema\033[0m (%d)\n", len(result.Unknown))
+		fmt.Printf("  %s\n", strings.Join(result.Unknown, ", "))
+	}
+
+	if len(result.Templates) > 0 {
+		fmt.Printf("\n\033[35;1mTemplates referencing absent keys\033[0m\n")
+		for _, usage := range result.Templates {
+			fmt.Printf("  %-12s %s\n", usage.Template, strings.Join(usage.Missing, ", "))
+		}
+	} else {
+		fmt.Printf("\n\033[32m✓ All templates resolve\033[0m\n")
+	}
+}
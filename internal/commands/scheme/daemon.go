@@ -0,0 +1,24 @@
+package scheme
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/daemon"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+)
+
+// daemonCheckInterval bounds how long scheme daemons sleep between
+// evaluations, so suspend/resume and clock changes are picked up quickly
+const daemonCheckInterval = time.Minute
+
+// schemeDaemon returns the background process of a scheme daemon, which
+// re-executes the current command without -d
+func schemeDaemon(name string) daemon.Process {
+	base := filepath.Join(paths.StateDir, "heimdall-scheme-"+name)
+	return daemon.Process{
+		Name:    name + " daemon",
+		PIDFile: base + ".pid",
+		LogFile: base + ".log",
+	}
+}
@@ -0,0 +1,226 @@
+package scheme
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/scheme/generator"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/color"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
+	"github.com/spf13/cobra"
+)
+
+// generatedScheme describes one scheme written by scheme generate
+type generatedScheme struct {
+	Name    string `json:"name"`
+	Flavour string `json:"flavour"`
+	Mode    string `json:"mode"`
+	Primary string `json:"primary"`
+}
+
+// generateResult is the JSON shape of scheme generate
+type generateResult struct {
+	Seed    string            `json:"seed"`
+	Source  string            `json:"source,omitempty"`
+	Schemes []generatedScheme `json:"schemes"`
+}
+
+// generateCommand creates the scheme generate subcommand
+func generateCommand() *cobra.Command {
+	var (
+		seed       string
+		fromScheme string
+		name       string
+		variants   []string
+		mode       string
+		set        bool
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate Material You schemes from a seed color",
+		Long: `Generate Material You schemes from a seed color instead of a wallpaper.
+
+Every variant is written for both modes as a user scheme, with one
+flavour per variant, the same way wallpaper generation does. Restrict
+the output with --variant and --mode.
+
+The seed is either a hex color (--seed) or the primary color of an
+existing scheme (--from-scheme name[/flavour[/mode]]).
+
+Variants: vibrant, tonal, expressive, fidelity, content, fruit_salad,
+rainbow, neutral, and monochrome when asked for with --variant
+
+Examples:
+  heimdall scheme generate --seed '#7aa2f7'                        # All variants, both modes
+  heimdall scheme generate --seed '#7aa2f7' --variant tonal --mode dark
+  heimdall scheme generate --seed '#e64553' --name acme --set      # Brand theme, applied
+  heimdall scheme generate --from-scheme rosepine/main             # Seed from a scheme`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (seed == "") == (fromScheme == "") {
+				return fmt.Errorf("exactly one of --seed or --from-scheme is required")
+			}
+
+			manager := scheme.NewManager()
+
+			source := ""
+			if fromScheme != "" {
+				primary, err := schemePrimary(manager, fromScheme)
+				if err != nil {
+					return err
+				}
+				seed = primary
+				source = fromScheme
+			}
+
+			seedColor, err := color.NewFromHex(seed)
+			if err != nil {
+				return fmt.Errorf("invalid seed color %q: %w", seed, err)
+			}
+			seedHex := strings.TrimPrefix(seedColor.Hex, "#")
+
+			selected, err := parseVariants(variants)
+			if err != nil {
+				return err
+			}
+
+			modes := []string{"dark", "light"}
+			if mode != "" {
+				if mode != "dark" && mode != "light" {
+					return fmt.Errorf("invalid mode: %s (must be 'dark' or 'light')", mode)
+				}
+				modes = []string{mode}
+			}
+
+			if name == "" {
+				name = "seed-" + strings.ToLower(seedHex)
+			}
+
+			argb := 0xFF000000 | uint32(seedColor.RGB.R)<<16 | uint32(seedColor.RGB.G)<<8 | uint32(seedColor.RGB.B)
+			wallpaperGen := generator.NewWallpaperGenerator()
+			if cfg := config.Get(); cfg != nil {
+				if err := wallpaperGen.SetCustomColors(cfg.Scheme.CustomColors); err != nil {
+					return err
+				}
+			}
+
+			generated, err := wallpaperGen.GenerateFromSeed(argb, selected, modes)
+			if err != nil {
+				return fmt.Errorf("failed to generate schemes: %w", err)
+			}
+
+			result := generateResult{Seed: "#" + strings.ToLower(seedHex), Source: source}
+
+			keys := make([]string, 0, len(generated))
+			for key := range generated {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				s := generated[key]
+				s.Name = name
+				s.Flavour = s.Variant
+
+				if err := manager.SaveSchemeToUser(s); err != nil {
+					return fmt.Errorf("failed to save %s/%s/%s: %w", s.Name, s.Flavour, s.Mode, err)
+				}
+				logger.Info("Saved generated scheme", "scheme", s.Name, "flavour", s.Flavour, "mode", s.Mode)
+
+				result.Schemes = append(result.Schemes, generatedScheme{
+					Name:    s.Name,
+					Flavour: s.Flavour,
+					Mode:    s.Mode,
+					Primary: s.Colours["primary"],
+				})
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(result, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal result: %w", err)
+				}
+				fmt.Println(string(data))
+			} else {
+				printGenerateResult(result)
+			}
+
+			if set {
+				flavour := string(selected[0])
+				if len(variants) == 0 {
+					flavour = string(generator.VariantTonal)
+				}
+				return setSchemeByFlags(manager, name, flavour, modes[0], "", true, false, nil, false)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&seed, "seed", "", "Seed color in hex (e.g. '#7aa2f7')")
+	cmd.Flags().StringVar(&fromScheme, "from-scheme", "", "Seed from a scheme's primary color (name[/flavour[/mode]])")
+	cmd.Flags().StringVarP(&name, "name", "n", "", "Name of the generated scheme (default seed-<hex>)")
+	cmd.Flags().StringSliceVarP(&variants, "variant", "v", nil, "Variants to generate (default all but monochrome)")
+	cmd.Flags().StringVarP(&mode, "mode", "m", "", "Mode to generate: dark or light (default both)")
+	cmd.Flags().BoolVar(&set, "set", false, "Set and apply the generated scheme (first variant and mode)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// parseVariants validates variant names, defaulting to every variant
+func parseVariants(names []string) ([]generator.MaterialYouVariant, error) {
+	if len(names) == 0 {
+		return generator.AllVariants, nil
+	}
+
+	variants := make([]generator.MaterialYouVariant, 0, len(names))
+	for _, name := range names {
+		variant, err := generator.ParseVariant(name)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
+
+// schemePrimary returns the primary color of a scheme given as
+// name[/flavour[/mode]]
+func schemePrimary(manager *scheme.Manager, ref string) (string, error) {
+	source, err := resolveCheckTarget(manager, strings.Split(ref, "/"))
+	if err != nil {
+		return "", err
+	}
+
+	primary, ok := source.Colours["primary"]
+	if !ok {
+		return "", fmt.Errorf("scheme %s has no primary color", ref)
+	}
+	return primary, nil
+}
+
+// printGenerateResult renders the generated schemes for humans
+func printGenerateResult(result generateResult) {
+	fmt.Printf("\033[36;1mGenerated Schemes\033[0m\n")
+	fmt.Printf("━━━━━━━━━━━━━━━━━\n")
+	fmt.Printf("Seed:   %s\n", result.Seed)
+	if result.Source != "" {
+		fmt.Printf("Source: %s\n", result.Source)
+	}
+	fmt.Println()
+
+	for _, s := range result.Schemes {
+		primary := s.Primary
+		if !strings.HasPrefix(primary, "#") {
+			primary = "#" + primary
+		}
+		fmt.Printf("  %s/%s/%s  primary %s\n", s.Name, s.Flavour, s.Mode, primary)
+	}
+}
@@ -171,6 +171,8 @@ func displaySchemeInfo(s *scheme.Scheme, useColor bool) error {
 				sourceColor = "\033[32m" // Green for user
 			case scheme.SourceGenerated:
 				sourceColor = "\033[33m" // Yellow for generated
+			case scheme.SourceSynced:
+				sourceColor = "\033[35m" // Magenta for synced
 			case scheme.SourceBundled:
 				sourceColor = "\033[36m" // Cyan for bundled
 			}
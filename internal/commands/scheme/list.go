@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/theme"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +26,9 @@ func listCommand() *cobra.Command {
 		showColors   bool
 		jsonOutput   bool
 		sourceFilter string
+		tags         []string
+		modeFilter   string
+		favourites   bool
 	)
 
 	cmd := &cobra.Command{
@@ -41,18 +45,21 @@ Examples:
   heimdall scheme list -m                 # List modes for current scheme/flavour
   heimdall scheme list -v                 # List Material You variants
   heimdall scheme list -s rosepine        # List flavours for rosepine
-  heimdall scheme list -s rosepine -f main # List modes for rosepine/main`,
+  heimdall scheme list -s rosepine -f main # List modes for rosepine/main
+  heimdall scheme list --tag warm --mode dark # Warm schemes with a dark mode
+  heimdall scheme list --favourites -n    # Favourite scheme names only`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			manager := scheme.NewManager()
+			filter := scheme.Filter{Tags: tags, Mode: modeFilter, Favourites: favourites}
 
 			// Handle tree view (showColors implies treeView)
 			if treeView || showColors {
-				return listTreeView(manager, showColors, sourceFilter)
+				return listTreeView(manager, showColors, sourceFilter, filter)
 			}
 
 			// Handle compatibility flags
 			if listNames {
-				return listSchemeNames(manager, sourceFilter)
+				return listSchemeNames(manager, sourceFilter, filter)
 			}
 
 			if listFlavours {
@@ -74,7 +81,7 @@ Examples:
 
 			// If no flags, show tree view (default)
 			if schemeName == "" && flavour == "" {
-				return listTreeView(manager, false, sourceFilter)
+				return listTreeView(manager, false, sourceFilter, filter)
 			}
 
 			// List modes for specific scheme/flavour
@@ -120,7 +127,10 @@ Examples:
 	cmd.Flags().BoolVarP(&treeView, "tree", "t", false, "Display schemes in tree view with structure")
 	cmd.Flags().BoolVarP(&showColors, "colors", "c", false, "Show color preview in tree view")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format (legacy)")
-	cmd.Flags().StringVar(&sourceFilter, "source", "", "Filter by source (bundled, user, generated)")
+	cmd.Flags().StringVar(&sourceFilter, "source", "", "Filter by source (bundled, user, generated, synced)")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Only schemes with this tag (repeatable)")
+	cmd.Flags().StringVar(&modeFilter, "mode", "", "Only schemes providing this mode (dark or light)")
+	cmd.Flags().BoolVar(&favourites, "favourites", false, "Only favourite schemes")
 
 	return cmd
 }
@@ -154,6 +164,8 @@ func listJSONFormat() error {
 			sourceStr = "user"
 		case scheme.SourceGenerated:
 			sourceStr = "generated"
+		case scheme.SourceSynced:
+			sourceStr = "synced"
 		case scheme.SourceBundled:
 			sourceStr = "bundled"
 		}
@@ -340,12 +352,15 @@ func readMaterialYouJSONFile(path string) (map[string]string, error) {
 }
 
 // listSchemeNames lists all available scheme names
-func listSchemeNames(manager *scheme.Manager, sourceFilter string) error {
+func listSchemeNames(manager *scheme.Manager, sourceFilter string, filter scheme.Filter) error {
 	schemes, err := manager.ListSchemes()
 	if err != nil {
 		return err
 	}
 
+	metadata := theme.NewStateManager().GetAllSchemeMetadata()
+	schemes = manager.FilterSchemes(schemes, metadata, filter)
+
 	sort.Strings(schemes)
 	for _, schemeName := range schemes {
 		// Apply source filter if specified
@@ -364,6 +379,10 @@ func listSchemeNames(manager *scheme.Manager, sourceFilter string) error {
 				if source != scheme.SourceGenerated {
 					continue
 				}
+			case "synced":
+				if source != scheme.SourceSynced {
+					continue
+				}
 			}
 		}
 		fmt.Println(schemeName)
@@ -442,12 +461,15 @@ func listMaterialYouVariants() error {
 }
 
 // listTreeView displays schemes in an organized tree structure with optional color previews
-func listTreeView(manager *scheme.Manager, showColors bool, sourceFilter string) error {
+func listTreeView(manager *scheme.Manager, showColors bool, sourceFilter string, filter scheme.Filter) error {
 	schemes, err := manager.ListSchemes()
 	if err != nil {
 		return fmt.Errorf("failed to list schemes: %w", err)
 	}
 
+	metadata := theme.NewStateManager().GetAllSchemeMetadata()
+	schemes = manager.FilterSchemes(schemes, metadata, filter)
+
 	// Filter out non-scheme entries
 	var validSchemes []string
 	for _, s := range schemes {
@@ -481,6 +503,10 @@ func listTreeView(manager *scheme.Manager, showColors bool, sourceFilter string)
 				if source != scheme.SourceGenerated {
 					continue
 				}
+			case "synced":
+				if source != scheme.SourceSynced {
+					continue
+				}
 			}
 		}
 
@@ -491,17 +517,22 @@ func listTreeView(manager *scheme.Manager, showColors bool, sourceFilter string)
 			sourceIndicator = " \033[32m[user]\033[0m"
 		case scheme.SourceGenerated:
 			sourceIndicator = " \033[33m[generated]\033[0m"
+		case scheme.SourceSynced:
+			sourceIndicator = " \033[35m[synced]\033[0m"
 		case scheme.SourceBundled:
 			sourceIndicator = " \033[34m[bundled]\033[0m"
 		}
 
-		fmt.Printf("\033[35;1m%s\033[0m%s\n", schemeName, sourceIndicator)
+		fmt.Printf("\033[35;1m%s\033[0m%s%s\n", schemeName, sourceIndicator, metadataIndicator(metadata[schemeName]))
 
 		// Get flavours for this scheme
 		flavours, err := manager.ListFlavours(schemeName)
 		if err != nil {
 			continue // Skip if can't get flavours
 		}
+		if filter.Mode != "" {
+			flavours = manager.FlavoursWithMode(schemeName, filter.Mode)
+		}
 
 		sort.Strings(flavours)
 
@@ -521,6 +552,9 @@ func listTreeView(manager *scheme.Manager, showColors bool, sourceFilter string)
 			}
 
 			sort.Strings(modes)
+			if filter.Mode != "" {
+				modes = []string{filter.Mode}
+			}
 
 			// Print flavour
 			if isLastFlavour {
@@ -576,6 +610,18 @@ func listTreeView(manager *scheme.Manager, showColors bool, sourceFilter string)
 	return nil
 }
 
+// metadataIndicator formats the favourite marker and tags of a scheme
+func metadataIndicator(meta scheme.Metadata) string {
+	indicator := ""
+	if meta.Favourite {
+		indicator += " \033[33m★\033[0m"
+	}
+	if len(meta.Tags) > 0 {
+		indicator += fmt.Sprintf(" \033[2m#%s\033[0m", strings.Join(meta.Tags, " #"))
+	}
+	return indicator
+}
+
 // contains checks if a string slice contains a specific string
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -0,0 +1,512 @@
+package scheme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/theme"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/notify"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+	"github.com/spf13/cobra"
+)
+
+// rotateOptions controls how playlist entries are applied
+type rotateOptions struct {
+	notify bool
+	apps   []string
+}
+
+// rotateCommand creates the scheme rotate subcommand
+func rotateCommand() *cobra.Command {
+	var (
+		daemon       bool
+		stop         bool
+		enableNotify bool
+		apps         string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rotate [playlist]",
+		Short: "Rotate through a scheme playlist on a schedule",
+		Long: `Rotate through a named scheme playlist on a schedule.
+
+Playlists are defined under scheme.playlists in the configuration:
+  schemes  - Entries as name[/flavour[/mode]], or wallpaper:PATH to set a
+             wallpaper and apply the scheme generated from it
+  interval - Rotate every N minutes
+  times    - Times of day (HH:MM) to rotate at
+  weekdays - Days the schedule is active (mon, tue, ...)
+  shuffle  - Random order without repeats until every entry has played
+
+Entries without a mode keep the current mode when the scheme provides it.
+Each rotation is recorded in the theme history.
+
+Subcommands:
+  next     - Apply the next entry now
+  previous - Apply the previous entry now
+  skip     - Skip the upcoming entry without applying anything
+  status   - Show the active playlist and next rotation
+  list     - List configured playlists
+
+Examples:
+  heimdall scheme rotate evening        # Rotate the 'evening' playlist
+  heimdall scheme rotate evening -d     # Rotate in the background
+  heimdall scheme rotate next           # Jump to the next entry
+  heimdall scheme rotate --stop         # Stop the background daemon`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if stop {
+				pid, err := schemeDaemon("rotate").Stop()
+				if err != nil {
+					return err
+				}
+				fmt.Printf("✓ Stopped rotation daemon (PID: %d)\n", pid)
+				return nil
+			}
+
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			playlist, err := resolvePlaylist(name)
+			if err != nil {
+				return err
+			}
+			if !playlist.Scheduled() {
+				return fmt.Errorf("playlist %s has no interval or times; use 'heimdall scheme rotate next %s'", playlist.Name, playlist.Name)
+			}
+
+			if daemon {
+				pid, err := schemeDaemon("rotate").Start()
+				if err != nil {
+					return err
+				}
+				fmt.Printf("✓ Rotation daemon started for %s (PID: %d)\n", playlist.Name, pid)
+				fmt.Printf("  To stop: heimdall scheme rotate --stop\n")
+				return nil
+			}
+
+			return runRotation(playlist, rotateOptions{notify: enableNotify, apps: splitApps(apps)})
+		},
+	}
+
+	cmd.Flags().BoolVarP(&daemon, "daemon", "d", false, "Run in the background")
+	cmd.Flags().BoolVar(&stop, "stop", false, "Stop the background daemon")
+	cmd.PersistentFlags().BoolVar(&enableNotify, "notify", false, "Send a desktop notification on each rotation")
+	cmd.PersistentFlags().StringVar(&apps, "apps", "", "Comma-separated list of apps to theme (e.g., 'gtk,qt,discord')")
+
+	cmd.AddCommand(rotateStepCommand("next", nil, "Apply the next playlist entry now", 1, &enableNotify, &apps))
+	cmd.AddCommand(rotateStepCommand("previous", []string{"prev"}, "Apply the previous playlist entry now", -1, &enableNotify, &apps))
+	cmd.AddCommand(rotateSkipCommand())
+	cmd.AddCommand(rotateStatusCommand())
+	cmd.AddCommand(rotateListCommand())
+
+	return cmd
+}
+
+// rotateStepCommand creates the next and previous control commands
+func rotateStepCommand(use string, aliases []string, short string, step int, enableNotify *bool, apps *string) *cobra.Command {
+	return &cobra.Command{
+		Use:     use + " [playlist]",
+		Aliases: aliases,
+		Short:   short,
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			playlist, err := resolvePlaylist(name)
+			if err != nil {
+				return err
+			}
+
+			entry, err := stepPlaylist(playlist, step, rotateOptions{notify: *enableNotify, apps: splitApps(*apps)})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Playlist %s: %s\n", playlist.Name, entry)
+			return nil
+		},
+	}
+}
+
+// rotateSkipCommand creates the skip control command
+func rotateSkipCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "skip [playlist]",
+		Short: "Skip the upcoming playlist entry",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			playlist, err := resolvePlaylist(name)
+			if err != nil {
+				return err
+			}
+
+			stateManager := theme.NewStateManager()
+			info := playlistRotation(stateManager, playlist)
+			info.Cursor = playlist.Advance(info.Cursor)
+			if err := stateManager.SetRotation(info); err != nil {
+				return fmt.Errorf("failed to save rotation state: %w", err)
+			}
+
+			fmt.Printf("Playlist %s: skipped %s\n", playlist.Name, playlist.Entries[info.Cursor.Current()])
+			return nil
+		},
+	}
+}
+
+// rotationStatus is the JSON form of the rotation status
+type rotationStatus struct {
+	Playlist     string    `json:"playlist"`
+	Schedule     string    `json:"schedule"`
+	Running      bool      `json:"running"`
+	PID          int       `json:"pid,omitempty"`
+	Current      string    `json:"current,omitempty"`
+	LastRotation time.Time `json:"last_rotation,omitempty"`
+	NextRotation time.Time `json:"next_rotation,omitempty"`
+}
+
+// rotateStatusCommand creates the status command
+func rotateStatusCommand() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the active playlist and next rotation",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := theme.NewStateManager().GetRotation()
+			if info.Playlist == "" {
+				return fmt.Errorf("no playlist has been rotated yet")
+			}
+
+			playlist, err := resolvePlaylist(info.Playlist)
+			if err != nil {
+				return err
+			}
+
+			pid := schemeDaemon("rotate").PID()
+			status := rotationStatus{
+				Playlist:     playlist.Name,
+				Schedule:     playlist.Describe(),
+				Running:      pid != 0,
+				PID:          pid,
+				LastRotation: info.LastRotation,
+				NextRotation: playlist.NextRotation(info.LastRotation, time.Now()),
+			}
+			if current := info.Cursor.Current(); current >= 0 && current < len(playlist.Entries) {
+				status.Current = playlist.Entries[current].String()
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(status, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal status: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			fmt.Printf("\033[36;1mPlaylist Rotation\033[0m\n")
+			fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+			fmt.Printf("Playlist: %s (%d entries)\n", status.Playlist, len(playlist.Entries))
+			fmt.Printf("Schedule: %s\n", status.Schedule)
+			if status.Running {
+				fmt.Printf("Daemon:   running (PID: %d)\n", pid)
+			} else {
+				fmt.Printf("Daemon:   not running\n")
+			}
+			if status.Current != "" {
+				fmt.Printf("Current:  %s\n", status.Current)
+			}
+			if !status.LastRotation.IsZero() {
+				fmt.Printf("Last:     %s\n", status.LastRotation.Format("2006-01-02 15:04"))
+			}
+			if !status.NextRotation.IsZero() {
+				fmt.Printf("Next:     %s\n", status.NextRotation.Format("2006-01-02 15:04"))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+// rotateListCommand creates the list command
+func rotateListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List configured playlists",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			playlists, err := scheme.LoadPlaylists(config.Get().Scheme.Playlists)
+			if err != nil {
+				return err
+			}
+			if len(playlists) == 0 {
+				fmt.Println("No playlists configured (add them under scheme.playlists)")
+				return nil
+			}
+
+			for _, playlist := range playlists {
+				fmt.Printf("%s - %s\n", playlist.Name, playlist.Describe())
+				for _, entry := range playlist.Entries {
+					fmt.Printf("  %s\n", entry)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// resolvePlaylist finds a configured playlist by name. Without a name, the
+// most recently rotated playlist is used, or the only one configured.
+func resolvePlaylist(name string) (*scheme.Playlist, error) {
+	configured := config.Get().Scheme.Playlists
+	if len(configured) == 0 {
+		return nil, fmt.Errorf("no playlists configured (add them under scheme.playlists)")
+	}
+
+	if name == "" {
+		name = theme.NewStateManager().GetRotation().Playlist
+	}
+	if name == "" && len(configured) == 1 {
+		for only := range configured {
+			name = only
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("playlist name is required when several playlists are configured")
+	}
+
+	cfg, ok := configured[name]
+	if !ok {
+		return nil, fmt.Errorf("playlist not found: %s", name)
+	}
+	return scheme.NewPlaylist(name, cfg)
+}
+
+// playlistRotation returns the stored rotation, reset when it belongs to
+// another playlist
+func playlistRotation(stateManager *theme.StateManager, playlist *scheme.Playlist) theme.RotationInfo {
+	info := stateManager.GetRotation()
+	if info.Playlist != playlist.Name {
+		info = theme.RotationInfo{Playlist: playlist.Name, Active: info.Active}
+	}
+	return info
+}
+
+// stepPlaylist moves the playlist forward (step > 0) or back and applies the
+// entry. The new position is recorded even if applying fails, so a broken
+// entry does not stall the rotation.
+func stepPlaylist(playlist *scheme.Playlist, step int, opts rotateOptions) (scheme.PlaylistEntry, error) {
+	stateManager := theme.NewStateManager()
+	info := playlistRotation(stateManager, playlist)
+
+	if step < 0 {
+		info.Cursor = playlist.Retreat(info.Cursor)
+	} else {
+		info.Cursor = playlist.Advance(info.Cursor)
+	}
+	entry := playlist.Entries[info.Cursor.Current()]
+
+	applyErr := applyPlaylistEntry(playlist, entry, opts)
+
+	// Applying updated the current theme on disk, reload before saving
+	stateManager.Load()
+	info.LastRotation = time.Now()
+	info.NextRotation = playlist.NextRotation(info.LastRotation, info.LastRotation)
+	if err := stateManager.SetRotation(info); err != nil {
+		logger.Error("Failed to save rotation state", "error", err)
+	}
+
+	if applyErr != nil {
+		return entry, fmt.Errorf("failed to apply %s: %w", entry, applyErr)
+	}
+	return entry, nil
+}
+
+// applyPlaylistEntry sets and applies a playlist entry
+func applyPlaylistEntry(playlist *scheme.Playlist, entry scheme.PlaylistEntry, opts rotateOptions) error {
+	manager := scheme.NewManager()
+
+	name, flavour, mode := entry.Scheme, entry.Flavour, entry.Mode
+	metadata := map[string]string{"playlist": playlist.Name}
+
+	if entry.Wallpaper != "" {
+		// The wallpaper command owns setting wallpapers and generating
+		// schemes from them, so run it rather than duplicating that logic
+		cmd := exec.Command(os.Args[0], "wallpaper", "-f", entry.Wallpaper)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to set wallpaper: %s: %w", strings.TrimSpace(string(output)), err)
+		}
+
+		prefs := theme.NewStateManager().GetPreferences()
+		name = "generated"
+		flavour = prefs.PreferredVariant
+		if flavour == "" {
+			flavour = "tonal"
+		}
+		mode = prefs.PreferredMode
+		if mode == "" {
+			mode, _ = wallpaper.NewAnalyzer().DetermineMode(entry.Wallpaper)
+		}
+		metadata["wallpaper"] = entry.Wallpaper
+	}
+
+	if flavour == "" {
+		flavours, err := manager.ListFlavours(name)
+		if err != nil {
+			return fmt.Errorf("failed to list flavours: %w", err)
+		}
+		if len(flavours) == 0 {
+			return fmt.Errorf("no flavours available for scheme %s", name)
+		}
+		flavour = flavours[0]
+	}
+
+	if mode == "" {
+		// Keep the current mode so rotation plays well with auto mode
+		modes, err := manager.ListModes(name, flavour)
+		if err != nil {
+			return fmt.Errorf("failed to list modes: %w", err)
+		}
+		if len(modes) == 0 {
+			return fmt.Errorf("no modes available for scheme %s/%s", name, flavour)
+		}
+		mode = modes[0]
+		if current, err := manager.GetCurrent(); err == nil {
+			for _, m := range modes {
+				if m == current.Mode {
+					mode = m
+				}
+			}
+		}
+	}
+
+	newScheme, err := manager.LoadSchemeWithFallback(name, flavour, mode)
+	if err != nil {
+		return fmt.Errorf("failed to load scheme: %w", err)
+	}
+
+	if err := manager.SetScheme(newScheme); err != nil {
+		return fmt.Errorf("failed to set scheme: %w", err)
+	}
+
+	stateManager := theme.NewStateManager()
+	stateManager.SetCurrent(theme.CurrentTheme{
+		Name:     name,
+		Flavour:  flavour,
+		Mode:     mode,
+		Source:   newScheme.Source,
+		Metadata: metadata,
+	})
+
+	if err := applyThemeWithOptions(newScheme, opts.apps); err != nil {
+		return fmt.Errorf("failed to apply theme: %w", err)
+	}
+
+	logger.Info("Playlist rotated",
+		"playlist", playlist.Name,
+		"scheme", name,
+		"flavour", flavour,
+		"mode", mode)
+
+	if opts.notify {
+		notifier := notify.NewNotifier()
+		notifier.Send(&notify.Notification{
+			Summary: "Scheme Rotated",
+			Body:    fmt.Sprintf("%s: applied %s/%s/%s", playlist.Name, name, flavour, mode),
+			Urgency: notify.UrgencyLow,
+		})
+	}
+
+	return nil
+}
+
+// runRotation rotates the playlist on its schedule until interrupted
+func runRotation(playlist *scheme.Playlist, opts rotateOptions) error {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	stateManager := theme.NewStateManager()
+	info := playlistRotation(stateManager, playlist)
+	info.Active = true
+	if err := stateManager.SetRotation(info); err != nil {
+		logger.Error("Failed to save rotation state", "error", err)
+	}
+
+	logger.Info("Playlist rotation started", "playlist", playlist.Name, "schedule", playlist.Describe())
+
+	for {
+		// Control commands run in other processes, so re-read the state
+		stateManager = theme.NewStateManager()
+		info = playlistRotation(stateManager, playlist)
+
+		now := time.Now()
+		next := playlist.NextRotation(info.LastRotation, now)
+		if !next.After(now) {
+			if _, err := stepPlaylist(playlist, 1, opts); err != nil {
+				logger.Error("Failed to rotate playlist", "playlist", playlist.Name, "error", err)
+			}
+			continue
+		}
+
+		if !info.NextRotation.Equal(next) || !info.Active {
+			info.NextRotation = next
+			info.Active = true
+			if err := stateManager.SetRotation(info); err != nil {
+				logger.Error("Failed to save rotation state", "error", err)
+			}
+		}
+
+		wait := daemonCheckInterval
+		if until := time.Until(next); until < wait {
+			wait = until
+		}
+
+		select {
+		case <-sigChan:
+			logger.Info("Playlist rotation stopped", "playlist", playlist.Name)
+			stateManager = theme.NewStateManager()
+			info = stateManager.GetRotation()
+			info.Active = false
+			stateManager.SetRotation(info)
+			schemeDaemon("rotate").RemovePID()
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// splitApps parses a comma-separated app list
+func splitApps(apps string) []string {
+	if apps == "" {
+		return nil
+	}
+
+	selected := strings.Split(apps, ",")
+	for i := range selected {
+		selected[i] = strings.TrimSpace(selected[i])
+	}
+	return selected
+}
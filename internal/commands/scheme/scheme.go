@@ -15,9 +15,17 @@ Available subcommands:
   list        - List available schemes, flavours, or modes
   get         - Get current scheme or specific property
   set         - Set the active scheme
+  search      - Fuzzy search schemes by name and tag
+  tag         - Add, remove or show scheme tags
+  favourite   - Mark a scheme as favourite
   install     - Install bundled color schemes
   bundled     - Show bundled schemes with details
   status      - Show current theme status and state
+  check       - Check a scheme against the color key schema
+  generate    - Generate Material You schemes from a seed color
+  auto        - Switch light/dark mode on a time or sun schedule
+  rotate      - Rotate through a scheme playlist on a schedule
+  sync        - Sync scheme repositories from git
   revert      - Revert to the previous theme
   preferences - Manage theme preferences`,
 	}
@@ -26,9 +34,17 @@ Available subcommands:
 	cmd.AddCommand(listCommand())
 	cmd.AddCommand(getCommand())
 	cmd.AddCommand(setCommand())
+	cmd.AddCommand(searchCommand())
+	cmd.AddCommand(tagCommand())
+	cmd.AddCommand(favouriteCommand())
 	cmd.AddCommand(installCommand())
 	cmd.AddCommand(bundledCommand())
 	cmd.AddCommand(statusCommand())
+	cmd.AddCommand(checkCommand())
+	cmd.AddCommand(generateCommand())
+	cmd.AddCommand(autoCommand())
+	cmd.AddCommand(rotateCommand())
+	cmd.AddCommand(syncCommand())
 	cmd.AddCommand(revertCommand())
 	cmd.AddCommand(preferencesCommand())
 
@@ -0,0 +1,222 @@
+package scheme
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/theme"
+	"github.com/spf13/cobra"
+)
+
+// searchCommand creates the scheme search subcommand
+func searchCommand() *cobra.Command {
+	var (
+		tags       []string
+		mode       string
+		favourites bool
+		limit      int
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Fuzzy search schemes by name and tag",
+		Long: `Fuzzy search available schemes by name and tag.
+
+Characters of the query must appear in order, so "rsp" finds rosepine.
+Matches are ranked by closeness, then favourites and usage count.
+
+Examples:
+  heimdall scheme search cat              # Find catppuccin
+  heimdall scheme search warm             # Schemes tagged warm
+  heimdall scheme search dark --mode light # Matches that have a light mode
+  heimdall scheme search gr --favourites  # Only favourites`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager := scheme.NewManager()
+			stateManager := theme.NewStateManager()
+
+			schemes, err := manager.ListSchemes()
+			if err != nil {
+				return fmt.Errorf("failed to list schemes: %w", err)
+			}
+
+			metadata := stateManager.GetAllSchemeMetadata()
+			filter := scheme.Filter{Tags: tags, Mode: mode, Favourites: favourites}
+			schemes = manager.FilterSchemes(schemes, metadata, filter)
+
+			results := scheme.Search(args[0], schemes, metadata)
+			if limit > 0 && len(results) > limit {
+				results = results[:limit]
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(results, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal results: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			if len(results) == 0 {
+				fmt.Printf("No schemes match %q\n", args[0])
+				return nil
+			}
+
+			for _, result := range results {
+				line := fmt.Sprintf("\033[35;1m%s\033[0m%s", result.Name, metadataIndicator(result.Metadata))
+				if result.MatchedOn != result.Name {
+					line += fmt.Sprintf("  (tag: %s)", result.MatchedOn)
+				}
+				if result.Metadata.UsageCount > 0 {
+					line += fmt.Sprintf("  \033[2mused %d×\033[0m", result.Metadata.UsageCount)
+				}
+				fmt.Println(line)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Only schemes with this tag (repeatable)")
+	cmd.Flags().StringVar(&mode, "mode", "", "Only schemes providing this mode (dark or light)")
+	cmd.Flags().BoolVar(&favourites, "favourites", false, "Only favourite schemes")
+	cmd.Flags().IntVarP(&limit, "limit", "l", 0, "Maximum number of results (0 for all)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+// tagCommand creates the scheme tag subcommand
+func tagCommand() *cobra.Command {
+	var remove bool
+
+	cmd := &cobra.Command{
+		Use:   "tag <scheme> [tag...]",
+		Short: "Add, remove or show scheme tags",
+		Long: `Add, remove or show the tags of a scheme.
+
+Tags are lowercased and spaces become dashes. Without tags, the scheme's
+current tags and usage are shown.
+
+Examples:
+  heimdall scheme tag gruvbox warm high-contrast  # Add tags
+  heimdall scheme tag gruvbox --remove warm       # Remove a tag
+  heimdall scheme tag gruvbox                     # Show tags`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, tags := args[0], args[1:]
+			stateManager := theme.NewStateManager()
+
+			if len(tags) == 0 {
+				if remove {
+					return fmt.Errorf("specify the tags to remove")
+				}
+				printSchemeMetadata(name, stateManager.GetSchemeMetadata(name))
+				return nil
+			}
+
+			if err := requireScheme(name); err != nil {
+				return err
+			}
+
+			err := stateManager.UpdateSchemeMetadata(name, func(meta *scheme.Metadata) {
+				if remove {
+					meta.RemoveTags(tags...)
+				} else {
+					meta.AddTags(tags...)
+				}
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update tags: %w", err)
+			}
+
+			meta := stateManager.GetSchemeMetadata(name)
+			if len(meta.Tags) == 0 {
+				fmt.Printf("%s has no tags\n", name)
+			} else {
+				fmt.Printf("%s tags: %s\n", name, strings.Join(meta.Tags, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&remove, "remove", false, "Remove the given tags instead of adding them")
+
+	return cmd
+}
+
+// favouriteCommand creates the scheme favourite subcommand
+func favouriteCommand() *cobra.Command {
+	var remove bool
+
+	cmd := &cobra.Command{
+		Use:     "favourite [scheme]",
+		Aliases: []string{"fav"},
+		Short:   "Mark a scheme as favourite",
+		Long: `Mark a scheme as favourite, or unmark it with --remove.
+Defaults to the current scheme.
+
+Favourites are starred in 'scheme list', rank first in 'scheme search' and
+can be picked from with 'scheme set --random --favourites'.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stateManager := theme.NewStateManager()
+
+			name := stateManager.GetCurrent().Name
+			if len(args) > 0 {
+				name = args[0]
+			}
+			if err := requireScheme(name); err != nil {
+				return err
+			}
+
+			err := stateManager.UpdateSchemeMetadata(name, func(meta *scheme.Metadata) {
+				meta.Favourite = !remove
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update favourite: %w", err)
+			}
+
+			if remove {
+				fmt.Printf("Removed %s from favourites\n", name)
+			} else {
+				fmt.Printf("Added %s to favourites\n", name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&remove, "remove", false, "Remove the scheme from favourites")
+
+	return cmd
+}
+
+// requireScheme returns an error when no scheme with the name exists
+func requireScheme(name string) error {
+	schemes, err := scheme.NewManager().ListSchemes()
+	if err != nil {
+		return fmt.Errorf("failed to list schemes: %w", err)
+	}
+	if !contains(schemes, name) {
+		return fmt.Errorf("scheme %q not found", name)
+	}
+	return nil
+}
+
+// printSchemeMetadata prints the tags and usage of a scheme
+func printSchemeMetadata(name string, meta scheme.Metadata) {
+	fmt.Printf("\033[35;1m%s\033[0m%s\n", name, metadataIndicator(meta))
+	if len(meta.Tags) == 0 {
+		fmt.Println("  Tags:      none")
+	} else {
+		fmt.Printf("  Tags:      %s\n", strings.Join(meta.Tags, ", "))
+	}
+	fmt.Printf("  Favourite: %v\n", meta.Favourite)
+	fmt.Printf("  Used:      %d times\n", meta.UsageCount)
+	if !meta.LastUsed.IsZero() {
+		fmt.Printf("  Last used: %s\n", meta.LastUsed.Format("2006-01-02 15:04"))
+	}
+}
@@ -29,6 +29,8 @@ func setCommand() *cobra.Command {
 		enableNotify bool
 		apps         string
 		dryRun       bool
+		randomTags   []string
+		favourites   bool
 	)
 
 	cmd := &cobra.Command{
@@ -47,6 +49,8 @@ Examples:
   heimdall scheme set rosepine main dark  # Use rosepine/main/dark
   heimdall scheme set -n catppuccin -f mocha -m dark -v blue
   heimdall scheme set -r                  # Random scheme selection
+  heimdall scheme set -r --tag warm -m dark # Random warm scheme in dark mode
+  heimdall scheme set -r --favourites     # Random favourite scheme
   heimdall scheme set --notify rosepine   # With desktop notifications`,
 		Args: cobra.RangeArgs(0, 3),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -65,7 +69,8 @@ Examples:
 
 			// Handle random scheme selection
 			if randomScheme {
-				return setRandomScheme(manager, !noApply, enableNotify, selectedApps, dryRun)
+				filter := scheme.Filter{Tags: randomTags, Mode: setMode, Favourites: favourites}
+				return setRandomScheme(manager, filter, !noApply, enableNotify, selectedApps, dryRun)
 			}
 
 			// Handle compatibility flags
@@ -132,6 +137,7 @@ Examples:
 				Variant: setVariant,
 				Source:  newScheme.Source,
 			})
+			stateManager.RecordUsage(schemeName)
 
 			logger.Info("Scheme set",
 				"scheme", schemeName,
@@ -187,6 +193,8 @@ Examples:
 	cmd.Flags().StringVarP(&setMode, "mode", "m", "", "Set mode")
 	cmd.Flags().StringVarP(&setVariant, "variant", "v", "", "Set variant")
 	cmd.Flags().BoolVarP(&randomScheme, "random", "r", false, "Random scheme selection")
+	cmd.Flags().StringSliceVar(&randomTags, "tag", nil, "Only pick random schemes with this tag (repeatable)")
+	cmd.Flags().BoolVar(&favourites, "favourites", false, "Only pick random schemes from favourites")
 	cmd.Flags().BoolVar(&enableNotify, "notify", false, "Enable desktop notifications")
 	cmd.Flags().StringVar(&apps, "apps", "", "Comma-separated list of apps to theme (e.g., 'gtk,qt,discord')")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview changes without applying them")
@@ -231,11 +239,12 @@ func applyThemeWithOptions(s *scheme.Scheme, selectedApps []string) error {
 			"alacritty": true,
 			"wezterm":   true,
 			"nvim":      true,
+			"hyprlock":  true,
 		}
 
 		for _, app := range selectedApps {
 			if !validApps[app] {
-				return fmt.Errorf("invalid app: %s (valid apps: btop, discord, fuzzel, gtk, qt, spicetify, terminal, kitty, alacritty, wezterm)", app)
+				return fmt.Errorf("invalid app: %s (valid apps: btop, discord, fuzzel, gtk, qt, spicetify, terminal, kitty, alacritty, wezterm, nvim, hyprlock)", app)
 			}
 			apps = append(apps, app)
 		}
@@ -271,6 +280,9 @@ func applyThemeWithOptions(s *scheme.Scheme, selectedApps []string) error {
 		if cfg.Theme.EnableNvim {
 			apps = append(apps, "nvim")
 		}
+		if cfg.Theme.EnableHyprlock {
+			apps = append(apps, "hyprlock")
+		}
 		// Terminal sequences are always applied unless explicitly disabled
 		apps = append(apps, "terminal")
 	}
@@ -347,6 +359,7 @@ func performDryRun(s *scheme.Scheme, selectedApps []string) error {
 			"alacritty": true,
 			"wezterm":   true,
 			"nvim":      true,
+			"hyprlock":  true,
 		}
 
 		for _, app := range selectedApps {
@@ -387,6 +400,9 @@ func performDryRun(s *scheme.Scheme, selectedApps []string) error {
 		if cfg.Theme.EnableNvim {
 			apps = append(apps, "nvim")
 		}
+		if cfg.Theme.EnableHyprlock {
+			apps = append(apps, "hyprlock")
+		}
 		apps = append(apps, "terminal")
 	}
 
@@ -439,29 +455,46 @@ func performDryRun(s *scheme.Scheme, selectedApps []string) error {
 	return nil
 }
 
-// setRandomScheme selects and applies a random scheme
-func setRandomScheme(manager *scheme.Manager, shouldApplyTheme, shouldNotify bool, selectedApps []string, dryRun bool) error {
+// setRandomScheme selects and applies a random scheme matching the filter
+func setRandomScheme(manager *scheme.Manager, filter scheme.Filter, shouldApplyTheme, shouldNotify bool, selectedApps []string, dryRun bool) error {
 	// Get all available schemes
 	schemes, err := manager.ListSchemes()
 	if err != nil {
 		return fmt.Errorf("failed to list schemes: %w", err)
 	}
 
+	if filter.Mode != "" && filter.Mode != "dark" && filter.Mode != "light" {
+		return fmt.Errorf("invalid mode: %s (must be 'dark' or 'light')", filter.Mode)
+	}
+
+	stateManager := theme.NewStateManager()
+	schemes = manager.FilterSchemes(schemes, stateManager.GetAllSchemeMetadata(), filter)
+
 	if len(schemes) == 0 {
+		if !filter.IsEmpty() {
+			return fmt.Errorf("no schemes match the given filters")
+		}
 		return fmt.Errorf("no schemes available")
 	}
 
 	// Seed random number generator
 	rand.Seed(time.Now().UnixNano())
 
+	// Prefer schemes not used recently, falling back to all when every
+	// scheme has been used
+	candidates := excludeRecentSchemes(schemes, stateManager)
+
 	// Pick random scheme
-	randomScheme := schemes[rand.Intn(len(schemes))]
+	randomScheme := candidates[rand.Intn(len(candidates))]
 
-	// Get flavours for the random scheme
+	// Get flavours for the random scheme, limited to those with the mode
 	flavours, err := manager.ListFlavours(randomScheme)
 	if err != nil {
 		return fmt.Errorf("failed to list flavours for %s: %w", randomScheme, err)
 	}
+	if filter.Mode != "" {
+		flavours = manager.FlavoursWithMode(randomScheme, filter.Mode)
+	}
 
 	if len(flavours) == 0 {
 		return fmt.Errorf("no flavours available for scheme %s", randomScheme)
@@ -482,6 +515,9 @@ func setRandomScheme(manager *scheme.Manager, shouldApplyTheme, shouldNotify boo
 
 	// Pick random mode
 	randomMode := modes[rand.Intn(len(modes))]
+	if filter.Mode != "" {
+		randomMode = filter.Mode
+	}
 
 	// Load and apply the random scheme
 	newScheme, err := manager.LoadSchemeWithFallback(randomScheme, randomFlavour, randomMode)
@@ -494,6 +530,15 @@ func setRandomScheme(manager *scheme.Manager, shouldApplyTheme, shouldNotify boo
 		return fmt.Errorf("failed to set random scheme: %w", err)
 	}
 
+	// Update theme state
+	stateManager.SetCurrent(theme.CurrentTheme{
+		Name:    randomScheme,
+		Flavour: randomFlavour,
+		Mode:    randomMode,
+		Source:  newScheme.Source,
+	})
+	stateManager.RecordUsage(randomScheme)
+
 	logger.Info("Random scheme selected",
 		"scheme", randomScheme,
 		"flavour", randomFlavour,
@@ -526,6 +571,35 @@ func setRandomScheme(manager *scheme.Manager, shouldApplyTheme, shouldNotify boo
 	return nil
 }
 
+// recentSchemeCount is how many history entries random selection avoids
+const recentSchemeCount = 2
+
+// excludeRecentSchemes removes the current scheme and the last
+// recentSchemeCount schemes in the theme history, returning all schemes when
+// nothing would be left
+func excludeRecentSchemes(schemes []string, stateManager *theme.StateManager) []string {
+	recent := map[string]bool{stateManager.GetCurrent().Name: true}
+	history := stateManager.GetHistory()
+	if len(history) > recentSchemeCount {
+		history = history[:recentSchemeCount]
+	}
+	for _, entry := range history {
+		recent[entry.Name] = true
+	}
+
+	var candidates []string
+	for _, name := range schemes {
+		if !recent[name] {
+			candidates = append(candidates, name)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return schemes
+	}
+	return candidates
+}
+
 // setSchemeByFlags sets scheme using individual flags
 func setSchemeByFlags(manager *scheme.Manager, name, flavour, mode, variant string, shouldApplyTheme, shouldNotify bool, selectedApps []string, dryRun bool) error {
 	// Get current scheme to fill in missing values
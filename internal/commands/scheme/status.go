@@ -250,6 +250,8 @@ func getSourceColor(source scheme.SchemeSource) string {
 		return "\033[32m" // Green
 	case scheme.SourceGenerated:
 		return "\033[33m" // Yellow
+	case scheme.SourceSynced:
+		return "\033[35m" // Magenta
 	case scheme.SourceBundled:
 		return "\033[36m" // Cyan
 	default:
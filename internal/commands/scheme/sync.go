@@ -0,0 +1,145 @@
+package scheme
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
+	"github.com/spf13/cobra"
+)
+
+// syncCommand creates the scheme sync subcommand
+func syncCommand() *cobra.Command {
+	var (
+		prune      bool
+		list       bool
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Sync scheme repositories from git",
+		Long: `Clone or update the scheme repositories listed under scheme.repos.
+
+Each repository is checked out at its pinned ref (branch, tag or commit) into
+the heimdall data directory. Its schemes become available with the 'synced'
+source, taking priority over generated and bundled schemes but not over user
+schemes. If a repository cannot be fetched, the existing checkout is kept.
+
+Repositories can be remote URLs or local file:// paths:
+  {"scheme": {"repos": [
+    {"url": "https://github.com/team/schemes.git", "ref": "v1.2.0", "path": "schemes"},
+    {"name": "local", "url": "file:///srv/git/schemes.git"}
+  ]}}
+
+Examples:
+  heimdall scheme sync            # Sync all configured repositories
+  heimdall scheme sync --prune    # Also remove repositories no longer configured
+  heimdall scheme sync --list     # Show synced repositories without syncing`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			syncer := scheme.NewRepoSyncer("")
+
+			if list {
+				manifest, err := scheme.ReadSyncManifest("")
+				if err != nil {
+					return err
+				}
+				return printSyncedRepos(manifest.Repos, jsonOutput)
+			}
+
+			repos := config.Get().Scheme.Repos
+			if len(repos) == 0 && !prune {
+				return fmt.Errorf("no scheme repositories configured (add them under scheme.repos)")
+			}
+
+			results, errs := syncer.SyncAll(repos, prune)
+			for _, err := range errs {
+				logger.Error("Scheme sync failed", "error", err)
+			}
+
+			if jsonOutput {
+				output := map[string]interface{}{"results": results}
+				if len(errs) > 0 {
+					messages := make([]string, len(errs))
+					for i, err := range errs {
+						messages[i] = err.Error()
+					}
+					output["errors"] = messages
+				}
+				data, err := json.MarshalIndent(output, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal results: %w", err)
+				}
+				fmt.Println(string(data))
+			} else {
+				for _, result := range results {
+					status := "up to date"
+					switch {
+					case result.Stale:
+						status = "\033[33moffline, kept existing checkout\033[0m"
+					case result.Updated:
+						status = "\033[32mupdated\033[0m"
+					}
+					fmt.Printf("%s @ %s: %s (%d schemes)\n", result.Repo.Name, shortCommit(result.Repo.Commit), status, len(result.Repo.Schemes))
+				}
+				for _, err := range errs {
+					fmt.Printf("\033[31m✗\033[0m %v\n", err)
+				}
+			}
+
+			if len(errs) > 0 {
+				return fmt.Errorf("%d of %d repositories failed to sync", len(errs), len(repos))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&prune, "prune", false, "Remove checkouts of repositories no longer configured")
+	cmd.Flags().BoolVar(&list, "list", false, "Show synced repositories without syncing")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+// printSyncedRepos prints the repositories recorded in the sync manifest
+func printSyncedRepos(repos []scheme.SyncedRepo, jsonOutput bool) error {
+	if jsonOutput {
+		data, err := json.MarshalIndent(repos, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal repositories: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(repos) == 0 {
+		fmt.Println("No scheme repositories synced yet (run 'heimdall scheme sync')")
+		return nil
+	}
+
+	fmt.Printf("\033[36;1mSynced Scheme Repositories\033[0m\n")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	for _, repo := range repos {
+		ref := repo.Ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		fmt.Printf("\033[35;1m%s\033[0m  %s (%s @ %s)\n", repo.Name, repo.URL, ref, shortCommit(repo.Commit))
+		fmt.Printf("  Synced:  %s\n", repo.SyncedAt.Format("2006-01-02 15:04"))
+		for _, name := range repo.Schemes {
+			fmt.Printf("  • %s\n", name)
+		}
+	}
+	return nil
+}
+
+// shortCommit abbreviates a commit hash for display
+func shortCommit(commit string) string {
+	if len(commit) > 8 {
+		return commit[:8]
+	}
+	return commit
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/arthur404dev/heimdall-cli/internal/config"
 	"github.com/arthur404dev/heimdall-cli/internal/config/manager"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/daemon"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/notify"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
@@ -117,7 +118,7 @@ Use -d flag to run in detached/daemon mode.`,
 			}
 
 			// Check if daemon is already running
-			if isDaemonRunning(pidFile) {
+			if shellDaemon().Running() {
 				return fmt.Errorf("shell daemon is already running")
 			}
 
@@ -131,7 +132,7 @@ Use -d flag to run in detached/daemon mode.`,
 			// Start shell
 			if daemon {
 				// Start in daemon mode (detached)
-				return startDaemon(cfg, pidFile)
+				return startDaemon(cfg)
 			} else {
 				// Start in attached mode (default)
 				return startAttached(cfg, pidFile)
@@ -150,27 +151,13 @@ Use -d flag to run in detached/daemon mode.`,
 	return cmd
 }
 
-// isDaemonRunning checks if the daemon is already running
-func isDaemonRunning(pidFile string) bool {
-	data, err := os.ReadFile(pidFile)
-	if err != nil {
-		return false
-	}
-
-	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
-	if err != nil {
-		return false
+// shellDaemon returns the shell process tracked by the PID file
+func shellDaemon() daemon.Process {
+	return daemon.Process{
+		Name:    "shell daemon",
+		PIDFile: filepath.Join(paths.StateDir, "shell.pid"),
+		LogFile: filepath.Join(paths.StateDir, "shell.log"),
 	}
-
-	// Check if process exists
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-
-	// Send signal 0 to check if process is alive
-	err = process.Signal(syscall.Signal(0))
-	return err == nil
 }
 
 // startAttached starts the shell in attached mode (default)
@@ -280,7 +267,7 @@ func shouldLogLine(_ string) bool {
 }
 
 // startDaemon starts the shell daemon in detached mode
-func startDaemon(cfg *config.Config, pidFile string) error {
+func startDaemon(cfg *config.Config) error {
 	logger.Info("Starting shell daemon", "command", cfg.Shell.Command)
 
 	// Build command with args
@@ -294,34 +281,12 @@ func startDaemon(cfg *config.Config, pidFile string) error {
 		}
 	}
 
-	// Create command
-	cmd := exec.Command(cfg.Shell.Command, args...)
-
-	// Set up environment
-	cmd.Env = os.Environ()
-
-	// Set up logging
-	logFile := filepath.Join(paths.StateDir, "shell.log")
-	logOut, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	// Start the process in the background
+	process := shellDaemon()
+	process.Command, process.Args = cfg.Shell.Command, args
+	pid, err := process.Start()
 	if err != nil {
-		return fmt.Errorf("failed to open log file: %w", err)
-	}
-	defer logOut.Close()
-
-	cmd.Stdout = logOut
-	cmd.Stderr = logOut
-
-	// Start the process
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start shell daemon: %w", err)
-	}
-
-	// Write PID file
-	pid := cmd.Process.Pid
-	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(pid)), 0644); err != nil {
-		// Try to kill the process if we can't write the PID file
-		cmd.Process.Kill()
-		return fmt.Errorf("failed to write PID file: %w", err)
+		return err
 	}
 
 	// Send notification
@@ -332,10 +297,7 @@ func startDaemon(cfg *config.Config, pidFile string) error {
 		Urgency: notify.UrgencyNormal,
 	})
 
-	logger.Info("Shell daemon started", "pid", pid, "log", logFile)
-
-	// Detach from the process
-	cmd.Process.Release()
+	logger.Info("Shell daemon started", "pid", pid, "log", process.LogFile)
 
 	return nil
 }
@@ -363,47 +325,10 @@ func sendMessage(cfg *config.Config, message string) error {
 
 // StopDaemon stops the running shell daemon gracefully
 func StopDaemon() error {
-	pidFile := filepath.Join(paths.StateDir, "shell.pid")
-
-	// Read PID
-	data, err := os.ReadFile(pidFile)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("shell daemon is not running")
-		}
-		return fmt.Errorf("failed to read PID file: %w", err)
-	}
-
-	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
-	if err != nil {
-		return fmt.Errorf("invalid PID in file: %w", err)
-	}
-
-	// Find process
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return fmt.Errorf("failed to find process: %w", err)
-	}
-
-	// Send SIGTERM
-	if err := process.Signal(syscall.SIGTERM); err != nil {
-		return fmt.Errorf("failed to stop daemon: %w", err)
-	}
-
-	// Wait a bit for graceful shutdown
-	time.Sleep(100 * time.Millisecond)
-
-	// Check if still running and force kill if necessary
-	if err := process.Signal(syscall.Signal(0)); err == nil {
-		// Still running, force kill
-		if err := process.Kill(); err != nil {
-			return fmt.Errorf("failed to kill daemon: %w", err)
-		}
+	if _, err := shellDaemon().Stop(); err != nil {
+		return err
 	}
 
-	// Remove PID file
-	os.Remove(pidFile)
-
 	// Send notification
 	notifier := notify.NewNotifier()
 	notifier.Send(&notify.Notification{
@@ -419,36 +344,10 @@ func StopDaemon() error {
 
 // KillDaemon force kills the running shell daemon
 func KillDaemon() error {
-	pidFile := filepath.Join(paths.StateDir, "shell.pid")
-
-	// Read PID
-	data, err := os.ReadFile(pidFile)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("shell daemon is not running")
-		}
-		return fmt.Errorf("failed to read PID file: %w", err)
-	}
-
-	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
-	if err != nil {
-		return fmt.Errorf("invalid PID in file: %w", err)
+	if _, err := shellDaemon().Kill(); err != nil {
+		return err
 	}
 
-	// Find process
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return fmt.Errorf("failed to find process: %w", err)
-	}
-
-	// Force kill
-	if err := process.Kill(); err != nil {
-		return fmt.Errorf("failed to kill daemon: %w", err)
-	}
-
-	// Remove PID file
-	os.Remove(pidFile)
-
 	logger.Info("Shell daemon killed")
 
 	return nil
@@ -456,28 +355,20 @@ func KillDaemon() error {
 
 // ListDaemon lists the status of the shell daemon
 func ListDaemon() error {
-	pidFile := filepath.Join(paths.StateDir, "shell.pid")
-
-	if !isDaemonRunning(pidFile) {
+	process := shellDaemon()
+	pid := process.PID()
+	if pid == 0 {
 		fmt.Println("Shell daemon is not running")
 		return nil
 	}
 
-	// Read PID
-	data, err := os.ReadFile(pidFile)
-	if err != nil {
-		return fmt.Errorf("failed to read PID file: %w", err)
-	}
-
-	pid := strings.TrimSpace(string(data))
-	fmt.Printf("Shell daemon is running (PID: %s)\n", pid)
+	fmt.Printf("Shell daemon is running (PID: %d)\n", pid)
 
 	// Show log file location
-	logFile := filepath.Join(paths.StateDir, "shell.log")
-	if paths.Exists(logFile) {
-		info, err := os.Stat(logFile)
+	if paths.Exists(process.LogFile) {
+		info, err := os.Stat(process.LogFile)
 		if err == nil {
-			fmt.Printf("Log file: %s (size: %d bytes)\n", logFile, info.Size())
+			fmt.Printf("Log file: %s (size: %d bytes)\n", process.LogFile, info.Size())
 		}
 	}
 
@@ -77,9 +77,13 @@ func TestCommand(t *testing.T) {
 	}
 }
 
-func TestIsDaemonRunning(t *testing.T) {
+func TestDaemonRunning(t *testing.T) {
 	tempDir := t.TempDir()
-	pidFile := filepath.Join(tempDir, "test.pid")
+	originalStateDir := paths.StateDir
+	paths.StateDir = tempDir
+	defer func() { paths.StateDir = originalStateDir }()
+
+	pidFile := filepath.Join(tempDir, "shell.pid")
 
 	tests := []struct {
 		name     string
@@ -128,9 +132,9 @@ func TestIsDaemonRunning(t *testing.T) {
 				t.Fatalf("Setup failed: %v", err)
 			}
 
-			result := isDaemonRunning(pidFile)
+			result := shellDaemon().Running()
 			if result != tt.expected {
-				t.Errorf("isDaemonRunning() = %v, expected %v", result, tt.expected)
+				t.Errorf("Running() = %v, expected %v", result, tt.expected)
 			}
 		})
 	}
@@ -164,7 +168,7 @@ func TestStopDaemon(t *testing.T) {
 				return os.WriteFile(pidFile, []byte("invalid"), 0644)
 			},
 			expectError: true,
-			errorMsg:    "invalid PID",
+			errorMsg:    "not running",
 		},
 		{
 			name: "handles non-existent process gracefully",
@@ -172,7 +176,7 @@ func TestStopDaemon(t *testing.T) {
 				return os.WriteFile(pidFile, []byte("99999"), 0644)
 			},
 			expectError: true,
-			errorMsg:    "failed to stop daemon",
+			errorMsg:    "not running",
 		},
 	}
 
@@ -231,7 +235,7 @@ func TestKillDaemon(t *testing.T) {
 				return os.WriteFile(pidFile, []byte("invalid"), 0644)
 			},
 			expectError: true,
-			errorMsg:    "invalid PID",
+			errorMsg:    "not running",
 		},
 		{
 			name: "handles non-existent process gracefully",
@@ -239,7 +243,7 @@ func TestKillDaemon(t *testing.T) {
 				return os.WriteFile(pidFile, []byte("99999"), 0644)
 			},
 			expectError: true,
-			errorMsg:    "failed to kill daemon",
+			errorMsg:    "not running",
 		},
 	}
 
@@ -677,9 +681,13 @@ func TestStartAttachedConfiguration(t *testing.T) {
 }
 
 // Benchmark tests
-func BenchmarkIsDaemonRunning(b *testing.B) {
+func BenchmarkDaemonRunning(b *testing.B) {
 	tempDir := b.TempDir()
-	pidFile := filepath.Join(tempDir, "test.pid")
+	originalStateDir := paths.StateDir
+	paths.StateDir = tempDir
+	defer func() { paths.StateDir = originalStateDir }()
+
+	pidFile := filepath.Join(tempDir, "shell.pid")
 
 	// Create a valid pid file
 	pid := os.Getpid()
@@ -687,7 +695,7 @@ func BenchmarkIsDaemonRunning(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		isDaemonRunning(pidFile)
+		shellDaemon().Running()
 	}
 }
 
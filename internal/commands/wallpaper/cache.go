@@ -0,0 +1,255 @@
+package wallpaper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/material"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+	"github.com/spf13/cobra"
+)
+
+// cachedPalette is the palette of a set of wallpapers and the cache it is
+// stored in, nil when the cache is disabled
+type cachedPalette struct {
+	cache *wallpaper.PaletteCache
+	entry *wallpaper.PaletteEntry
+}
+
+// paletteCacheEnabled reports whether wallpaper.palette_cache is on
+func paletteCacheEnabled() bool {
+	cfg := config.Get()
+	return cfg == nil || cfg.Wallpaper.PaletteCache
+}
+
+// loadPalette returns the extracted colors of the sources, from the
+// palette cache when they were extracted before unless noCache is set
+func loadPalette(sources []wallpaper.MonitorWallpaper, noCache bool) (*cachedPalette, error) {
+	palette := &cachedPalette{entry: &wallpaper.PaletteEntry{Sources: sourcePaths(sources)}}
+
+	if paletteCacheEnabled() {
+		key, err := wallpaper.PaletteKey(sources)
+		if err != nil {
+			return nil, err
+		}
+		palette.cache = wallpaper.NewPaletteCache(wallpaper.DefaultPaletteCacheDir())
+
+		if entry, ok := palette.cache.Get(key); ok && !noCache {
+			logger.Info("Using cached palette", "wallpaper", entry.Sources)
+			palette.entry = entry
+			return palette, nil
+		}
+		palette.entry.Key = key
+	}
+
+	images, err := loadWeightedImages(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	extracted, err := material.NewEnhancedExtractor().ExtractColorsWeighted(images)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract colors: %w", err)
+	}
+	palette.entry.Extracted = extracted
+	palette.save()
+
+	return palette, nil
+}
+
+// schemes returns the cached variants when they were generated with the
+// same seed choice and generator settings
+func (p *cachedPalette) schemes(key string) map[string]*scheme.Scheme {
+	if p.entry.SchemesKey != key || len(p.entry.Schemes) == 0 {
+		return nil
+	}
+	return p.entry.Schemes
+}
+
+// setSchemes records generated variants under their settings key
+func (p *cachedPalette) setSchemes(key string, variants map[string]*scheme.Scheme) {
+	p.entry.SchemesKey = key
+	p.entry.Schemes = variants
+}
+
+// save writes the entry to the cache when it is enabled
+func (p *cachedPalette) save() {
+	if p.cache == nil {
+		return
+	}
+	if err := p.cache.Put(p.entry); err != nil {
+		logger.Warn("Failed to cache palette", "error", err)
+	}
+}
+
+// schemesKey identifies what generated variants depend on besides the
+// wallpaper: the chosen seed and the configured custom colors
+func schemesKey(seed uint32, chosen bool) string {
+	key := "auto"
+	if chosen {
+		key = argbToHex(seed)
+	}
+	if cfg := config.Get(); cfg != nil {
+		data, _ := json.Marshal(cfg.Scheme.CustomColors)
+		key += string(data)
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// detectMode returns the light/dark mode of a wallpaper, from the palette
+// cache when it was generated before
+func detectMode(wallpaperPath string) (string, error) {
+	if paletteCacheEnabled() {
+		key, err := wallpaper.PaletteKey([]wallpaper.MonitorWallpaper{{Path: wallpaperPath, Weight: 1}})
+		if err == nil {
+			cache := wallpaper.NewPaletteCache(wallpaper.DefaultPaletteCacheDir())
+			if entry, ok := cache.Get(key); ok && entry.Mode != "" {
+				return entry.Mode, nil
+			}
+		}
+	}
+	return wallpaper.NewAnalyzer().DetermineMode(wallpaperPath)
+}
+
+// cacheCommand creates the wallpaper cache subcommand
+func cacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the wallpaper palette cache",
+		Long: `Manage the palette cache.
+
+Extracted colors, seed candidates and generated schemes are cached by
+wallpaper content, so switching back to a known wallpaper skips color
+extraction. Use --no-cache on the wallpaper command to extract again.
+
+Subcommands:
+  stats   Show the number and size of cached palettes
+  prune   Remove unused and outdated palettes`,
+	}
+
+	cmd.AddCommand(cacheStatsCommand())
+	cmd.AddCommand(cachePruneCommand())
+
+	return cmd
+}
+
+// cacheStatsCommand creates the wallpaper cache stats subcommand
+func cacheStatsCommand() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show palette cache statistics",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stats, err := wallpaper.NewPaletteCache(wallpaper.DefaultPaletteCacheDir()).Stats()
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(stats, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal stats: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			fmt.Printf("\033[36;1mPalette Cache\033[0m\n")
+			fmt.Printf("━━━━━━━━━━━━━\n")
+			fmt.Printf("Directory: %s\n", stats.Dir)
+			fmt.Printf("Entries:   %d", stats.Entries)
+			if stats.Stale > 0 {
+				fmt.Printf(" (%d outdated)", stats.Stale)
+			}
+			fmt.Printf("\nSize:      %s\n", formatBytes(stats.Size))
+			if stats.Entries > 0 {
+				fmt.Printf("Oldest:    %s\n", stats.Oldest.Format(time.RFC3339))
+				fmt.Printf("Newest:    %s\n", stats.Newest.Format(time.RFC3339))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// cachePruneCommand creates the wallpaper cache prune subcommand
+func cachePruneCommand() *cobra.Command {
+	var (
+		all       bool
+		olderThan int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove unused and outdated palettes",
+		Long: `Remove palettes unused for longer than wallpaper.cache_max_age days,
+and palettes written by an older version of the color extraction.
+
+Derived images unused for as long are removed too, except those of the
+current wallpaper.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			maxAge := 90
+			if cfg := config.Get(); cfg != nil && cfg.Wallpaper.CacheMaxAge > 0 {
+				maxAge = cfg.Wallpaper.CacheMaxAge
+			}
+			if cmd.Flags().Changed("older-than") {
+				if olderThan < 0 {
+					return fmt.Errorf("--older-than must be non-negative")
+				}
+				maxAge = olderThan
+			}
+
+			age := time.Duration(maxAge) * 24 * time.Hour
+			if all {
+				age = 0
+			}
+
+			removed, freed, err := wallpaper.NewPaletteCache(wallpaper.DefaultPaletteCacheDir()).Prune(age)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Removed %d cached palettes (%s)\n", removed, formatBytes(freed))
+
+			removed, freed, err = wallpaper.PruneDerived(wallpaper.DefaultDerivedCacheDir(), age)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Removed %d derived images (%s)\n", removed, formatBytes(freed))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Remove every cached palette")
+	cmd.Flags().IntVar(&olderThan, "older-than", 0, "Remove palettes unused for this many days (default wallpaper.cache_max_age)")
+
+	return cmd
+}
+
+// formatBytes formats bytes into human-readable format
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,134 @@
+package wallpaper
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+)
+
+// controlSocket is the control socket of a wallpaper daemon. Clients send
+// one command per connection and get the daemon's status back as JSON.
+type controlSocket struct {
+	name string        // Daemon name used in messages, e.g. "slideshow"
+	use  string        // Subcommand that runs the daemon
+	path func() string // Socket location
+}
+
+var (
+	slideshowSocket = controlSocket{name: "slideshow", use: "slideshow", path: wallpaper.SlideshowSocketPath}
+	dynamicSocket   = controlSocket{name: "dynamic wallpaper", use: "dynamic", path: wallpaper.DynamicSocketPath}
+)
+
+// controlRequest is a control command waiting for the daemon's reply
+type controlRequest struct {
+	command string
+	reply   chan any
+	sent    chan struct{} // Closed once the reply was written
+}
+
+// listen opens the socket for a new daemon. A socket nobody answers on was
+// left behind by a daemon that did not exit cleanly; the socket of a
+// running one is never removed.
+func (c controlSocket) listen() (net.Listener, error) {
+	if err := c.checkStopped(); err != nil {
+		return nil, err
+	}
+
+	socketPath := c.path()
+	os.Remove(socketPath)
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s socket: %w", c.name, err)
+	}
+	return listener, nil
+}
+
+// serve reads one control command per connection and passes it to the
+// daemon loop, which answers on the request's reply channel
+func (c controlSocket) serve(listener net.Listener, requests chan<- controlRequest) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return // Listener closed
+		}
+
+		go func(conn net.Conn) {
+			defer conn.Close()
+
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			req := controlRequest{
+				command: strings.TrimSpace(line),
+				reply:   make(chan any, 1),
+				sent:    make(chan struct{}),
+			}
+			requests <- req
+			data, _ := json.Marshal(<-req.reply)
+			fmt.Fprintf(conn, "%s\n", data)
+			close(req.sent)
+		}(conn)
+	}
+}
+
+// send sends a control command to the running daemon and decodes its
+// status into reply. A status with an error is returned as the error.
+func (c controlSocket) send(command string, reply any) error {
+	conn, err := net.DialTimeout("unix", c.path(), 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("%s is not running", c.name)
+	}
+	defer conn.Close()
+
+	// Changing the wallpaper may generate a scheme first
+	conn.SetDeadline(time.Now().Add(2 * time.Minute))
+
+	if _, err := fmt.Fprintf(conn, "%s\n", command); err != nil {
+		return fmt.Errorf("failed to send %s command: %w", c.name, err)
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read %s reply: %w", c.name, err)
+	}
+
+	var status struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(line, &status); err != nil {
+		return fmt.Errorf("failed to parse %s reply: %w", c.name, err)
+	}
+	if status.Error != "" {
+		return fmt.Errorf("%s", status.Error)
+	}
+	if err := json.Unmarshal(line, reply); err != nil {
+		return fmt.Errorf("failed to parse %s reply: %w", c.name, err)
+	}
+	return nil
+}
+
+// checkStopped returns an error when the daemon is already running
+func (c controlSocket) checkStopped() error {
+	if c.running() {
+		return fmt.Errorf("%s already running; stop it with 'heimdall wallpaper %s --stop'", c.name, c.use)
+	}
+	return nil
+}
+
+// running reports whether a daemon answers on the socket
+func (c controlSocket) running() bool {
+	conn, err := net.DialTimeout("unix", c.path(), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
@@ -0,0 +1,200 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+	"github.com/spf13/cobra"
+)
+
+// dedupeCommand creates the wallpaper dedupe subcommand
+func dedupeCommand() *cobra.Command {
+	var (
+		distance   int
+		hash       string
+		move       bool
+		quarantine string
+		exclude    bool
+		reset      bool
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dedupe [DIR]",
+		Short: "Find near-duplicate wallpapers",
+		Long: `Find resized and re-encoded copies of the same wallpaper.
+
+Every indexed wallpaper gets a perceptual hash; wallpapers whose hashes
+differ in at most --distance bits (of 64) are grouped, and the highest
+resolution copy of each group is kept. pHash tolerates re-encoding and
+color shifts best, dHash is stricter about gradients.
+
+By default the groups are only reported. --exclude keeps the duplicates
+out of random selection and the slideshow, --move moves them to a
+quarantine directory, and --reset includes excluded wallpapers again.
+
+Examples:
+  heimdall wallpaper dedupe
+  heimdall wallpaper dedupe ~/Pictures/Wallpapers --distance 4
+  heimdall wallpaper dedupe --exclude
+  heimdall wallpaper dedupe --move --quarantine ~/Pictures/Duplicates`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if move && exclude {
+				return fmt.Errorf("--move and --exclude cannot be combined")
+			}
+			if distance < 0 || distance > 64 {
+				return fmt.Errorf("--distance must be between 0 and 64")
+			}
+			if !slices.Contains(wallpaper.HashNames, hash) {
+				return fmt.Errorf("invalid hash %q (must be one of: %s)", hash, strings.Join(wallpaper.HashNames, ", "))
+			}
+
+			cfg := config.Get()
+			dir := ""
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			dir, err := libraryDir(cfg, dir)
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stat(dir); err != nil {
+				return fmt.Errorf("wallpaper directory not found: %w", err)
+			}
+
+			index, err := wallpaper.LoadIndex(wallpaper.DefaultIndexPath())
+			if err != nil {
+				return err
+			}
+
+			if reset {
+				cleared := index.ClearDuplicates(dir)
+				if err := index.Save(); err != nil {
+					return err
+				}
+				fmt.Printf("Included %d excluded duplicates again\n", cleared)
+				return nil
+			}
+
+			if !jsonOutput {
+				fmt.Printf("Indexing %s...\n", dir)
+			}
+			if _, err := updateIndex(cfg, index, []string{dir}); err != nil {
+				return err
+			}
+
+			entries := wallpaper.ExistingEntries(index.Query(wallpaper.IndexQuery{Dir: dir, Duplicates: true}))
+			groups, err := wallpaper.FindDuplicates(entries, hash, distance)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				if groups == nil {
+					groups = []wallpaper.DuplicateGroup{}
+				}
+				data, err := json.MarshalIndent(groups, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal duplicates: %w", err)
+				}
+				fmt.Println(string(data))
+			} else {
+				printDuplicates(groups, hash)
+			}
+
+			switch {
+			case len(groups) == 0:
+				return nil
+			case exclude:
+				index.MarkDuplicates(groups)
+				if err := index.Save(); err != nil {
+					return err
+				}
+				if !jsonOutput {
+					fmt.Printf("Excluded %d duplicates from random selection\n", countDuplicates(groups))
+				}
+			case move:
+				if quarantine == "" {
+					quarantine = filepath.Join(paths.HeimdallDataDir, "wallpaper", "duplicates")
+				} else if strings.HasPrefix(quarantine, "~/") {
+					home, _ := os.UserHomeDir()
+					quarantine = filepath.Join(home, quarantine[2:])
+				}
+
+				moved := 0
+				for _, group := range groups {
+					for _, duplicate := range group.Duplicates {
+						if _, err := wallpaper.Quarantine(duplicate.Path, dir, quarantine); err != nil {
+							fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+							continue
+						}
+						delete(index.Entries, duplicate.Path)
+						moved++
+					}
+				}
+				if err := index.Save(); err != nil {
+					return err
+				}
+				if !jsonOutput {
+					fmt.Printf("Moved %d duplicates to %s\n", moved, quarantine)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&distance, "distance", wallpaper.DefaultDuplicateDistance, "Maximum number of differing hash bits between duplicates")
+	cmd.Flags().StringVar(&hash, "hash", wallpaper.HashPHash, "Perceptual hash: phash or dhash")
+	cmd.Flags().BoolVar(&exclude, "exclude", false, "Exclude duplicates from random selection")
+	cmd.Flags().BoolVar(&move, "move", false, "Move duplicates to the quarantine directory")
+	cmd.Flags().StringVar(&quarantine, "quarantine", "", "Directory duplicates are moved to (default: ~/.local/share/heimdall/wallpaper/duplicates)")
+	cmd.Flags().BoolVar(&reset, "reset", false, "Include wallpapers excluded as duplicates again")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output duplicate groups in JSON format")
+
+	return cmd
+}
+
+// printDuplicates prints each group with the kept copy first
+func printDuplicates(groups []wallpaper.DuplicateGroup, hash string) {
+	if len(groups) == 0 {
+		fmt.Println("No duplicates found")
+		return
+	}
+
+	fmt.Printf("\033[36;1mDuplicate Wallpapers\033[0m\n")
+	fmt.Println(strings.Repeat("━", 50))
+	for _, group := range groups {
+		fmt.Printf("\033[32mkeep\033[0m  %5dx%-5d %s\n", group.Keep.Width, group.Keep.Height, group.Keep.Path)
+		for _, duplicate := range group.Duplicates {
+			bits := hashOf(group.Keep, hash).Distance(hashOf(duplicate, hash))
+			fmt.Printf("\033[33mdupe\033[0m  %5dx%-5d %s  (%d bits)\n", duplicate.Width, duplicate.Height, duplicate.Path, bits)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%d groups, %d duplicates\n", len(groups), countDuplicates(groups))
+}
+
+// hashOf returns the perceptual hash of entry computed with hash
+func hashOf(entry *wallpaper.IndexEntry, hash string) wallpaper.PerceptualHash {
+	if hash == wallpaper.HashDHash {
+		return entry.DHash
+	}
+	return entry.PHash
+}
+
+// countDuplicates returns the number of copies not kept
+func countDuplicates(groups []wallpaper.DuplicateGroup) int {
+	count := 0
+	for _, group := range groups {
+		count += len(group.Duplicates)
+	}
+	return count
+}
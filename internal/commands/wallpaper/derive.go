@@ -0,0 +1,167 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/hypr"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+	"github.com/spf13/cobra"
+)
+
+// deriveOptions returns the derive settings of the configuration, the
+// current scheme and the connected monitors, or only monitor when it is set
+func deriveOptions(cfg *config.Config, variants []string, monitor string) wallpaper.DeriveOptions {
+	opts := wallpaper.DeriveOptions{
+		Variants:   variants,
+		BlurRadius: 20,
+		DimAmount:  0.4,
+		TintAmount: 0.35,
+		Mode:       "dark",
+	}
+	if cfg != nil {
+		opts.BlurRadius = cfg.Wallpaper.BlurRadius
+		opts.DimAmount = cfg.Wallpaper.DimAmount
+		opts.TintAmount = cfg.Wallpaper.TintAmount
+	}
+
+	if current, err := currentScheme(); err != nil {
+		logger.Warn("Failed to get current scheme", "error", err)
+	} else {
+		if current.Mode != "" {
+			opts.Mode = current.Mode
+		}
+		opts.Surface = current.Colours["surface"]
+	}
+
+	if slices.Contains(variants, wallpaper.DeriveMonitors) {
+		opts.Monitors = monitorSizes(monitor)
+	}
+	return opts
+}
+
+// monitorSizes returns the resolution of the enabled Hyprland monitors, or
+// only of monitor when it is set. Rotated monitors swap width and height.
+func monitorSizes(monitor string) []wallpaper.MonitorSize {
+	if !hypr.IsRunning() {
+		return nil
+	}
+	client, err := hypr.NewClient()
+	if err != nil {
+		return nil
+	}
+	monitors, err := client.GetMonitors()
+	if err != nil {
+		logger.Warn("Failed to get monitors", "error", err)
+		return nil
+	}
+
+	var sizes []wallpaper.MonitorSize
+	for _, m := range monitors {
+		if m.Disabled || (monitor != "" && m.Name != monitor) {
+			continue
+		}
+		size := wallpaper.MonitorSize{Name: m.Name, Width: m.Width, Height: m.Height}
+		if m.Transform%2 == 1 {
+			size.Width, size.Height = size.Height, size.Width
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes
+}
+
+// deriveImages produces the configured derived images of a new wallpaper
+// set on monitor, or on every monitor
+func deriveImages(wallpaperPath, monitor string) {
+	cfg := config.Get()
+	if cfg == nil || len(cfg.Wallpaper.Derive) == 0 {
+		return
+	}
+
+	derived, err := wallpaper.Derive(wallpaperPath, wallpaper.DefaultDerivedCacheDir(), deriveOptions(cfg, cfg.Wallpaper.Derive, monitor))
+	if err != nil {
+		logger.Error("Failed to derive wallpaper images", "error", err)
+	}
+	for _, d := range derived {
+		logger.Info("Derived wallpaper image", "name", d.Name, "link", d.Link)
+	}
+}
+
+// deriveCommand creates the wallpaper derive subcommand
+func deriveCommand() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "derive [VARIANT...]",
+		Short: "Derive lock screen and panel images from the current wallpaper",
+		Long: `Derive images from the current wallpaper for lock screens and panels.
+
+Variants:
+  blur     - Gaussian blur (wallpaper.blur_radius)
+  dim      - Darkened by wallpaper.dim_amount, half as much in light mode
+  tint     - Overlaid with the scheme surface color (wallpaper.tint_amount)
+  monitors - Cropped to the resolution of each monitor
+
+Each image is linked next to the current wallpaper link as current-<name>
+(current-blur, current-DP-1, ...), so lock screen and bar configs can use
+a fixed path. With wallpaper.derive set, the listed variants are derived on
+every wallpaper change. Without arguments the configured variants, or all
+of them, are derived.
+
+Examples:
+  heimdall wallpaper derive
+  heimdall wallpaper derive blur dim`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, variant := range args {
+				if !slices.Contains(wallpaper.DeriveNames, variant) {
+					return fmt.Errorf("invalid variant %q (must be one of: %s)", variant, strings.Join(wallpaper.DeriveNames, ", "))
+				}
+			}
+
+			cfg := config.Get()
+			variants := args
+			if len(variants) == 0 {
+				variants = wallpaper.DeriveNames
+				if cfg != nil && len(cfg.Wallpaper.Derive) > 0 {
+					variants = cfg.Wallpaper.Derive
+				}
+			}
+
+			current, err := currentWallpaperPath()
+			if err != nil {
+				return err
+			}
+
+			derived, err := wallpaper.Derive(current, wallpaper.DefaultDerivedCacheDir(), deriveOptions(cfg, variants, ""))
+			if err != nil && len(derived) == 0 {
+				return err
+			}
+			if err != nil {
+				logger.Warn("Some images were not derived", "error", err)
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(derived, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal derived images: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			for _, d := range derived {
+				fmt.Printf("%-10s %s\n", d.Name, d.Link)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output derived images in JSON format")
+
+	return cmd
+}
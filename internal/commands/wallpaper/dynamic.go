@@ -0,0 +1,432 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/solar"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+	"github.com/spf13/cobra"
+)
+
+// dynamicOptions configures a dynamic wallpaper run
+type dynamicOptions struct {
+	latitude  float64
+	longitude float64
+	scheme    bool
+}
+
+// dynamicStatus is the reply of the dynamic wallpaper daemon to control
+// commands
+type dynamicStatus struct {
+	PID        int        `json:"pid"`
+	Name       string     `json:"name"`
+	Dir        string     `json:"dir"`
+	Kind       string     `json:"kind"`
+	Frame      int        `json:"frame"`
+	Frames     int        `json:"frames"`
+	Current    string     `json:"current,omitempty"`
+	Mode       string     `json:"mode,omitempty"`
+	NextChange *time.Time `json:"next_change,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// dynamicCommand creates the wallpaper dynamic subcommand
+func dynamicCommand() *cobra.Command {
+	var (
+		latitude   float64
+		longitude  float64
+		noScheme   bool
+		once       bool
+		list       bool
+		jsonOutput bool
+		daemon     bool
+		stop       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dynamic [SET]",
+		Short: "Show a time-of-day wallpaper set through the day",
+		Long: `Show the frames of a dynamic wallpaper as the day goes by.
+
+SET is a directory of frames with a dynamic.json manifest, or the manifest
+itself (default: wallpaper.dynamic). Frames start at a time of day or at a
+sun elevation:
+
+  {"name": "Valley", "frames": [
+    {"file": "night.jpg", "elevation": -20},
+    {"file": "dawn.jpg", "elevation": 2, "phase": "rise"},
+    {"file": "noon.jpg", "elevation": 50, "mode": "light"},
+    {"file": "dusk.jpg", "elevation": 2, "phase": "set", "mode": "dark"}
+  ]}
+
+Time frames use "time": "HH:MM" instead. The metadata exported from macOS
+HEIC dynamic wallpapers (fileName, altitude, azimuth, isForLight and
+isForDark) works as a manifest as is. A directory without a manifest
+spreads its images evenly over the day.
+
+Sun elevations follow scheme.auto.latitude and scheme.auto.longitude, scaled
+to the day so the highest frame shows at noon in every season. A scheme is
+generated and cached for every frame when the daemon starts, so the theme
+shifts from light to dark with the image; a frame's mode overrides the
+mode detected from the image.
+
+Examples:
+  heimdall wallpaper dynamic ~/Pictures/Dynamic/valley -d
+  heimdall wallpaper dynamic ~/Pictures/Dynamic/mojave --list
+  heimdall wallpaper dynamic --once
+  heimdall wallpaper dynamic status
+  heimdall wallpaper dynamic --stop`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if stop {
+				status, err := sendDynamicCommand("stop")
+				if err != nil {
+					return err
+				}
+				fmt.Printf("✓ Stopped dynamic wallpaper (PID: %d)\n", status.PID)
+				return nil
+			}
+
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			cfg := config.Get()
+
+			set := ""
+			if len(args) > 0 {
+				set = args[0]
+			} else if cfg != nil {
+				set = cfg.Wallpaper.Dynamic
+			}
+			if set == "" {
+				return fmt.Errorf("no dynamic wallpaper given; pass a set or configure wallpaper.dynamic")
+			}
+			set, err := libraryDir(cfg, set)
+			if err != nil {
+				return err
+			}
+			dw, err := wallpaper.LoadDynamicWallpaper(set)
+			if err != nil {
+				return err
+			}
+
+			opts := dynamicOptions{scheme: !noScheme}
+			if cfg != nil {
+				opts.latitude, opts.longitude = cfg.Scheme.Auto.Latitude, cfg.Scheme.Auto.Longitude
+			}
+			if cmd.Flags().Changed("latitude") {
+				opts.latitude = latitude
+			}
+			if cmd.Flags().Changed("longitude") {
+				opts.longitude = longitude
+			}
+			if dw.Kind == wallpaper.KeyframeSun {
+				if err := solar.ValidateCoordinates(opts.latitude, opts.longitude); err != nil {
+					return err
+				}
+				if opts.latitude == 0 && opts.longitude == 0 {
+					return fmt.Errorf("latitude and longitude must be configured (scheme.auto.latitude and scheme.auto.longitude) for sun frames")
+				}
+			}
+
+			switch {
+			case list:
+				return printDynamicSchedule(dw, opts, jsonOutput)
+			case once:
+				frame := dw.FrameAt(time.Now(), opts.latitude, opts.longitude)
+				return showDynamicFrame(dw.Frames[frame], opts.scheme)
+			}
+
+			if err := dynamicSocket.checkStopped(); err != nil {
+				return err
+			}
+			if slideshowSocket.running() {
+				return fmt.Errorf("slideshow running; stop it with 'heimdall wallpaper slideshow --stop'")
+			}
+
+			if daemon {
+				pid, err := startDaemon("dynamic")
+				if err != nil {
+					return err
+				}
+				fmt.Printf("✓ Dynamic wallpaper %s started (PID: %d)\n", dw.Name, pid)
+				fmt.Printf("  To stop: heimdall wallpaper dynamic --stop\n")
+				return nil
+			}
+
+			return runDynamic(dw, opts)
+		},
+	}
+
+	cmd.Flags().Float64Var(&latitude, "latitude", 0, "Latitude for sun frames (default: scheme.auto.latitude)")
+	cmd.Flags().Float64Var(&longitude, "longitude", 0, "Longitude for sun frames (default: scheme.auto.longitude)")
+	cmd.Flags().BoolVar(&noScheme, "no-scheme", false, "Change only the wallpaper, not the scheme")
+	cmd.Flags().BoolVar(&once, "once", false, "Show the current frame and exit")
+	cmd.Flags().BoolVar(&list, "list", false, "List the frames and when they show today")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the frame list in JSON format")
+	cmd.Flags().BoolVarP(&daemon, "daemon", "d", false, "Run in the background")
+	cmd.Flags().BoolVar(&stop, "stop", false, "Stop the running dynamic wallpaper")
+
+	cmd.AddCommand(dynamicStatusCommand())
+
+	return cmd
+}
+
+// dynamicStatusCommand creates the status command
+func dynamicStatusCommand() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the current frame and next change",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := sendDynamicCommand("status")
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(status, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal status: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			fmt.Printf("\033[36;1mDynamic Wallpaper\033[0m\n")
+			fmt.Println(strings.Repeat("━", 50))
+			fmt.Printf("Set:     %s (PID: %d)\n", status.Name, status.PID)
+			fmt.Printf("Frames:  %d by %s\n", status.Frames, status.Kind)
+			if status.Current != "" {
+				fmt.Printf("Current: %d. %s", status.Frame, filepath.Base(status.Current))
+				if status.Mode != "" {
+					fmt.Printf(" (%s)", status.Mode)
+				}
+				fmt.Println()
+			}
+			if status.NextChange != nil {
+				fmt.Printf("Next:    %s\n", status.NextChange.Format("15:04"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output status in JSON format")
+	return cmd
+}
+
+// printDynamicSchedule lists the frames in the order they show today
+func printDynamicSchedule(dw *wallpaper.DynamicWallpaper, opts dynamicOptions, jsonOutput bool) error {
+	now := time.Now()
+	schedule := dw.Schedule(now, opts.latitude, opts.longitude)
+	current := dw.FrameAt(now, opts.latitude, opts.longitude)
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"wallpaper": dw,
+			"current":   current,
+			"schedule":  schedule,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal schedule: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("\033[36;1m%s\033[0m (%d frames by %s)\n", dw.Name, len(dw.Frames), dw.Kind)
+	fmt.Println(strings.Repeat("━", 50))
+	for _, scheduled := range schedule {
+		frame := dw.Frames[scheduled.Frame]
+		marker := " "
+		if scheduled.Frame == current {
+			marker = "\033[32m▶\033[0m"
+		}
+
+		keyframe := frame.Time
+		if frame.Elevation != nil {
+			keyframe = fmt.Sprintf("%.0f°", *frame.Elevation)
+			if frame.Phase != "" {
+				keyframe += " " + frame.Phase
+			}
+		}
+		mode := frame.Mode
+		if mode == "" {
+			mode = "auto"
+		}
+		fmt.Printf("%s %s  %-10s %-5s %s\n", marker, scheduled.At.Format("15:04"), keyframe, mode, filepath.Base(frame.Path))
+	}
+
+	if skipped := len(dw.Frames) - uniqueFrames(schedule); skipped > 0 {
+		fmt.Printf("\n%d frames do not show today\n", skipped)
+	}
+	return nil
+}
+
+// uniqueFrames counts the distinct frames of a schedule
+func uniqueFrames(schedule []wallpaper.ScheduledFrame) int {
+	seen := make(map[int]bool)
+	for _, scheduled := range schedule {
+		seen[scheduled.Frame] = true
+	}
+	return len(seen)
+}
+
+// showDynamicFrame sets a frame in the mode it calls for, without
+// recording it in the history
+func showDynamicFrame(frame wallpaper.DynamicFrame, enableSmartMode bool) error {
+	if frame.Mode == "" && enableSmartMode {
+		if mode, err := detectMode(frame.Path); err == nil {
+			frame.Mode = mode
+		}
+	}
+
+	return setWallpaper(frame.Path, setOptions{smart: enableSmartMode, mode: frame.Mode})
+}
+
+// warmDynamicScheme generates and caches the scheme of a frame, so
+// changing to it applies its scheme instantly. sources are the wallpapers
+// the scheme is generated from once the frame is shown.
+func warmDynamicScheme(dw *wallpaper.DynamicWallpaper, frame int, sources []wallpaper.MonitorWallpaper) {
+	path := dw.Frames[frame].Path
+	generated, err := generateSchemes(path, sources, setOptions{})
+	if err != nil {
+		logger.Warn("Failed to generate frame scheme", "frame", path, "error", err)
+		return
+	}
+	logger.Info("Prepared frame scheme", "frame", fmt.Sprintf("%d/%d", frame+1, len(dw.Frames)), "mode", generated.mode)
+}
+
+// frameSources returns the generation sources once frame replaces the
+// shown frame on the monitors showing it
+func frameSources(sources []wallpaper.MonitorWallpaper, shown, frame string) []wallpaper.MonitorWallpaper {
+	result := make([]wallpaper.MonitorWallpaper, len(sources))
+	for i, source := range sources {
+		if source.Path == shown {
+			source.Path = frame
+		}
+		result[i] = source
+	}
+	return result
+}
+
+// runDynamic shows the frame of the current time, changing it as the day
+// goes by, and serves control commands until stopped
+func runDynamic(dw *wallpaper.DynamicWallpaper, opts dynamicOptions) error {
+	listener, err := dynamicSocket.listen()
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	requests := make(chan controlRequest)
+	go dynamicSocket.serve(listener, requests)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	logger.Info("Dynamic wallpaper started", "name", dw.Name, "frames", len(dw.Frames), "kind", dw.Kind)
+
+	current := dw.FrameAt(time.Now(), opts.latitude, opts.longitude)
+	show := func(frame int) {
+		if err := showDynamicFrame(dw.Frames[frame], opts.scheme); err != nil {
+			logger.Error("Failed to show frame", "frame", dw.Frames[frame].Path, "error", err)
+		}
+	}
+
+	// Showing the first frame and preparing the scheme of each frame run
+	// one step at a time between control requests, so status and --stop
+	// are answered right away. Frames are combined with the wallpapers of
+	// the other monitors, as they are once shown.
+	var (
+		shown   string
+		sources []wallpaper.MonitorWallpaper
+	)
+	work := []func(){func() {
+		show(current)
+		if opts.scheme {
+			shown = dw.Frames[current].Path
+			sources = generationSources(shown)
+		}
+	}}
+	if opts.scheme {
+		for i := range dw.Frames {
+			work = append(work, func() { warmDynamicScheme(dw, i, frameSources(sources, shown, dw.Frames[i].Path)) })
+		}
+	}
+
+	status := func() dynamicStatus {
+		frame := dw.Frames[current]
+		status := dynamicStatus{
+			PID:     os.Getpid(),
+			Name:    dw.Name,
+			Dir:     dw.Dir,
+			Kind:    dw.Kind,
+			Frame:   current + 1,
+			Frames:  len(dw.Frames),
+			Current: frame.Path,
+			Mode:    frame.Mode,
+		}
+		if next := dw.NextChange(time.Now(), opts.latitude, opts.longitude); !next.IsZero() {
+			status.NextChange = &next
+		}
+		return status
+	}
+
+	for {
+		var ready <-chan time.Time
+		if len(work) > 0 {
+			ready = time.After(0)
+		}
+
+		// Checking every minute picks up suspend/resume and clock changes
+		select {
+		case <-ready:
+			work[0]()
+			work = work[1:]
+		case <-time.After(time.Until(time.Now().Truncate(time.Minute).Add(time.Minute))):
+			if frame := dw.FrameAt(time.Now(), opts.latitude, opts.longitude); frame != current {
+				current = frame
+				logger.Info("Showing frame", "frame", current+1, "path", dw.Frames[current].Path)
+				show(current)
+			}
+		case req := <-requests:
+			reply := status()
+			switch req.command {
+			case "status", "stop":
+			default:
+				reply.Error = fmt.Sprintf("unknown dynamic wallpaper command %q", req.command)
+			}
+			req.reply <- reply
+			if req.command == "stop" {
+				<-req.sent
+				logger.Info("Dynamic wallpaper stopped")
+				return nil
+			}
+		case <-sigChan:
+			logger.Info("Dynamic wallpaper stopped")
+			return nil
+		}
+	}
+}
+
+// sendDynamicCommand sends a control command to the running daemon
+func sendDynamicCommand(command string) (*dynamicStatus, error) {
+	var status dynamicStatus
+	if err := dynamicSocket.send(command, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
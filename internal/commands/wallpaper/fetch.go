@@ -0,0 +1,209 @@
+package wallpaper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+	"github.com/spf13/cobra"
+)
+
+// fetchCommand creates the wallpaper fetch subcommand
+func fetchCommand() *cobra.Command {
+	var (
+		sourceURL  string
+		apiKey     string
+		limit      int
+		minWidth   int
+		minHeight  int
+		anySize    bool
+		sort       string
+		dir        string
+		distance   int
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "fetch SOURCE [TERMS...]",
+		Short: "Download wallpapers from a remote or local collection",
+		Long: `Download wallpapers into the library from a collection.
+
+SOURCE is the name of a source in wallpaper.sources, or a provider used
+directly with --url:
+  wallhaven  Wallhaven-style search API (https://wallhaven.cc by default)
+  feed       RSS or Atom feed with image enclosures or media content
+  local      Directory, such as a mounted network share
+
+TERMS search the collection; feeds and directories match them against
+item titles and file names. Wallpapers smaller than the largest monitor
+are skipped unless --any-size or --min-width/--min-height are given, and
+wallpapers already in the library, by source or by perceptual hash, are
+never downloaded twice. Fetched wallpapers are saved to wallpaper.fetch_dir
+(fetched/ inside the wallpaper directory by default) and indexed.
+
+Examples:
+  heimdall wallpaper fetch wallhaven nature --limit 10
+  heimdall wallpaper fetch wallhaven --sort toplist --min-width 3840
+  heimdall wallpaper fetch feed --url https://example.com/wallpapers.rss
+  heimdall wallpaper fetch local mountains --url /mnt/share/wallpapers`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if limit < 1 {
+				return fmt.Errorf("--limit must be at least 1")
+			}
+			if distance > 64 {
+				return fmt.Errorf("--distance must be at most 64")
+			}
+
+			cfg := config.Get()
+			source, err := resolveSource(cfg, args[0])
+			if err != nil {
+				return err
+			}
+			if sourceURL != "" {
+				source.URL = sourceURL
+			}
+			if apiKey != "" {
+				source.APIKey = apiKey
+			}
+
+			provider, err := wallpaper.NewProvider(source.Provider, wallpaper.ProviderOptions{
+				URL:        source.URL,
+				APIKey:     source.APIKey,
+				Client:     &http.Client{Timeout: 2 * time.Minute},
+				Extensions: wallpaperExtensions(cfg),
+			})
+			if err != nil {
+				return err
+			}
+
+			query := wallpaper.FetchQuery{
+				Terms:     strings.Join(args[1:], " "),
+				MinWidth:  minWidth,
+				MinHeight: minHeight,
+				Sort:      sort,
+				Limit:     limit,
+			}
+			if query.Terms == "" {
+				query.Terms = source.Query
+			}
+			if !anySize && minWidth == 0 && minHeight == 0 {
+				for _, size := range monitorSizes("") {
+					query.MinWidth = max(query.MinWidth, size.Width)
+					query.MinHeight = max(query.MinHeight, size.Height)
+				}
+			}
+
+			if dir == "" && cfg != nil && cfg.Wallpaper.FetchDir != "" {
+				dir = cfg.Wallpaper.FetchDir
+			}
+			if dir == "" {
+				library, err := libraryDir(cfg, "")
+				if err != nil {
+					return err
+				}
+				dir = filepath.Join(library, "fetched")
+			}
+			if dir, err = libraryDir(cfg, dir); err != nil {
+				return err
+			}
+
+			index, err := wallpaper.LoadIndex(wallpaper.DefaultIndexPath())
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if !jsonOutput {
+				fmt.Printf("Fetching from %s...\n", source.Name)
+			}
+			result, err := wallpaper.Fetch(ctx, provider, query, index, wallpaper.FetchOptions{Dir: dir, Distance: distance})
+			if result != nil && len(result.Fetched) > 0 {
+				if err := index.Save(); err != nil {
+					return err
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to fetch wallpapers: %w", err)
+			}
+
+			if jsonOutput {
+				if result.Fetched == nil {
+					result.Fetched = []*wallpaper.IndexEntry{}
+				}
+				if result.Skipped == nil {
+					result.Skipped = []wallpaper.SkippedWallpaper{}
+				}
+				data, err := json.MarshalIndent(result, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal fetch result: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			printFetchResult(result, dir)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&sourceURL, "url", "", "API base URL, feed URL or directory, overriding the configured one")
+	cmd.Flags().StringVar(&apiKey, "api-key", "", "API key for wallhaven searches")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 5, "Number of new wallpapers to fetch")
+	cmd.Flags().IntVar(&minWidth, "min-width", 0, "Minimum width in pixels (default: largest monitor)")
+	cmd.Flags().IntVar(&minHeight, "min-height", 0, "Minimum height in pixels (default: largest monitor)")
+	cmd.Flags().BoolVar(&anySize, "any-size", false, "Fetch wallpapers of any resolution")
+	cmd.Flags().StringVar(&sort, "sort", "", "Result order: relevance, date_added, views, favorites, toplist or random for wallhaven; random for directories")
+	cmd.Flags().StringVar(&dir, "dir", "", "Directory fetched wallpapers are saved to")
+	cmd.Flags().IntVar(&distance, "distance", wallpaper.DefaultDuplicateDistance, "Maximum number of differing hash bits to an indexed wallpaper that counts as a duplicate (-1 disables)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output fetched and skipped wallpapers in JSON format")
+
+	return cmd
+}
+
+// resolveSource returns the configured source named name, or an ad hoc
+// source when name is a provider
+func resolveSource(cfg *config.Config, name string) (config.WallpaperSourceConfig, error) {
+	if cfg != nil {
+		for _, source := range cfg.Wallpaper.Sources {
+			sourceName := source.Name
+			if sourceName == "" {
+				sourceName = source.Provider
+			}
+			if sourceName == name {
+				source.Name = sourceName
+				return source, nil
+			}
+		}
+	}
+	if slices.Contains(wallpaper.ProviderNames, name) {
+		return config.WallpaperSourceConfig{Name: name, Provider: name}, nil
+	}
+	return config.WallpaperSourceConfig{}, fmt.Errorf("unknown source %q (configure it in wallpaper.sources or use one of: %s)", name, strings.Join(wallpaper.ProviderNames, ", "))
+}
+
+// printFetchResult prints the fetched wallpapers and why others were skipped
+func printFetchResult(result *wallpaper.FetchResult, dir string) {
+	fmt.Printf("\033[36;1mFetched Wallpapers\033[0m\n")
+	fmt.Println(strings.Repeat("━", 50))
+	for _, entry := range result.Fetched {
+		fmt.Printf("\033[32m✓\033[0m %5dx%-5d %s\n", entry.Width, entry.Height, filepath.Base(entry.Path))
+	}
+	for _, skipped := range result.Skipped {
+		fmt.Printf("\033[33m-\033[0m %s: %s\n", skipped.Wallpaper.ID, skipped.Reason)
+	}
+	if len(result.Fetched) == 0 && len(result.Skipped) == 0 {
+		fmt.Println("No wallpapers found")
+	}
+	fmt.Printf("\n%d fetched, %d skipped; saved to %s\n", len(result.Fetched), len(result.Skipped), dir)
+}
@@ -0,0 +1,269 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+	"github.com/spf13/cobra"
+)
+
+// recordHistory adds a wallpaper change to the history when opts.record is
+// set, with the scheme generated from it in smart mode
+func recordHistory(wallpaperPath string, opts setOptions) {
+	if !opts.record {
+		return
+	}
+
+	path, err := filepath.Abs(wallpaperPath)
+	if err != nil {
+		logger.Warn("Failed to resolve wallpaper path", "path", wallpaperPath, "error", err)
+		return
+	}
+	entry := wallpaper.HistoryEntry{Path: path, Monitor: opts.monitor, SetAt: time.Now()}
+	if opts.smart {
+		if current, err := scheme.NewManager().GetCurrent(); err == nil {
+			entry.Scheme = current.Name
+			entry.Variant = current.Variant
+			entry.Mode = current.Mode
+		}
+	}
+
+	history, err := wallpaper.LoadHistory(wallpaper.DefaultHistoryPath())
+	if err != nil {
+		logger.Warn("Failed to load wallpaper history", "error", err)
+		return
+	}
+
+	size := wallpaper.DefaultHistorySize
+	if cfg := config.Get(); cfg != nil {
+		size = cfg.Wallpaper.HistorySize
+	}
+	history.Record(entry, size)
+	if err := history.Save(); err != nil {
+		logger.Warn("Failed to save wallpaper history", "error", err)
+	}
+}
+
+// setPreviousWallpaper restores the wallpaper shown before the current one
+// on the target monitor, or on all monitors
+func setPreviousWallpaper(opts setOptions) error {
+	history, err := wallpaper.LoadHistory(wallpaper.DefaultHistoryPath())
+	if err != nil {
+		return err
+	}
+
+	previous, err := history.Previous(opts.monitor)
+	if err != nil {
+		return err
+	}
+	if err := history.Save(); err != nil {
+		return err
+	}
+
+	// Regenerate the scheme only if the wallpaper had one, in the variant
+	// and mode it had, and do not record the restore as a new change
+	opts.smart = opts.smart && previous.Scheme != ""
+	opts.variant, opts.mode = previous.Variant, previous.Mode
+	opts.record = false
+	return setWallpaper(previous.Path, opts)
+}
+
+// setRandomFavourite sets a random favourite wallpaper, from below dir when
+// it is set and matching the library query
+func setRandomFavourite(cfg *config.Config, dir string, opts setOptions) error {
+	index, err := wallpaper.LoadIndex(wallpaper.DefaultIndexPath())
+	if err != nil {
+		return err
+	}
+
+	query := opts.query
+	query.Tags = append(append([]string{}, query.Tags...), wallpaper.FavouriteTag)
+	if dir != "" {
+		if query.Dir, err = libraryDir(cfg, dir); err != nil {
+			return err
+		}
+	}
+
+	candidates := wallpaper.ExistingEntries(index.Query(query))
+	if len(candidates) == 0 {
+		return fmt.Errorf("no favourite wallpaper matches (add favourites with 'heimdall wallpaper fav')")
+	}
+	if palette := schemePalette(cfg, opts.match); palette != nil {
+		candidates = wallpaper.ClosestEntries(candidates, palette, matchThreshold(cfg))
+	}
+
+	entry, err := wallpaper.PickRandom(candidates)
+	if err != nil {
+		return err
+	}
+	logger.Info("Selected favourite wallpaper", "path", entry.Path)
+	return setWallpaper(entry.Path, opts)
+}
+
+// historyCommand creates the wallpaper history subcommand
+func historyCommand() *cobra.Command {
+	var (
+		clear      bool
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show recent wallpaper changes",
+		Long: `Show recent wallpaper changes, newest first, with the monitor they were
+set on and the scheme generated from them. Favourites are marked with ★.
+
+'heimdall wallpaper --previous' goes back one change; wallpaper.history_size
+sets how many changes are kept.
+
+Examples:
+  heimdall wallpaper history
+  heimdall wallpaper history --json
+  heimdall wallpaper history --clear`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			history, err := wallpaper.LoadHistory(wallpaper.DefaultHistoryPath())
+			if err != nil {
+				return err
+			}
+
+			if clear {
+				history.Clear()
+				if err := history.Save(); err != nil {
+					return err
+				}
+				fmt.Println("Wallpaper history cleared")
+				return nil
+			}
+
+			if jsonOutput {
+				entries := history.Entries
+				if entries == nil {
+					entries = []wallpaper.HistoryEntry{}
+				}
+				data, err := json.MarshalIndent(entries, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal history: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			if len(history.Entries) == 0 {
+				fmt.Println("No wallpaper history")
+				return nil
+			}
+
+			favourites := make(map[string]bool)
+			if index, err := wallpaper.LoadIndex(wallpaper.DefaultIndexPath()); err == nil {
+				for _, entry := range index.Query(wallpaper.IndexQuery{Tags: []string{wallpaper.FavouriteTag}, Duplicates: true}) {
+					favourites[entry.Path] = true
+				}
+			}
+
+			fmt.Printf("\033[36;1mWallpaper History\033[0m\n")
+			fmt.Println(strings.Repeat("━", 50))
+			for i, entry := range history.Entries {
+				fmt.Printf("%d. %s", i+1, entry.Path)
+				if favourites[entry.Path] {
+					fmt.Printf(" \033[33m★\033[0m")
+				}
+				if entry.Monitor != "" {
+					fmt.Printf(" [%s]", entry.Monitor)
+				}
+				if entry.Scheme != "" {
+					fmt.Printf(" \033[35m%s", entry.Scheme)
+					if entry.Variant != "" {
+						fmt.Printf("/%s", entry.Variant)
+					}
+					if entry.Mode != "" {
+						fmt.Printf(" (%s)", entry.Mode)
+					}
+					fmt.Printf("\033[0m")
+				}
+				fmt.Printf(" - %s\n", formatAge(entry.SetAt))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&clear, "clear", false, "Forget all recorded wallpaper changes")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output history in JSON format")
+
+	return cmd
+}
+
+// favouriteCommand creates the wallpaper fav and unfav subcommands
+func favouriteCommand(use, short string, favourite bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " [PATH]",
+		Short: short,
+		Long: short + `. Without a path the current wallpaper is used.
+
+Favourites are tagged "favourite" in the wallpaper index; pick a random one
+with 'heimdall wallpaper --favourites', and the weighted slideshow shows
+them more often.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var path string
+			if len(args) > 0 {
+				path = args[0]
+				if strings.HasPrefix(path, "~/") {
+					home, _ := os.UserHomeDir()
+					path = filepath.Join(home, path[2:])
+				}
+			} else {
+				current, err := currentWallpaperPath()
+				if err != nil {
+					return err
+				}
+				path = current
+			}
+			path, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+
+			index, err := wallpaper.LoadIndex(wallpaper.DefaultIndexPath())
+			if err != nil {
+				return err
+			}
+			if err := index.SetFavourite(path, favourite); err != nil {
+				return err
+			}
+			if err := index.Save(); err != nil {
+				return err
+			}
+
+			if favourite {
+				fmt.Printf("Added to favourites: %s\n", path)
+			} else {
+				fmt.Printf("Removed from favourites: %s\n", path)
+			}
+			return nil
+		},
+	}
+}
+
+// formatAge describes how long ago t was
+func formatAge(t time.Time) string {
+	duration := time.Since(t)
+	switch {
+	case duration < time.Minute:
+		return "just now"
+	case duration < time.Hour:
+		return fmt.Sprintf("%d minutes ago", int(duration.Minutes()))
+	case duration < 24*time.Hour:
+		return fmt.Sprintf("%d hours ago", int(duration.Hours()))
+	default:
+		return fmt.Sprintf("%d days ago", int(duration.Hours()/24))
+	}
+}
@@ -0,0 +1,418 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/imageio"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// schemePalette returns the palette of the current scheme when random
+// selection should match it: with --match, or with wallpaper.filter for
+// fixed schemes. Generated schemes follow the wallpaper, so matching them
+// would only keep picking look-alikes of the last one.
+func schemePalette(cfg *config.Config, force bool) *wallpaper.SchemePalette {
+	if !force && (cfg == nil || !cfg.Wallpaper.Filter) {
+		return nil
+	}
+
+	current, err := currentScheme()
+	if err != nil {
+		logger.Warn("Failed to get current scheme", "error", err)
+		return nil
+	}
+	if current.Name == "generated" && !force {
+		return nil
+	}
+
+	palette, err := wallpaper.NewSchemePalette(current.Colours)
+	if err != nil {
+		logger.Warn("Not matching wallpapers to the scheme", "scheme", current.Name, "error", err)
+		return nil
+	}
+	return palette
+}
+
+// currentScheme returns a copy of the current scheme with its derived keys,
+// such as surface for schemes that only define base colors
+func currentScheme() (*scheme.Scheme, error) {
+	current, err := scheme.NewManager().GetCurrent()
+	if err != nil {
+		return nil, err
+	}
+
+	derived := *current
+	derived.Colours = make(map[string]string, len(current.Colours))
+	for key, value := range current.Colours {
+		derived.Colours[key] = value
+	}
+	scheme.DeriveKeys(&derived)
+	return &derived, nil
+}
+
+// matchThreshold returns wallpaper.threshold as a 0-1 strictness, reading
+// values above 1 as percentages
+func matchThreshold(cfg *config.Config) float64 {
+	threshold := 0.8
+	if cfg != nil {
+		threshold = cfg.Wallpaper.Threshold
+	}
+	if threshold > 1 {
+		threshold /= 100
+	}
+	return threshold
+}
+
+// addQueryFlags registers the library query flags on flags
+func addQueryFlags(flags *pflag.FlagSet, q *wallpaper.IndexQuery) {
+	flags.StringVar(&q.Mode, "mode", "", "Only wallpapers suited to this scheme mode (dark or light)")
+	flags.IntVar(&q.MinWidth, "min-width", 0, "Only wallpapers at least this wide")
+	flags.IntVar(&q.MinHeight, "min-height", 0, "Only wallpapers at least this tall")
+	flags.StringVar(&q.Hue, "hue", "", fmt.Sprintf("Only wallpapers with this dominant hue (%s)", strings.Join(wallpaper.HueNames, ", ")))
+	flags.StringSliceVar(&q.Tags, "tag", nil, "Only wallpapers with these tags")
+}
+
+// validateQuery checks the values of the library query flags
+func validateQuery(q wallpaper.IndexQuery) error {
+	if q.Mode != "" && q.Mode != "dark" && q.Mode != "light" {
+		return fmt.Errorf("invalid --mode %q (must be dark or light)", q.Mode)
+	}
+	if q.Hue != "" && !slices.Contains(wallpaper.HueNames, q.Hue) {
+		return fmt.Errorf("invalid --hue %q (available: %s)", q.Hue, strings.Join(wallpaper.HueNames, ", "))
+	}
+	if q.MinWidth < 0 || q.MinHeight < 0 {
+		return fmt.Errorf("--min-width and --min-height must be non-negative")
+	}
+	return nil
+}
+
+// wallpaperExtensions returns the image extensions of wallpapers
+func wallpaperExtensions(cfg *config.Config) []string {
+	if cfg != nil && len(cfg.Wallpaper.Extensions) > 0 {
+		return cfg.Wallpaper.Extensions
+	}
+	return imageio.Extensions()
+}
+
+// libraryDir returns the absolute form of a wallpaper directory, the
+// configured one when dir is empty
+func libraryDir(cfg *config.Config, dir string) (string, error) {
+	if dir == "" {
+		dir = paths.WallpapersDir
+		if cfg != nil && cfg.Wallpaper.Directory != "" {
+			dir = cfg.Wallpaper.Directory
+		}
+	}
+	if strings.HasPrefix(dir, "~/") {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, dir[2:])
+	}
+	return filepath.Abs(dir)
+}
+
+// randomFromIndex picks a random wallpaper below dir from the library
+// index, matching the query of opts. It returns an empty path when the
+// index does not cover dir and no query needs it; with a query the
+// directory is indexed first.
+func randomFromIndex(cfg *config.Config, dir string, enableSizeFilter bool, threshold float64, opts setOptions) (string, error) {
+	dir, err := libraryDir(cfg, dir)
+	if err != nil {
+		return "", err
+	}
+
+	index, err := wallpaper.LoadIndex(wallpaper.DefaultIndexPath())
+	if err != nil {
+		if opts.query.Empty() {
+			logger.Warn("Ignoring the wallpaper index", "error", err)
+			return "", nil
+		}
+		return "", err
+	}
+
+	if len(index.Query(wallpaper.IndexQuery{Dir: dir})) == 0 {
+		if opts.query.Empty() && !opts.match {
+			return "", nil
+		}
+		fmt.Printf("Indexing %s...\n", dir)
+		if _, err := updateIndex(cfg, index, []string{dir}); err != nil {
+			return "", err
+		}
+	}
+
+	query := opts.query
+	query.Dir = dir
+
+	sized, sizeFiltered := query, false
+	if enableSizeFilter {
+		if width, height, err := requiredSize(threshold); err != nil {
+			logger.Warn("Failed to apply size filtering", "error", err)
+		} else {
+			sized.MinWidth = max(sized.MinWidth, width)
+			sized.MinHeight = max(sized.MinHeight, height)
+			sizeFiltered = true
+		}
+	}
+
+	candidates := wallpaper.ExistingEntries(index.Query(sized))
+	if len(candidates) == 0 && sizeFiltered {
+		logger.Warn("No wallpapers passed size filter, using all", "threshold", threshold)
+		candidates = wallpaper.ExistingEntries(index.Query(query))
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no wallpaper in %s matches the query", dir)
+	}
+
+	if palette := schemePalette(cfg, opts.match); palette != nil {
+		total := len(candidates)
+		candidates = wallpaper.ClosestEntries(candidates, palette, matchThreshold(cfg))
+		logger.Info("Matching wallpapers to the scheme", "candidates", len(candidates), "of", total)
+	}
+
+	entry, err := wallpaper.PickRandom(candidates)
+	if err != nil {
+		return "", err
+	}
+	return entry.Path, nil
+}
+
+// updateIndex indexes dirs and saves the index
+func updateIndex(cfg *config.Config, index *wallpaper.Index, dirs []string) (*wallpaper.IndexStats, error) {
+	stats, err := index.Update(dirs, wallpaperExtensions(cfg), func(path string, err error) {
+		if err != nil {
+			logger.Warn("Failed to index wallpaper", "path", path, "error", err)
+			return
+		}
+		logger.Debug("Indexed wallpaper", "path", path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := index.Save(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// indexCommand creates the wallpaper index subcommand
+func indexCommand() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "index [DIR...]",
+		Short: "Index the wallpaper library",
+		Long: `Index wallpapers with their size, colourfulness, suited scheme mode and
+dominant colors, so random selection and queries do not decode images.
+
+Indexing is incremental: unchanged files are skipped, moved files keep
+their metadata and tags, and deleted files are dropped. Without
+directories the configured wallpaper directory is indexed.
+
+Random selection with 'heimdall wallpaper -r' queries the index with
+--mode, --min-width, --min-height, --hue and --tag. With wallpaper.filter
+set to a fixed scheme (like catppuccin), it picks among the wallpapers
+whose colors are closest to it; wallpaper.threshold sets how strict the
+match is, and --match also matches generated schemes.
+
+Examples:
+  heimdall wallpaper index
+  heimdall wallpaper index ~/Pictures/Wallpapers ~/Pictures/Art
+  heimdall wallpaper index list --mode dark --hue blue
+  heimdall wallpaper index tag ~/Pictures/Wallpapers/a.jpg nature calm
+  heimdall wallpaper -r --mode dark --min-width 3840 --hue blue`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.Get()
+
+			dirs := args
+			if len(dirs) == 0 {
+				dirs = []string{""}
+			}
+			for i, dir := range dirs {
+				abs, err := libraryDir(cfg, dir)
+				if err != nil {
+					return err
+				}
+				if _, err := os.Stat(abs); err != nil {
+					return fmt.Errorf("wallpaper directory not found: %w", err)
+				}
+				dirs[i] = abs
+			}
+
+			index, err := wallpaper.LoadIndex(wallpaper.DefaultIndexPath())
+			if err != nil {
+				return err
+			}
+			stats, err := updateIndex(cfg, index, dirs)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(stats, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal stats: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			fmt.Printf("Indexed %d wallpapers: %d analyzed, %d unchanged, %d moved, %d removed",
+				stats.Total, stats.Analyzed, stats.Unchanged, stats.Moved, stats.Removed)
+			if stats.Failed > 0 {
+				fmt.Printf(", %d failed", stats.Failed)
+			}
+			fmt.Println()
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output statistics in JSON format")
+
+	cmd.AddCommand(indexListCommand())
+	cmd.AddCommand(indexTagCommand("tag", "Add tags to an indexed wallpaper", false))
+	cmd.AddCommand(indexTagCommand("untag", "Remove tags from an indexed wallpaper", true))
+
+	return cmd
+}
+
+// indexListCommand creates the wallpaper index list subcommand
+func indexListCommand() *cobra.Command {
+	var (
+		query      wallpaper.IndexQuery
+		dir        string
+		match      bool
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List indexed wallpapers matching a query",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateQuery(query); err != nil {
+				return err
+			}
+			if dir != "" {
+				abs, err := libraryDir(config.Get(), dir)
+				if err != nil {
+					return err
+				}
+				query.Dir = abs
+			}
+
+			index, err := wallpaper.LoadIndex(wallpaper.DefaultIndexPath())
+			if err != nil {
+				return err
+			}
+			entries := index.Query(query)
+
+			// Rank by distance to the scheme when matching
+			var scored []wallpaper.ScoredEntry
+			if match {
+				palette := schemePalette(config.Get(), true)
+				if palette == nil {
+					return fmt.Errorf("the current scheme has no colors to match")
+				}
+				scored = wallpaper.RankByPalette(entries, palette)
+			} else {
+				for _, entry := range entries {
+					scored = append(scored, wallpaper.ScoredEntry{IndexEntry: entry})
+				}
+			}
+
+			if jsonOutput {
+				var output any = entries
+				if match {
+					output = scored
+				}
+				if entries == nil {
+					output = []*wallpaper.IndexEntry{}
+				}
+				data, err := json.MarshalIndent(output, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal entries: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			for _, entry := range scored {
+				fmt.Printf("%s  %5dx%-5d %-5s ", colorSwatches(entry.Colors), entry.Width, entry.Height, entry.Mode)
+				if match {
+					fmt.Printf("%5.1f ", entry.Distance)
+				}
+				fmt.Print(entry.Path)
+				if len(entry.Tags) > 0 {
+					fmt.Printf("  [%s]", strings.Join(entry.Tags, ", "))
+				}
+				if entry.DuplicateOf != "" {
+					fmt.Printf("  (duplicate of %s)", entry.DuplicateOf)
+				}
+				fmt.Println()
+			}
+			fmt.Printf("%d of %d wallpapers\n", len(entries), len(index.Entries))
+			return nil
+		},
+	}
+
+	addQueryFlags(cmd.Flags(), &query)
+	cmd.Flags().StringVar(&dir, "dir", "", "Only wallpapers below this directory")
+	cmd.Flags().BoolVar(&match, "match", false, "Sort by distance to the current scheme, closest first")
+	cmd.Flags().BoolVar(&query.Duplicates, "duplicates", false, "Include wallpapers excluded by dedupe")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// indexTagCommand creates the wallpaper index tag and untag subcommands
+func indexTagCommand(use, short string, remove bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " PATH TAG...",
+		Short: short,
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := filepath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+
+			index, err := wallpaper.LoadIndex(wallpaper.DefaultIndexPath())
+			if err != nil {
+				return err
+			}
+			if err := index.SetTags(path, args[1:], remove); err != nil {
+				return fmt.Errorf("%w (run 'heimdall wallpaper index' first)", err)
+			}
+			if err := index.Save(); err != nil {
+				return err
+			}
+
+			fmt.Printf("%s: %s\n", path, strings.Join(index.Entries[path].Tags, ", "))
+			return nil
+		},
+	}
+}
+
+// colorSwatches renders colors as terminal swatches
+func colorSwatches(colors []string) string {
+	var b strings.Builder
+	for _, hex := range colors {
+		var r, g, bl int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(hex, "#"), "%02x%02x%02x", &r, &g, &bl); err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "\033[48;2;%d;%d;%dm  \033[0m", r, g, bl)
+	}
+	return b.String()
+}
@@ -0,0 +1,140 @@
+package wallpaper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/hypr"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/imageio"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/material"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+)
+
+// wallpaperBackend returns the configured wallpaper backend
+func wallpaperBackend() (wallpaper.Backend, error) {
+	name := wallpaper.BackendAuto
+	opts := wallpaper.BackendOptions{}
+	if cfg := config.Get(); cfg != nil {
+		if cfg.Wallpaper.Backend != "" {
+			name = cfg.Wallpaper.Backend
+		}
+		opts = wallpaper.BackendOptions{
+			Transition:         cfg.Wallpaper.Transition,
+			TransitionDuration: cfg.Wallpaper.TransitionDuration,
+			TransitionFPS:      cfg.Wallpaper.TransitionFPS,
+		}
+	}
+	return wallpaper.NewBackend(name, opts)
+}
+
+// validateMonitor checks that a Hyprland monitor with the name exists.
+// Outside Hyprland the name is trusted.
+func validateMonitor(name string) error {
+	if name == "" || !hypr.IsRunning() {
+		return nil
+	}
+
+	client, err := hypr.NewClient()
+	if err != nil {
+		return nil
+	}
+	monitors, err := client.GetMonitors()
+	if err != nil {
+		logger.Warn("Failed to get monitors", "error", err)
+		return nil
+	}
+
+	names := make([]string, 0, len(monitors))
+	for _, m := range monitors {
+		if m.Name == name {
+			return nil
+		}
+		names = append(names, m.Name)
+	}
+	return fmt.Errorf("monitor %q not found (available: %s)", name, strings.Join(names, ", "))
+}
+
+// generationSources returns the wallpapers to generate a scheme from. When
+// monitors show different wallpapers they are all used, weighted as set by
+// wallpaper.multi_monitor; otherwise only wallpaperPath is.
+func generationSources(wallpaperPath string) []wallpaper.MonitorWallpaper {
+	single := []wallpaper.MonitorWallpaper{{Path: wallpaperPath, Weight: 1}}
+
+	weighting := wallpaper.WeightingArea
+	if cfg := config.Get(); cfg != nil && cfg.Wallpaper.MultiMonitor != "" {
+		weighting = cfg.Wallpaper.MultiMonitor
+	}
+
+	if weighting == wallpaper.WeightingOff || !hypr.IsRunning() {
+		return single
+	}
+
+	client, err := hypr.NewClient()
+	if err != nil {
+		return single
+	}
+	monitors, err := client.GetMonitors()
+	if err != nil {
+		logger.Warn("Failed to get monitors", "error", err)
+		return single
+	}
+
+	backend, err := wallpaperBackend()
+	if err != nil {
+		logger.Warn("No wallpaper backend", "error", err)
+		return single
+	}
+
+	active, err := wallpaper.ActiveWallpapers(backend)
+	if err != nil {
+		logger.Warn("Failed to get active wallpapers", "error", err)
+		return single
+	}
+
+	sources, err := wallpaper.MonitorWallpapers(monitors, active, weighting, primaryMonitor())
+	if err != nil {
+		logger.Warn("Using the last set wallpaper only", "error", err)
+		return single
+	}
+
+	if len(sources) > 1 || weighting == wallpaper.WeightingPrimary {
+		for _, source := range sources {
+			logger.Info("Using monitor wallpaper", "monitor", source.Monitor, "wallpaper", source.Path, "weight", source.Weight)
+		}
+		return sources
+	}
+	return single
+}
+
+// primaryMonitor returns the configured primary monitor, empty for the first
+func primaryMonitor() string {
+	if cfg := config.Get(); cfg != nil {
+		return cfg.Wallpaper.PrimaryMonitor
+	}
+	return ""
+}
+
+// loadWeightedImages decodes the wallpaper of each source
+func loadWeightedImages(sources []wallpaper.MonitorWallpaper) ([]material.WeightedImage, error) {
+	images := make([]material.WeightedImage, 0, len(sources))
+	for _, source := range sources {
+		img, err := imageio.Decode(source.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		images = append(images, material.WeightedImage{Image: img, Weight: source.Weight})
+	}
+	return images, nil
+}
+
+// sourcePaths returns the wallpaper paths of sources
+func sourcePaths(sources []wallpaper.MonitorWallpaper) []string {
+	paths := make([]string, len(sources))
+	for i, source := range sources {
+		paths[i] = source.Path
+	}
+	return paths
+}
@@ -0,0 +1,225 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/color"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/material"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+)
+
+// seedOptions controls how the seed color of a wallpaper is chosen
+type seedOptions struct {
+	Index int  // 1-based candidate rank, 0 to use the remembered or automatic seed
+	Pick  bool // Prompt for a candidate with fuzzel
+	Reset bool // Forget the remembered choice first
+}
+
+// resolveSeed returns the seed color for the wallpapers of sources. An
+// explicit choice (--seed-index or the fuzzel prompt) wins and is
+// remembered by content hash; otherwise a remembered choice is reused. ok
+// is false when neither exists and the automatic seed should be used.
+func resolveSeed(sources []wallpaper.MonitorWallpaper, extract func() (*material.ExtractedColors, error), opts seedOptions) (seed uint32, ok bool, err error) {
+	hash, err := wallpaper.HashFiles(sources, primaryMonitor())
+	if err != nil {
+		return 0, false, err
+	}
+	wallpaperPath := strings.Join(sourcePaths(sources), ", ")
+
+	store, err := wallpaper.LoadSeedStore(wallpaper.DefaultSeedStorePath())
+	if err != nil {
+		return 0, false, err
+	}
+
+	if opts.Reset {
+		if err := store.Delete(hash); err != nil {
+			return 0, false, err
+		}
+		logger.Info("Forgot seed choice", "wallpaper", wallpaperPath)
+	}
+
+	if opts.Index > 0 || opts.Pick {
+		extracted, err := extract()
+		if err != nil {
+			return 0, false, err
+		}
+
+		index := opts.Index
+		if opts.Pick {
+			index, err = promptSeedCandidate(extracted.Candidates)
+			if err != nil {
+				return 0, false, err
+			}
+		}
+
+		if index > 0 {
+			return rememberSeed(store, hash, wallpaperPath, extracted, index)
+		}
+	}
+
+	if choice, found := store.Get(hash); found {
+		c, err := color.NewFromHex(choice.Color)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid remembered seed %q: %w", choice.Color, err)
+		}
+		return 0xFF000000 | uint32(c.RGB.R)<<16 | uint32(c.RGB.G)<<8 | uint32(c.RGB.B), true, nil
+	}
+
+	return 0, false, nil
+}
+
+// rememberSeed stores the candidate at rank as the wallpaper's seed
+func rememberSeed(store *wallpaper.SeedStore, hash, wallpaperPath string, extracted *material.ExtractedColors, rank int) (uint32, bool, error) {
+	candidate, err := extracted.SeedCandidate(rank)
+	if err != nil {
+		return 0, false, err
+	}
+
+	choice := wallpaper.SeedChoice{
+		Color:    argbToHex(candidate.Color),
+		Path:     wallpaperPath,
+		ChosenAt: time.Now(),
+	}
+	if err := store.Set(hash, choice); err != nil {
+		return 0, false, err
+	}
+	logger.Info("Remembered seed choice", "wallpaper", wallpaperPath, "seed", choice.Color)
+	return candidate.Color, true, nil
+}
+
+// showSeedCandidates extracts and lists the seed candidates of a wallpaper,
+// or of the current wallpaper when path is empty
+func showSeedCandidates(wallpaperPath string, jsonOutput, noCache bool) error {
+	if wallpaperPath == "" {
+		current, err := currentWallpaperPath()
+		if err != nil {
+			return err
+		}
+		wallpaperPath = current
+	}
+	if strings.HasPrefix(wallpaperPath, "~/") {
+		home, _ := os.UserHomeDir()
+		wallpaperPath = filepath.Join(home, wallpaperPath[2:])
+	}
+
+	palette, err := loadPalette([]wallpaper.MonitorWallpaper{{Path: wallpaperPath, Weight: 1}}, noCache)
+	if err != nil {
+		return err
+	}
+
+	return printSeedCandidates(wallpaperPath, palette.entry.Extracted, jsonOutput)
+}
+
+// candidateLine formats a seed candidate for listings and the fuzzel prompt
+func candidateLine(rank int, candidate material.SeedCandidate) string {
+	line := fmt.Sprintf("%d. %s  score %5.1f  population %5.1f  chroma %5.1f  (%s)",
+		rank, argbToHex(candidate.Color), candidate.Score, candidate.PopulationScore,
+		candidate.Chroma, strings.Join(candidate.Sources, ", "))
+	if candidate.Auto {
+		line += "  [auto]"
+	}
+	return line
+}
+
+// promptSeedCandidate asks for a candidate with fuzzel, returning its
+// 1-based rank or 0 when cancelled
+func promptSeedCandidate(candidates []material.SeedCandidate) (int, error) {
+	if len(candidates) == 0 {
+		return 0, fmt.Errorf("no seed candidates to choose from")
+	}
+
+	fuzzelCmd := "fuzzel"
+	if cfg := config.Get(); cfg != nil && cfg.External.Fuzzel != "" {
+		fuzzelCmd = cfg.External.Fuzzel
+	}
+
+	lines := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		lines[i] = candidateLine(i+1, candidate)
+	}
+
+	cmd := exec.Command(fuzzelCmd, "--dmenu", "--prompt", "Seed> ")
+	cmd.Stdin = strings.NewReader(strings.Join(lines, "\n") + "\n")
+
+	output, err := cmd.Output()
+	if err != nil {
+		// User cancelled
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to run fuzzel: %w", err)
+	}
+
+	selected := strings.TrimSpace(string(output))
+	if selected == "" {
+		return 0, nil
+	}
+
+	rank, err := strconv.Atoi(strings.TrimSuffix(strings.Fields(selected)[0], "."))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected selection %q", selected)
+	}
+	return rank, nil
+}
+
+// printSeedCandidates lists the ranked seed candidates of a wallpaper
+func printSeedCandidates(wallpaperPath string, extracted *material.ExtractedColors, jsonOutput bool) error {
+	if jsonOutput {
+		type candidateJSON struct {
+			Rank            int      `json:"rank"`
+			Color           string   `json:"color"`
+			Score           float64  `json:"score"`
+			PopulationScore float64  `json:"population_score"`
+			ChromaScore     float64  `json:"chroma_score"`
+			Hue             float64  `json:"hue"`
+			Chroma          float64  `json:"chroma"`
+			Tone            float64  `json:"tone"`
+			Sources         []string `json:"sources"`
+			Auto            bool     `json:"auto,omitempty"`
+		}
+
+		out := make([]candidateJSON, len(extracted.Candidates))
+		for i, c := range extracted.Candidates {
+			out[i] = candidateJSON{
+				Rank:            i + 1,
+				Color:           argbToHex(c.Color),
+				Score:           c.Score,
+				PopulationScore: c.PopulationScore,
+				ChromaScore:     c.ChromaScore,
+				Hue:             c.Hue,
+				Chroma:          c.Chroma,
+				Tone:            c.Tone,
+				Sources:         c.Sources,
+				Auto:            c.Auto,
+			}
+		}
+
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal candidates: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("\033[36;1mSeed Candidates\033[0m\n")
+	fmt.Printf("━━━━━━━━━━━━━━━\n")
+	fmt.Printf("Wallpaper: %s\n\n", wallpaperPath)
+
+	for i, candidate := range extracted.Candidates {
+		r, g, b := (candidate.Color>>16)&0xFF, (candidate.Color>>8)&0xFF, candidate.Color&0xFF
+		fmt.Printf("\033[48;2;%d;%d;%dm    \033[0m %s\n", r, g, b, candidateLine(i+1, candidate))
+	}
+
+	fmt.Printf("\nUse --seed-index N (or --pick-seed) to choose one for this wallpaper.\n")
+	return nil
+}
@@ -0,0 +1,530 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/daemon"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/hypr"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+	"github.com/spf13/cobra"
+)
+
+// slideshowCheckInterval bounds how long the slideshow sleeps between
+// checks, so suspend/resume and clock changes are picked up quickly
+const slideshowCheckInterval = time.Minute
+
+// slideshowOptions configures a slideshow run
+type slideshowOptions struct {
+	dir             string
+	interval        time.Duration
+	order           string
+	scheme          bool
+	pauseFullscreen bool
+}
+
+// slideshowStatus is the reply of the slideshow daemon to control commands
+type slideshowStatus struct {
+	PID        int        `json:"pid"`
+	Dir        string     `json:"dir"`
+	Order      string     `json:"order"`
+	Interval   string     `json:"interval"`
+	Current    string     `json:"current,omitempty"`
+	Paused     bool       `json:"paused"`
+	Fullscreen bool       `json:"fullscreen"`
+	NextChange *time.Time `json:"next_change,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// slideshowCommand creates the wallpaper slideshow subcommand
+func slideshowCommand() *cobra.Command {
+	var (
+		dir          string
+		interval     time.Duration
+		order        string
+		scheme       bool
+		noFullscreen bool
+		daemon       bool
+		stop         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "slideshow",
+		Short: "Cycle wallpapers on an interval",
+		Long: `Cycle the wallpapers of a directory on an interval.
+
+Orders:
+  shuffle  - Random order without repeats until every wallpaper was shown
+  ordered  - By path, wrapping around
+  weighted - Random, showing wallpapers tagged 'favourite' in the index
+             more often
+
+The slideshow pauses while a Hyprland window is fullscreen and continues
+where it stopped after a restart. With --scheme the Material You scheme
+is generated from each wallpaper and applied.
+
+Subcommands control a running slideshow:
+  next     - Show the next wallpaper now
+  previous - Show the previous wallpaper again
+  pause    - Stop changing wallpapers
+  resume   - Continue changing wallpapers
+  status   - Show the current wallpaper and next change
+
+Examples:
+  heimdall wallpaper slideshow --dir ~/Pictures/Wallpapers --interval 30m
+  heimdall wallpaper slideshow --order weighted --scheme -d
+  heimdall wallpaper slideshow next
+  heimdall wallpaper slideshow --stop`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if stop {
+				status, err := sendSlideshowCommand("stop")
+				if err != nil {
+					return err
+				}
+				fmt.Printf("✓ Stopped slideshow (PID: %d)\n", status.PID)
+				return nil
+			}
+
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			cfg := config.Get()
+
+			opts, err := resolveSlideshowOptions(cfg, dir, order)
+			if err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("interval") {
+				if interval <= 0 {
+					return fmt.Errorf("--interval must be positive")
+				}
+				opts.interval = interval
+			}
+			opts.scheme = scheme
+			if noFullscreen {
+				opts.pauseFullscreen = false
+			}
+
+			if err := slideshowSocket.checkStopped(); err != nil {
+				return err
+			}
+			if dynamicSocket.running() {
+				return fmt.Errorf("dynamic wallpaper running; stop it with 'heimdall wallpaper dynamic --stop'")
+			}
+
+			if daemon {
+				pid, err := startDaemon("slideshow")
+				if err != nil {
+					return err
+				}
+				fmt.Printf("✓ Slideshow started for %s (PID: %d)\n", opts.dir, pid)
+				fmt.Printf("  To stop: heimdall wallpaper slideshow --stop\n")
+				return nil
+			}
+
+			return runSlideshow(cfg, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "Wallpaper directory (default: wallpaper.directory)")
+	cmd.Flags().DurationVar(&interval, "interval", 0, "Time each wallpaper is shown, e.g. 30m or 1h (default: wallpaper.slideshow_interval)")
+	cmd.Flags().StringVar(&order, "order", "", "Order: shuffle, ordered or weighted (default: wallpaper.slideshow_order)")
+	cmd.Flags().BoolVar(&scheme, "scheme", false, "Generate and apply the Material You scheme on each change")
+	cmd.Flags().BoolVar(&noFullscreen, "no-fullscreen-pause", false, "Keep changing wallpapers while a window is fullscreen")
+	cmd.Flags().BoolVarP(&daemon, "daemon", "d", false, "Run in the background")
+	cmd.Flags().BoolVar(&stop, "stop", false, "Stop the running slideshow")
+
+	cmd.AddCommand(slideshowControlCommand("next", nil, "Show the next wallpaper now"))
+	cmd.AddCommand(slideshowControlCommand("previous", []string{"prev"}, "Show the previous wallpaper again"))
+	cmd.AddCommand(slideshowControlCommand("pause", nil, "Pause the slideshow"))
+	cmd.AddCommand(slideshowControlCommand("resume", nil, "Resume the slideshow"))
+	cmd.AddCommand(slideshowStatusCommand())
+
+	return cmd
+}
+
+// resolveSlideshowOptions fills the options the flags leave unset from the
+// configuration
+func resolveSlideshowOptions(cfg *config.Config, dir, order string) (slideshowOptions, error) {
+	opts := slideshowOptions{interval: 30 * time.Minute, order: wallpaper.OrderShuffle, pauseFullscreen: true}
+	if cfg != nil {
+		if cfg.Wallpaper.SlideshowInterval != "" {
+			interval, err := time.ParseDuration(cfg.Wallpaper.SlideshowInterval)
+			if err != nil {
+				return opts, fmt.Errorf("invalid wallpaper.slideshow_interval: %w", err)
+			}
+			opts.interval = interval
+		}
+		if cfg.Wallpaper.SlideshowOrder != "" {
+			opts.order = cfg.Wallpaper.SlideshowOrder
+		}
+		opts.pauseFullscreen = cfg.Wallpaper.SlideshowPauseFullscreen
+	}
+
+	if order != "" {
+		opts.order = order
+	}
+	if !slices.Contains(wallpaper.SlideshowOrders, opts.order) {
+		return opts, fmt.Errorf("invalid order %q (must be one of: %s)", opts.order, strings.Join(wallpaper.SlideshowOrders, ", "))
+	}
+
+	abs, err := libraryDir(cfg, dir)
+	if err != nil {
+		return opts, err
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return opts, fmt.Errorf("wallpaper directory not found: %w", err)
+	}
+	opts.dir = abs
+	return opts, nil
+}
+
+// slideshowControlCommand creates a command sent to the running slideshow
+func slideshowControlCommand(use string, aliases []string, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:     use,
+		Aliases: aliases,
+		Short:   short,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := sendSlideshowCommand(use)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case use == "pause" || use == "resume":
+				fmt.Printf("Slideshow %sd\n", use)
+			case status.Current != "":
+				fmt.Printf("Wallpaper: %s\n", status.Current)
+			}
+			return nil
+		},
+	}
+}
+
+// slideshowStatusCommand creates the status command
+func slideshowStatusCommand() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the current wallpaper and next change",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := sendSlideshowCommand("status")
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(status, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal status: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			state := "running"
+			switch {
+			case status.Paused:
+				state = "paused"
+			case status.Fullscreen:
+				state = "paused while fullscreen"
+			}
+
+			fmt.Printf("\033[36;1mWallpaper Slideshow\033[0m\n")
+			fmt.Println(strings.Repeat("━", 50))
+			fmt.Printf("State:     %s (PID: %d)\n", state, status.PID)
+			fmt.Printf("Directory: %s\n", status.Dir)
+			fmt.Printf("Order:     %s every %s\n", status.Order, status.Interval)
+			if status.Current != "" {
+				fmt.Printf("Current:   %s\n", status.Current)
+			}
+			if status.NextChange != nil {
+				fmt.Printf("Next:      %s\n", status.NextChange.Format("15:04:05"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output status in JSON format")
+	return cmd
+}
+
+// sendSlideshowCommand sends a control command to the running slideshow
+func sendSlideshowCommand(command string) (*slideshowStatus, error) {
+	var status slideshowStatus
+	if err := slideshowSocket.send(command, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// startDaemon re-executes the current command in the background without
+// -d, logging to heimdall-wallpaper-<name>.log. The daemon is found again
+// through its control socket rather than a PID file.
+func startDaemon(name string) (int, error) {
+	return daemon.Process{
+		Name:    name,
+		LogFile: filepath.Join(paths.StateDir, "heimdall-wallpaper-"+name+".log"),
+	}.Start()
+}
+
+// slideshow is the state of a running slideshow
+type slideshow struct {
+	cfg        *config.Config
+	opts       slideshowOptions
+	state      *wallpaper.SlideshowState
+	next       time.Time     // When the wallpaper changes while running
+	remaining  time.Duration // Time left until the change while halted
+	paused     bool
+	fullscreen bool
+}
+
+// runSlideshow changes wallpapers on the interval and serves control
+// commands until stopped
+func runSlideshow(cfg *config.Config, opts slideshowOptions) error {
+	state, err := wallpaper.LoadSlideshowState(wallpaper.DefaultSlideshowStatePath(), opts.dir, opts.order)
+	if err != nil {
+		return err
+	}
+	s := &slideshow{cfg: cfg, opts: opts, state: state}
+
+	listener, err := slideshowSocket.listen()
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	requests := make(chan controlRequest)
+	go slideshowSocket.serve(listener, requests)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	var events <-chan hypr.Event
+	if opts.pauseFullscreen {
+		events = s.watchFullscreen()
+	}
+
+	logger.Info("Slideshow started", "dir", opts.dir, "order", opts.order, "interval", opts.interval)
+	s.step(1)
+
+	for {
+		var due <-chan time.Time
+		if !s.halted() {
+			wait := slideshowCheckInterval
+			if until := time.Until(s.next); until < wait {
+				wait = until
+			}
+			due = time.After(wait)
+		}
+
+		select {
+		case <-due:
+			if !time.Now().Before(s.next) {
+				s.step(1)
+			}
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				s.update(func() { s.fullscreen = false })
+				continue
+			}
+			s.handleEvent(event)
+		case req := <-requests:
+			req.reply <- s.handle(req.command)
+			if req.command == "stop" {
+				<-req.sent
+				logger.Info("Slideshow stopped")
+				return nil
+			}
+		case <-sigChan:
+			logger.Info("Slideshow stopped")
+			return nil
+		}
+	}
+}
+
+// handle runs a control command and returns the resulting status
+func (s *slideshow) handle(command string) slideshowStatus {
+	var err error
+	switch command {
+	case "next":
+		err = s.step(1)
+	case "previous", "prev":
+		err = s.step(-1)
+	case "pause":
+		s.update(func() { s.paused = true })
+	case "resume":
+		s.update(func() { s.paused = false })
+	case "status", "stop":
+	default:
+		err = fmt.Errorf("unknown slideshow command %q", command)
+	}
+
+	status := s.status()
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// status returns the current slideshow status
+func (s *slideshow) status() slideshowStatus {
+	status := slideshowStatus{
+		PID:        os.Getpid(),
+		Dir:        s.opts.dir,
+		Order:      s.opts.order,
+		Interval:   s.opts.interval.String(),
+		Current:    s.state.Current(),
+		Paused:     s.paused,
+		Fullscreen: s.fullscreen,
+	}
+	if !s.halted() {
+		status.NextChange = &s.next
+	}
+	return status
+}
+
+// step shows the next wallpaper, or the previous one when direction is
+// negative, and restarts the interval
+func (s *slideshow) step(direction int) error {
+	wallpapers, err := wallpaper.ListImages(s.opts.dir, wallpaperExtensions(s.cfg))
+	if err != nil {
+		return err
+	}
+
+	index, err := wallpaper.LoadIndex(wallpaper.DefaultIndexPath())
+	if err != nil {
+		logger.Warn("Ignoring the wallpaper index", "error", err)
+		index = nil
+	}
+	wallpapers = withoutDuplicates(wallpapers, index)
+
+	var path string
+	if direction < 0 {
+		path, err = s.state.Previous(wallpapers)
+	} else {
+		path, err = s.state.Next(wallpapers, s.weights(index))
+	}
+
+	// Retry on the next interval rather than spinning on an empty directory
+	s.next = time.Now().Add(s.opts.interval)
+	s.remaining = s.opts.interval
+	if err != nil {
+		logger.Error("Failed to pick the next wallpaper", "dir", s.opts.dir, "error", err)
+		return err
+	}
+
+	if err := s.state.Save(); err != nil {
+		logger.Error("Failed to save slideshow state", "error", err)
+	}
+	if err := setWallpaper(path, setOptions{smart: s.opts.scheme, record: true}); err != nil {
+		logger.Error("Failed to set wallpaper", "path", path, "error", err)
+		return err
+	}
+	return nil
+}
+
+// weights returns the weight function of the weighted order
+func (s *slideshow) weights(index *wallpaper.Index) func(string) float64 {
+	if s.opts.order != wallpaper.OrderWeighted || index == nil {
+		return nil
+	}
+	return wallpaper.FavouriteWeights(index)
+}
+
+// withoutDuplicates drops the wallpapers dedupe excluded
+func withoutDuplicates(wallpapers []string, index *wallpaper.Index) []string {
+	if index == nil {
+		return wallpapers
+	}
+
+	kept := wallpapers[:0]
+	for _, path := range wallpapers {
+		if entry, ok := index.Entries[path]; ok && entry.DuplicateOf != "" {
+			continue
+		}
+		kept = append(kept, path)
+	}
+	return kept
+}
+
+// halted reports whether the slideshow is not changing wallpapers
+func (s *slideshow) halted() bool {
+	return s.paused || s.fullscreen
+}
+
+// update applies a pause change, keeping the time left until the next
+// change while halted
+func (s *slideshow) update(change func()) {
+	wasHalted := s.halted()
+	change()
+
+	switch {
+	case !wasHalted && s.halted():
+		s.remaining = max(0, time.Until(s.next))
+	case wasHalted && !s.halted():
+		s.next = time.Now().Add(s.remaining)
+	}
+}
+
+// watchFullscreen subscribes to the Hyprland events that change whether a
+// fullscreen window is visible, or returns nil outside Hyprland
+func (s *slideshow) watchFullscreen() <-chan hypr.Event {
+	if !hypr.IsRunning() {
+		return nil
+	}
+
+	client, err := hypr.NewClient()
+	if err != nil {
+		return nil
+	}
+	events, err := client.Subscribe([]string{"fullscreen", "workspace", "focusedmon"})
+	if err != nil {
+		logger.Warn("Not pausing for fullscreen windows", "error", err)
+		return nil
+	}
+
+	if workspace, err := client.GetActiveWorkspace(); err == nil {
+		s.fullscreen = workspace.HasFullscreen
+	}
+	return events
+}
+
+// handleEvent pauses or resumes for a Hyprland event. Switching workspaces
+// or monitors is checked against the newly active workspace.
+func (s *slideshow) handleEvent(event hypr.Event) {
+	fullscreen := event.Data == "1"
+	if event.Type != "fullscreen" {
+		client, err := hypr.NewClient()
+		if err != nil {
+			return
+		}
+		workspace, err := client.GetActiveWorkspace()
+		if err != nil {
+			return
+		}
+		fullscreen = workspace.HasFullscreen
+	}
+
+	if fullscreen != s.fullscreen {
+		logger.Info("Fullscreen changed", "fullscreen", fullscreen)
+		s.update(func() { s.fullscreen = fullscreen })
+	}
+}
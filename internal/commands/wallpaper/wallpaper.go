@@ -3,23 +3,18 @@ package wallpaper
 import (
 	"encoding/json"
 	"fmt"
-	"image"
-	_ "image/jpeg"
-	_ "image/png"
 	"math/rand"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
-	_ "golang.org/x/image/webp"
-
 	"github.com/arthur404dev/heimdall-cli/internal/config"
 	"github.com/arthur404dev/heimdall-cli/internal/scheme"
 	"github.com/arthur404dev/heimdall-cli/internal/scheme/generator"
 	"github.com/arthur404dev/heimdall-cli/internal/theme"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/hypr"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/imageio"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/material"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/notify"
@@ -39,6 +34,23 @@ func Command() *cobra.Command {
 		threshold float64 // -t, --threshold FLOAT - Minimum size ratio for wallpaper selection
 		noSmart   bool    // -N, --no-smart - Disable automatic mode/variant detection
 
+		// Seed color selection
+		candidates bool // --candidates - List ranked seed color candidates
+		jsonOutput bool // --json - Output candidates as JSON
+		seedIndex  int  // --seed-index N - Use the Nth candidate as seed
+		pickSeed   bool // --pick-seed - Choose the seed with fuzzel
+		resetSeed  bool // --reset-seed - Forget the remembered seed
+
+		noCache bool // --no-cache - Extract colors again instead of using the palette cache
+
+		monitor string // -m, --monitor NAME - Target a single monitor
+
+		query       wallpaper.IndexQuery // --mode, --min-width, ... - Library query for random selection
+		matchScheme bool                 // --match - Match random wallpapers to generated schemes too
+
+		previous   bool // --previous - Restore the wallpaper before the current one
+		favourites bool // --favourites - Pick a random favourite wallpaper
+
 		// Legacy flags (deprecated)
 		legacyFilter   bool // --filter (deprecated, use --no-filter instead)
 		generateScheme bool // -s, --scheme (deprecated, use smart detection)
@@ -57,13 +69,24 @@ Caelestia-compatible interface:
   - Automatic light/dark mode detection based on wallpaper brightness
   - JSON output for color schemes
 
+Wallpapers are shown with hyprpaper, swww, swaybg or mpvpaper, chosen by
+wallpaper.backend or detected, per monitor with -m. The seed color chosen
+with --seed-index or --pick-seed is remembered per wallpaper. Random
+selection uses the library index (see 'heimdall wallpaper index').
+
 Examples:
   heimdall wallpaper                           # Get current wallpaper path
   heimdall wallpaper -f ~/Pictures/sunset.jpg # Set specific wallpaper
   heimdall wallpaper -r                        # Random wallpaper from default directory
   heimdall wallpaper -r ~/Wallpapers          # Random from custom directory
   heimdall wallpaper -p ~/Pictures/test.jpg   # Extract colors without changing wallpaper
-  heimdall wallpaper -f ~/Pictures/dark.jpg -N # Set wallpaper without smart mode detection`,
+  heimdall wallpaper -f ~/Pictures/dark.jpg -N # Set wallpaper without smart mode detection
+  heimdall wallpaper -f ~/Pictures/a.jpg -m DP-1 # Set the wallpaper of one monitor
+  heimdall wallpaper --candidates              # List seed colors of the current wallpaper
+  heimdall wallpaper -r --mode dark --hue blue # Random dark, mostly blue wallpaper
+  heimdall wallpaper --previous                # Undo the last wallpaper change
+  heimdall wallpaper --favourites              # Random favourite wallpaper`,
+		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Load configuration
 			if err := config.Load(); err != nil {
@@ -81,27 +104,67 @@ Examples:
 			if info {
 				logger.Warn("Flag --info is deprecated, use --print instead")
 				if len(args) > 0 {
-					return printColorScheme(args[0])
+					return printColorScheme(args[0], seedOptions{})
+				}
+			}
+
+			if seedIndex < 0 {
+				return fmt.Errorf("--seed-index must be 1 or greater")
+			}
+			if err := validateMonitor(monitor); err != nil {
+				return err
+			}
+			if err := validateQuery(query); err != nil {
+				return err
+			}
+			if (!query.Empty() || matchScheme) && !cmd.Flags().Changed("random") && !favourites {
+				return fmt.Errorf("--mode, --min-width, --min-height, --hue, --tag and --match select random wallpapers and need --random or --favourites")
+			}
+
+			opts := setOptions{
+				smart:   !noSmart,
+				monitor: monitor,
+				seed:    seedOptions{Index: seedIndex, Pick: pickSeed, Reset: resetSeed},
+				noCache: noCache,
+				query:   query,
+				match:   matchScheme,
+				record:  true,
+			}
+
+			// Handle seed candidate listing
+			if candidates {
+				target := printPath
+				if target == "" {
+					target = filePath
 				}
+				return showSeedCandidates(target, jsonOutput, opts.noCache)
 			}
 
 			// Handle color extraction/printing
 			if printPath != "" {
-				return printColorScheme(printPath)
+				return printColorScheme(printPath, opts.seed)
 			}
 			if cmd.Flags().Changed("print") && printPath == "" {
 				// -p without argument uses current wallpaper
-				return printCurrentWallpaperScheme()
+				return printCurrentWallpaperScheme(opts.seed)
 			}
 
 			// Handle file setting
 			if filePath != "" {
-				return setWallpaper(filePath, !noSmart)
+				return setWallpaper(filePath, opts)
+			}
+
+			// Handle undo and the favourites pool
+			if previous {
+				return setPreviousWallpaper(opts)
+			}
+			if favourites {
+				return setRandomFavourite(cfg, randomDir, opts)
 			}
 
 			// Handle random wallpaper selection
 			if randomDir != "" {
-				return setRandomWallpaperFromDir(cfg, randomDir, !noFilter, threshold, !noSmart)
+				return setRandomWallpaperFromDir(cfg, randomDir, !noFilter, threshold, opts)
 			}
 			if cmd.Flags().Changed("random") && randomDir == "" {
 				// -r without argument uses default directory
@@ -109,11 +172,11 @@ Examples:
 				if defaultDir == "" {
 					defaultDir = paths.WallpapersDir
 				}
-				return setRandomWallpaperFromDir(cfg, defaultDir, !noFilter, threshold, !noSmart)
+				return setRandomWallpaperFromDir(cfg, defaultDir, !noFilter, threshold, opts)
 			}
 
 			// No flags provided - return current wallpaper path
-			return getCurrentWallpaper()
+			return getCurrentWallpaper(monitor)
 		},
 	}
 
@@ -125,6 +188,27 @@ Examples:
 	cmd.Flags().Float64VarP(&threshold, "threshold", "t", 0.8, "Minimum size ratio for wallpaper selection")
 	cmd.Flags().BoolVarP(&noSmart, "no-smart", "N", false, "Disable automatic mode/variant detection")
 
+	// Seed color selection
+	cmd.Flags().BoolVar(&candidates, "candidates", false, "List ranked seed color candidates (current wallpaper unless -p/-f is given)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output seed candidates in JSON format")
+	cmd.Flags().IntVar(&seedIndex, "seed-index", 0, "Use the Nth seed candidate (1 = best) and remember it for this wallpaper")
+	cmd.Flags().BoolVar(&pickSeed, "pick-seed", false, "Choose the seed color with fuzzel and remember it for this wallpaper")
+	cmd.Flags().BoolVar(&resetSeed, "reset-seed", false, "Forget the remembered seed color of the wallpaper")
+
+	// Library queries for random selection
+	addQueryFlags(cmd.Flags(), &query)
+	cmd.Flags().BoolVar(&matchScheme, "match", false, "Pick a random wallpaper matching the current scheme, even a generated one")
+
+	// Monitor targeting
+	cmd.Flags().StringVarP(&monitor, "monitor", "m", "", "Set or print the wallpaper of a single monitor (e.g. DP-1)")
+
+	// History and favourites
+	cmd.Flags().BoolVar(&previous, "previous", false, "Restore the wallpaper set before the current one")
+	cmd.Flags().BoolVar(&favourites, "favourites", false, "Pick a random favourite wallpaper (from --random DIR when given)")
+
+	// Palette cache
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Extract colors again instead of using the palette cache")
+
 	// Legacy flags (deprecated but maintained for backward compatibility)
 	cmd.Flags().BoolVar(&legacyFilter, "filter", false, "Filter by colourfulness (deprecated)")
 	cmd.Flags().BoolVarP(&generateScheme, "scheme", "s", false, "Generate Material You scheme (deprecated)")
@@ -135,11 +219,51 @@ Examples:
 	cmd.Flags().MarkHidden("scheme")
 	cmd.Flags().MarkHidden("info")
 
+	cmd.AddCommand(cacheCommand())
+	cmd.AddCommand(indexCommand())
+	cmd.AddCommand(slideshowCommand())
+	cmd.AddCommand(dynamicCommand())
+	cmd.AddCommand(dedupeCommand())
+	cmd.AddCommand(fetchCommand())
+	cmd.AddCommand(deriveCommand())
+	cmd.AddCommand(historyCommand())
+	cmd.AddCommand(favouriteCommand("fav", "Add a wallpaper to the favourites", true))
+	cmd.AddCommand(favouriteCommand("unfav", "Remove a wallpaper from the favourites", false))
+
 	return cmd
 }
 
-// getCurrentWallpaper returns the current wallpaper path
-func getCurrentWallpaper() error {
+// setOptions are the flags of a wallpaper change. They are passed down
+// explicitly, so a slideshow or dynamic wallpaper daemon never carries the
+// flags of one change over to the next.
+type setOptions struct {
+	smart   bool                 // Generate a scheme with mode and variant detection
+	monitor string               // Monitor to set the wallpaper of; empty for all
+	seed    seedOptions          // Seed color choice
+	noCache bool                 // Extract colors again instead of using the palette cache
+	query   wallpaper.IndexQuery // Library query for random selection
+	match   bool                 // Match random wallpapers to the scheme, even a generated one
+	record  bool                 // Add the change to the wallpaper history
+	variant string               // Generated variant to apply instead of the preferred one
+	mode    string               // Scheme mode to apply instead of the preferred or detected one
+}
+
+// getCurrentWallpaper returns the current wallpaper path, or the wallpaper
+// of monitor when it is set
+func getCurrentWallpaper(monitor string) error {
+	if monitor != "" {
+		state, err := wallpaper.LoadMonitorState(wallpaper.DefaultMonitorStatePath())
+		if err != nil {
+			return err
+		}
+		path, ok := state.Wallpaper(monitor)
+		if !ok {
+			return fmt.Errorf("no wallpaper set on monitor %s", monitor)
+		}
+		fmt.Println(path)
+		return nil
+	}
+
 	linkPath := paths.WallpaperLinkPath
 	if linkPath == "" {
 		linkPath = filepath.Join(paths.StateDir, "current_wallpaper")
@@ -161,7 +285,17 @@ func getCurrentWallpaper() error {
 }
 
 // printCurrentWallpaperScheme prints the color scheme of the current wallpaper
-func printCurrentWallpaperScheme() error {
+func printCurrentWallpaperScheme(seed seedOptions) error {
+	target, err := currentWallpaperPath()
+	if err != nil {
+		return err
+	}
+
+	return printColorScheme(target, seed)
+}
+
+// currentWallpaperPath resolves the current wallpaper symlink
+func currentWallpaperPath() (string, error) {
 	linkPath := paths.WallpaperLinkPath
 	if linkPath == "" {
 		linkPath = filepath.Join(paths.StateDir, "current_wallpaper")
@@ -169,20 +303,20 @@ func printCurrentWallpaperScheme() error {
 
 	// Check if symlink exists
 	if _, err := os.Lstat(linkPath); err != nil {
-		return fmt.Errorf("no wallpaper currently set")
+		return "", fmt.Errorf("no wallpaper currently set")
 	}
 
 	// Read the symlink target
 	target, err := os.Readlink(linkPath)
 	if err != nil {
-		return fmt.Errorf("failed to read current wallpaper: %w", err)
+		return "", fmt.Errorf("failed to read current wallpaper: %w", err)
 	}
 
-	return printColorScheme(target)
+	return target, nil
 }
 
 // printColorScheme extracts and prints the color scheme from a wallpaper in JSON format
-func printColorScheme(wallpaperPath string) error {
+func printColorScheme(wallpaperPath string, seedOpts seedOptions) error {
 	// Expand path
 	if strings.HasPrefix(wallpaperPath, "~/") {
 		home, _ := os.UserHomeDir()
@@ -194,17 +328,10 @@ func printColorScheme(wallpaperPath string) error {
 		return fmt.Errorf("wallpaper not found: %w", err)
 	}
 
-	// Open image
-	file, err := os.Open(wallpaperPath)
-	if err != nil {
-		return fmt.Errorf("failed to open wallpaper: %w", err)
-	}
-	defer file.Close()
-
 	// Decode image
-	img, _, err := image.Decode(file)
+	img, err := imageio.Decode(wallpaperPath)
 	if err != nil {
-		return fmt.Errorf("failed to decode image: %w", err)
+		return err
 	}
 
 	// Generate Material You palette
@@ -234,8 +361,19 @@ func printColorScheme(wallpaperPath string) error {
 		variant = "tonalspot"
 	}
 
+	// A picked or remembered seed overrides the automatic one
+	seed := palette.Seed
+	extract := func() (*material.ExtractedColors, error) {
+		return material.NewEnhancedExtractor().ExtractColors(img)
+	}
+	if chosen, ok, err := resolveSeed([]wallpaper.MonitorWallpaper{{Path: wallpaperPath, Weight: 1}}, extract, seedOpts); err != nil {
+		return fmt.Errorf("failed to resolve seed color: %w", err)
+	} else if ok {
+		seed = chosen
+	}
+
 	// Create scheme
-	materialScheme, err := generator.GenerateScheme(palette.Seed, mode == "dark")
+	materialScheme, err := generator.GenerateScheme(seed, mode == "dark")
 	if err != nil {
 		return fmt.Errorf("failed to generate scheme: %w", err)
 	}
@@ -260,18 +398,28 @@ func printColorScheme(wallpaperPath string) error {
 }
 
 // setRandomWallpaperFromDir selects and sets a random wallpaper from a directory
-func setRandomWallpaperFromDir(cfg *config.Config, wallpaperDir string, enableSizeFilter bool, threshold float64, enableSmartMode bool) error {
+func setRandomWallpaperFromDir(cfg *config.Config, wallpaperDir string, enableSizeFilter bool, threshold float64, opts setOptions) error {
 	// Expand home directory
 	if strings.HasPrefix(wallpaperDir, "~/") {
 		home, _ := os.UserHomeDir()
 		wallpaperDir = filepath.Join(home, wallpaperDir[2:])
 	}
 
+	// Pick from the library index when it covers the directory
+	selected, err := randomFromIndex(cfg, wallpaperDir, enableSizeFilter, threshold, opts)
+	if err != nil {
+		return err
+	}
+	if selected != "" {
+		logger.Info("Selected wallpaper", "path", selected)
+		return setWallpaper(selected, opts)
+	}
+
 	// Find all image files
 	var wallpapers []string
-	extensions := []string{".jpg", ".jpeg", ".png", ".webp", ".tif", ".tiff"}
+	extensions := wallpaperExtensions(cfg)
 
-	err := filepath.Walk(wallpaperDir, func(path string, info os.FileInfo, err error) error {
+	err = filepath.Walk(wallpaperDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Skip errors
 		}
@@ -315,28 +463,55 @@ func setRandomWallpaperFromDir(cfg *config.Config, wallpaperDir string, enableSi
 
 	// Select random wallpaper
 	rand.Seed(time.Now().UnixNano())
-	selected := wallpapers[rand.Intn(len(wallpapers))]
+	selected = wallpapers[rand.Intn(len(wallpapers))]
 
 	logger.Info("Selected wallpaper", "path", selected)
 
-	return setWallpaper(selected, enableSmartMode)
+	return setWallpaper(selected, opts)
 }
 
 // filterWallpapersBySize filters wallpapers based on monitor size requirements
 func filterWallpapersBySize(wallpapers []string, threshold float64) ([]string, error) {
+	reqWidth, reqHeight, err := requiredSize(threshold)
+	if err != nil {
+		return wallpapers, err
+	}
+
+	// Filter wallpapers by size
+	var filtered []string
+	analyzer := wallpaper.NewAnalyzer()
+
+	for _, wp := range wallpapers {
+		width, height, err := analyzer.GetDimensions(wp)
+		if err != nil {
+			logger.Warn("Failed to get wallpaper dimensions", "path", wp, "error", err)
+			continue
+		}
+
+		if width >= reqWidth && height >= reqHeight {
+			filtered = append(filtered, wp)
+		}
+	}
+
+	return filtered, nil
+}
+
+// requiredSize returns the minimum wallpaper size for the smallest monitor
+// scaled by threshold
+func requiredSize(threshold float64) (int, int, error) {
 	// Get monitor information via Hyprland IPC
 	client, err := hypr.NewClient()
 	if err != nil {
-		return wallpapers, fmt.Errorf("failed to create Hyprland client: %w", err)
+		return 0, 0, fmt.Errorf("failed to create Hyprland client: %w", err)
 	}
 
 	monitors, err := client.GetMonitors()
 	if err != nil {
-		return wallpapers, fmt.Errorf("failed to get monitors: %w", err)
+		return 0, 0, fmt.Errorf("failed to get monitors: %w", err)
 	}
 
 	if len(monitors) == 0 {
-		return wallpapers, fmt.Errorf("no monitors found")
+		return 0, 0, fmt.Errorf("no monitors found")
 	}
 
 	// Find the smallest monitor dimensions
@@ -356,24 +531,7 @@ func filterWallpapersBySize(wallpapers []string, threshold float64) ([]string, e
 	reqHeight := int(float64(minHeight) * threshold)
 
 	logger.Info("Size filtering criteria", "minWidth", reqWidth, "minHeight", reqHeight, "threshold", threshold)
-
-	// Filter wallpapers by size
-	var filtered []string
-	analyzer := wallpaper.NewAnalyzer()
-
-	for _, wp := range wallpapers {
-		width, height, err := analyzer.GetDimensions(wp)
-		if err != nil {
-			logger.Warn("Failed to get wallpaper dimensions", "path", wp, "error", err)
-			continue
-		}
-
-		if width >= reqWidth && height >= reqHeight {
-			filtered = append(filtered, wp)
-		}
-	}
-
-	return filtered, nil
+	return reqWidth, reqHeight, nil
 }
 
 // setRandomWallpaper selects and sets a random wallpaper (legacy function)
@@ -395,11 +553,11 @@ func setRandomWallpaper(cfg *config.Config, filter bool, threshold float64, gene
 	}
 
 	// Call the new directory-based function
-	return setRandomWallpaperFromDir(cfg, wallpaperDir, enableSizeFilter, sizeThreshold, generateScheme || cfg.Wallpaper.SmartMode)
+	return setRandomWallpaperFromDir(cfg, wallpaperDir, enableSizeFilter, sizeThreshold, setOptions{smart: generateScheme || cfg.Wallpaper.SmartMode, record: true})
 }
 
 // setWallpaper sets a specific wallpaper
-func setWallpaper(wallpaperPath string, enableSmartMode bool) error {
+func setWallpaper(wallpaperPath string, opts setOptions) error {
 	// Expand path
 	if strings.HasPrefix(wallpaperPath, "~/") {
 		home, _ := os.UserHomeDir()
@@ -411,38 +569,29 @@ func setWallpaper(wallpaperPath string, enableSmartMode bool) error {
 		return fmt.Errorf("wallpaper not found: %w", err)
 	}
 
+	// Show the wallpaper on the target monitor, or on all of them
+	backend, err := wallpaperBackend()
+	if err != nil {
+		return err
+	}
+	if err := wallpaper.SetWallpaper(backend, wallpaperPath, opts.monitor); err != nil {
+		return err
+	}
+
 	// Create symlink for current wallpaper
 	linkPath := paths.WallpaperLinkPath
 	if linkPath == "" {
 		linkPath = filepath.Join(paths.StateDir, "current_wallpaper")
 	}
 
-	// Remove old link if exists
-	os.Remove(linkPath)
-
-	// Create new symlink
-	if err := os.Symlink(wallpaperPath, linkPath); err != nil {
+	// Replace the old link, creating the state directory on first use
+	if err := paths.CreateSymlink(wallpaperPath, linkPath); err != nil {
 		logger.Error("Failed to create wallpaper symlink", "error", err)
 	}
 
-	// Set wallpaper using hyprpaper
-	// First try using hyprctl to set wallpaper
-	cmd := exec.Command("hyprctl", "hyprpaper", "wallpaper", fmt.Sprintf(",path=%s", wallpaperPath))
-	if err := cmd.Run(); err != nil {
-		// Try alternative method using swww if available
-		if _, err := exec.LookPath("swww"); err == nil {
-			cmd = exec.Command("swww", "img", wallpaperPath)
-			if err := cmd.Run(); err != nil {
-				logger.Error("Failed to set wallpaper with swww", "error", err)
-			}
-		} else {
-			logger.Error("Failed to set wallpaper", "error", err)
-		}
-	}
-
 	// Generate Material You scheme if smart mode is enabled
-	if enableSmartMode {
-		if err := generateMaterialYouScheme(wallpaperPath); err != nil {
+	if opts.smart {
+		if err := generateMaterialYouScheme(wallpaperPath, opts); err != nil {
 			logger.Error("Failed to generate scheme", "error", err)
 		}
 
@@ -460,14 +609,19 @@ func setWallpaper(wallpaperPath string, enableSmartMode bool) error {
 
 			// Load the preferred variant
 			variant := prefs.PreferredVariant
+			if opts.variant != "" {
+				variant = opts.variant
+			}
 			if variant == "" {
 				variant = "tonal"
 			}
 			mode := prefs.PreferredMode
+			if opts.mode != "" {
+				mode = opts.mode
+			}
 			if mode == "" {
 				// Use detected mode
-				analyzer := wallpaper.NewAnalyzer()
-				mode, _ = analyzer.DetermineMode(wallpaperPath)
+				mode, _ = detectMode(wallpaperPath)
 				if mode == "" {
 					mode = "dark"
 				}
@@ -504,6 +658,14 @@ func setWallpaper(wallpaperPath string, enableSmartMode bool) error {
 		}
 	}
 
+	// Derive lock screen and panel images once the scheme is settled
+	deriveImages(wallpaperPath, opts.monitor)
+
+	// Point the lock screen at the new wallpaper
+	applyLockScreenTheme()
+
+	recordHistory(wallpaperPath, opts)
+
 	// Send notification
 	notifier := notify.NewNotifier()
 	notifier.Send(&notify.Notification{
@@ -512,7 +674,11 @@ func setWallpaper(wallpaperPath string, enableSmartMode bool) error {
 		Urgency: notify.UrgencyNormal,
 	})
 
-	fmt.Printf("Wallpaper set: %s\n", wallpaperPath)
+	if opts.monitor != "" {
+		fmt.Printf("Wallpaper set on %s: %s\n", opts.monitor, wallpaperPath)
+	} else {
+		fmt.Printf("Wallpaper set: %s\n", wallpaperPath)
+	}
 
 	return nil
 }
@@ -552,35 +718,45 @@ func showWallpaperInfo(wallpaperPath string) error {
 	return nil
 }
 
-// generateMaterialYouScheme generates all Material You variants from the wallpaper
-func generateMaterialYouScheme(wallpaperPath string) error {
-	logger.Info("Generating Material You schemes from wallpaper")
+// applyLockScreenTheme regenerates the hyprlock include with the current
+// scheme and wallpaper
+func applyLockScreenTheme() {
+	cfg := config.Get()
+	if cfg == nil || !cfg.Theme.EnableHyprlock {
+		return
+	}
 
-	// Open image
-	file, err := os.Open(wallpaperPath)
+	current, err := currentScheme()
 	if err != nil {
-		return fmt.Errorf("failed to open wallpaper: %w", err)
+		logger.Warn("Failed to get current scheme", "error", err)
+		return
 	}
-	defer file.Close()
 
-	// Decode image
-	img, _, err := image.Decode(file)
-	if err != nil {
-		return fmt.Errorf("failed to decode image: %w", err)
+	applier := theme.NewApplier(paths.ConfigDir, paths.DataDir)
+	if err := applier.ApplyTheme("hyprlock", current.GetColors(), current.Mode); err != nil {
+		logger.Error("Failed to apply theme", "app", "hyprlock", "error", err)
+		return
 	}
+	logger.Info("Applied theme", "app", "hyprlock")
+}
 
-	// Generate all Material You variants
-	wallpaperGen := generator.NewWallpaperGenerator()
-	variants, err := wallpaperGen.GenerateAllVariants(img, wallpaperPath)
+// generateMaterialYouScheme generates all Material You variants from the wallpaper
+func generateMaterialYouScheme(wallpaperPath string, opts setOptions) error {
+	logger.Info("Generating Material You schemes from wallpaper")
+
+	// Extract the colors of every monitor wallpaper that feeds the scheme
+	sources := generationSources(wallpaperPath)
+	generated, err := generateSchemes(wallpaperPath, sources, opts)
 	if err != nil {
-		return fmt.Errorf("failed to generate variants: %w", err)
+		return err
 	}
+	extracted, variants := generated.extracted, generated.variants
+	seed, chosen := generated.seed, generated.chosen
 
-	// Determine preferred mode based on wallpaper
-	analyzer := wallpaper.NewAnalyzer()
-	preferredMode, err := analyzer.DetermineMode(wallpaperPath)
-	if err != nil {
-		preferredMode = "dark" // Default to dark
+	// A dynamic wallpaper frame or a restored change sets the mode
+	preferredMode := generated.mode
+	if opts.mode != "" {
+		preferredMode = opts.mode
 	}
 
 	// Save all variants to user schemes directory
@@ -601,10 +777,13 @@ func generateMaterialYouScheme(wallpaperPath string) error {
 		},
 		"generation": map[string]interface{}{
 			"algorithm":     "enhanced-v2",
-			"detected_mode": preferredMode,
+			"detected_mode": generated.mode,
 		},
 		"variants": make(map[string]interface{}),
 	}
+	if len(sources) > 1 {
+		metadata["source"].(map[string]interface{})["monitors"] = sources
+	}
 
 	// Save each variant
 	for key, variantScheme := range variants {
@@ -661,6 +840,9 @@ func generateMaterialYouScheme(wallpaperPath string) error {
 	// Set the preferred variant as active
 	// Default to "content" variant in preferred mode
 	preferredVariant := "content"
+	if opts.variant != "" {
+		preferredVariant = opts.variant
+	}
 	preferredKey := fmt.Sprintf("%s/%s", preferredVariant, preferredMode)
 
 	var activeScheme *scheme.Scheme
@@ -669,14 +851,13 @@ func generateMaterialYouScheme(wallpaperPath string) error {
 		// Use the preferred variant
 		activeScheme = preferredScheme
 	} else {
-		// Fallback: generate a single scheme using the old method
-		materialGen := material.NewGenerator()
-		palette, err := materialGen.GenerateFromImage(img)
-		if err != nil {
-			return fmt.Errorf("failed to generate palette: %w", err)
+		// Fallback: generate a single scheme from the best seed color
+		if !chosen {
+			seed = extracted.GetBestSeedColor()
 		}
 
-		materialScheme, err := materialGen.GenerateScheme(palette.Seed, preferredMode == "dark")
+		materialGen := material.NewGenerator()
+		materialScheme, err := materialGen.GenerateScheme(seed, preferredMode == "dark")
 		if err != nil {
 			return fmt.Errorf("failed to generate scheme: %w", err)
 		}
@@ -763,10 +944,83 @@ func generateMaterialYouScheme(wallpaperPath string) error {
 	return nil
 }
 
+// generatedSchemes are the colors of a wallpaper and the schemes generated
+// from them
+type generatedSchemes struct {
+	extracted *material.ExtractedColors
+	variants  map[string]*scheme.Scheme // Keyed by "variant/mode"
+	seed      uint32
+	chosen    bool   // Whether seed was chosen rather than automatic
+	mode      string // Mode detected from the wallpaper
+}
+
+// generateSchemes extracts the colors of the sources and generates all
+// Material You variants and the detected mode, reusing and updating the
+// palette cache
+func generateSchemes(wallpaperPath string, sources []wallpaper.MonitorWallpaper, opts setOptions) (*generatedSchemes, error) {
+	palette, err := loadPalette(sources, opts.noCache)
+	if err != nil {
+		return nil, err
+	}
+	extracted := palette.entry.Extracted
+
+	seed, chosen, err := resolveSeed(sources, func() (*material.ExtractedColors, error) {
+		return extracted, nil
+	}, opts.seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve seed color: %w", err)
+	}
+
+	// Generate all Material You variants, from the chosen seed if there is
+	// one, unless they are cached for the same settings
+	key := schemesKey(seed, chosen)
+	variants := palette.schemes(key)
+	if variants == nil {
+		wallpaperGen := newWallpaperGenerator()
+		if chosen {
+			logger.Info("Using chosen seed color", "seed", argbToHex(seed))
+			variants, err = wallpaperGen.GenerateFromSeed(seed, generator.AllVariants, []string{"dark", "light"})
+		} else {
+			variants, err = wallpaperGen.GenerateAllVariantsFromColors(extracted, wallpaperPath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate variants: %w", err)
+		}
+		palette.setSchemes(key, variants)
+	}
+
+	// Determine preferred mode based on wallpaper
+	mode := palette.entry.Mode
+	if mode == "" {
+		analyzer := wallpaper.NewAnalyzer()
+		mode, err = analyzer.DetermineMode(wallpaperPath)
+		if err != nil {
+			mode = "dark" // Default to dark
+		} else {
+			palette.entry.Mode = mode
+		}
+	}
+	palette.save()
+
+	return &generatedSchemes{extracted: extracted, variants: variants, seed: seed, chosen: chosen, mode: mode}, nil
+}
+
+// newWallpaperGenerator creates a scheme generator with the configured
+// custom colors
+func newWallpaperGenerator() *generator.WallpaperGenerator {
+	wallpaperGen := generator.NewWallpaperGenerator()
+	if cfg := config.Get(); cfg != nil {
+		if err := wallpaperGen.SetCustomColors(cfg.Scheme.CustomColors); err != nil {
+			logger.Warn("Ignoring custom colors", "error", err)
+		}
+	}
+	return wallpaperGen
+}
+
 // convertMaterialColors converts Material You colors to complete Heimdall format (122 colors)
 func convertMaterialColors(ms *material.Scheme) map[string]string {
 	// Use the new generator to create a full scheme
-	generator := generator.NewWallpaperGenerator()
+	generator := newWallpaperGenerator()
 
 	// Determine if dark mode based on background luminance
 	isDark := isColorDark(argbToHex(ms.Background))
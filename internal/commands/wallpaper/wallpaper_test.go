@@ -11,6 +11,7 @@ import (
 	"github.com/arthur404dev/heimdall-cli/internal/config"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/hypr"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
 )
 
 // MockHyprClient is a simple mock implementation for testing
@@ -48,6 +49,21 @@ func (m *MockHyprClient) Close() error {
 	return nil
 }
 
+// useFakeBackend puts a swww that accepts every request on the PATH and
+// keeps the monitor state in a temporary directory
+func useFakeBackend(t *testing.T) {
+	t.Helper()
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "swww"), []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	originalStateDir := paths.HeimdallStateDir
+	paths.HeimdallStateDir = t.TempDir()
+	t.Cleanup(func() { paths.HeimdallStateDir = originalStateDir })
+}
+
 func TestCommand(t *testing.T) {
 	cmd := Command()
 
@@ -166,7 +182,7 @@ func TestGetCurrentWallpaper(t *testing.T) {
 				t.Fatalf("Setup failed: %v", err)
 			}
 
-			err = getCurrentWallpaper()
+			err = getCurrentWallpaper("")
 
 			if tt.expectError {
 				if err == nil {
@@ -222,7 +238,7 @@ func TestPrintColorScheme(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := printColorScheme(tt.imagePath)
+			err := printColorScheme(tt.imagePath, seedOptions{})
 
 			if tt.expectError {
 				if err == nil {
@@ -290,6 +306,7 @@ func TestColorConversion(t *testing.T) {
 }
 
 func TestSetWallpaper(t *testing.T) {
+	useFakeBackend(t)
 	tempDir := t.TempDir()
 	originalStateDir := paths.StateDir
 	paths.StateDir = tempDir
@@ -342,7 +359,7 @@ func TestSetWallpaper(t *testing.T) {
 			linkPath := filepath.Join(tempDir, "current_wallpaper")
 			os.Remove(linkPath)
 
-			err := setWallpaper(tt.wallpaperPath, tt.enableSmartMode)
+			err := setWallpaper(tt.wallpaperPath, setOptions{smart: tt.enableSmartMode})
 
 			if tt.expectError {
 				if err == nil {
@@ -453,6 +470,7 @@ func TestFilterWallpapersBySize(t *testing.T) {
 }
 
 func TestSetRandomWallpaperFromDir(t *testing.T) {
+	useFakeBackend(t)
 	tempDir := t.TempDir()
 	wallpaperDir := filepath.Join(tempDir, "wallpapers")
 	err := os.MkdirAll(wallpaperDir, 0755)
@@ -529,7 +547,7 @@ func TestSetRandomWallpaperFromDir(t *testing.T) {
 				},
 			}
 
-			err := setRandomWallpaperFromDir(cfg, tt.wallpaperDir, tt.enableSizeFilter, tt.threshold, tt.enableSmartMode)
+			err := setRandomWallpaperFromDir(cfg, tt.wallpaperDir, tt.enableSizeFilter, tt.threshold, setOptions{smart: tt.enableSmartMode})
 
 			if tt.expectError {
 				if err == nil {
@@ -756,3 +774,104 @@ func TestJSONOutput(t *testing.T) {
 		}
 	}
 }
+
+func TestRecordHistory(t *testing.T) {
+	tempDir := t.TempDir()
+	originalStateDir := paths.HeimdallStateDir
+	paths.HeimdallStateDir = tempDir
+	defer func() { paths.HeimdallStateDir = originalStateDir }()
+	t.Chdir(tempDir)
+
+	// Only changes with record set are kept, with absolute paths
+	recordHistory("frame.png", setOptions{})
+	recordHistory("wall.png", setOptions{record: true, monitor: "DP-1"})
+
+	history, err := wallpaper.LoadHistory(wallpaper.DefaultHistoryPath())
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if len(history.Entries) != 1 {
+		t.Fatalf("history = %+v, want one entry", history.Entries)
+	}
+	if entry := history.Entries[0]; entry.Path != filepath.Join(tempDir, "wall.png") || entry.Monitor != "DP-1" {
+		t.Errorf("entry = %+v, want the absolute path on DP-1", entry)
+	}
+}
+
+func TestControlSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	socket := controlSocket{name: "test daemon", use: "test", path: func() string { return socketPath }}
+
+	if err := socket.send("status", &slideshowStatus{}); err == nil || err.Error() != "test daemon is not running" {
+		t.Fatalf("send() without a daemon error = %v", err)
+	}
+
+	// A stale socket file is replaced
+	if err := os.WriteFile(socketPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := socket.listen()
+	if err != nil {
+		t.Fatalf("listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	requests := make(chan controlRequest)
+	go socket.serve(listener, requests)
+	go func() {
+		for req := range requests {
+			status := slideshowStatus{PID: 42, Current: req.command}
+			if req.command == "fail" {
+				status.Error = "failed on purpose"
+			}
+			req.reply <- status
+		}
+	}()
+
+	if _, err := socket.listen(); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("second listen() error = %v, want already running", err)
+	}
+
+	var status slideshowStatus
+	if err := socket.send("status", &status); err != nil || status.PID != 42 || status.Current != "status" {
+		t.Errorf("send(status) = %+v, %v", status, err)
+	}
+	if err := socket.send("fail", &status); err == nil || err.Error() != "failed on purpose" {
+		t.Errorf("send(fail) error = %v", err)
+	}
+}
+
+func TestFrameSources(t *testing.T) {
+	sources := []wallpaper.MonitorWallpaper{
+		{Monitor: "DP-1", Path: "/d/dawn.png", Weight: 0.7},
+		{Monitor: "HDMI-A-1", Path: "/w/other.png", Weight: 0.3},
+	}
+
+	got := frameSources(sources, "/d/dawn.png", "/d/night.png")
+	if got[0].Path != "/d/night.png" || got[0].Weight != 0.7 || got[1].Path != "/w/other.png" {
+		t.Errorf("frameSources() = %+v", got)
+	}
+	if sources[0].Path != "/d/dawn.png" {
+		t.Error("frameSources() changed its input")
+	}
+}
+
+func TestSetWallpaperWithoutBackend(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	tempDir := t.TempDir()
+	originalLinkPath := paths.WallpaperLinkPath
+	paths.WallpaperLinkPath = filepath.Join(tempDir, "current")
+	defer func() { paths.WallpaperLinkPath = originalLinkPath }()
+
+	wallpaperPath := filepath.Join(tempDir, "wall.png")
+	if err := os.WriteFile(wallpaperPath, []byte("fake image data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := setWallpaper(wallpaperPath, setOptions{}); err == nil || !strings.Contains(err.Error(), "no wallpaper backend") {
+		t.Errorf("setWallpaper() error = %v, want no wallpaper backend", err)
+	}
+	if _, err := os.Lstat(paths.WallpaperLinkPath); !os.IsNotExist(err) {
+		t.Error("the current wallpaper link changed although nothing was shown")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -46,6 +47,7 @@ type ThemeConfig struct {
 	EnableAlacritty bool             `mapstructure:"enableAlacritty" json:"enableAlacritty" yaml:"enableAlacritty" desc:"Apply themes to Alacritty terminal emulator" default:"false" example:"true"`
 	EnableWezterm   bool             `mapstructure:"enableWezterm" json:"enableWezterm" yaml:"enableWezterm" desc:"Apply themes to WezTerm terminal emulator" default:"false" example:"true"`
 	EnableNvim      bool             `mapstructure:"enableNvim" json:"enableNvim" yaml:"enableNvim" desc:"Apply themes to Neovim editor (LazyVim integration)" default:"true" example:"true"`
+	EnableHyprlock  bool             `mapstructure:"enableHyprlock" json:"enableHyprlock" yaml:"enableHyprlock" desc:"Apply themes and the current wallpaper to the hyprlock lock screen" default:"false" example:"true"`
 	Paths           ThemePathsConfig `mapstructure:"paths" json:"paths" yaml:"paths" desc:"Custom paths for theme configuration files"`
 }
 
@@ -62,6 +64,7 @@ type ThemePathsConfig struct {
 	Alacritty     string `mapstructure:"alacritty" json:"alacritty" yaml:"alacritty" desc:"Path to Alacritty theme TOML file" example:"~/.config/alacritty/themes/heimdall.toml"`
 	Wezterm       string `mapstructure:"wezterm" json:"wezterm" yaml:"wezterm" desc:"Path to WezTerm color scheme Lua file" example:"~/.config/wezterm/colors/heimdall.lua"`
 	Nvim          string `mapstructure:"nvim" json:"nvim" yaml:"nvim" desc:"Path to Neovim LazyVim theme plugin file" example:"~/.config/nvim/lua/user/heimdall.lua"`
+	Hyprlock      string `mapstructure:"hyprlock" json:"hyprlock" yaml:"hyprlock" desc:"Path to hyprlock include with lock screen colors and wallpaper" example:"~/.config/hypr/heimdall/hyprlock.conf"`
 	Terminal      string `mapstructure:"terminal" json:"terminal" yaml:"terminal" desc:"Path to terminal escape sequences file" example:"~/.config/heimdall/sequences.txt"`
 	Vesktop       string `mapstructure:"vesktop" json:"vesktop" yaml:"vesktop" desc:"Path to Vesktop theme CSS file" example:"~/.config/vesktop/themes/heimdall.css"`
 	Discord       string `mapstructure:"discord" json:"discord" yaml:"discord" desc:"Path to Discord theme CSS file" example:"~/.config/discord/themes/heimdall.css"`
@@ -97,20 +100,85 @@ type ShellConfig struct {
 
 // SchemeConfig represents scheme configuration
 type SchemeConfig struct {
-	Default       string   `mapstructure:"default" json:"default" yaml:"default" desc:"Default color scheme to use" default:"rosepine" example:"catppuccin-mocha"`
-	AutoMode      bool     `mapstructure:"auto_mode" json:"auto_mode" yaml:"auto_mode" desc:"Automatically switch between light/dark variants based on time" default:"true" example:"true"`
-	MaterialYou   bool     `mapstructure:"material_you" json:"material_you" yaml:"material_you" desc:"Generate Material You color schemes from wallpapers" default:"true" example:"false"`
-	UserPaths     []string `mapstructure:"user_paths" json:"user_paths" yaml:"user_paths" desc:"Additional directories to search for user-defined schemes" example:"[\"~/.config/heimdall/schemes\", \"~/custom-schemes\"]"`
-	GeneratedPath string   `mapstructure:"generated_path" json:"generated_path" yaml:"generated_path" desc:"Directory for storing generated Material You schemes" example:"~/.local/share/heimdall/schemes"`
+	Default       string                          `mapstructure:"default" json:"default" yaml:"default" desc:"Default color scheme to use" default:"rosepine" example:"catppuccin-mocha"`
+	AutoMode      bool                            `mapstructure:"auto_mode" json:"auto_mode" yaml:"auto_mode" desc:"Automatically switch between light/dark variants based on time" default:"true" example:"true"`
+	MaterialYou   bool                            `mapstructure:"material_you" json:"material_you" yaml:"material_you" desc:"Generate Material You color schemes from wallpapers" default:"true" example:"false"`
+	UserPaths     []string                        `mapstructure:"user_paths" json:"user_paths" yaml:"user_paths" desc:"Additional directories to search for user-defined schemes" example:"[\"~/.config/heimdall/schemes\", \"~/custom-schemes\"]"`
+	GeneratedPath string                          `mapstructure:"generated_path" json:"generated_path" yaml:"generated_path" desc:"Directory for storing generated Material You schemes" example:"~/.local/share/heimdall/schemes"`
+	Auto          SchemeAutoConfig                `mapstructure:"auto" json:"auto" yaml:"auto" desc:"Schedule used by 'heimdall scheme auto' when auto_mode is enabled"`
+	Playlists     map[string]SchemePlaylistConfig `mapstructure:"playlists" json:"playlists" yaml:"playlists" desc:"Named scheme playlists rotated by 'heimdall scheme rotate'"`
+	Repos         []SchemeRepoConfig              `mapstructure:"repos" json:"repos" yaml:"repos" desc:"Git repositories of schemes synced by 'heimdall scheme sync'"`
+	CustomColors  []CustomColorConfig             `mapstructure:"custom_colors" json:"custom_colors" yaml:"custom_colors" desc:"Fixed colors added to generated schemes as NAME, onNAME, NAMEContainer and onNAMEContainer; success, warning and info are built in and can be overridden"`
+}
+
+// CustomColorConfig represents a fixed color added to generated schemes
+type CustomColorConfig struct {
+	Name  string `mapstructure:"name" json:"name" yaml:"name" desc:"Scheme key of the color, letters and digits starting with a lowercase letter" example:"brand"`
+	Color string `mapstructure:"color" json:"color" yaml:"color" desc:"Color in hex" example:"#e64553"`
+	Fixed bool   `mapstructure:"fixed" json:"fixed" yaml:"fixed" desc:"Keep the exact hue instead of harmonizing it toward the seed color" default:"false" example:"true"`
+}
+
+// SchemeRepoConfig represents a git repository of color schemes
+type SchemeRepoConfig struct {
+	Name string `mapstructure:"name" json:"name" yaml:"name" desc:"Local name for the repository (defaults to the last URL segment)" example:"team-schemes"`
+	URL  string `mapstructure:"url" json:"url" yaml:"url" desc:"Repository URL, either remote or file:// for local repositories" example:"https://github.com/team/schemes.git"`
+	Ref  string `mapstructure:"ref" json:"ref" yaml:"ref" desc:"Branch, tag or commit to pin (defaults to the remote HEAD)" example:"v1.2.0"`
+	Path string `mapstructure:"path" json:"path" yaml:"path" desc:"Subdirectory of the repository containing the schemes" example:"schemes"`
+}
+
+// SchemePlaylistConfig represents a named playlist of schemes and its schedule
+type SchemePlaylistConfig struct {
+	Schemes  []string `mapstructure:"schemes" json:"schemes" yaml:"schemes" desc:"Entries as name[/flavour[/mode]], or wallpaper:PATH to set a wallpaper and apply its generated scheme" example:"[\"catppuccin/mocha\", \"rosepine/main/dark\", \"wallpaper:~/Pictures/Wallpapers/forest.jpg\"]"`
+	Interval int      `mapstructure:"interval" json:"interval" yaml:"interval" desc:"Rotate every N minutes (0 disables interval rotation)" default:"0" example:"60"`
+	Times    []string `mapstructure:"times" json:"times" yaml:"times" desc:"Times of day (HH:MM) to rotate at" example:"[\"09:00\", \"18:00\"]"`
+	Weekdays []string `mapstructure:"weekdays" json:"weekdays" yaml:"weekdays" desc:"Days the schedule is active (mon, tue, ...); empty means every day" example:"[\"mon\", \"wed\", \"fri\"]"`
+	Shuffle  bool     `mapstructure:"shuffle" json:"shuffle" yaml:"shuffle" desc:"Play entries in random order without repeats until all have played" default:"false" example:"true"`
+}
+
+// SchemeAutoConfig represents the light/dark switching schedule
+type SchemeAutoConfig struct {
+	Trigger   string  `mapstructure:"trigger" json:"trigger" yaml:"trigger" desc:"What drives mode switches: 'time' uses fixed times, 'sun' uses local sunrise/sunset" default:"time" example:"sun"`
+	LightTime string  `mapstructure:"light_time" json:"light_time" yaml:"light_time" desc:"Time (HH:MM) to switch to light mode when trigger is 'time'" default:"07:00" example:"06:30"`
+	DarkTime  string  `mapstructure:"dark_time" json:"dark_time" yaml:"dark_time" desc:"Time (HH:MM) to switch to dark mode when trigger is 'time'" default:"19:00" example:"20:00"`
+	Latitude  float64 `mapstructure:"latitude" json:"latitude" yaml:"latitude" desc:"Latitude used to compute sunrise/sunset when trigger is 'sun'" default:"0" example:"52.52"`
+	Longitude float64 `mapstructure:"longitude" json:"longitude" yaml:"longitude" desc:"Longitude used to compute sunrise/sunset when trigger is 'sun'" default:"0" example:"13.405"`
 }
 
 // WallpaperConfig represents wallpaper configuration
 type WallpaperConfig struct {
-	Directory  string   `mapstructure:"directory" json:"directory" yaml:"directory" desc:"Directory containing wallpaper images" example:"~/Pictures/Wallpapers"`
-	Filter     bool     `mapstructure:"filter" json:"filter" yaml:"filter" desc:"Filter wallpapers based on color similarity to current scheme" default:"true" example:"false"`
-	Threshold  float64  `mapstructure:"threshold" json:"threshold" yaml:"threshold" desc:"Color similarity threshold for filtering (0.0-1.0, higher = stricter)" default:"0.8" example:"0.7"`
-	SmartMode  bool     `mapstructure:"smart_mode" json:"smart_mode" yaml:"smart_mode" desc:"Use intelligent wallpaper selection based on scheme colors" default:"true" example:"true"`
-	Extensions []string `mapstructure:"extensions" json:"extensions" yaml:"extensions" desc:"Supported image file extensions" default:"[\".jpg\", \".jpeg\", \".png\", \".webp\"]" example:"[\".jpg\", \".png\"]"`
+	Directory                string                  `mapstructure:"directory" json:"directory" yaml:"directory" desc:"Directory containing wallpaper images" example:"~/Pictures/Wallpapers"`
+	Filter                   bool                    `mapstructure:"filter" json:"filter" yaml:"filter" desc:"Pick random wallpapers among the indexed ones closest in color to the current scheme (fixed schemes only unless --match is given)" default:"true" example:"false"`
+	Threshold                float64                 `mapstructure:"threshold" json:"threshold" yaml:"threshold" desc:"How strictly random wallpapers must match the scheme (0.0-1.0, higher = stricter); 0.8 keeps the closest 20%" default:"0.8" example:"0.7"`
+	SmartMode                bool                    `mapstructure:"smart_mode" json:"smart_mode" yaml:"smart_mode" desc:"Use intelligent wallpaper selection based on scheme colors" default:"true" example:"true"`
+	Extensions               []string                `mapstructure:"extensions" json:"extensions" yaml:"extensions" desc:"Supported image file extensions" default:"[\".jpg\", \".jpeg\", \".png\", \".webp\", \".gif\", \".bmp\", \".tif\", \".tiff\", \".avif\", \".heic\", \".heif\", \".jxl\"]" example:"[\".jpg\", \".png\"]"`
+	MultiMonitor             string                  `mapstructure:"multi_monitor" json:"multi_monitor" yaml:"multi_monitor" desc:"How the wallpapers of several monitors feed scheme generation: area (weighted by monitor area), equal, primary (primary monitor only) or off (last set wallpaper)" default:"area" example:"primary"`
+	PrimaryMonitor           string                  `mapstructure:"primary_monitor" json:"primary_monitor" yaml:"primary_monitor" desc:"Monitor driving the scheme when multi_monitor is primary (lowest monitor ID if empty)" example:"DP-1"`
+	PaletteCache             bool                    `mapstructure:"palette_cache" json:"palette_cache" yaml:"palette_cache" desc:"Cache extracted colors and generated schemes by wallpaper content so known wallpapers switch instantly" default:"true" example:"false"`
+	CacheMaxAge              int                     `mapstructure:"cache_max_age" json:"cache_max_age" yaml:"cache_max_age" desc:"Days an unused palette cache entry is kept by 'heimdall wallpaper cache prune'" default:"90" example:"30"`
+	Backend                  string                  `mapstructure:"backend" json:"backend" yaml:"backend" desc:"Program showing wallpapers: auto, hyprpaper, swww, swaybg or mpvpaper (auto prefers a running hyprpaper or swww daemon)" default:"auto" example:"swww"`
+	Transition               string                  `mapstructure:"transition" json:"transition" yaml:"transition" desc:"swww transition type (simple, fade, wipe, grow, outer, wave, random, ...)" default:"simple" example:"grow"`
+	TransitionDuration       float64                 `mapstructure:"transition_duration" json:"transition_duration" yaml:"transition_duration" desc:"swww transition duration in seconds" default:"1" example:"2.5"`
+	TransitionFPS            int                     `mapstructure:"transition_fps" json:"transition_fps" yaml:"transition_fps" desc:"swww transition frame rate" default:"60" example:"144"`
+	SlideshowInterval        string                  `mapstructure:"slideshow_interval" json:"slideshow_interval" yaml:"slideshow_interval" desc:"Time each wallpaper is shown by 'heimdall wallpaper slideshow' (Go duration)" default:"30m" example:"1h"`
+	SlideshowOrder           string                  `mapstructure:"slideshow_order" json:"slideshow_order" yaml:"slideshow_order" desc:"Slideshow order: shuffle (no repeats until every wallpaper was shown), ordered (by path) or weighted (favourites more often)" default:"shuffle" example:"weighted"`
+	SlideshowPauseFullscreen bool                    `mapstructure:"slideshow_pause_fullscreen" json:"slideshow_pause_fullscreen" yaml:"slideshow_pause_fullscreen" desc:"Pause the slideshow while a fullscreen window is active" default:"true" example:"false"`
+	Derive                   []string                `mapstructure:"derive" json:"derive" yaml:"derive" desc:"Images derived from each new wallpaper for lock screens and panels: blur, dim, tint (scheme surface overlay) and monitors (per-monitor crops), linked next to the current wallpaper as current-<name>" default:"[]" example:"[\"blur\", \"dim\"]"`
+	BlurRadius               float64                 `mapstructure:"blur_radius" json:"blur_radius" yaml:"blur_radius" desc:"Gaussian blur radius of the blur image, in pixels at 1920px" default:"20" example:"30"`
+	DimAmount                float64                 `mapstructure:"dim_amount" json:"dim_amount" yaml:"dim_amount" desc:"How much the dim image darkens in dark mode (0.0-1.0); light mode dims half as much" default:"0.4" example:"0.5"`
+	TintAmount               float64                 `mapstructure:"tint_amount" json:"tint_amount" yaml:"tint_amount" desc:"Opacity of the surface color over the tint image (0.0-1.0)" default:"0.35" example:"0.5"`
+	HistorySize              int                     `mapstructure:"history_size" json:"history_size" yaml:"history_size" desc:"Number of wallpaper changes kept for --previous and the history command" default:"50" example:"100"`
+	Sources                  []WallpaperSourceConfig `mapstructure:"sources" json:"sources" yaml:"sources" desc:"Collections 'heimdall wallpaper fetch' downloads wallpapers from"`
+	Dynamic                  string                  `mapstructure:"dynamic" json:"dynamic" yaml:"dynamic" desc:"Dynamic wallpaper (directory of frames with a dynamic.json manifest) shown by 'heimdall wallpaper dynamic' without an argument" example:"~/Pictures/Dynamic/valley"`
+	FetchDir                 string                  `mapstructure:"fetch_dir" json:"fetch_dir" yaml:"fetch_dir" desc:"Directory fetched wallpapers are saved to (defaults to fetched/ inside the wallpaper directory)" example:"~/Pictures/Wallpapers/fetched"`
+}
+
+// WallpaperSourceConfig represents a collection wallpapers are fetched from
+type WallpaperSourceConfig struct {
+	Name     string `mapstructure:"name" json:"name" yaml:"name" desc:"Name used with 'heimdall wallpaper fetch NAME' (defaults to the provider)" example:"wallhaven"`
+	Provider string `mapstructure:"provider" json:"provider" yaml:"provider" desc:"Kind of collection: wallhaven (Wallhaven-style search API), feed (RSS or Atom feed) or local (directory such as a network share)" example:"feed"`
+	URL      string `mapstructure:"url" json:"url" yaml:"url" desc:"API base URL, feed URL or directory (wallhaven defaults to https://wallhaven.cc)" example:"https://example.com/wallpapers.rss"`
+	APIKey   string `mapstructure:"api_key" json:"api_key" yaml:"api_key" desc:"API key sent to wallhaven searches" example:"abc123"`
+	Query    string `mapstructure:"query" json:"query" yaml:"query" desc:"Search terms used when none are given on the command line" example:"nature landscape"`
 }
 
 // ScreenshotConfig represents screenshot configuration
@@ -193,23 +261,24 @@ type NetworkConfig struct {
 
 // ExternalTools represents external tool paths
 type ExternalTools struct {
-	Grim        string `mapstructure:"grim" json:"grim" yaml:"grim" desc:"Path to grim screenshot tool" default:"grim" example:"/usr/bin/grim"`
-	Slurp       string `mapstructure:"slurp" json:"slurp" yaml:"slurp" desc:"Path to slurp selection tool" default:"slurp" example:"/usr/bin/slurp"`
-	Swappy      string `mapstructure:"swappy" json:"swappy" yaml:"swappy" desc:"Path to swappy screenshot editor" default:"swappy" example:"/usr/bin/swappy"`
-	WlClipboard string `mapstructure:"wl_clipboard" json:"wl_clipboard" yaml:"wl_clipboard" desc:"Path to wl-copy clipboard tool" default:"wl-copy" example:"/usr/bin/wl-copy"`
-	WlScreenrec string `mapstructure:"wl_screenrec" json:"wl_screenrec" yaml:"wl_screenrec" desc:"Path to wl-screenrec recording tool" default:"wl-screenrec" example:"/usr/bin/wl-screenrec"`
-	Cliphist    string `mapstructure:"cliphist" json:"cliphist" yaml:"cliphist" desc:"Path to cliphist clipboard manager" default:"cliphist" example:"/usr/bin/cliphist"`
-	Fuzzel      string `mapstructure:"fuzzel" json:"fuzzel" yaml:"fuzzel" desc:"Path to fuzzel launcher" default:"fuzzel" example:"/usr/bin/fuzzel"`
-	DartSass    string `mapstructure:"dart_sass" json:"dart_sass" yaml:"dart_sass" desc:"Path to Dart Sass compiler" default:"sass" example:"/usr/bin/sass"`
-	Libnotify   string `mapstructure:"libnotify" json:"libnotify" yaml:"libnotify" desc:"Path to notify-send notification tool" default:"notify-send" example:"/usr/bin/notify-send"`
-	Dunstify    string `mapstructure:"dunstify" json:"dunstify" yaml:"dunstify" desc:"Path to dunstify notification tool" default:"dunstify" example:"/usr/bin/dunstify"`
-	Qs          string `mapstructure:"qs" json:"qs" yaml:"qs" desc:"Path to Quickshell executable" default:"qs" example:"/usr/bin/qs"`
-	App2unit    string `mapstructure:"app2unit" json:"app2unit" yaml:"app2unit" desc:"Path to app2unit systemd integration tool" default:"app2unit" example:"/usr/bin/app2unit"`
-	Xclip       string `mapstructure:"xclip" json:"xclip" yaml:"xclip" desc:"Path to xclip X11 clipboard tool" default:"xclip" example:"/usr/bin/xclip"`
-	Pactl       string `mapstructure:"pactl" json:"pactl" yaml:"pactl" desc:"Path to PulseAudio control utility" default:"pactl" example:"/usr/bin/pactl"`
-	Pidof       string `mapstructure:"pidof" json:"pidof" yaml:"pidof" desc:"Path to pidof process finder" default:"pidof" example:"/usr/bin/pidof"`
-	Pkill       string `mapstructure:"pkill" json:"pkill" yaml:"pkill" desc:"Path to pkill process killer" default:"pkill" example:"/usr/bin/pkill"`
-	Gdbus       string `mapstructure:"gdbus" json:"gdbus" yaml:"gdbus" desc:"Path to gdbus D-Bus tool" default:"gdbus" example:"/usr/bin/gdbus"`
+	Grim           string `mapstructure:"grim" json:"grim" yaml:"grim" desc:"Path to grim screenshot tool" default:"grim" example:"/usr/bin/grim"`
+	Slurp          string `mapstructure:"slurp" json:"slurp" yaml:"slurp" desc:"Path to slurp selection tool" default:"slurp" example:"/usr/bin/slurp"`
+	Swappy         string `mapstructure:"swappy" json:"swappy" yaml:"swappy" desc:"Path to swappy screenshot editor" default:"swappy" example:"/usr/bin/swappy"`
+	WlClipboard    string `mapstructure:"wl_clipboard" json:"wl_clipboard" yaml:"wl_clipboard" desc:"Path to wl-copy clipboard tool" default:"wl-copy" example:"/usr/bin/wl-copy"`
+	WlScreenrec    string `mapstructure:"wl_screenrec" json:"wl_screenrec" yaml:"wl_screenrec" desc:"Path to wl-screenrec recording tool" default:"wl-screenrec" example:"/usr/bin/wl-screenrec"`
+	Cliphist       string `mapstructure:"cliphist" json:"cliphist" yaml:"cliphist" desc:"Path to cliphist clipboard manager" default:"cliphist" example:"/usr/bin/cliphist"`
+	Fuzzel         string `mapstructure:"fuzzel" json:"fuzzel" yaml:"fuzzel" desc:"Path to fuzzel launcher" default:"fuzzel" example:"/usr/bin/fuzzel"`
+	DartSass       string `mapstructure:"dart_sass" json:"dart_sass" yaml:"dart_sass" desc:"Path to Dart Sass compiler" default:"sass" example:"/usr/bin/sass"`
+	Libnotify      string `mapstructure:"libnotify" json:"libnotify" yaml:"libnotify" desc:"Path to notify-send notification tool" default:"notify-send" example:"/usr/bin/notify-send"`
+	Dunstify       string `mapstructure:"dunstify" json:"dunstify" yaml:"dunstify" desc:"Path to dunstify notification tool" default:"dunstify" example:"/usr/bin/dunstify"`
+	Qs             string `mapstructure:"qs" json:"qs" yaml:"qs" desc:"Path to Quickshell executable" default:"qs" example:"/usr/bin/qs"`
+	App2unit       string `mapstructure:"app2unit" json:"app2unit" yaml:"app2unit" desc:"Path to app2unit systemd integration tool" default:"app2unit" example:"/usr/bin/app2unit"`
+	Xclip          string `mapstructure:"xclip" json:"xclip" yaml:"xclip" desc:"Path to xclip X11 clipboard tool" default:"xclip" example:"/usr/bin/xclip"`
+	Pactl          string `mapstructure:"pactl" json:"pactl" yaml:"pactl" desc:"Path to PulseAudio control utility" default:"pactl" example:"/usr/bin/pactl"`
+	Pidof          string `mapstructure:"pidof" json:"pidof" yaml:"pidof" desc:"Path to pidof process finder" default:"pidof" example:"/usr/bin/pidof"`
+	Pkill          string `mapstructure:"pkill" json:"pkill" yaml:"pkill" desc:"Path to pkill process killer" default:"pkill" example:"/usr/bin/pkill"`
+	Gdbus          string `mapstructure:"gdbus" json:"gdbus" yaml:"gdbus" desc:"Path to gdbus D-Bus tool" default:"gdbus" example:"/usr/bin/gdbus"`
+	ImageConverter string `mapstructure:"image_converter" json:"image_converter" yaml:"image_converter" desc:"Tool converting AVIF, HEIC and JPEG XL wallpapers to PNG, called as 'tool INPUT OUTPUT.png' (empty to disable)" default:"magick" example:"heif-convert"`
 }
 
 // Global config instance
@@ -239,6 +308,7 @@ func getDefaults() Config {
 			EnableAlacritty: false,
 			EnableWezterm:   false,
 			EnableNvim:      true,
+			EnableHyprlock:  false,
 			Paths: ThemePathsConfig{
 				Gtk3:          filepath.Join(paths.ConfigDir, "gtk-3.0", "colors.css"),                        // GTK uses CSS, colors.css makes sense
 				Gtk4:          filepath.Join(paths.ConfigDir, "gtk-4.0", "colors.css"),                        // GTK uses CSS, colors.css makes sense
@@ -251,6 +321,7 @@ func getDefaults() Config {
 				Alacritty:     filepath.Join(paths.ConfigDir, "alacritty", "themes", "heimdall.toml"),         // Alacritty can import from themes/ dir
 				Wezterm:       filepath.Join(paths.ConfigDir, "wezterm", "colors", "heimdall.lua"),            // WezTerm color schemes go in colors/ dir
 				Nvim:          filepath.Join(paths.ConfigDir, "nvim", "lua", "user", "heimdall.lua"),          // Neovim LazyVim plugin file
+				Hyprlock:      filepath.Join(paths.ConfigDir, "hypr", "heimdall", "hyprlock.conf"),            // Sourced from hyprlock.conf
 				Terminal:      filepath.Join(paths.ConfigDir, "heimdall", "sequences.txt"),                    // Our own sequences file
 				Vesktop:       filepath.Join(paths.ConfigDir, "vesktop", "themes", "heimdall.css"),            // Discord clients use themes/ dir
 				Discord:       filepath.Join(paths.ConfigDir, "discord", "themes", "heimdall.css"),
@@ -274,13 +345,34 @@ func getDefaults() Config {
 			MaterialYou:   true,
 			UserPaths:     []string{filepath.Join(paths.HeimdallConfigDir, "schemes")},
 			GeneratedPath: filepath.Join(paths.DataDir, "schemes"),
+			Auto: SchemeAutoConfig{
+				Trigger:   "time",
+				LightTime: "07:00",
+				DarkTime:  "19:00",
+			},
 		},
 		Wallpaper: WallpaperConfig{
-			Directory:  paths.WallpapersDir,
-			Filter:     true,
-			Threshold:  0.8,
-			SmartMode:  true,
-			Extensions: []string{".jpg", ".jpeg", ".png", ".webp"},
+			Directory: paths.WallpapersDir,
+			Filter:    true,
+			Threshold: 0.8,
+			SmartMode: true,
+			Extensions: []string{".jpg", ".jpeg", ".png", ".webp", ".gif", ".bmp",
+				".tif", ".tiff", ".avif", ".heic", ".heif", ".jxl"},
+			MultiMonitor:             "area",
+			PaletteCache:             true,
+			CacheMaxAge:              90,
+			Backend:                  "auto",
+			Transition:               "simple",
+			TransitionDuration:       1,
+			TransitionFPS:            60,
+			SlideshowInterval:        "30m",
+			SlideshowOrder:           "shuffle",
+			SlideshowPauseFullscreen: true,
+			Derive:                   []string{},
+			BlurRadius:               20,
+			DimAmount:                0.4,
+			TintAmount:               0.35,
+			HistorySize:              50,
 		},
 		Screenshot: ScreenshotConfig{
 			Directory:           paths.ScreenshotsDir,
@@ -351,23 +443,24 @@ func getDefaults() Config {
 			HyprIPCTimeout: 5,
 		},
 		External: ExternalTools{
-			Grim:        "grim",
-			Slurp:       "slurp",
-			Swappy:      "swappy",
-			WlClipboard: "wl-copy",
-			WlScreenrec: "wl-screenrec",
-			Cliphist:    "cliphist",
-			Fuzzel:      "fuzzel",
-			DartSass:    "sass",
-			Libnotify:   "notify-send",
-			Dunstify:    "dunstify",
-			Qs:          "qs",
-			App2unit:    "app2unit",
-			Xclip:       "xclip",
-			Pactl:       "pactl",
-			Pidof:       "pidof",
-			Pkill:       "pkill",
-			Gdbus:       "gdbus",
+			Grim:           "grim",
+			Slurp:          "slurp",
+			Swappy:         "swappy",
+			WlClipboard:    "wl-copy",
+			WlScreenrec:    "wl-screenrec",
+			Cliphist:       "cliphist",
+			Fuzzel:         "fuzzel",
+			DartSass:       "sass",
+			Libnotify:      "notify-send",
+			Dunstify:       "dunstify",
+			Qs:             "qs",
+			App2unit:       "app2unit",
+			Xclip:          "xclip",
+			Pactl:          "pactl",
+			Pidof:          "pidof",
+			Pkill:          "pkill",
+			Gdbus:          "gdbus",
+			ImageConverter: "magick",
 		},
 	}
 }
@@ -656,6 +749,7 @@ func setDefaults() {
 	viper.SetDefault("theme.enableAlacritty", defaults.Theme.EnableAlacritty)
 	viper.SetDefault("theme.enableWezterm", defaults.Theme.EnableWezterm)
 	viper.SetDefault("theme.enableNvim", defaults.Theme.EnableNvim)
+	viper.SetDefault("theme.enableHyprlock", defaults.Theme.EnableHyprlock)
 	viper.SetDefault("theme.paths", defaults.Theme.Paths)
 
 	// Shell defaults
@@ -673,6 +767,11 @@ func setDefaults() {
 	viper.SetDefault("scheme.material_you", defaults.Scheme.MaterialYou)
 	viper.SetDefault("scheme.user_paths", defaults.Scheme.UserPaths)
 	viper.SetDefault("scheme.generated_path", defaults.Scheme.GeneratedPath)
+	viper.SetDefault("scheme.auto.trigger", defaults.Scheme.Auto.Trigger)
+	viper.SetDefault("scheme.auto.light_time", defaults.Scheme.Auto.LightTime)
+	viper.SetDefault("scheme.auto.dark_time", defaults.Scheme.Auto.DarkTime)
+	viper.SetDefault("scheme.auto.latitude", defaults.Scheme.Auto.Latitude)
+	viper.SetDefault("scheme.auto.longitude", defaults.Scheme.Auto.Longitude)
 
 	// Wallpaper defaults
 	viper.SetDefault("wallpaper.directory", defaults.Wallpaper.Directory)
@@ -680,6 +779,24 @@ func setDefaults() {
 	viper.SetDefault("wallpaper.threshold", defaults.Wallpaper.Threshold)
 	viper.SetDefault("wallpaper.smart_mode", defaults.Wallpaper.SmartMode)
 	viper.SetDefault("wallpaper.extensions", defaults.Wallpaper.Extensions)
+	viper.SetDefault("wallpaper.multi_monitor", defaults.Wallpaper.MultiMonitor)
+	viper.SetDefault("wallpaper.primary_monitor", defaults.Wallpaper.PrimaryMonitor)
+	viper.SetDefault("wallpaper.palette_cache", defaults.Wallpaper.PaletteCache)
+	viper.SetDefault("wallpaper.cache_max_age", defaults.Wallpaper.CacheMaxAge)
+	viper.SetDefault("wallpaper.backend", defaults.Wallpaper.Backend)
+	viper.SetDefault("wallpaper.transition", defaults.Wallpaper.Transition)
+	viper.SetDefault("wallpaper.transition_duration", defaults.Wallpaper.TransitionDuration)
+	viper.SetDefault("wallpaper.transition_fps", defaults.Wallpaper.TransitionFPS)
+	viper.SetDefault("wallpaper.slideshow_interval", defaults.Wallpaper.SlideshowInterval)
+	viper.SetDefault("wallpaper.slideshow_order", defaults.Wallpaper.SlideshowOrder)
+	viper.SetDefault("wallpaper.slideshow_pause_fullscreen", defaults.Wallpaper.SlideshowPauseFullscreen)
+	viper.SetDefault("wallpaper.derive", defaults.Wallpaper.Derive)
+	viper.SetDefault("wallpaper.blur_radius", defaults.Wallpaper.BlurRadius)
+	viper.SetDefault("wallpaper.dim_amount", defaults.Wallpaper.DimAmount)
+	viper.SetDefault("wallpaper.tint_amount", defaults.Wallpaper.TintAmount)
+	viper.SetDefault("wallpaper.history_size", defaults.Wallpaper.HistorySize)
+	viper.SetDefault("wallpaper.fetch_dir", defaults.Wallpaper.FetchDir)
+	viper.SetDefault("wallpaper.dynamic", defaults.Wallpaper.Dynamic)
 
 	// Screenshot defaults
 	viper.SetDefault("screenshot.directory", defaults.Screenshot.Directory)
@@ -761,6 +878,7 @@ func setDefaults() {
 	viper.SetDefault("external.pidof", defaults.External.Pidof)
 	viper.SetDefault("external.pkill", defaults.External.Pkill)
 	viper.SetDefault("external.gdbus", defaults.External.Gdbus)
+	viper.SetDefault("external.image_converter", defaults.External.ImageConverter)
 }
 
 // Reload reloads the configuration from file
@@ -828,6 +946,65 @@ func Validate() error {
 		errors = append(errors, "wallpaper.threshold must be between 0 and 100")
 	}
 
+	validMultiMonitor := []string{"area", "equal", "primary", "off"}
+	if c.Wallpaper.MultiMonitor != "" && !contains(validMultiMonitor, c.Wallpaper.MultiMonitor) {
+		errors = append(errors, fmt.Sprintf("wallpaper.multi_monitor must be one of: %v", validMultiMonitor))
+	}
+	if c.Wallpaper.CacheMaxAge < 0 {
+		errors = append(errors, "wallpaper.cache_max_age must be non-negative")
+	}
+	validBackends := []string{"auto", "hyprpaper", "swww", "swaybg", "mpvpaper"}
+	if c.Wallpaper.Backend != "" && !contains(validBackends, c.Wallpaper.Backend) {
+		errors = append(errors, fmt.Sprintf("wallpaper.backend must be one of: %v", validBackends))
+	}
+	if c.Wallpaper.TransitionDuration < 0 || c.Wallpaper.TransitionFPS < 0 {
+		errors = append(errors, "wallpaper.transition_duration and wallpaper.transition_fps must be non-negative")
+	}
+	if c.Wallpaper.SlideshowInterval != "" {
+		if interval, err := time.ParseDuration(c.Wallpaper.SlideshowInterval); err != nil || interval <= 0 {
+			errors = append(errors, "wallpaper.slideshow_interval must be a positive duration (e.g. 30m)")
+		}
+	}
+	validOrders := []string{"shuffle", "ordered", "weighted"}
+	if c.Wallpaper.SlideshowOrder != "" && !contains(validOrders, c.Wallpaper.SlideshowOrder) {
+		errors = append(errors, fmt.Sprintf("wallpaper.slideshow_order must be one of: %v", validOrders))
+	}
+	validDerive := []string{"blur", "dim", "tint", "monitors"}
+	for _, variant := range c.Wallpaper.Derive {
+		if !contains(validDerive, variant) {
+			errors = append(errors, fmt.Sprintf("wallpaper.derive entries must be one of: %v", validDerive))
+			break
+		}
+	}
+	if c.Wallpaper.BlurRadius < 0 {
+		errors = append(errors, "wallpaper.blur_radius must be non-negative")
+	}
+	if c.Wallpaper.DimAmount < 0 || c.Wallpaper.DimAmount > 1 || c.Wallpaper.TintAmount < 0 || c.Wallpaper.TintAmount > 1 {
+		errors = append(errors, "wallpaper.dim_amount and wallpaper.tint_amount must be between 0 and 1")
+	}
+	if c.Wallpaper.HistorySize < 1 {
+		errors = append(errors, "wallpaper.history_size must be at least 1")
+	}
+	validProviders := []string{"wallhaven", "feed", "local"}
+	sourceNames := make(map[string]bool)
+	for i, source := range c.Wallpaper.Sources {
+		if !contains(validProviders, source.Provider) {
+			errors = append(errors, fmt.Sprintf("wallpaper.sources[%d].provider must be one of: %v", i, validProviders))
+			continue
+		}
+		if source.URL == "" && source.Provider != "wallhaven" {
+			errors = append(errors, fmt.Sprintf("wallpaper.sources[%d].url is required", i))
+		}
+		name := source.Name
+		if name == "" {
+			name = source.Provider
+		}
+		if sourceNames[name] {
+			errors = append(errors, fmt.Sprintf("wallpaper.sources has duplicate name %q", name))
+		}
+		sourceNames[name] = true
+	}
+
 	// Validate file formats
 	validImageFormats := []string{"png", "jpg", "jpeg", "webp"}
 	if !contains(validImageFormats, c.Screenshot.FileFormat) {
@@ -845,6 +1022,55 @@ func Validate() error {
 		errors = append(errors, fmt.Sprintf("notification.default_urgency must be one of: %v", validUrgencies))
 	}
 
+	// Validate scheme auto mode schedule
+	validTriggers := []string{"time", "sun"}
+	if !contains(validTriggers, c.Scheme.Auto.Trigger) {
+		errors = append(errors, fmt.Sprintf("scheme.auto.trigger must be one of: %v", validTriggers))
+	}
+	if c.Scheme.Auto.Latitude < -90 || c.Scheme.Auto.Latitude > 90 {
+		errors = append(errors, "scheme.auto.latitude must be between -90 and 90")
+	}
+	if c.Scheme.Auto.Longitude < -180 || c.Scheme.Auto.Longitude > 180 {
+		errors = append(errors, "scheme.auto.longitude must be between -180 and 180")
+	}
+
+	for name, playlist := range c.Scheme.Playlists {
+		if len(playlist.Schemes) == 0 {
+			errors = append(errors, fmt.Sprintf("scheme.playlists.%s must list at least one scheme", name))
+		}
+		if playlist.Interval < 0 {
+			errors = append(errors, fmt.Sprintf("scheme.playlists.%s.interval must be non-negative", name))
+		} else if playlist.Interval == 0 && len(playlist.Times) == 0 {
+			warnings = append(warnings, fmt.Sprintf("scheme.playlists.%s has no interval or times and only rotates manually", name))
+		}
+	}
+
+	repoNames := make(map[string]bool)
+	for i, repo := range c.Scheme.Repos {
+		if repo.URL == "" {
+			errors = append(errors, fmt.Sprintf("scheme.repos[%d].url is required", i))
+			continue
+		}
+		name := repo.LocalName()
+		if repoNames[name] {
+			errors = append(errors, fmt.Sprintf("scheme.repos has duplicate name %q", name))
+		}
+		repoNames[name] = true
+	}
+
+	customNames := make(map[string]bool)
+	for i, custom := range c.Scheme.CustomColors {
+		if !customColorName.MatchString(custom.Name) {
+			errors = append(errors, fmt.Sprintf("scheme.custom_colors[%d].name %q must be letters and digits starting with a lowercase letter", i, custom.Name))
+		} else if customNames[custom.Name] {
+			errors = append(errors, fmt.Sprintf("scheme.custom_colors has duplicate name %q", custom.Name))
+		}
+		customNames[custom.Name] = true
+		if !hexColor.MatchString(custom.Color) {
+			errors = append(errors, fmt.Sprintf("scheme.custom_colors[%d].color %q is not a hex color", i, custom.Color))
+		}
+	}
+
 	// Validate PIP window position
 	validPositions := []string{"top-left", "top-right", "bottom-left", "bottom-right"}
 	if !contains(validPositions, c.PIP.WindowPosition) {
@@ -1029,6 +1255,22 @@ func (c ScreenshotConfig) GetNotificationTimeout() time.Duration {
 	return time.Duration(c.NotificationTimeout) * time.Second
 }
 
+// LocalName returns the name of the repository checkout, defaulting to the
+// last URL segment without .git
+func (c SchemeRepoConfig) LocalName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return strings.TrimSuffix(filepath.Base(strings.TrimRight(c.URL, "/")), ".git")
+}
+
+var (
+	// customColorName matches custom color names usable as scheme keys
+	customColorName = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	// hexColor matches #RRGGBB colors, with or without the hash
+	hexColor = regexp.MustCompile(`^#?[0-9a-fA-F]{6}$`)
+)
+
 // contains checks if a string is in a slice
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -0,0 +1,177 @@
+package scheme
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/solar"
+)
+
+// Auto mode triggers
+const (
+	AutoTriggerTime = "time"
+	AutoTriggerSun  = "sun"
+)
+
+// ModeTransition is a scheduled switch to a light or dark mode
+type ModeTransition struct {
+	Mode string    `json:"mode"`
+	At   time.Time `json:"at"`
+}
+
+// clock is a wall clock time of day
+type clock struct {
+	hour   int
+	minute int
+}
+
+func (c clock) String() string {
+	return fmt.Sprintf("%02d:%02d", c.hour, c.minute)
+}
+
+// on returns the clock time on the calendar day of day
+func (c clock) on(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), c.hour, c.minute, 0, 0, day.Location())
+}
+
+// AutoSchedule decides which mode should be active at a given moment
+type AutoSchedule struct {
+	Trigger   string
+	Latitude  float64
+	Longitude float64
+	lightAt   clock
+	darkAt    clock
+}
+
+// NewAutoSchedule builds a schedule from the scheme auto configuration
+func NewAutoSchedule(cfg config.SchemeAutoConfig) (*AutoSchedule, error) {
+	schedule := &AutoSchedule{
+		Trigger:   strings.ToLower(cfg.Trigger),
+		Latitude:  cfg.Latitude,
+		Longitude: cfg.Longitude,
+	}
+	if schedule.Trigger == "" {
+		schedule.Trigger = AutoTriggerTime
+	}
+
+	switch schedule.Trigger {
+	case AutoTriggerTime:
+		var err error
+		if schedule.lightAt, err = parseClock(cfg.LightTime); err != nil {
+			return nil, fmt.Errorf("invalid light time: %w", err)
+		}
+		if schedule.darkAt, err = parseClock(cfg.DarkTime); err != nil {
+			return nil, fmt.Errorf("invalid dark time: %w", err)
+		}
+		if schedule.lightAt == schedule.darkAt {
+			return nil, fmt.Errorf("light and dark times must differ (both %s)", schedule.lightAt)
+		}
+	case AutoTriggerSun:
+		if err := solar.ValidateCoordinates(cfg.Latitude, cfg.Longitude); err != nil {
+			return nil, err
+		}
+		if cfg.Latitude == 0 && cfg.Longitude == 0 {
+			return nil, fmt.Errorf("latitude and longitude must be configured for the sun trigger")
+		}
+	default:
+		return nil, fmt.Errorf("unknown trigger %q (must be 'time' or 'sun')", cfg.Trigger)
+	}
+
+	return schedule, nil
+}
+
+// parseClock parses an HH:MM time of day
+func parseClock(value string) (clock, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return clock{}, fmt.Errorf("%q is not in HH:MM format", value)
+	}
+
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return clock{}, fmt.Errorf("%q has an invalid hour", value)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return clock{}, fmt.Errorf("%q has an invalid minute", value)
+	}
+
+	return clock{hour: hour, minute: minute}, nil
+}
+
+// ModeAt returns the mode that should be active at t and the next transition
+// after t. The next transition is zero when none occurs within the next two
+// days, which only happens during polar day or night with the sun trigger.
+func (s *AutoSchedule) ModeAt(t time.Time) (string, ModeTransition) {
+	var events []ModeTransition
+	var polarMode string
+
+	for offset := -1; offset <= 2; offset++ {
+		dayEvents, dayPolar := s.transitions(t.AddDate(0, 0, offset))
+		if offset == 0 {
+			polarMode = dayPolar
+		}
+		events = append(events, dayEvents...)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].At.Before(events[j].At)
+	})
+
+	mode := "dark"
+	var next ModeTransition
+	for _, event := range events {
+		if !event.At.After(t) {
+			mode = event.Mode
+			continue
+		}
+		next = event
+		break
+	}
+
+	// The sun neither rises nor sets today, so the whole day has one mode
+	if polarMode != "" {
+		mode = polarMode
+	}
+
+	return mode, next
+}
+
+// transitions returns the switches on the calendar day of day. When the sun
+// trigger hits polar day or night, no switches are returned and the second
+// value holds the mode for the whole day.
+func (s *AutoSchedule) transitions(day time.Time) ([]ModeTransition, string) {
+	if s.Trigger == AutoTriggerSun {
+		times, err := solar.SunTimes(day, s.Latitude, s.Longitude)
+		switch {
+		case errors.Is(err, solar.ErrSunNeverRises):
+			return nil, "dark"
+		case errors.Is(err, solar.ErrSunNeverSets):
+			return nil, "light"
+		case err != nil:
+			return nil, ""
+		}
+		return []ModeTransition{
+			{Mode: "light", At: times.Sunrise},
+			{Mode: "dark", At: times.Sunset},
+		}, ""
+	}
+
+	return []ModeTransition{
+		{Mode: "light", At: s.lightAt.on(day)},
+		{Mode: "dark", At: s.darkAt.on(day)},
+	}, ""
+}
+
+// Describe returns a short human readable summary of the schedule
+func (s *AutoSchedule) Describe() string {
+	if s.Trigger == AutoTriggerSun {
+		return fmt.Sprintf("sunrise/sunset at %.4f, %.4f", s.Latitude, s.Longitude)
+	}
+	return fmt.Sprintf("light at %s, dark at %s", s.lightAt, s.darkAt)
+}
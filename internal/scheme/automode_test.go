@@ -0,0 +1,134 @@
+package scheme
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAutoSchedule(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.SchemeAutoConfig
+		expectError bool
+	}{
+		{
+			name: "fixed times",
+			cfg:  config.SchemeAutoConfig{Trigger: "time", LightTime: "07:00", DarkTime: "19:30"},
+		},
+		{
+			name: "empty trigger defaults to time",
+			cfg:  config.SchemeAutoConfig{LightTime: "07:00", DarkTime: "19:00"},
+		},
+		{
+			name:        "invalid time",
+			cfg:         config.SchemeAutoConfig{Trigger: "time", LightTime: "7am", DarkTime: "19:00"},
+			expectError: true,
+		},
+		{
+			name:        "identical times",
+			cfg:         config.SchemeAutoConfig{Trigger: "time", LightTime: "07:00", DarkTime: "07:00"},
+			expectError: true,
+		},
+		{
+			name: "sun with coordinates",
+			cfg:  config.SchemeAutoConfig{Trigger: "sun", Latitude: 52.52, Longitude: 13.405},
+		},
+		{
+			name:        "sun without coordinates",
+			cfg:         config.SchemeAutoConfig{Trigger: "sun"},
+			expectError: true,
+		},
+		{
+			name:        "unknown trigger",
+			cfg:         config.SchemeAutoConfig{Trigger: "moon"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAutoSchedule(tt.cfg)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAutoScheduleModeAtFixedTimes(t *testing.T) {
+	schedule, err := NewAutoSchedule(config.SchemeAutoConfig{Trigger: "time", LightTime: "07:00", DarkTime: "19:00"})
+	require.NoError(t, err)
+
+	day := func(hour, minute int) time.Time {
+		return time.Date(2024, 5, 10, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		mode     string
+		nextMode string
+		nextAt   time.Time
+	}{
+		{"early morning", day(3, 0), "dark", "light", day(7, 0)},
+		{"exactly at light time", day(7, 0), "light", "dark", day(19, 0)},
+		{"afternoon", day(15, 0), "light", "dark", day(19, 0)},
+		{"evening", day(22, 0), "dark", "light", day(7, 0).AddDate(0, 0, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, next := schedule.ModeAt(tt.at)
+			assert.Equal(t, tt.mode, mode)
+			assert.Equal(t, tt.nextMode, next.Mode)
+			assert.True(t, tt.nextAt.Equal(next.At), "next at %s, want %s", next.At, tt.nextAt)
+		})
+	}
+}
+
+func TestAutoScheduleModeAtOvernightLight(t *testing.T) {
+	// Light period that wraps past midnight
+	schedule, err := NewAutoSchedule(config.SchemeAutoConfig{Trigger: "time", LightTime: "22:00", DarkTime: "06:00"})
+	require.NoError(t, err)
+
+	mode, next := schedule.ModeAt(time.Date(2024, 5, 10, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, "light", mode)
+	assert.Equal(t, "dark", next.Mode)
+	assert.Equal(t, 6, next.At.Hour())
+
+	mode, _ = schedule.ModeAt(time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, "dark", mode)
+}
+
+func TestAutoScheduleModeAtSun(t *testing.T) {
+	schedule, err := NewAutoSchedule(config.SchemeAutoConfig{Trigger: "sun", Latitude: 51.5074, Longitude: -0.1278})
+	require.NoError(t, err)
+
+	mode, next := schedule.ModeAt(time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, "light", mode)
+	assert.Equal(t, "dark", next.Mode)
+	assert.Equal(t, 15, next.At.Hour())
+
+	mode, next = schedule.ModeAt(time.Date(2024, 12, 21, 2, 0, 0, 0, time.UTC))
+	assert.Equal(t, "dark", mode)
+	assert.Equal(t, "light", next.Mode)
+	assert.Equal(t, 8, next.At.Hour())
+}
+
+func TestAutoScheduleModeAtPolar(t *testing.T) {
+	schedule, err := NewAutoSchedule(config.SchemeAutoConfig{Trigger: "sun", Latitude: 78.2232, Longitude: 15.6267})
+	require.NoError(t, err)
+
+	mode, next := schedule.ModeAt(time.Date(2024, 6, 21, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, "light", mode)
+	assert.True(t, next.At.IsZero())
+
+	mode, _ = schedule.ModeAt(time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, "dark", mode)
+}
@@ -273,6 +273,8 @@ func (m *Manager) LoadSchemeWithFallback(name, flavour, mode string) (*Scheme, e
 			scheme.Colours[key] = hexColor
 		}
 
+		scheme.Schema, _ = EnforceSchema(scheme)
+
 		return scheme, nil
 	}
 
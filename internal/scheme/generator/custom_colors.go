@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/color"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/material"
+)
+
+// Fixed hues harmonized toward the seed for terminal colors
+const (
+	ansiGreen  uint32 = 0xFF4CAF50
+	ansiYellow uint32 = 0xFFFFC107
+	ansiOrange uint32 = 0xFFFF9800
+)
+
+// DefaultCustomColors are the semantic colors every generated scheme gets
+var DefaultCustomColors = []material.CustomColor{
+	{Name: "success", Value: 0xFF4CAF50, Blend: true},
+	{Name: "warning", Value: 0xFFFFB300, Blend: true},
+	{Name: "info", Value: 0xFF2196F3, Blend: true},
+}
+
+// customColorName matches custom color names usable as scheme keys, the
+// same rule the config validation applies
+var customColorName = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+// SetCustomColors adds configured custom colors to the defaults; entries
+// named like a default replace it. Other names must not produce keys the
+// scheme already has, such as primary or onSurface.
+func (g *WallpaperGenerator) SetCustomColors(configured []config.CustomColorConfig) error {
+	colors := make([]material.CustomColor, len(DefaultCustomColors))
+	copy(colors, DefaultCustomColors)
+
+	for _, cc := range configured {
+		if !customColorName.MatchString(cc.Name) {
+			return fmt.Errorf("invalid custom color name %q: must be letters and digits starting with a lowercase letter", cc.Name)
+		}
+		if !isDefaultCustomColor(cc.Name) {
+			for _, key := range customColorKeys(cc.Name) {
+				if _, ok := scheme.LookupSchemaKey(key); ok {
+					return fmt.Errorf("custom color %s clashes with the scheme color %s", cc.Name, key)
+				}
+			}
+		}
+
+		parsed, err := color.NewFromHex(cc.Color)
+		if err != nil {
+			return fmt.Errorf("invalid custom color %s: %w", cc.Name, err)
+		}
+
+		custom := material.CustomColor{
+			Name:  cc.Name,
+			Value: 0xFF000000 | uint32(parsed.RGB.R)<<16 | uint32(parsed.RGB.G)<<8 | uint32(parsed.RGB.B),
+			Blend: !cc.Fixed,
+		}
+
+		replaced := false
+		for i := range colors {
+			if colors[i].Name == custom.Name {
+				colors[i] = custom
+				replaced = true
+			}
+		}
+		if !replaced {
+			colors = append(colors, custom)
+		}
+	}
+
+	g.customColors = colors
+	return nil
+}
+
+// isDefaultCustomColor reports whether name is one of DefaultCustomColors
+func isDefaultCustomColor(name string) bool {
+	for _, custom := range DefaultCustomColors {
+		if custom.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// customColorKeys returns the scheme keys of a custom color: the color, its
+// on-color and both containers
+func customColorKeys(name string) [4]string {
+	onName := "on" + strings.ToUpper(name[:1]) + name[1:]
+	return [4]string{name, onName, name + "Container", onName + "Container"}
+}
+
+// addCustomColors adds each custom color and its on-/container roles
+func (g *WallpaperGenerator) addCustomColors(scheme *scheme.Scheme, ms *material.Scheme, isDark bool) {
+	for _, custom := range g.customColors {
+		roles := material.CustomColorRoles(custom, ms.Seed, isDark)
+		keys := customColorKeys(custom.Name)
+
+		scheme.Colours[keys[0]] = argbToHex(roles.Color)
+		scheme.Colours[keys[1]] = argbToHex(roles.OnColor)
+		scheme.Colours[keys[2]] = argbToHex(roles.ColorContainer)
+		scheme.Colours[keys[3]] = argbToHex(roles.OnColorContainer)
+	}
+}
+
+// harmonizedAccent returns a fixed hue harmonized toward the seed, at the
+// tone accents use in the mode
+func harmonizedAccent(value, seed uint32, isDark bool) string {
+	roles := material.CustomColorRoles(material.CustomColor{Value: value, Blend: true}, seed, isDark)
+	return argbToHex(roles.Color)
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+)
+
+func TestSetCustomColors(t *testing.T) {
+	g := NewWallpaperGenerator()
+	err := g.SetCustomColors([]config.CustomColorConfig{
+		{Name: "brand", Color: "#e64553"},
+		{Name: "warning", Color: "#ff8800", Fixed: true}, // Replaces the default
+	})
+	if err != nil {
+		t.Fatalf("SetCustomColors() error = %v", err)
+	}
+	if len(g.customColors) != len(DefaultCustomColors)+1 {
+		t.Fatalf("%d custom colors, want %d", len(g.customColors), len(DefaultCustomColors)+1)
+	}
+
+	variants, err := g.GenerateFromSeed(0xff7aa2f7, []MaterialYouVariant{VariantTonal}, []string{"dark"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"brand", "onBrand", "brandContainer", "onBrandContainer", "warning"} {
+		if variants["tonal/dark"].Colours[key] == "" {
+			t.Errorf("missing custom color key %s", key)
+		}
+	}
+
+	invalid := map[string]config.CustomColorConfig{
+		"empty name": {Name: "", Color: "#e64553"},
+		"uppercase":  {Name: "Brand", Color: "#e64553"},
+		"separator":  {Name: "brand-red", Color: "#e64553"},
+		"core key":   {Name: "primary", Color: "#e64553"},
+		"core color": {Name: "surface", Color: "#e64553"},
+		"bad color":  {Name: "brand", Color: "red"},
+	}
+	for name, cc := range invalid {
+		g := NewWallpaperGenerator()
+		if err := g.SetCustomColors([]config.CustomColorConfig{cc}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		if len(g.customColors) != len(DefaultCustomColors) {
+			t.Errorf("%s: custom colors changed despite the error", name)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 type WallpaperGenerator struct {
 	materialGen       *material.Generator
 	enhancedExtractor *material.EnhancedExtractor
+	customColors      []material.CustomColor
 }
 
 // NewWallpaperGenerator creates a new wallpaper-based scheme generator
@@ -21,6 +22,7 @@ func NewWallpaperGenerator() *WallpaperGenerator {
 	return &WallpaperGenerator{
 		materialGen:       material.NewGenerator(),
 		enhancedExtractor: material.NewEnhancedExtractor(),
+		customColors:      DefaultCustomColors,
 	}
 }
 
@@ -45,7 +47,7 @@ func (g *WallpaperGenerator) GenerateFullScheme(
 	g.addMaterialColors(heimdallScheme, materialScheme)
 
 	// 2. Generate Material Design Fixed variants
-	g.addMaterialFixedVariants(heimdallScheme, materialScheme, isDark)
+	g.addMaterialFixedVariants(heimdallScheme, materialScheme)
 
 	// 3. Generate ANSI terminal colors
 	g.addANSIColors(heimdallScheme, materialScheme, isDark)
@@ -53,8 +55,8 @@ func (g *WallpaperGenerator) GenerateFullScheme(
 	// 4. Generate surface hierarchy (12 levels)
 	g.addSurfaceHierarchy(heimdallScheme, materialScheme, isDark)
 
-	// 5. Generate semantic colors (success, warning)
-	g.addSemanticColors(heimdallScheme, materialScheme, isDark)
+	// 5. Generate custom colors (success, warning, info and configured ones)
+	g.addCustomColors(heimdallScheme, materialScheme, isDark)
 
 	// 6. Generate theme-specific colors (base, mantle, crust, overlays, subtexts)
 	g.addThemeSpecificColors(heimdallScheme, materialScheme, isDark)
@@ -123,52 +125,29 @@ func (g *WallpaperGenerator) addMaterialColors(scheme *scheme.Scheme, ms *materi
 	scheme.Colours["foreground"] = argbToHex(ms.OnBackground)
 }
 
-// addMaterialFixedVariants generates Material Design 3 fixed color variants
-func (g *WallpaperGenerator) addMaterialFixedVariants(scheme *scheme.Scheme, ms *material.Scheme, isDark bool) {
-	primary := scheme.Colours["primary"]
-	secondary := scheme.Colours["secondary"]
-	tertiary := scheme.Colours["tertiary"]
+// addMaterialFixedVariants adds Material Design 3 fixed colors and palette key colors
+func (g *WallpaperGenerator) addMaterialFixedVariants(scheme *scheme.Scheme, ms *material.Scheme) {
+	scheme.Colours["primaryFixed"] = argbToHex(ms.PrimaryFixed)
+	scheme.Colours["primaryFixedDim"] = argbToHex(ms.PrimaryFixedDim)
+	scheme.Colours["onPrimaryFixed"] = argbToHex(ms.OnPrimaryFixed)
+	scheme.Colours["onPrimaryFixedVariant"] = argbToHex(ms.OnPrimaryFixedVariant)
 
-	if isDark {
-		// Dark mode fixed variants
-		scheme.Colours["primaryFixed"] = adjustLightness(primary, 15)
-		scheme.Colours["primaryFixedDim"] = primary
-		scheme.Colours["onPrimaryFixed"] = adjustLightness(primary, -40)
-		scheme.Colours["onPrimaryFixedVariant"] = adjustLightness(primary, -20)
-
-		scheme.Colours["secondaryFixed"] = adjustLightness(secondary, 15)
-		scheme.Colours["secondaryFixedDim"] = secondary
-		scheme.Colours["onSecondaryFixed"] = adjustLightness(secondary, -40)
-		scheme.Colours["onSecondaryFixedVariant"] = adjustLightness(secondary, -20)
-
-		scheme.Colours["tertiaryFixed"] = adjustLightness(tertiary, 15)
-		scheme.Colours["tertiaryFixedDim"] = tertiary
-		scheme.Colours["onTertiaryFixed"] = adjustLightness(tertiary, -40)
-		scheme.Colours["onTertiaryFixedVariant"] = adjustLightness(tertiary, -20)
-	} else {
-		// Light mode fixed variants
-		scheme.Colours["primaryFixed"] = adjustLightness(primary, -10)
-		scheme.Colours["primaryFixedDim"] = adjustLightness(primary, -5)
-		scheme.Colours["onPrimaryFixed"] = adjustLightness(primary, 40)
-		scheme.Colours["onPrimaryFixedVariant"] = adjustLightness(primary, 20)
-
-		scheme.Colours["secondaryFixed"] = adjustLightness(secondary, -10)
-		scheme.Colours["secondaryFixedDim"] = adjustLightness(secondary, -5)
-		scheme.Colours["onSecondaryFixed"] = adjustLightness(secondary, 40)
-		scheme.Colours["onSecondaryFixedVariant"] = adjustLightness(secondary, 20)
-
-		scheme.Colours["tertiaryFixed"] = adjustLightness(tertiary, -10)
-		scheme.Colours["tertiaryFixedDim"] = adjustLightness(tertiary, -5)
-		scheme.Colours["onTertiaryFixed"] = adjustLightness(tertiary, 40)
-		scheme.Colours["onTertiaryFixedVariant"] = adjustLightness(tertiary, 20)
-	}
+	scheme.Colours["secondaryFixed"] = argbToHex(ms.SecondaryFixed)
+	scheme.Colours["secondaryFixedDim"] = argbToHex(ms.SecondaryFixedDim)
+	scheme.Colours["onSecondaryFixed"] = argbToHex(ms.OnSecondaryFixed)
+	scheme.Colours["onSecondaryFixedVariant"] = argbToHex(ms.OnSecondaryFixedVariant)
+
+	scheme.Colours["tertiaryFixed"] = argbToHex(ms.TertiaryFixed)
+	scheme.Colours["tertiaryFixedDim"] = argbToHex(ms.TertiaryFixedDim)
+	scheme.Colours["onTertiaryFixed"] = argbToHex(ms.OnTertiaryFixed)
+	scheme.Colours["onTertiaryFixedVariant"] = argbToHex(ms.OnTertiaryFixedVariant)
 
 	// Add palette key colors
-	scheme.Colours["primary_paletteKeyColor"] = scheme.Colours["primaryContainer"]
-	scheme.Colours["secondary_paletteKeyColor"] = scheme.Colours["secondaryContainer"]
-	scheme.Colours["tertiary_paletteKeyColor"] = scheme.Colours["tertiaryContainer"]
-	scheme.Colours["neutral_paletteKeyColor"] = adjustLightness(scheme.Colours["onSurface"], -30)
-	scheme.Colours["neutral_variant_paletteKeyColor"] = adjustLightness(scheme.Colours["onSurfaceVariant"], -35)
+	scheme.Colours["primary_paletteKeyColor"] = argbToHex(ms.PrimaryPaletteKeyColor)
+	scheme.Colours["secondary_paletteKeyColor"] = argbToHex(ms.SecondaryPaletteKeyColor)
+	scheme.Colours["tertiary_paletteKeyColor"] = argbToHex(ms.TertiaryPaletteKeyColor)
+	scheme.Colours["neutral_paletteKeyColor"] = argbToHex(ms.NeutralPaletteKeyColor)
+	scheme.Colours["neutral_variant_paletteKeyColor"] = argbToHex(ms.NeutralVariantPaletteKeyColor)
 }
 
 // addANSIColors generates ANSI terminal colors from Material palette
@@ -186,13 +165,13 @@ func (g *WallpaperGenerator) addANSIColors(scheme *scheme.Scheme, ms *material.S
 	if !isDarkColor(background) {
 		blackAdjust = 10
 	}
-	scheme.Colours["term0"] = adjustLightness(background, blackAdjust) // Black
-	scheme.Colours["term1"] = error                                    // Red
-	scheme.Colours["term2"] = generateGreen(primary, isDark)           // Green
-	scheme.Colours["term3"] = generateYellow(primary, isDark)          // Yellow
-	scheme.Colours["term4"] = primary                                  // Blue
-	scheme.Colours["term5"] = secondary                                // Magenta
-	scheme.Colours["term6"] = tertiary                                 // Cyan
+	scheme.Colours["term0"] = adjustLightness(background, blackAdjust)      // Black
+	scheme.Colours["term1"] = error                                         // Red
+	scheme.Colours["term2"] = harmonizedAccent(ansiGreen, ms.Seed, isDark)  // Green
+	scheme.Colours["term3"] = harmonizedAccent(ansiYellow, ms.Seed, isDark) // Yellow
+	scheme.Colours["term4"] = primary                                       // Blue
+	scheme.Colours["term5"] = secondary                                     // Magenta
+	scheme.Colours["term6"] = tertiary                                      // Cyan
 	whiteAdjust := float64(-10)
 	if !isDark {
 		whiteAdjust = 10
@@ -216,72 +195,31 @@ func (g *WallpaperGenerator) addANSIColors(scheme *scheme.Scheme, ms *material.S
 	}
 }
 
-// addSurfaceHierarchy generates the complete surface elevation hierarchy
+// addSurfaceHierarchy adds the surface elevation hierarchy
 func (g *WallpaperGenerator) addSurfaceHierarchy(scheme *scheme.Scheme, ms *material.Scheme, isDark bool) {
 	background := scheme.Colours["background"]
-	surface := scheme.Colours["surface"]
 
+	scheme.Colours["surfaceDim"] = argbToHex(ms.SurfaceDim)
+	scheme.Colours["surfaceBright"] = argbToHex(ms.SurfaceBright)
+	scheme.Colours["surfaceContainerLowest"] = argbToHex(ms.SurfaceContainerLowest)
+	scheme.Colours["surfaceContainerLow"] = argbToHex(ms.SurfaceContainerLow)
+	scheme.Colours["surfaceContainer"] = argbToHex(ms.SurfaceContainer)
+	scheme.Colours["surfaceContainerHigh"] = argbToHex(ms.SurfaceContainerHigh)
+	scheme.Colours["surfaceContainerHighest"] = argbToHex(ms.SurfaceContainerHighest)
+	scheme.Colours["surfaceTint"] = argbToHex(ms.SurfaceTint)
+
+	// Additional surface levels
 	if isDark {
-		// Dark mode: progressive lightening for elevation
-		scheme.Colours["surfaceDim"] = adjustLightness(surface, -5)
-		scheme.Colours["surfaceBright"] = adjustLightness(surface, 15)
-		scheme.Colours["surfaceContainerLowest"] = adjustLightness(background, -2)
-		scheme.Colours["surfaceContainerLow"] = adjustLightness(background, 3)
-		scheme.Colours["surfaceContainer"] = adjustLightness(background, 5)
-		scheme.Colours["surfaceContainerHigh"] = adjustLightness(background, 8)
-		scheme.Colours["surfaceContainerHighest"] = adjustLightness(background, 12)
-		scheme.Colours["surfaceTint"] = scheme.Colours["primary"]
-
-		// Additional surface levels
 		scheme.Colours["surface0"] = adjustLightness(background, 5)
 		scheme.Colours["surface1"] = adjustLightness(background, 10)
 		scheme.Colours["surface2"] = adjustLightness(background, 15)
 	} else {
-		// Light mode: subtle variations
-		scheme.Colours["surfaceDim"] = adjustLightness(surface, -8)
-		scheme.Colours["surfaceBright"] = adjustLightness(surface, 5)
-		scheme.Colours["surfaceContainerLowest"] = adjustLightness(background, -3)
-		scheme.Colours["surfaceContainerLow"] = background
-		scheme.Colours["surfaceContainer"] = background
-		scheme.Colours["surfaceContainerHigh"] = adjustLightness(background, 1)
-		scheme.Colours["surfaceContainerHighest"] = adjustLightness(background, 2)
-		scheme.Colours["surfaceTint"] = scheme.Colours["primary"]
-
-		// Additional surface levels
 		scheme.Colours["surface0"] = adjustLightness(background, -2)
 		scheme.Colours["surface1"] = adjustLightness(background, -4)
 		scheme.Colours["surface2"] = adjustLightness(background, -6)
 	}
 }
 
-// addSemanticColors generates semantic colors (success, warning)
-func (g *WallpaperGenerator) addSemanticColors(scheme *scheme.Scheme, ms *material.Scheme, isDark bool) {
-	primary := scheme.Colours["primary"]
-	background := scheme.Colours["background"]
-
-	// Success colors (green-based)
-	successBase := generateGreen(primary, isDark)
-	scheme.Colours["success"] = successBase
-	successContrast := "#ffffff"
-	if isDark {
-		successContrast = "#000000"
-	}
-	scheme.Colours["onSuccess"] = ensureContrast(successBase, successContrast, 4.5)
-	scheme.Colours["successContainer"] = mixColors(successBase, background, 0.7)
-	scheme.Colours["onSuccessContainer"] = ensureContrast(scheme.Colours["successContainer"], successBase, 3.0)
-
-	// Warning colors (yellow/orange-based) - not in standard Material but useful
-	warningBase := generateYellow(primary, isDark)
-	scheme.Colours["warning"] = warningBase
-	warningContrast := "#ffffff"
-	if isDark {
-		warningContrast = "#000000"
-	}
-	scheme.Colours["onWarning"] = ensureContrast(warningBase, warningContrast, 4.5)
-	scheme.Colours["warningContainer"] = mixColors(warningBase, background, 0.7)
-	scheme.Colours["onWarningContainer"] = ensureContrast(scheme.Colours["warningContainer"], warningBase, 3.0)
-}
-
 // addThemeSpecificColors adds theme-specific colors (base, mantle, crust, overlays, subtexts)
 func (g *WallpaperGenerator) addThemeSpecificColors(scheme *scheme.Scheme, ms *material.Scheme, isDark bool) {
 	background := scheme.Colours["background"]
@@ -317,7 +255,7 @@ func (g *WallpaperGenerator) addCatppuccinColors(scheme *scheme.Scheme, ms *mate
 	scheme.Colours["mauve"] = mixColors(scheme.Colours["primary"], scheme.Colours["tertiary"], 0.5)
 	scheme.Colours["red"] = scheme.Colours["error"]
 	scheme.Colours["maroon"] = adjustLightness(scheme.Colours["error"], -10)
-	scheme.Colours["peach"] = generateOrange(scheme.Colours["primary"], scheme.Colours["error"])
+	scheme.Colours["peach"] = harmonizedAccent(ansiOrange, ms.Seed, isDark)
 	scheme.Colours["yellow"] = scheme.Colours["term3"]
 	scheme.Colours["green"] = scheme.Colours["term2"]
 	scheme.Colours["teal"] = scheme.Colours["term6"]
@@ -335,11 +273,14 @@ func (g *WallpaperGenerator) addCompatibilityKeys(scheme *scheme.Scheme) {
 	}
 
 	// Ensure both on_* and on* formats exist
-	keysToCheck := []string{"primary", "secondary", "tertiary", "error", "success", "warning",
+	keysToCheck := []string{"primary", "secondary", "tertiary", "error",
 		"surface", "background", "surfaceVariant", "primaryContainer", "secondaryContainer",
-		"tertiaryContainer", "errorContainer", "successContainer", "warningContainer",
+		"tertiaryContainer", "errorContainer",
 		"primaryFixed", "secondaryFixed", "tertiaryFixed", "primaryFixedVariant",
 		"secondaryFixedVariant", "tertiaryFixedVariant"}
+	for _, custom := range g.customColors {
+		keysToCheck = append(keysToCheck, custom.Name, custom.Name+"Container")
+	}
 
 	for _, key := range keysToCheck {
 		// Check for on* version
@@ -563,55 +504,6 @@ func ensureContrast(bg, fg string, minContrast float64) string {
 	return fg // Fallback
 }
 
-func generateGreen(primary string, isDark bool) string {
-	primaryHSL := hexToHSL(primary)
-	greenHSL := HSL{
-		H: 120, // Green hue
-		S: primaryHSL.S,
-		L: primaryHSL.L,
-	}
-
-	// Adjust for visibility
-	if isDark && greenHSL.L < 50 {
-		greenHSL.L = 50
-	} else if !isDark && greenHSL.L > 50 {
-		greenHSL.L = 40
-	}
-
-	return hslToHex(greenHSL)
-}
-
-func generateYellow(primary string, isDark bool) string {
-	primaryHSL := hexToHSL(primary)
-	yellowHSL := HSL{
-		H: 60, // Yellow hue
-		S: primaryHSL.S,
-		L: primaryHSL.L,
-	}
-
-	// Yellow needs special handling for visibility
-	if isDark {
-		yellowHSL.L = math.Max(60, yellowHSL.L)
-	} else {
-		yellowHSL.L = math.Min(40, yellowHSL.L)
-	}
-
-	return hslToHex(yellowHSL)
-}
-
-func generateOrange(primary, error string) string {
-	primaryHSL := hexToHSL(primary)
-	errorHSL := hexToHSL(error)
-
-	orangeHSL := HSL{
-		H: 30, // Orange hue
-		S: (primaryHSL.S + errorHSL.S) / 2,
-		L: (primaryHSL.L + errorHSL.L) / 2,
-	}
-
-	return hslToHex(orangeHSL)
-}
-
 func toSnakeCase(s string) string {
 	var result strings.Builder
 	for i, r := range s {
@@ -635,8 +527,33 @@ const (
 	VariantFruitSalad MaterialYouVariant = "fruit_salad"
 	VariantRainbow    MaterialYouVariant = "rainbow"
 	VariantNeutral    MaterialYouVariant = "neutral"
+	VariantMonochrome MaterialYouVariant = "monochrome"
 )
 
+// AllVariants lists the Material You variants generated for wallpapers, in
+// generation order. Monochrome is only generated when asked for.
+var AllVariants = []MaterialYouVariant{
+	VariantVibrant, VariantTonal, VariantExpressive, VariantFidelity,
+	VariantContent, VariantFruitSalad, VariantRainbow, VariantNeutral,
+}
+
+// ParseVariant parses a variant name, also accepting the material package
+// spellings ("tonalspot", "fruitsalad")
+func ParseVariant(name string) (MaterialYouVariant, error) {
+	parsed, err := material.ParseVariant(strings.ToLower(name))
+	if err != nil {
+		return "", err
+	}
+
+	switch parsed {
+	case material.VariantTonalSpot:
+		return VariantTonal, nil
+	case material.VariantFruitSalad:
+		return VariantFruitSalad, nil
+	}
+	return MaterialYouVariant(parsed), nil
+}
+
 // GenerateAllVariants generates all Material You variants from a wallpaper
 func (g *WallpaperGenerator) GenerateAllVariants(img image.Image, wallpaperPath string) (map[string]*scheme.Scheme, error) {
 	// Extract colors using enhanced extractor
@@ -645,26 +562,54 @@ func (g *WallpaperGenerator) GenerateAllVariants(img image.Image, wallpaperPath
 		return nil, fmt.Errorf("failed to extract colors: %w", err)
 	}
 
+	return g.GenerateAllVariantsFromColors(extractedColors, wallpaperPath)
+}
+
+// GenerateAllVariantsFromColors generates all Material You variants from
+// colors already extracted from a wallpaper, letting each variant pick the
+// extracted color that suits it best
+func (g *WallpaperGenerator) GenerateAllVariantsFromColors(extractedColors *material.ExtractedColors, wallpaperPath string) (map[string]*scheme.Scheme, error) {
 	// Get best seed color
 	seedColor := extractedColors.GetBestSeedColor()
 
-	// Generate all variants
+	return g.generateVariants(AllVariants, []string{"dark", "light"}, wallpaperPath, func(variant MaterialYouVariant) uint32 {
+		return g.seedForVariant(seedColor, extractedColors, variant)
+	})
+}
+
+// GenerateFromSeed generates Material You variants from a seed color
+// without a wallpaper. Results are keyed by "variant/mode".
+func (g *WallpaperGenerator) GenerateFromSeed(
+	seedColor uint32,
+	variants []MaterialYouVariant,
+	modes []string,
+) (map[string]*scheme.Scheme, error) {
+	return g.generateVariants(variants, modes, "", func(MaterialYouVariant) uint32 {
+		return seedColor
+	})
+}
+
+// generateVariants builds a full scheme for every variant and mode, using
+// seedFor to choose the seed of each variant
+func (g *WallpaperGenerator) generateVariants(
+	variantTypes []MaterialYouVariant,
+	modes []string,
+	wallpaperPath string,
+	seedFor func(MaterialYouVariant) uint32,
+) (map[string]*scheme.Scheme, error) {
 	variants := make(map[string]*scheme.Scheme)
-	variantTypes := []MaterialYouVariant{
-		VariantVibrant, VariantTonal, VariantExpressive, VariantFidelity,
-		VariantContent, VariantFruitSalad, VariantRainbow, VariantNeutral,
-	}
 
 	for _, variant := range variantTypes {
-		// Generate both dark and light modes for each variant
-		for _, isDark := range []bool{true, false} {
-			mode := "light"
-			if isDark {
-				mode = "dark"
-			}
+		materialVariant, err := material.ParseVariant(string(variant))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mode := range modes {
+			isDark := mode == "dark"
 
 			// Generate Material scheme for this variant
-			materialScheme, err := g.generateVariantScheme(seedColor, extractedColors, variant, isDark)
+			materialScheme, err := g.materialGen.GenerateVariantScheme(seedFor(variant), materialVariant, isDark)
 			if err != nil {
 				return nil, fmt.Errorf("failed to generate %s %s variant: %w", variant, mode, err)
 			}
@@ -687,33 +632,9 @@ func (g *WallpaperGenerator) GenerateAllVariants(img image.Image, wallpaperPath
 	return variants, nil
 }
 
-// generateVariantScheme generates a specific Material You variant
-func (g *WallpaperGenerator) generateVariantScheme(
-	seedColor uint32,
-	extractedColors *material.ExtractedColors,
-	variant MaterialYouVariant,
-	isDark bool,
-) (*material.Scheme, error) {
-	// Adjust seed color based on variant
-	adjustedSeed := g.adjustSeedForVariant(seedColor, extractedColors, variant)
-
-	// Generate base palette
-	palette, err := g.materialGen.GenerateFromColor(adjustedSeed)
-	if err != nil {
-		return nil, err
-	}
-
-	// Apply variant-specific modifications
-	g.applyVariantModifications(palette, variant, extractedColors)
-
-	// Generate scheme with variant-specific tone mappings
-	scheme := g.generateSchemeWithVariantTones(palette, variant, isDark)
-
-	return scheme, nil
-}
-
-// adjustSeedForVariant adjusts the seed color based on the variant type
-func (g *WallpaperGenerator) adjustSeedForVariant(
+// seedForVariant picks the extracted color that best suits a variant; the
+// variant itself derives its palettes from the seed in HCT
+func (g *WallpaperGenerator) seedForVariant(
 	seedColor uint32,
 	extractedColors *material.ExtractedColors,
 	variant MaterialYouVariant,
@@ -724,366 +645,19 @@ func (g *WallpaperGenerator) adjustSeedForVariant(
 		if len(extractedColors.Accents) > 0 {
 			return extractedColors.Accents[0].Color
 		}
-		return g.boostSaturation(seedColor, 1.3)
-
-	case VariantTonal:
-		// Use balanced color
-		return seedColor
 
 	case VariantExpressive:
 		// Use bold, high-contrast color
 		if len(extractedColors.EdgeColors) > 0 {
 			return extractedColors.EdgeColors[0].Color
 		}
-		return g.boostSaturation(seedColor, 1.2)
 
 	case VariantFidelity:
 		// Use color closest to source
 		if len(extractedColors.Dominant) > 0 {
 			return extractedColors.Dominant[0].Color
 		}
-		return seedColor
-
-	case VariantContent:
-		// Adaptive based on content
-		return seedColor
-
-	case VariantFruitSalad:
-		// Use multiple accent colors - rotate hue for variety
-		return g.rotateHue(seedColor, 30)
-
-	case VariantRainbow:
-		// Full spectrum - shift hue significantly
-		return g.rotateHue(seedColor, 60)
-
-	case VariantNeutral:
-		// Desaturated version
-		return g.desaturate(seedColor, 0.3)
-
-	default:
-		return seedColor
-	}
-}
-
-// applyVariantModifications applies variant-specific modifications to the palette
-func (g *WallpaperGenerator) applyVariantModifications(
-	palette *material.Palette,
-	variant MaterialYouVariant,
-	extractedColors *material.ExtractedColors,
-) {
-	switch variant {
-	case VariantVibrant:
-		// Boost saturation across all palettes
-		g.boostPaletteSaturation(palette, 1.3)
-
-	case VariantExpressive:
-		// Increase contrast between tones
-		g.increaseTonesContrast(palette, 1.2)
-
-	case VariantFruitSalad:
-		// Add more color variety
-		g.addColorVariety(palette, extractedColors)
-
-	case VariantRainbow:
-		// Spread colors across spectrum
-		g.spreadAcrossSpectrum(palette)
-
-	case VariantNeutral:
-		// Reduce saturation significantly
-		g.reducePaletteSaturation(palette, 0.3)
-	}
-}
-
-// generateSchemeWithVariantTones generates a scheme with variant-specific tone mappings
-func (g *WallpaperGenerator) generateSchemeWithVariantTones(
-	palette *material.Palette,
-	variant MaterialYouVariant,
-	isDark bool,
-) *material.Scheme {
-	scheme := &material.Scheme{
-		Seed:    palette.Seed,
-		IsDark:  isDark,
-		Palette: palette,
-	}
-
-	// Apply variant-specific tone mappings
-	switch variant {
-	case VariantVibrant:
-		g.applyVibrantTones(scheme, palette, isDark)
-	case VariantExpressive:
-		g.applyExpressiveTones(scheme, palette, isDark)
-	case VariantFidelity:
-		g.applyFidelityTones(scheme, palette, isDark)
-	default:
-		g.applyStandardTones(scheme, palette, isDark)
-	}
-
-	return scheme
-}
-
-// Tone mapping functions for different variants
-func (g *WallpaperGenerator) applyVibrantTones(scheme *material.Scheme, palette *material.Palette, isDark bool) {
-	if isDark {
-		// Vibrant dark: more saturated, brighter accents
-		scheme.Primary = palette.Primary.Tone(85)
-		scheme.OnPrimary = palette.Primary.Tone(10)
-		scheme.PrimaryContainer = palette.Primary.Tone(35)
-		scheme.OnPrimaryContainer = palette.Primary.Tone(95)
-	} else {
-		// Vibrant light: deeper, more saturated colors
-		scheme.Primary = palette.Primary.Tone(35)
-		scheme.OnPrimary = palette.Primary.Tone(100)
-		scheme.PrimaryContainer = palette.Primary.Tone(85)
-		scheme.OnPrimaryContainer = palette.Primary.Tone(5)
-	}
-	// Apply similar adjustments to other color roles
-	g.applyStandardTonesForOtherRoles(scheme, palette, isDark)
-}
-
-func (g *WallpaperGenerator) applyExpressiveTones(scheme *material.Scheme, palette *material.Palette, isDark bool) {
-	if isDark {
-		// Expressive dark: high contrast, bold colors
-		scheme.Primary = palette.Primary.Tone(90)
-		scheme.OnPrimary = palette.Primary.Tone(5)
-		scheme.PrimaryContainer = palette.Primary.Tone(40)
-		scheme.OnPrimaryContainer = palette.Primary.Tone(100)
-	} else {
-		// Expressive light: strong, bold colors
-		scheme.Primary = palette.Primary.Tone(30)
-		scheme.OnPrimary = palette.Primary.Tone(100)
-		scheme.PrimaryContainer = palette.Primary.Tone(80)
-		scheme.OnPrimaryContainer = palette.Primary.Tone(0)
-	}
-	g.applyStandardTonesForOtherRoles(scheme, palette, isDark)
-}
-
-func (g *WallpaperGenerator) applyFidelityTones(scheme *material.Scheme, palette *material.Palette, isDark bool) {
-	// Fidelity: closer to source colors, less transformation
-	if isDark {
-		scheme.Primary = palette.Primary.Tone(75)
-		scheme.OnPrimary = palette.Primary.Tone(25)
-		scheme.PrimaryContainer = palette.Primary.Tone(30)
-		scheme.OnPrimaryContainer = palette.Primary.Tone(85)
-	} else {
-		scheme.Primary = palette.Primary.Tone(45)
-		scheme.OnPrimary = palette.Primary.Tone(100)
-		scheme.PrimaryContainer = palette.Primary.Tone(90)
-		scheme.OnPrimaryContainer = palette.Primary.Tone(15)
-	}
-	g.applyStandardTonesForOtherRoles(scheme, palette, isDark)
-}
-
-func (g *WallpaperGenerator) applyStandardTones(scheme *material.Scheme, palette *material.Palette, isDark bool) {
-	// Standard Material You tone mappings
-	if isDark {
-		scheme.Primary = palette.Primary.Tone(80)
-		scheme.OnPrimary = palette.Primary.Tone(20)
-		scheme.PrimaryContainer = palette.Primary.Tone(30)
-		scheme.OnPrimaryContainer = palette.Primary.Tone(90)
-	} else {
-		scheme.Primary = palette.Primary.Tone(40)
-		scheme.OnPrimary = palette.Primary.Tone(100)
-		scheme.PrimaryContainer = palette.Primary.Tone(90)
-		scheme.OnPrimaryContainer = palette.Primary.Tone(10)
-	}
-	g.applyStandardTonesForOtherRoles(scheme, palette, isDark)
-}
-
-func (g *WallpaperGenerator) applyStandardTonesForOtherRoles(scheme *material.Scheme, palette *material.Palette, isDark bool) {
-	// Apply standard tones for secondary, tertiary, error, etc.
-	if isDark {
-		scheme.Secondary = palette.Secondary.Tone(80)
-		scheme.OnSecondary = palette.Secondary.Tone(20)
-		scheme.SecondaryContainer = palette.Secondary.Tone(30)
-		scheme.OnSecondaryContainer = palette.Secondary.Tone(90)
-
-		scheme.Tertiary = palette.Tertiary.Tone(80)
-		scheme.OnTertiary = palette.Tertiary.Tone(20)
-		scheme.TertiaryContainer = palette.Tertiary.Tone(30)
-		scheme.OnTertiaryContainer = palette.Tertiary.Tone(90)
-
-		scheme.Error = palette.Error.Tone(80)
-		scheme.OnError = palette.Error.Tone(20)
-		scheme.ErrorContainer = palette.Error.Tone(30)
-		scheme.OnErrorContainer = palette.Error.Tone(90)
-
-		scheme.Background = palette.Neutral.Tone(10)
-		scheme.OnBackground = palette.Neutral.Tone(90)
-		scheme.Surface = palette.Neutral.Tone(10)
-		scheme.OnSurface = palette.Neutral.Tone(90)
-		scheme.SurfaceVariant = palette.NeutralVariant.Tone(30)
-		scheme.OnSurfaceVariant = palette.NeutralVariant.Tone(80)
-		scheme.Outline = palette.NeutralVariant.Tone(60)
-		scheme.OutlineVariant = palette.NeutralVariant.Tone(30)
-	} else {
-		scheme.Secondary = palette.Secondary.Tone(40)
-		scheme.OnSecondary = palette.Secondary.Tone(100)
-		scheme.SecondaryContainer = palette.Secondary.Tone(90)
-		scheme.OnSecondaryContainer = palette.Secondary.Tone(10)
-
-		scheme.Tertiary = palette.Tertiary.Tone(40)
-		scheme.OnTertiary = palette.Tertiary.Tone(100)
-		scheme.TertiaryContainer = palette.Tertiary.Tone(90)
-		scheme.OnTertiaryContainer = palette.Tertiary.Tone(10)
-
-		scheme.Error = palette.Error.Tone(40)
-		scheme.OnError = palette.Error.Tone(100)
-		scheme.ErrorContainer = palette.Error.Tone(90)
-		scheme.OnErrorContainer = palette.Error.Tone(10)
-
-		scheme.Background = palette.Neutral.Tone(99)
-		scheme.OnBackground = palette.Neutral.Tone(10)
-		scheme.Surface = palette.Neutral.Tone(99)
-		scheme.OnSurface = palette.Neutral.Tone(10)
-		scheme.SurfaceVariant = palette.NeutralVariant.Tone(90)
-		scheme.OnSurfaceVariant = palette.NeutralVariant.Tone(30)
-		scheme.Outline = palette.NeutralVariant.Tone(50)
-		scheme.OutlineVariant = palette.NeutralVariant.Tone(80)
-	}
-
-	scheme.Shadow = 0xFF000000
-	scheme.Scrim = 0xFF000000
-	scheme.InverseSurface = palette.Neutral.Tone(90)
-	scheme.InverseOnSurface = palette.Neutral.Tone(20)
-	scheme.InversePrimary = palette.Primary.Tone(40)
-
-	if !isDark {
-		scheme.InverseSurface = palette.Neutral.Tone(20)
-		scheme.InverseOnSurface = palette.Neutral.Tone(95)
-		scheme.InversePrimary = palette.Primary.Tone(80)
-	}
-}
-
-// Helper functions for color manipulation
-func (g *WallpaperGenerator) boostSaturation(argb uint32, factor float64) uint32 {
-	hex := argbToHex(argb)
-	hsl := hexToHSL(hex)
-	hsl.S = math.Min(100, hsl.S*factor)
-	newHex := hslToHex(hsl)
-	r, gr, b := hexToRGB(newHex)
-	return 0xFF000000 | uint32(r)<<16 | uint32(gr)<<8 | uint32(b)
-}
-
-func (g *WallpaperGenerator) desaturate(argb uint32, factor float64) uint32 {
-	hex := argbToHex(argb)
-	hsl := hexToHSL(hex)
-	hsl.S = hsl.S * factor
-	newHex := hslToHex(hsl)
-	r, gr, b := hexToRGB(newHex)
-	return 0xFF000000 | uint32(r)<<16 | uint32(gr)<<8 | uint32(b)
-}
-
-func (g *WallpaperGenerator) rotateHue(argb uint32, degrees float64) uint32 {
-	hex := argbToHex(argb)
-	hsl := hexToHSL(hex)
-	hsl.H = math.Mod(hsl.H+degrees, 360)
-	newHex := hslToHex(hsl)
-	r, gr, b := hexToRGB(newHex)
-	return 0xFF000000 | uint32(r)<<16 | uint32(gr)<<8 | uint32(b)
-}
-
-func (g *WallpaperGenerator) boostPaletteSaturation(palette *material.Palette, factor float64) {
-	// Boost saturation for all tones in primary palette
-	for tone, color := range palette.Primary.Tones {
-		palette.Primary.Tones[tone] = g.boostSaturation(color, factor)
-	}
-	for tone, color := range palette.Secondary.Tones {
-		palette.Secondary.Tones[tone] = g.boostSaturation(color, factor*0.8)
-	}
-	for tone, color := range palette.Tertiary.Tones {
-		palette.Tertiary.Tones[tone] = g.boostSaturation(color, factor*0.6)
-	}
-}
-
-func (g *WallpaperGenerator) reducePaletteSaturation(palette *material.Palette, factor float64) {
-	// Reduce saturation for all tones
-	for tone, color := range palette.Primary.Tones {
-		palette.Primary.Tones[tone] = g.desaturate(color, factor)
-	}
-	for tone, color := range palette.Secondary.Tones {
-		palette.Secondary.Tones[tone] = g.desaturate(color, factor)
-	}
-	for tone, color := range palette.Tertiary.Tones {
-		palette.Tertiary.Tones[tone] = g.desaturate(color, factor)
-	}
-}
-
-func (g *WallpaperGenerator) increaseTonesContrast(palette *material.Palette, factor float64) {
-	// Increase contrast by making darks darker and lights lighter
-	g.adjustPaletteContrast(palette.Primary, factor)
-	g.adjustPaletteContrast(palette.Secondary, factor)
-	g.adjustPaletteContrast(palette.Tertiary, factor)
-}
-
-func (g *WallpaperGenerator) adjustPaletteContrast(tonalPalette material.TonalPalette, factor float64) {
-	for tone, color := range tonalPalette.Tones {
-		hex := argbToHex(color)
-		hsl := hexToHSL(hex)
-
-		// Adjust lightness based on tone
-		if tone < 50 {
-			// Make darks darker
-			hsl.L = hsl.L / factor
-		} else {
-			// Make lights lighter
-			hsl.L = math.Min(100, hsl.L*factor)
-		}
-
-		newHex := hslToHex(hsl)
-		r, gr, b := hexToRGB(newHex)
-		tonalPalette.Tones[tone] = 0xFF000000 | uint32(r)<<16 | uint32(gr)<<8 | uint32(b)
-	}
-}
-
-func (g *WallpaperGenerator) addColorVariety(palette *material.Palette, extractedColors *material.ExtractedColors) {
-	// Use multiple accent colors for variety
-	if len(extractedColors.Accents) > 1 {
-		// Rotate secondary and tertiary hues more
-		palette.Secondary = g.generateTonalPaletteFromColor(extractedColors.Accents[1].Color)
-	}
-	if len(extractedColors.Accents) > 2 {
-		palette.Tertiary = g.generateTonalPaletteFromColor(extractedColors.Accents[2].Color)
-	}
-}
-
-func (g *WallpaperGenerator) spreadAcrossSpectrum(palette *material.Palette) {
-	// Spread colors evenly across the spectrum
-	baseSeed := palette.Seed
-
-	// Primary stays as is
-	// Secondary at +120 degrees
-	palette.Secondary = g.generateTonalPaletteFromColor(g.rotateHue(baseSeed, 120))
-
-	// Tertiary at +240 degrees
-	palette.Tertiary = g.generateTonalPaletteFromColor(g.rotateHue(baseSeed, 240))
-}
-
-func (g *WallpaperGenerator) generateTonalPaletteFromColor(argb uint32) material.TonalPalette {
-	palette := material.TonalPalette{
-		Tones: make(map[int]uint32),
-	}
-
-	hex := argbToHex(argb)
-	baseHSL := hexToHSL(hex)
-
-	// Standard Material You tone values
-	tones := []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 95, 99, 100}
-
-	for _, tone := range tones {
-		hsl := baseHSL
-		hsl.L = float64(tone)
-
-		// Adjust saturation based on tone
-		if tone < 20 {
-			hsl.S *= float64(tone) / 20.0
-		} else if tone > 80 {
-			hsl.S *= float64(100-tone) / 20.0
-		}
-
-		newHex := hslToHex(hsl)
-		r, gr, b := hexToRGB(newHex)
-		palette.Tones[tone] = 0xFF000000 | uint32(r)<<16 | uint32(gr)<<8 | uint32(b)
 	}
 
-	return palette
+	return seedColor
 }
@@ -0,0 +1,90 @@
+package generator
+
+import "testing"
+
+func TestParseVariant(t *testing.T) {
+	tests := []struct {
+		name string
+		want MaterialYouVariant
+	}{
+		{"vibrant", VariantVibrant},
+		{"tonal", VariantTonal},
+		{"tonalspot", VariantTonal},
+		{"Tonal", VariantTonal},
+		{"fruit_salad", VariantFruitSalad},
+		{"fruitsalad", VariantFruitSalad},
+		{"neutral", VariantNeutral},
+		{"monochrome", VariantMonochrome},
+	}
+	for _, tt := range tests {
+		got, err := ParseVariant(tt.name)
+		if err != nil {
+			t.Errorf("ParseVariant(%q) error = %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVariant(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	for _, name := range []string{"", "pastel", "tonal spot"} {
+		if _, err := ParseVariant(name); err == nil {
+			t.Errorf("ParseVariant(%q) expected an error", name)
+		}
+	}
+
+	// Every generated variant round-trips
+	for _, variant := range AllVariants {
+		if got, err := ParseVariant(string(variant)); err != nil || got != variant {
+			t.Errorf("ParseVariant(%q) = %q, %v", variant, got, err)
+		}
+	}
+}
+
+func TestGenerateFromSeed(t *testing.T) {
+	const seed uint32 = 0xff7aa2f7
+	g := NewWallpaperGenerator()
+
+	variants, err := g.GenerateFromSeed(seed, []MaterialYouVariant{VariantTonal, VariantMonochrome}, []string{"dark", "light"})
+	if err != nil {
+		t.Fatalf("GenerateFromSeed() error = %v", err)
+	}
+	if len(variants) != 4 {
+		t.Fatalf("GenerateFromSeed() returned %d schemes, want 4", len(variants))
+	}
+
+	for _, key := range []string{"tonal/dark", "tonal/light", "monochrome/dark", "monochrome/light"} {
+		s, ok := variants[key]
+		if !ok {
+			t.Errorf("missing scheme %s", key)
+			continue
+		}
+		if s.Colours["primary"] == "" || s.Colours["surface"] == "" || s.Colours["onSurface"] == "" {
+			t.Errorf("%s is missing core colors", key)
+		}
+	}
+
+	dark, light := variants["tonal/dark"], variants["tonal/light"]
+	if dark.Mode != "dark" || light.Mode != "light" || dark.Variant != "tonal" {
+		t.Errorf("tonal schemes have mode %q/%q and variant %q", dark.Mode, light.Mode, dark.Variant)
+	}
+	if dark.Colours["surface"] == light.Colours["surface"] {
+		t.Error("dark and light schemes share a surface color")
+	}
+	if variants["monochrome/dark"].Colours["primary"] == dark.Colours["primary"] {
+		t.Error("monochrome primary matches the tonal primary")
+	}
+
+	// The same seed always gives the same scheme
+	again, err := g.GenerateFromSeed(seed, []MaterialYouVariant{VariantTonal}, []string{"dark"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again["tonal/dark"].Colours["primary"] != dark.Colours["primary"] {
+		t.Error("GenerateFromSeed() is not deterministic")
+	}
+
+	if _, err := g.GenerateFromSeed(seed, []MaterialYouVariant{"pastel"}, []string{"dark"}); err == nil {
+		t.Error("expected an error for an unknown variant")
+	}
+}
@@ -20,22 +20,26 @@ const (
 	SourceBundled   SchemeSource = "bundled"
 	SourceUser      SchemeSource = "user"
 	SourceGenerated SchemeSource = "generated"
+	SourceSynced    SchemeSource = "synced"
 )
 
 // Scheme represents a color scheme
 type Scheme struct {
-	Name    string            `json:"name"`
-	Flavour string            `json:"flavour"`
-	Mode    string            `json:"mode"`
-	Variant string            `json:"variant"`
-	Colours map[string]string `json:"colours"` // British spelling, simple strings
-	Source  SchemeSource      `json:"-"`       // Not persisted, runtime only
+	Name          string            `json:"name"`
+	Flavour       string            `json:"flavour"`
+	Mode          string            `json:"mode"`
+	Variant       string            `json:"variant"`
+	SchemaVersion int               `json:"schema_version,omitempty"` // 0 means legacy/unversioned
+	Colours       map[string]string `json:"colours"`                  // British spelling, simple strings
+	Source        SchemeSource      `json:"-"`                        // Not persisted, runtime only
+	Schema        *SchemaReport     `json:"-"`                        // Schema check result from loading, runtime only
 }
 
 // Manager manages color schemes
 type Manager struct {
 	schemesDir string
 	stateDir   string
+	repoDir    string
 }
 
 // NewManager creates a new scheme manager
@@ -43,6 +47,7 @@ func NewManager() *Manager {
 	m := &Manager{
 		schemesDir: paths.SchemeDataDir, // Default, will be overridden
 		stateDir:   paths.StateDir,
+		repoDir:    paths.SchemeRepoDir,
 	}
 
 	// Use configured generated path if available
@@ -74,6 +79,25 @@ func (m *Manager) getUserSchemePaths() []string {
 	return expandedPaths
 }
 
+// getSyncedSchemePaths returns the scheme directories of repositories synced
+// with 'heimdall scheme sync', in configuration order
+func (m *Manager) getSyncedSchemePaths() []string {
+	if m.repoDir == "" {
+		return nil
+	}
+
+	manifest, err := ReadSyncManifest(m.repoDir)
+	if err != nil {
+		return nil
+	}
+
+	dirs := make([]string, 0, len(manifest.Repos))
+	for _, repo := range manifest.Repos {
+		dirs = append(dirs, repo.SchemesDir)
+	}
+	return dirs
+}
+
 // getGeneratedSchemePath returns the configured generated scheme path
 func (m *Manager) getGeneratedSchemePath() string {
 	cfg := config.Get()
@@ -138,11 +162,12 @@ func (m *Manager) SetScheme(scheme *Scheme) error {
 
 	// Prepare Heimdall format data (colors stored without # prefix)
 	heimdallScheme := &Scheme{
-		Name:    scheme.Name,
-		Flavour: scheme.Flavour,
-		Mode:    scheme.Mode,
-		Variant: scheme.Variant,
-		Colours: normalizedColors,
+		Name:          scheme.Name,
+		Flavour:       scheme.Flavour,
+		Mode:          scheme.Mode,
+		Variant:       scheme.Variant,
+		SchemaVersion: scheme.SchemaVersion,
+		Colours:       normalizedColors,
 	}
 
 	// 1. Primary write to Heimdall config location
@@ -232,12 +257,12 @@ func (m *Manager) ListSchemes() ([]string, error) {
 		}
 	}
 
-	// Then, add schemes from filesystem directories (legacy locations)
-	schemeDirs := []string{
+	// Then, add schemes from filesystem directories (synced repos and legacy locations)
+	schemeDirs := append(m.getSyncedSchemePaths(),
 		m.schemesDir, // Primary location (data dir)
 		filepath.Join(paths.SchemeCacheDir, "schemes"), // Legacy cache location with extra "schemes" level
 		paths.SchemeCacheDir,                           // Direct cache location
-	}
+	)
 
 	for _, schemeDir := range schemeDirs {
 		entries, err := os.ReadDir(schemeDir)
@@ -305,11 +330,15 @@ func (m *Manager) ListFlavours(schemeName string) ([]string, error) {
 	}
 
 	// Then check filesystem locations
-	schemePaths := []string{
+	var schemePaths []string
+	for _, syncedPath := range m.getSyncedSchemePaths() {
+		schemePaths = append(schemePaths, filepath.Join(syncedPath, schemeName))
+	}
+	schemePaths = append(schemePaths,
 		filepath.Join(m.schemesDir, schemeName),                    // Primary location (data dir)
 		filepath.Join(paths.SchemeCacheDir, "schemes", schemeName), // Legacy cache location with extra "schemes" level
 		filepath.Join(paths.SchemeCacheDir, schemeName),            // Direct cache location
-	}
+	)
 
 	for _, schemePath := range schemePaths {
 		entries, err := os.ReadDir(schemePath)
@@ -382,11 +411,15 @@ func (m *Manager) ListModes(schemeName, flavour string) ([]string, error) {
 	}
 
 	// Then check filesystem locations
-	flavourPaths := []string{
+	var flavourPaths []string
+	for _, syncedPath := range m.getSyncedSchemePaths() {
+		flavourPaths = append(flavourPaths, filepath.Join(syncedPath, schemeName, flavour))
+	}
+	flavourPaths = append(flavourPaths,
 		filepath.Join(m.schemesDir, schemeName, flavour),                    // Primary location (data dir)
 		filepath.Join(paths.SchemeCacheDir, "schemes", schemeName, flavour), // Legacy cache location with extra "schemes" level
 		filepath.Join(paths.SchemeCacheDir, schemeName, flavour),            // Direct cache location
-	}
+	)
 
 	for _, flavourPath := range flavourPaths {
 		entries, err := os.ReadDir(flavourPath)
@@ -450,6 +483,17 @@ func (m *Manager) LoadScheme(name, flavour, mode string) (*Scheme, error) {
 		}
 	}
 
+	// Then try synced repositories
+	if data == nil {
+		for _, syncedPath := range m.getSyncedSchemePaths() {
+			data, err = os.ReadFile(filepath.Join(syncedPath, name, flavour, mode+".json"))
+			if err == nil {
+				source = SourceSynced
+				break
+			}
+		}
+	}
+
 	// Then try other filesystem locations
 	if data == nil {
 		schemePaths := []string{
@@ -514,6 +558,11 @@ func (m *Manager) LoadScheme(name, flavour, mode string) (*Scheme, error) {
 			scheme.Variant = variant
 		}
 
+		// Extract schema version if present (JSON numbers decode as float64)
+		if version, ok := rawData["schema_version"].(float64); ok {
+			scheme.SchemaVersion = int(version)
+		}
+
 		// Always use the detected source based on file location
 		// The source should be determined by WHERE the file is, not what's IN the file
 		scheme.Source = source
@@ -523,6 +572,13 @@ func (m *Manager) LoadScheme(name, flavour, mode string) (*Scheme, error) {
 		// Sanitize first to fix common issues
 		SanitizeScheme(scheme)
 
+		// Fill derivable keys and enforce the declared schema version
+		report, err := EnforceSchema(scheme)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scheme %s: %w", name, err)
+		}
+		scheme.Schema = report
+
 		// Then validate
 		if err := ValidateScheme(scheme); err != nil {
 			// Log the validation error but don't fail - allow partial schemes
@@ -561,6 +617,12 @@ func (m *Manager) LoadScheme(name, flavour, mode string) (*Scheme, error) {
 			scheme.Colours[key] = strings.TrimPrefix(value, "#")
 		}
 
+		report, err := EnforceSchema(&scheme)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundled scheme %s: %w", name, err)
+		}
+		scheme.Schema = report
+
 		return &scheme, nil
 	}
 
@@ -593,6 +655,8 @@ func (m *Manager) LoadScheme(name, flavour, mode string) (*Scheme, error) {
 			}
 		}
 
+		scheme.Schema, _ = EnforceSchema(scheme)
+
 		return scheme, nil
 	}
 
@@ -676,6 +740,13 @@ func (m *Manager) GetSchemeSource(schemeName string) SchemeSource {
 		}
 	}
 
+	// Check synced repositories
+	for _, syncedPath := range m.getSyncedSchemePaths() {
+		if paths.IsDir(filepath.Join(syncedPath, schemeName)) {
+			return SourceSynced
+		}
+	}
+
 	// Check data directory (generated schemes location)
 	schemePath := filepath.Join(m.schemesDir, schemeName)
 	if paths.IsDir(schemePath) {
@@ -0,0 +1,220 @@
+package scheme
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Metadata holds user-assigned and usage information for a scheme
+type Metadata struct {
+	Tags       []string  `json:"tags,omitempty"`
+	Favourite  bool      `json:"favourite,omitempty"`
+	LastUsed   time.Time `json:"last_used,omitempty"`
+	UsageCount int       `json:"usage_count,omitempty"`
+}
+
+// HasTag reports whether the metadata carries the given tag
+func (m Metadata) HasTag(tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTags adds tags, keeping them normalized, unique and sorted
+func (m *Metadata) AddTags(tags ...string) {
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" && !m.HasTag(tag) {
+			m.Tags = append(m.Tags, tag)
+		}
+	}
+	sort.Strings(m.Tags)
+}
+
+// RemoveTags removes tags, ignoring those not present
+func (m *Metadata) RemoveTags(tags ...string) {
+	remove := make(map[string]bool)
+	for _, tag := range tags {
+		remove[NormalizeTag(tag)] = true
+	}
+
+	kept := m.Tags[:0]
+	for _, tag := range m.Tags {
+		if !remove[tag] {
+			kept = append(kept, tag)
+		}
+	}
+	m.Tags = kept
+}
+
+// IsZero reports whether the metadata carries no information
+func (m Metadata) IsZero() bool {
+	return len(m.Tags) == 0 && !m.Favourite && m.LastUsed.IsZero() && m.UsageCount == 0
+}
+
+// NormalizeTag lowercases a tag and replaces whitespace with dashes
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+// Filter narrows a scheme list by metadata and available modes
+type Filter struct {
+	Tags       []string // All tags must be present
+	Mode       string   // At least one flavour must provide this mode
+	Favourites bool     // Only favourite schemes
+}
+
+// IsEmpty reports whether the filter matches every scheme
+func (f Filter) IsEmpty() bool {
+	return len(f.Tags) == 0 && f.Mode == "" && !f.Favourites
+}
+
+// matchesMetadata checks the tag and favourite criteria
+func (f Filter) matchesMetadata(meta Metadata) bool {
+	if f.Favourites && !meta.Favourite {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !meta.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterSchemes returns the schemes matching the filter, in input order
+func (m *Manager) FilterSchemes(names []string, metadata map[string]Metadata, filter Filter) []string {
+	if filter.IsEmpty() {
+		return names
+	}
+
+	var matched []string
+	for _, name := range names {
+		if !filter.matchesMetadata(metadata[name]) {
+			continue
+		}
+		if filter.Mode != "" && len(m.FlavoursWithMode(name, filter.Mode)) == 0 {
+			continue
+		}
+		matched = append(matched, name)
+	}
+	return matched
+}
+
+// FlavoursWithMode returns the flavours of a scheme that provide the mode
+func (m *Manager) FlavoursWithMode(name, mode string) []string {
+	flavours, err := m.ListFlavours(name)
+	if err != nil {
+		return nil
+	}
+
+	var matched []string
+	for _, flavour := range flavours {
+		modes, err := m.ListModes(name, flavour)
+		if err != nil {
+			continue
+		}
+		for _, candidate := range modes {
+			if candidate == mode {
+				matched = append(matched, flavour)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// SearchResult is a scheme matched by a fuzzy search
+type SearchResult struct {
+	Name      string   `json:"name"`
+	Score     int      `json:"score"`
+	MatchedOn string   `json:"matched_on"` // The name or tag that matched best
+	Metadata  Metadata `json:"metadata"`
+}
+
+// Search fuzzy-matches the query against scheme names and tags. Results are
+// ordered by score, then favourites, usage count and name.
+func Search(query string, names []string, metadata map[string]Metadata) []SearchResult {
+	var results []SearchResult
+	for _, name := range names {
+		meta := metadata[name]
+
+		best, matchedOn := FuzzyScore(query, name), name
+		for _, tag := range meta.Tags {
+			// Names win ties with equally good tag matches
+			if score := FuzzyScore(query, tag); score > best {
+				best, matchedOn = score, tag
+			}
+		}
+
+		if best > 0 {
+			results = append(results, SearchResult{Name: name, Score: best, MatchedOn: matchedOn, Metadata: meta})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Metadata.Favourite != b.Metadata.Favourite {
+			return a.Metadata.Favourite
+		}
+		if a.Metadata.UsageCount != b.Metadata.UsageCount {
+			return a.Metadata.UsageCount > b.Metadata.UsageCount
+		}
+		return a.Name < b.Name
+	})
+
+	return results
+}
+
+// FuzzyScore scores how well query matches target as a case-insensitive
+// subsequence. Zero means no match; consecutive characters, word starts and
+// prefix or exact matches score higher.
+func FuzzyScore(query, target string) int {
+	q := []rune(strings.ToLower(strings.TrimSpace(query)))
+	t := []rune(strings.ToLower(target))
+	if len(q) == 0 || len(q) > len(t) {
+		return 0
+	}
+
+	score := 0
+	qi := 0
+	lastMatch := -1
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+
+		score++
+		if lastMatch >= 0 && lastMatch == ti-1 {
+			score += 2 // Consecutive characters
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 3 // Start of a word
+		}
+		lastMatch = ti
+		qi++
+	}
+
+	if qi < len(q) {
+		return 0
+	}
+
+	switch {
+	case string(t) == string(q):
+		score += 10
+	case strings.HasPrefix(string(t), string(q)):
+		score += 5
+	case strings.Contains(string(t), string(q)):
+		score += 2
+	}
+
+	return score
+}
@@ -0,0 +1,84 @@
+package scheme
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataTags(t *testing.T) {
+	var meta Metadata
+	meta.AddTags("Warm", "high contrast", "warm", "")
+	assert.Equal(t, []string{"high-contrast", "warm"}, meta.Tags)
+	assert.True(t, meta.HasTag("WARM"))
+
+	meta.RemoveTags("warm", "missing")
+	assert.Equal(t, []string{"high-contrast"}, meta.Tags)
+	assert.False(t, meta.IsZero())
+
+	meta.RemoveTags("high-contrast")
+	assert.True(t, meta.IsZero())
+}
+
+func TestFuzzyScore(t *testing.T) {
+	assert.Zero(t, FuzzyScore("xyz", "rosepine"))
+	assert.Zero(t, FuzzyScore("", "rosepine"))
+	assert.Zero(t, FuzzyScore("pr", "rosepine"), "characters must appear in order")
+	assert.Positive(t, FuzzyScore("rsp", "rosepine"))
+
+	assert.Greater(t, FuzzyScore("rose", "rosepine"), FuzzyScore("rsp", "rosepine"), "consecutive prefix beats scattered")
+	assert.Greater(t, FuzzyScore("gruvbox", "gruvbox"), FuzzyScore("gruvbox", "gruvbox-material"), "exact beats prefix")
+	assert.Greater(t, FuzzyScore("hc", "high-contrast"), FuzzyScore("hc", "nightcity"), "word starts score higher")
+}
+
+func TestSearch(t *testing.T) {
+	names := []string{"catppuccin", "gruvbox", "rosepine", "tokyonight"}
+	metadata := map[string]Metadata{
+		"gruvbox":  {Tags: []string{"warm"}, Favourite: true},
+		"rosepine": {Tags: []string{"pastel", "warm"}, UsageCount: 3},
+	}
+
+	results := Search("warm", names, metadata)
+	if assert.Len(t, results, 2) {
+		// Equal scores: favourites first
+		assert.Equal(t, "gruvbox", results[0].Name)
+		assert.Equal(t, "warm", results[0].MatchedOn)
+		assert.Equal(t, "rosepine", results[1].Name)
+	}
+
+	results = Search("cat", names, metadata)
+	if assert.NotEmpty(t, results) {
+		assert.Equal(t, "catppuccin", results[0].Name)
+		assert.Equal(t, "catppuccin", results[0].MatchedOn)
+	}
+
+	assert.Empty(t, Search("zzz", names, metadata))
+}
+
+func TestFilterSchemes(t *testing.T) {
+	tempDir := t.TempDir()
+	userDir := filepath.Join(tempDir, "schemes")
+	createTestScheme(t, userDir, "warmdark", "default", `{"name": "warmdark", "colours": {"background": "#000000"}}`)
+	createTestScheme(t, userDir, "plain", "default", `{"name": "plain", "colours": {"background": "#000000"}}`)
+	createTestSchemeMode(t, userDir, "plain", "day", "light", `{"name": "plain", "colours": {"background": "#ffffff"}}`)
+
+	manager := &Manager{
+		schemesDir: userDir,
+		stateDir:   filepath.Join(tempDir, "state"),
+		repoDir:    filepath.Join(tempDir, "repos"),
+	}
+	names := []string{"plain", "warmdark"}
+	metadata := map[string]Metadata{
+		"warmdark": {Tags: []string{"warm"}, Favourite: true},
+	}
+
+	assert.Equal(t, names, manager.FilterSchemes(names, metadata, Filter{}))
+	assert.Equal(t, []string{"warmdark"}, manager.FilterSchemes(names, metadata, Filter{Tags: []string{"Warm"}}))
+	assert.Equal(t, []string{"warmdark"}, manager.FilterSchemes(names, metadata, Filter{Favourites: true}))
+	assert.Empty(t, manager.FilterSchemes(names, metadata, Filter{Tags: []string{"warm", "pastel"}}))
+
+	assert.Equal(t, names, manager.FilterSchemes(names, metadata, Filter{Mode: "dark"}))
+	assert.Equal(t, []string{"plain"}, manager.FilterSchemes(names, metadata, Filter{Mode: "light"}))
+	assert.Equal(t, []string{"day"}, manager.FlavoursWithMode("plain", "light"))
+}
@@ -0,0 +1,326 @@
+package scheme
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+)
+
+// wallpaperEntryPrefix marks playlist entries that set a wallpaper and apply
+// the scheme generated from it
+const wallpaperEntryPrefix = "wallpaper:"
+
+// PlaylistEntry is a single item in a scheme playlist
+type PlaylistEntry struct {
+	Scheme    string `json:"scheme,omitempty"`
+	Flavour   string `json:"flavour,omitempty"`
+	Mode      string `json:"mode,omitempty"`
+	Wallpaper string `json:"wallpaper,omitempty"`
+}
+
+// ParsePlaylistEntry parses name[/flavour[/mode]] or wallpaper:PATH
+func ParsePlaylistEntry(value string) (PlaylistEntry, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return PlaylistEntry{}, fmt.Errorf("empty playlist entry")
+	}
+
+	if strings.HasPrefix(value, wallpaperEntryPrefix) {
+		path := strings.TrimSpace(strings.TrimPrefix(value, wallpaperEntryPrefix))
+		if path == "" {
+			return PlaylistEntry{}, fmt.Errorf("wallpaper entry %q has no path", value)
+		}
+		return PlaylistEntry{Wallpaper: path}, nil
+	}
+
+	parts := strings.Split(value, "/")
+	if len(parts) > 3 {
+		return PlaylistEntry{}, fmt.Errorf("entry %q must be name[/flavour[/mode]]", value)
+	}
+
+	entry := PlaylistEntry{Scheme: parts[0]}
+	if len(parts) > 1 {
+		entry.Flavour = parts[1]
+	}
+	if len(parts) > 2 {
+		entry.Mode = parts[2]
+		if entry.Mode != "dark" && entry.Mode != "light" {
+			return PlaylistEntry{}, fmt.Errorf("entry %q has invalid mode %q (must be 'dark' or 'light')", value, entry.Mode)
+		}
+	}
+	if entry.Scheme == "" {
+		return PlaylistEntry{}, fmt.Errorf("entry %q has no scheme name", value)
+	}
+
+	return entry, nil
+}
+
+// String returns the entry in its configuration form
+func (e PlaylistEntry) String() string {
+	if e.Wallpaper != "" {
+		return wallpaperEntryPrefix + e.Wallpaper
+	}
+
+	parts := []string{e.Scheme}
+	if e.Flavour != "" {
+		parts = append(parts, e.Flavour)
+	}
+	if e.Mode != "" {
+		parts = append(parts, e.Mode)
+	}
+	return strings.Join(parts, "/")
+}
+
+// Playlist is a named list of schemes rotated on a schedule
+type Playlist struct {
+	Name     string
+	Entries  []PlaylistEntry
+	Interval time.Duration
+	Shuffle  bool
+	times    []clock
+	weekdays map[time.Weekday]bool
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// NewPlaylist builds a playlist from its configuration
+func NewPlaylist(name string, cfg config.SchemePlaylistConfig) (*Playlist, error) {
+	if len(cfg.Schemes) == 0 {
+		return nil, fmt.Errorf("playlist %s has no schemes", name)
+	}
+	if cfg.Interval < 0 {
+		return nil, fmt.Errorf("playlist %s has a negative interval", name)
+	}
+
+	playlist := &Playlist{
+		Name:     name,
+		Interval: time.Duration(cfg.Interval) * time.Minute,
+		Shuffle:  cfg.Shuffle,
+	}
+
+	for _, value := range cfg.Schemes {
+		entry, err := ParsePlaylistEntry(value)
+		if err != nil {
+			return nil, fmt.Errorf("playlist %s: %w", name, err)
+		}
+		playlist.Entries = append(playlist.Entries, entry)
+	}
+
+	for _, value := range cfg.Times {
+		c, err := parseClock(value)
+		if err != nil {
+			return nil, fmt.Errorf("playlist %s: invalid time: %w", name, err)
+		}
+		playlist.times = append(playlist.times, c)
+	}
+	sort.Slice(playlist.times, func(i, j int) bool {
+		if playlist.times[i].hour != playlist.times[j].hour {
+			return playlist.times[i].hour < playlist.times[j].hour
+		}
+		return playlist.times[i].minute < playlist.times[j].minute
+	})
+
+	if len(cfg.Weekdays) > 0 {
+		playlist.weekdays = make(map[time.Weekday]bool)
+		for _, value := range cfg.Weekdays {
+			key := strings.ToLower(strings.TrimSpace(value))
+			if len(key) > 3 {
+				key = key[:3]
+			}
+			day, ok := weekdayNames[key]
+			if !ok {
+				return nil, fmt.Errorf("playlist %s: unknown weekday %q", name, value)
+			}
+			playlist.weekdays[day] = true
+		}
+	}
+
+	return playlist, nil
+}
+
+// Scheduled reports whether the playlist rotates on its own
+func (p *Playlist) Scheduled() bool {
+	return p.Interval > 0 || len(p.times) > 0
+}
+
+// activeOn reports whether the schedule runs on the weekday of t
+func (p *Playlist) activeOn(t time.Time) bool {
+	return len(p.weekdays) == 0 || p.weekdays[t.Weekday()]
+}
+
+// NextRotation returns when the playlist should next rotate, given the last
+// rotation (zero if it never rotated) and the current time. Returns the zero
+// time when the playlist only rotates manually.
+func (p *Playlist) NextRotation(last, now time.Time) time.Time {
+	base := last
+	if base.IsZero() {
+		base = now
+	}
+
+	var next time.Time
+
+	if p.Interval > 0 {
+		candidate := now
+		if !last.IsZero() {
+			candidate = last.Add(p.Interval)
+		}
+		// Move interval rotations off inactive days to the next active midnight
+		for i := 0; i < 7 && !p.activeOn(candidate); i++ {
+			candidate = time.Date(candidate.Year(), candidate.Month(), candidate.Day()+1, 0, 0, 0, 0, candidate.Location())
+		}
+		next = candidate
+	}
+
+	if len(p.times) > 0 {
+		for offset := 0; offset <= 7; offset++ {
+			day := base.AddDate(0, 0, offset)
+			if !p.activeOn(day) {
+				continue
+			}
+			found := false
+			for _, c := range p.times {
+				at := c.on(day)
+				if at.After(base) {
+					if next.IsZero() || at.Before(next) {
+						next = at
+					}
+					found = true
+					break
+				}
+			}
+			if found {
+				break
+			}
+		}
+	}
+
+	return next
+}
+
+// Describe returns a short human readable summary of the schedule
+func (p *Playlist) Describe() string {
+	var parts []string
+	if p.Interval > 0 {
+		parts = append(parts, fmt.Sprintf("every %s", p.Interval))
+	}
+	if len(p.times) > 0 {
+		times := make([]string, len(p.times))
+		for i, c := range p.times {
+			times[i] = c.String()
+		}
+		parts = append(parts, "at "+strings.Join(times, ", "))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "manual only")
+	}
+	if len(p.weekdays) > 0 {
+		var days []string
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if p.weekdays[day] {
+				days = append(days, day.String()[:3])
+			}
+		}
+		parts = append(parts, "on "+strings.Join(days, ", "))
+	}
+	if p.Shuffle {
+		parts = append(parts, "shuffled")
+	}
+	return strings.Join(parts, " ")
+}
+
+// PlaylistCursor tracks the play order of a playlist and the current entry
+type PlaylistCursor struct {
+	Order    []int `json:"order,omitempty"`
+	Position int   `json:"position"`
+}
+
+// Current returns the index of the current entry, or -1 before the first rotation
+func (c PlaylistCursor) Current() int {
+	if c.Position < 0 || c.Position >= len(c.Order) {
+		return -1
+	}
+	return c.Order[c.Position]
+}
+
+// valid reports whether the cursor still matches the playlist
+func (p *Playlist) valid(c PlaylistCursor) bool {
+	return len(c.Order) == len(p.Entries) && c.Current() >= 0
+}
+
+// newOrder returns the play order for one pass through the playlist. When
+// shuffling, the first entry differs from avoid so a reshuffle never repeats
+// the entry that just played.
+func (p *Playlist) newOrder(avoid int) []int {
+	n := len(p.Entries)
+	if !p.Shuffle {
+		order := make([]int, n)
+		for i := range order {
+			order[i] = i
+		}
+		return order
+	}
+
+	order := rand.Perm(n)
+	if n > 1 && order[0] == avoid {
+		swap := 1 + rand.Intn(n-1)
+		order[0], order[swap] = order[swap], order[0]
+	}
+	return order
+}
+
+// Advance moves to the next entry, starting a new pass at the end of the order
+func (p *Playlist) Advance(c PlaylistCursor) PlaylistCursor {
+	if !p.valid(c) {
+		return PlaylistCursor{Order: p.newOrder(-1), Position: 0}
+	}
+
+	if c.Position+1 < len(c.Order) {
+		return PlaylistCursor{Order: c.Order, Position: c.Position + 1}
+	}
+	return PlaylistCursor{Order: p.newOrder(c.Current()), Position: 0}
+}
+
+// Retreat moves to the previous entry, wrapping within the current order
+func (p *Playlist) Retreat(c PlaylistCursor) PlaylistCursor {
+	if !p.valid(c) {
+		order := p.newOrder(-1)
+		return PlaylistCursor{Order: order, Position: len(order) - 1}
+	}
+
+	position := c.Position - 1
+	if position < 0 {
+		position = len(c.Order) - 1
+	}
+	return PlaylistCursor{Order: c.Order, Position: position}
+}
+
+// LoadPlaylists builds every playlist in the configuration, sorted by name
+func LoadPlaylists(cfg map[string]config.SchemePlaylistConfig) ([]*Playlist, error) {
+	names := make([]string, 0, len(cfg))
+	for name := range cfg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	playlists := make([]*Playlist, 0, len(names))
+	for _, name := range names {
+		playlist, err := NewPlaylist(name, cfg[name])
+		if err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, playlist)
+	}
+	return playlists, nil
+}
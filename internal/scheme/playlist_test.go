@@ -0,0 +1,185 @@
+package scheme
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlaylistEntry(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    PlaylistEntry
+		expectError bool
+	}{
+		{value: "rosepine", expected: PlaylistEntry{Scheme: "rosepine"}},
+		{value: "catppuccin/mocha", expected: PlaylistEntry{Scheme: "catppuccin", Flavour: "mocha"}},
+		{value: "catppuccin/latte/light", expected: PlaylistEntry{Scheme: "catppuccin", Flavour: "latte", Mode: "light"}},
+		{value: "wallpaper:~/Pictures/forest.jpg", expected: PlaylistEntry{Wallpaper: "~/Pictures/forest.jpg"}},
+		{value: "catppuccin/mocha/dim", expectError: true},
+		{value: "a/b/c/d", expectError: true},
+		{value: "wallpaper:", expectError: true},
+		{value: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			entry, err := ParsePlaylistEntry(tt.value)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, entry)
+			assert.Equal(t, tt.value, entry.String())
+		})
+	}
+}
+
+func TestNewPlaylistValidation(t *testing.T) {
+	_, err := NewPlaylist("empty", config.SchemePlaylistConfig{})
+	assert.Error(t, err)
+
+	_, err = NewPlaylist("bad-time", config.SchemePlaylistConfig{Schemes: []string{"rosepine"}, Times: []string{"25:00"}})
+	assert.Error(t, err)
+
+	_, err = NewPlaylist("bad-day", config.SchemePlaylistConfig{Schemes: []string{"rosepine"}, Weekdays: []string{"someday"}})
+	assert.Error(t, err)
+
+	playlist, err := NewPlaylist("ok", config.SchemePlaylistConfig{
+		Schemes:  []string{"rosepine"},
+		Times:    []string{"18:00", "09:00"},
+		Weekdays: []string{"Monday", "fri"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "at 09:00, 18:00 on Mon, Fri", playlist.Describe())
+}
+
+func TestPlaylistNextRotation(t *testing.T) {
+	// 2024-05-10 is a Friday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 5, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config.SchemePlaylistConfig
+		last     time.Time
+		now      time.Time
+		expected time.Time
+	}{
+		{
+			name:     "interval rotates immediately on first run",
+			cfg:      config.SchemePlaylistConfig{Interval: 30},
+			now:      at(10, 12, 0),
+			expected: at(10, 12, 0),
+		},
+		{
+			name:     "interval after last rotation",
+			cfg:      config.SchemePlaylistConfig{Interval: 30},
+			last:     at(10, 12, 0),
+			now:      at(10, 12, 10),
+			expected: at(10, 12, 30),
+		},
+		{
+			name:     "next time of day",
+			cfg:      config.SchemePlaylistConfig{Times: []string{"09:00", "18:00"}},
+			now:      at(10, 12, 0),
+			expected: at(10, 18, 0),
+		},
+		{
+			name:     "time of day rolls over to tomorrow",
+			cfg:      config.SchemePlaylistConfig{Times: []string{"09:00"}},
+			now:      at(10, 12, 0),
+			expected: at(11, 9, 0),
+		},
+		{
+			name:     "missed time after suspend is due",
+			cfg:      config.SchemePlaylistConfig{Times: []string{"09:00"}},
+			last:     at(9, 9, 0),
+			now:      at(10, 12, 0),
+			expected: at(10, 9, 0),
+		},
+		{
+			name:     "weekdays skip inactive days",
+			cfg:      config.SchemePlaylistConfig{Times: []string{"09:00"}, Weekdays: []string{"mon"}},
+			now:      at(10, 12, 0),
+			expected: at(13, 9, 0),
+		},
+		{
+			name:     "interval waits for active day",
+			cfg:      config.SchemePlaylistConfig{Interval: 60, Weekdays: []string{"sun"}},
+			last:     at(10, 12, 0),
+			now:      at(10, 12, 30),
+			expected: at(12, 0, 0),
+		},
+		{
+			name: "manual only",
+			cfg:  config.SchemePlaylistConfig{},
+			now:  at(10, 12, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Schemes = []string{"rosepine"}
+			playlist, err := NewPlaylist("test", tt.cfg)
+			require.NoError(t, err)
+
+			next := playlist.NextRotation(tt.last, tt.now)
+			assert.True(t, tt.expected.Equal(next), "next = %s, want %s", next, tt.expected)
+		})
+	}
+}
+
+func TestPlaylistCursorSequential(t *testing.T) {
+	playlist, err := NewPlaylist("seq", config.SchemePlaylistConfig{Schemes: []string{"a", "b", "c"}})
+	require.NoError(t, err)
+
+	var cursor PlaylistCursor
+	var played []int
+	for i := 0; i < 4; i++ {
+		cursor = playlist.Advance(cursor)
+		played = append(played, cursor.Current())
+	}
+	assert.Equal(t, []int{0, 1, 2, 0}, played)
+
+	cursor = playlist.Retreat(cursor)
+	assert.Equal(t, 2, cursor.Current())
+}
+
+func TestPlaylistCursorShuffleWithoutRepeats(t *testing.T) {
+	playlist, err := NewPlaylist("shuffle", config.SchemePlaylistConfig{
+		Schemes: []string{"a", "b", "c", "d", "e"},
+		Shuffle: true,
+	})
+	require.NoError(t, err)
+
+	var cursor PlaylistCursor
+	previous := -1
+	for pass := 0; pass < 20; pass++ {
+		seen := make(map[int]bool)
+		for i := 0; i < len(playlist.Entries); i++ {
+			cursor = playlist.Advance(cursor)
+			current := cursor.Current()
+			assert.False(t, seen[current], "entry %d repeated within a pass", current)
+			assert.NotEqual(t, previous, current, "entry %d played twice in a row", current)
+			seen[current] = true
+			previous = current
+		}
+		assert.Len(t, seen, len(playlist.Entries))
+	}
+}
+
+func TestPlaylistCursorResetsWhenPlaylistChanges(t *testing.T) {
+	playlist, err := NewPlaylist("changed", config.SchemePlaylistConfig{Schemes: []string{"a", "b"}})
+	require.NoError(t, err)
+
+	stale := PlaylistCursor{Order: []int{0, 1, 2}, Position: 2}
+	cursor := playlist.Advance(stale)
+	assert.Equal(t, 0, cursor.Current())
+	assert.Len(t, cursor.Order, 2)
+}
@@ -0,0 +1,374 @@
+package scheme
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/color"
+)
+
+// CurrentSchemaVersion is the newest scheme schema version this build understands.
+// Schemes without a schema_version are treated as legacy (version 0) and are
+// loaded leniently; versioned schemes must satisfy every required key.
+const CurrentSchemaVersion = 1
+
+// KeyKind describes how a color key participates in the scheme contract
+type KeyKind string
+
+const (
+	// KeyRequired keys must be provided by the scheme (directly or via an alias)
+	KeyRequired KeyKind = "required"
+	// KeyDerivable keys are computed from other keys when the scheme omits them
+	KeyDerivable KeyKind = "derivable"
+	// KeyOptional keys are known to templates but never filled in automatically
+	KeyOptional KeyKind = "optional"
+)
+
+// SchemaKey describes a single color key in the scheme contract
+type SchemaKey struct {
+	Name    string
+	Kind    KeyKind
+	Aliases []string // Alternative key names carrying the same color
+	// Derive computes the key from the (partially filled) colour map.
+	// Only used for derivable keys; returns false when inputs are missing.
+	Derive func(colours map[string]string, mode string) (string, bool)
+}
+
+// SchemaReport summarizes how a scheme measures up against the schema
+type SchemaReport struct {
+	Version int      `json:"version"`
+	Missing []string `json:"missing,omitempty"` // Required keys that could not be resolved
+	Derived []string `json:"derived,omitempty"` // Keys filled in by the derivation layer
+	Unknown []string `json:"unknown,omitempty"` // Keys not described by the schema
+}
+
+// schemaKeys is the ordered V1 key list. Order matters: derivations may
+// depend on keys resolved earlier in the list.
+var schemaKeys = buildSchemaKeys()
+
+// SchemaKeys returns the key definitions for the current schema version
+func SchemaKeys() []SchemaKey {
+	keys := make([]SchemaKey, len(schemaKeys))
+	copy(keys, schemaKeys)
+	return keys
+}
+
+// SchemaKeyNames returns the names of all schema keys of the given kind
+func SchemaKeyNames(kind KeyKind) []string {
+	var names []string
+	for _, key := range schemaKeys {
+		if key.Kind == kind {
+			names = append(names, key.Name)
+		}
+	}
+	return names
+}
+
+// LookupSchemaKey returns the schema definition for a key name or alias
+func LookupSchemaKey(name string) (SchemaKey, bool) {
+	for _, key := range schemaKeys {
+		if key.Name == name {
+			return key, true
+		}
+		for _, alias := range key.Aliases {
+			if alias == name {
+				return key, true
+			}
+		}
+	}
+	return SchemaKey{}, false
+}
+
+// DeriveKeys fills in missing required keys from their aliases and missing
+// derivable keys from the derivation rules. It returns the keys it added.
+func DeriveKeys(scheme *Scheme) []string {
+	if scheme.Colours == nil {
+		scheme.Colours = make(map[string]string)
+	}
+
+	mode := scheme.Mode
+	if mode == "" {
+		mode = "dark"
+	}
+
+	var derived []string
+	for _, key := range schemaKeys {
+		if hasColour(scheme.Colours, key.Name) {
+			continue
+		}
+
+		// Aliases carry the same color under a different name
+		if value, ok := firstColour(scheme.Colours, key.Aliases...); ok {
+			scheme.Colours[key.Name] = value
+			derived = append(derived, key.Name)
+			continue
+		}
+
+		if key.Kind != KeyDerivable || key.Derive == nil {
+			continue
+		}
+
+		if value, ok := key.Derive(scheme.Colours, mode); ok {
+			scheme.Colours[key.Name] = value
+			derived = append(derived, key.Name)
+		}
+	}
+
+	return derived
+}
+
+// MissingRequiredKeys returns required keys that are absent from the scheme
+func MissingRequiredKeys(scheme *Scheme) []string {
+	var missing []string
+	for _, key := range schemaKeys {
+		if key.Kind != KeyRequired {
+			continue
+		}
+		if hasColour(scheme.Colours, key.Name) {
+			continue
+		}
+		if _, ok := firstColour(scheme.Colours, key.Aliases...); ok {
+			continue
+		}
+		missing = append(missing, key.Name)
+	}
+	return missing
+}
+
+// EnforceSchema derives missing keys and checks the scheme against its declared
+// schema version. Legacy schemes never fail; versioned schemes fail when a
+// required key is missing or the version is newer than this build supports.
+func EnforceSchema(scheme *Scheme) (*SchemaReport, error) {
+	if scheme.SchemaVersion > CurrentSchemaVersion {
+		return nil, ValidationError{
+			Field:   "schema_version",
+			Message: fmt.Sprintf("unsupported schema version %d (newest supported is %d)", scheme.SchemaVersion, CurrentSchemaVersion),
+		}
+	}
+
+	report := &SchemaReport{
+		Version: scheme.SchemaVersion,
+		Derived: DeriveKeys(scheme),
+		Missing: MissingRequiredKeys(scheme),
+	}
+
+	for key := range scheme.Colours {
+		if _, known := LookupSchemaKey(key); !known {
+			report.Unknown = append(report.Unknown, key)
+		}
+	}
+	sort.Strings(report.Unknown)
+
+	if scheme.SchemaVersion >= 1 && len(report.Missing) > 0 {
+		return report, ValidationError{
+			Field:   "colours",
+			Message: fmt.Sprintf("schema v%d requires keys: %s", scheme.SchemaVersion, strings.Join(report.Missing, ", ")),
+		}
+	}
+
+	return report, nil
+}
+
+// hasColour reports whether a key is present with a non-empty value
+func hasColour(colours map[string]string, key string) bool {
+	value, ok := colours[key]
+	return ok && strings.TrimPrefix(value, "#") != ""
+}
+
+// firstColour returns the value of the first present key
+func firstColour(colours map[string]string, keys ...string) (string, bool) {
+	for _, key := range keys {
+		if hasColour(colours, key) {
+			return colours[key], true
+		}
+	}
+	return "", false
+}
+
+// formatLike renders a color the same way as the reference value, keeping the
+// # prefix convention of whichever source the scheme came from
+func formatLike(reference string, c *color.Color) string {
+	hex := strings.ToLower(strings.TrimPrefix(c.Hex, "#"))
+	if strings.HasPrefix(reference, "#") {
+		return "#" + hex
+	}
+	return hex
+}
+
+// copyOf derives a key by copying the first available source key
+func copyOf(sources ...string) func(map[string]string, string) (string, bool) {
+	return func(colours map[string]string, _ string) (string, bool) {
+		return firstColour(colours, sources...)
+	}
+}
+
+// literal derives a key from a fixed color
+func literal(hex string) func(map[string]string, string) (string, bool) {
+	return func(colours map[string]string, _ string) (string, bool) {
+		if ref, ok := firstColour(colours, "background"); ok && strings.HasPrefix(ref, "#") {
+			return "#" + hex, true
+		}
+		return hex, true
+	}
+}
+
+// elevate derives a surface tone by moving the source lightness away from the
+// background: lighter in dark mode, darker in light mode
+func elevate(source string, amount float64) func(map[string]string, string) (string, bool) {
+	return func(colours map[string]string, mode string) (string, bool) {
+		ref, ok := firstColour(colours, source)
+		if !ok {
+			return "", false
+		}
+		c, err := color.NewFromHex(ref)
+		if err != nil {
+			return "", false
+		}
+		// Negative amounts sink below the source instead of rising above it
+		lighter := mode != "light"
+		if amount < 0 {
+			lighter = !lighter
+			amount = -amount
+		}
+		if lighter {
+			return formatLike(ref, c.Lighten(amount)), true
+		}
+		return formatLike(ref, c.Darken(amount)), true
+	}
+}
+
+// mix derives a key by blending two source keys (ratio is the weight of b)
+func mix(a, b string, ratio float64) func(map[string]string, string) (string, bool) {
+	return func(colours map[string]string, _ string) (string, bool) {
+		refA, okA := firstColour(colours, a)
+		refB, okB := firstColour(colours, b)
+		if !okA || !okB {
+			return "", false
+		}
+		ca, errA := color.NewFromHex(refA)
+		cb, errB := color.NewFromHex(refB)
+		if errA != nil || errB != nil {
+			return "", false
+		}
+		return formatLike(refA, color.Blend(ca, cb, ratio)), true
+	}
+}
+
+// onColour derives readable content on top of the source key by picking
+// whichever of foreground/background contrasts more
+func onColour(source string) func(map[string]string, string) (string, bool) {
+	return func(colours map[string]string, _ string) (string, bool) {
+		ref, ok := firstColour(colours, source)
+		if !ok {
+			return "", false
+		}
+		fg, okFg := firstColour(colours, "foreground")
+		bg, okBg := firstColour(colours, "background")
+		if !okFg || !okBg {
+			return "", false
+		}
+		c, errC := color.NewFromHex(ref)
+		cf, errF := color.NewFromHex(fg)
+		cbg, errB := color.NewFromHex(bg)
+		if errC != nil || errF != nil || errB != nil {
+			return "", false
+		}
+		if color.Contrast(c, cf) >= color.Contrast(c, cbg) {
+			return fg, true
+		}
+		return bg, true
+	}
+}
+
+// buildSchemaKeys assembles the V1 key contract
+func buildSchemaKeys() []SchemaKey {
+	keys := []SchemaKey{
+		{Name: "background", Kind: KeyRequired, Aliases: []string{"base", "base00"}},
+		{Name: "foreground", Kind: KeyRequired, Aliases: []string{"text", "base05"}},
+		{Name: "primary", Kind: KeyRequired},
+	}
+
+	// The normal terminal palette is required; bright colours can be derived
+	for i := 0; i < 8; i++ {
+		keys = append(keys, SchemaKey{
+			Name:    fmt.Sprintf("term%d", i),
+			Kind:    KeyRequired,
+			Aliases: []string{fmt.Sprintf("color%d", i), fmt.Sprintf("colour%d", i)},
+		})
+	}
+	for i := 8; i < 16; i++ {
+		keys = append(keys, SchemaKey{
+			Name:    fmt.Sprintf("term%d", i),
+			Kind:    KeyDerivable,
+			Aliases: []string{fmt.Sprintf("color%d", i), fmt.Sprintf("colour%d", i)},
+			Derive:  elevate(fmt.Sprintf("term%d", i-8), 10),
+		})
+	}
+
+	derive := func(name string, fn func(map[string]string, string) (string, bool)) {
+		keys = append(keys, SchemaKey{Name: name, Kind: KeyDerivable, Derive: fn})
+	}
+
+	derive("text", copyOf("foreground"))
+	derive("base", copyOf("background"))
+	derive("cursor", copyOf("foreground"))
+	derive("secondary", copyOf("term6"))
+	derive("tertiary", copyOf("term5"))
+	derive("error", copyOf("term1"))
+	derive("success", copyOf("term2"))
+	derive("warning", copyOf("term3"))
+	derive("info", copyOf("term4"))
+
+	derive("surface", copyOf("background"))
+	derive("surfaceDim", copyOf("surface"))
+	derive("surfaceBright", elevate("surface", 12))
+	derive("surfaceContainerLowest", elevate("surface", -2))
+	derive("surfaceContainerLow", elevate("surface", 2))
+	derive("surfaceContainer", elevate("surface", 4))
+	derive("surfaceContainerHigh", elevate("surface", 6))
+	derive("surfaceContainerHighest", elevate("surface", 8))
+	derive("surfaceVariant", elevate("surface", 7))
+	derive("surfaceTint", copyOf("primary"))
+	derive("onSurface", copyOf("foreground"))
+	derive("onSurfaceVariant", mix("foreground", "background", 0.2))
+	derive("onBackground", copyOf("foreground"))
+	derive("inverseSurface", copyOf("foreground"))
+	derive("inverseOnSurface", copyOf("background"))
+	derive("inversePrimary", mix("primary", "background", 0.5))
+	derive("outline", mix("foreground", "background", 0.55))
+	derive("outlineVariant", mix("foreground", "background", 0.75))
+	derive("shadow", literal("000000"))
+	derive("scrim", literal("000000"))
+
+	// Material roles: on-colour, container and on-container for each accent
+	for _, role := range []string{"primary", "secondary", "tertiary", "error", "success", "warning", "info"} {
+		container := role + "Container"
+		onRole := "on" + strings.ToUpper(role[:1]) + role[1:]
+		derive(onRole, onColour(role))
+		derive(container, mix(role, "background", 0.6))
+		derive(onRole+"Container", onColour(container))
+	}
+
+	// Palette-specific names are recognised but never synthesised
+	optional := []string{
+		"mantle", "crust", "surface0", "surface1", "surface2",
+		"overlay0", "overlay1", "overlay2", "subtext0", "subtext1",
+		"rosewater", "flamingo", "pink", "mauve", "red", "maroon", "peach",
+		"yellow", "green", "teal", "sky", "sapphire", "blue", "lavender",
+		"cursor_text",
+		"primaryFixed", "primaryFixedDim", "onPrimaryFixed", "onPrimaryFixedVariant",
+		"secondaryFixed", "secondaryFixedDim", "onSecondaryFixed", "onSecondaryFixedVariant",
+		"tertiaryFixed", "tertiaryFixedDim", "onTertiaryFixed", "onTertiaryFixedVariant",
+		"primary_paletteKeyColor", "secondary_paletteKeyColor", "tertiary_paletteKeyColor",
+		"neutral_paletteKeyColor", "neutral_variant_paletteKeyColor",
+	}
+	for i := 0; i <= 0x17; i++ {
+		optional = append(optional, fmt.Sprintf("base%02X", i))
+	}
+	for _, name := range optional {
+		keys = append(keys, SchemaKey{Name: name, Kind: KeyOptional})
+	}
+
+	return keys
+}
@@ -0,0 +1,181 @@
+package scheme
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func minimalColours() map[string]string {
+	return map[string]string{
+		"background": "1e1e2e",
+		"foreground": "cdd6f4",
+		"primary":    "89b4fa",
+		"term0":      "45475a",
+		"term1":      "f38ba8",
+		"term2":      "a6e3a1",
+		"term3":      "f9e2af",
+		"term4":      "89b4fa",
+		"term5":      "f5c2e7",
+		"term6":      "94e2d5",
+		"term7":      "bac2de",
+	}
+}
+
+func TestDeriveKeys(t *testing.T) {
+	scheme := &Scheme{Name: "minimal", Mode: "dark", Colours: minimalColours()}
+
+	derived := DeriveKeys(scheme)
+
+	assert.Contains(t, derived, "surface")
+	assert.Contains(t, derived, "surfaceContainerHigh")
+	assert.Contains(t, derived, "term15")
+	assert.Equal(t, "1e1e2e", scheme.Colours["surface"])
+	assert.Equal(t, "94e2d5", scheme.Colours["secondary"])
+	assert.NotEqual(t, scheme.Colours["surface"], scheme.Colours["surfaceContainerHigh"])
+
+	// Derived values keep the source's prefix convention
+	assert.Len(t, scheme.Colours["surfaceContainerHigh"], 6)
+}
+
+func TestDeriveKeysKeepsExistingValues(t *testing.T) {
+	colours := minimalColours()
+	colours["surfaceContainerHigh"] = "ff0000"
+	scheme := &Scheme{Name: "existing", Mode: "dark", Colours: colours}
+
+	derived := DeriveKeys(scheme)
+
+	assert.NotContains(t, derived, "surfaceContainerHigh")
+	assert.Equal(t, "ff0000", scheme.Colours["surfaceContainerHigh"])
+}
+
+func TestDeriveKeysLightModeDarkensContainers(t *testing.T) {
+	colours := minimalColours()
+	colours["background"] = "#fafafa"
+	scheme := &Scheme{Name: "light", Mode: "light", Colours: colours}
+
+	DeriveKeys(scheme)
+
+	assert.Equal(t, "#fafafa", scheme.Colours["surface"])
+	assert.Less(t, scheme.Colours["surfaceContainerHigh"], scheme.Colours["surface"])
+	assert.Equal(t, byte('#'), scheme.Colours["surfaceContainerHigh"][0])
+}
+
+func TestDeriveKeysFromAliases(t *testing.T) {
+	scheme := &Scheme{
+		Name: "aliases",
+		Colours: map[string]string{
+			"base":    "1e1e2e",
+			"text":    "cdd6f4",
+			"color0":  "45475a",
+			"colour8": "585b70",
+		},
+	}
+
+	DeriveKeys(scheme)
+
+	assert.Equal(t, "1e1e2e", scheme.Colours["background"])
+	assert.Equal(t, "cdd6f4", scheme.Colours["foreground"])
+	assert.Equal(t, "45475a", scheme.Colours["term0"])
+	assert.Equal(t, "585b70", scheme.Colours["term8"])
+}
+
+func TestEnforceSchema(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     int
+		colours     map[string]string
+		expectError bool
+		missing     []string
+	}{
+		{
+			name:    "legacy scheme with missing keys is lenient",
+			version: 0,
+			colours: map[string]string{"background": "000000", "foreground": "ffffff"},
+			missing: []string{"primary", "term0", "term1", "term2", "term3", "term4", "term5", "term6", "term7"},
+		},
+		{
+			name:    "versioned scheme with all required keys",
+			version: 1,
+			colours: minimalColours(),
+		},
+		{
+			name:        "versioned scheme missing required keys",
+			version:     1,
+			colours:     map[string]string{"background": "000000", "foreground": "ffffff", "primary": "ff0000"},
+			expectError: true,
+			missing:     []string{"term0", "term1", "term2", "term3", "term4", "term5", "term6", "term7"},
+		},
+		{
+			name:        "future schema version",
+			version:     CurrentSchemaVersion + 1,
+			colours:     minimalColours(),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := &Scheme{Name: "test", SchemaVersion: tt.version, Colours: tt.colours}
+
+			report, err := EnforceSchema(scheme)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if report != nil {
+				assert.Equal(t, tt.missing, report.Missing)
+			}
+		})
+	}
+}
+
+func TestEnforceSchemaReportsUnknownKeys(t *testing.T) {
+	colours := minimalColours()
+	colours["myCustomAccent"] = "123456"
+	scheme := &Scheme{Name: "unknown", Colours: colours}
+
+	report, err := EnforceSchema(scheme)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"myCustomAccent"}, report.Unknown)
+}
+
+func TestLoadSchemeEnforcesVersionedSchema(t *testing.T) {
+	tempDir := t.TempDir()
+	schemesDir := filepath.Join(tempDir, "schemes")
+
+	createTestScheme(t, schemesDir, "strict", "default",
+		`{"name": "strict", "schema_version": 1, "colours": {"background": "#000000", "foreground": "#ffffff"}}`)
+	createTestScheme(t, schemesDir, "legacy", "default",
+		`{"name": "legacy", "colours": {"background": "#000000", "foreground": "#ffffff"}}`)
+
+	manager := &Manager{
+		schemesDir: schemesDir,
+		stateDir:   filepath.Join(tempDir, "state"),
+	}
+
+	_, err := manager.LoadScheme("strict", "default", "dark")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "primary")
+
+	legacy, err := manager.LoadScheme("legacy", "default", "dark")
+	require.NoError(t, err)
+	require.NotNil(t, legacy.Schema)
+	assert.Equal(t, "#000000", legacy.Colours["surface"])
+	assert.Contains(t, legacy.Schema.Derived, "surface")
+}
+
+func TestBundledSchemesSatisfySchema(t *testing.T) {
+	manager := NewManager()
+
+	scheme, err := manager.LoadScheme("catppuccin", "mocha", "dark")
+	require.NoError(t, err)
+	require.NotNil(t, scheme.Schema)
+
+	assert.Empty(t, scheme.Schema.Missing)
+}
@@ -0,0 +1,279 @@
+package scheme
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+)
+
+// syncManifestName is the file recording synced repositories in the repo dir
+const syncManifestName = "manifest.json"
+
+// SyncedRepo records a repository checkout and the schemes it provides
+type SyncedRepo struct {
+	Name       string    `json:"name"`
+	URL        string    `json:"url"`
+	Ref        string    `json:"ref,omitempty"`
+	Commit     string    `json:"commit"`
+	SchemesDir string    `json:"schemes_dir"`
+	Schemes    []string  `json:"schemes"`
+	SyncedAt   time.Time `json:"synced_at"`
+}
+
+// SyncManifest lists every synced repository, in configuration order
+type SyncManifest struct {
+	Repos []SyncedRepo `json:"repos"`
+}
+
+// SyncResult describes the outcome of syncing one repository
+type SyncResult struct {
+	Repo           SyncedRepo `json:"repo"`
+	PreviousCommit string     `json:"previous_commit,omitempty"`
+	Updated        bool       `json:"updated"`
+	Stale          bool       `json:"stale"`             // Fetch failed, kept the existing checkout
+	Warning        string     `json:"warning,omitempty"` // Why the checkout is stale
+}
+
+// RepoSyncer clones and updates scheme repositories with git
+type RepoSyncer struct {
+	baseDir string
+}
+
+// NewRepoSyncer creates a syncer that keeps checkouts in baseDir
+func NewRepoSyncer(baseDir string) *RepoSyncer {
+	if baseDir == "" {
+		baseDir = paths.SchemeRepoDir
+	}
+	return &RepoSyncer{baseDir: baseDir}
+}
+
+// Sync clones or updates a repository and checks out its pinned ref. When a
+// fetch fails but a checkout already exists, the checkout is kept and the
+// result is marked stale, so syncing never breaks working schemes offline.
+func (s *RepoSyncer) Sync(repo config.SchemeRepoConfig) (*SyncResult, error) {
+	if repo.URL == "" {
+		return nil, fmt.Errorf("repository URL is required")
+	}
+
+	name := repo.LocalName()
+	if name == "" || name == "." || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid repository name %q", name)
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is required to sync scheme repositories: %w", err)
+	}
+
+	repoDir := filepath.Join(s.baseDir, name)
+	result := &SyncResult{}
+
+	if paths.IsDir(filepath.Join(repoDir, ".git")) {
+		result.PreviousCommit, _ = runGit(repoDir, "rev-parse", "HEAD")
+
+		if _, err := runGit(repoDir, "remote", "set-url", "origin", repo.URL); err != nil {
+			return nil, fmt.Errorf("failed to update remote for %s: %w", name, err)
+		}
+		if _, err := runGit(repoDir, "fetch", "--tags", "--prune", "--force", "origin"); err != nil {
+			if result.PreviousCommit == "" {
+				return nil, fmt.Errorf("failed to fetch %s: %w", name, err)
+			}
+			result.Stale = true
+			result.Warning = err.Error()
+		}
+	} else {
+		if err := paths.EnsureDir(s.baseDir); err != nil {
+			return nil, fmt.Errorf("failed to create repository directory: %w", err)
+		}
+		os.RemoveAll(repoDir)
+		if _, err := runGit(s.baseDir, "clone", "--no-checkout", repo.URL, name); err != nil {
+			os.RemoveAll(repoDir)
+			return nil, fmt.Errorf("failed to clone %s: %w", name, err)
+		}
+	}
+
+	// A stale checkout is kept only while it matches the ref; a changed ref
+	// already known locally is still checked out offline
+	target, err := resolveRef(repoDir, repo.Ref)
+	if err != nil {
+		if result.Stale {
+			return nil, fmt.Errorf("ref %q of %s is not available offline: %s", repo.Ref, name, result.Warning)
+		}
+		return nil, fmt.Errorf("failed to resolve ref for %s: %w", name, err)
+	}
+	if !result.Stale || target != result.PreviousCommit {
+		if _, err := runGit(repoDir, "checkout", "--force", "--detach", target); err != nil {
+			return nil, fmt.Errorf("failed to check out %s: %w", name, err)
+		}
+		runGit(repoDir, "clean", "-fdq")
+	}
+
+	commit, err := runGit(repoDir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit for %s: %w", name, err)
+	}
+
+	schemesDir := filepath.Join(repoDir, repo.Path)
+	if rel, err := filepath.Rel(repoDir, schemesDir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("path %q escapes repository %s", repo.Path, name)
+	}
+
+	result.Repo = SyncedRepo{
+		Name:       name,
+		URL:        repo.URL,
+		Ref:        repo.Ref,
+		Commit:     commit,
+		SchemesDir: schemesDir,
+		Schemes:    findSchemeDirs(schemesDir),
+		SyncedAt:   time.Now(),
+	}
+	result.Updated = commit != result.PreviousCommit
+
+	return result, nil
+}
+
+// SyncAll syncs every repository and rewrites the manifest. Repositories that
+// fail keep their previous manifest entry. With prune, checkouts of
+// repositories no longer configured are removed.
+func (s *RepoSyncer) SyncAll(repos []config.SchemeRepoConfig, prune bool) ([]*SyncResult, []error) {
+	previous, _ := ReadSyncManifest(s.baseDir)
+	previousByName := make(map[string]SyncedRepo)
+	if previous != nil {
+		for _, repo := range previous.Repos {
+			previousByName[repo.Name] = repo
+		}
+	}
+
+	var results []*SyncResult
+	var errs []error
+	manifest := &SyncManifest{}
+	configured := make(map[string]bool)
+
+	for _, repo := range repos {
+		name := repo.LocalName()
+		configured[name] = true
+
+		result, err := s.Sync(repo)
+		if err != nil {
+			errs = append(errs, err)
+			if old, ok := previousByName[name]; ok {
+				manifest.Repos = append(manifest.Repos, old)
+			}
+			continue
+		}
+		results = append(results, result)
+		manifest.Repos = append(manifest.Repos, result.Repo)
+	}
+
+	if prune {
+		entries, _ := os.ReadDir(s.baseDir)
+		for _, entry := range entries {
+			if entry.IsDir() && !configured[entry.Name()] {
+				if err := os.RemoveAll(filepath.Join(s.baseDir, entry.Name())); err != nil {
+					errs = append(errs, fmt.Errorf("failed to remove %s: %w", entry.Name(), err))
+				}
+			}
+		}
+	}
+
+	if err := paths.EnsureDir(s.baseDir); err == nil {
+		if err := paths.AtomicWriteJSON(filepath.Join(s.baseDir, syncManifestName), manifest); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write sync manifest: %w", err))
+		}
+	}
+
+	return results, errs
+}
+
+// ReadSyncManifest reads the manifest of synced repositories in baseDir.
+// A missing manifest yields an empty one.
+func ReadSyncManifest(baseDir string) (*SyncManifest, error) {
+	if baseDir == "" {
+		baseDir = paths.SchemeRepoDir
+	}
+
+	data, err := os.ReadFile(filepath.Join(baseDir, syncManifestName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &SyncManifest{}, nil
+		}
+		return nil, fmt.Errorf("failed to read sync manifest: %w", err)
+	}
+
+	var manifest SyncManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse sync manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// resolveRef turns a branch, tag or commit into a commit hash. Branches are
+// resolved against the remote so updates are picked up on every sync.
+func resolveRef(repoDir, ref string) (string, error) {
+	if ref == "" {
+		if commit, err := runGit(repoDir, "rev-parse", "--verify", "--quiet", "origin/HEAD^{commit}"); err == nil {
+			return commit, nil
+		}
+		ref = "HEAD"
+	}
+
+	candidates := []string{"origin/" + ref, "refs/tags/" + ref, ref}
+	for _, candidate := range candidates {
+		if commit, err := runGit(repoDir, "rev-parse", "--verify", "--quiet", candidate+"^{commit}"); err == nil {
+			return commit, nil
+		}
+	}
+	return "", fmt.Errorf("ref %q not found", ref)
+}
+
+// findSchemeDirs returns the scheme names in dir, i.e. directories holding
+// at least one flavour/mode.json file
+func findSchemeDirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(dir, entry.Name(), "*", "*.json"))
+		if len(matches) > 0 {
+			names = append(names, entry.Name())
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// runGit runs git in dir and returns its trimmed output. Prompts are
+// disabled so an unreachable remote fails instead of hanging.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=true")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			return "", fmt.Errorf("git %s: %w", args[0], err)
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], message)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,180 @@
+package scheme
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGit runs git with a fixed identity for test repositories
+func testGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "init.defaultBranch=main"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, output)
+	return string(output)
+}
+
+// createSchemeRepo creates a bare repository with a tagged first commit
+// (scheme "team") and a second commit adding scheme "extra"
+func createSchemeRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	bare := filepath.Join(root, "schemes.git")
+	work := filepath.Join(root, "work")
+
+	testGit(t, root, "init", "--bare", bare)
+	testGit(t, root, "clone", bare, work)
+
+	createTestScheme(t, filepath.Join(work, "schemes"), "team", "default",
+		`{"name": "team", "colours": {"background": "#101010", "foreground": "#f0f0f0"}}`)
+	testGit(t, work, "add", ".")
+	testGit(t, work, "commit", "-m", "Add team scheme")
+	testGit(t, work, "tag", "v1")
+
+	createTestScheme(t, filepath.Join(work, "schemes"), "extra", "default",
+		`{"name": "extra", "colours": {"background": "#202020", "foreground": "#e0e0e0"}}`)
+	testGit(t, work, "add", ".")
+	testGit(t, work, "commit", "-m", "Add extra scheme")
+	testGit(t, work, "push", "--tags", "origin", "HEAD:main")
+
+	return bare
+}
+
+func TestRepoSyncerPinnedRef(t *testing.T) {
+	bare := createSchemeRepo(t)
+	syncer := NewRepoSyncer(filepath.Join(t.TempDir(), "repos"))
+
+	result, err := syncer.Sync(config.SchemeRepoConfig{URL: "file://" + bare, Ref: "v1", Path: "schemes"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "schemes", result.Repo.Name)
+	assert.Equal(t, []string{"team"}, result.Repo.Schemes)
+	assert.True(t, result.Updated)
+	assert.Len(t, result.Repo.Commit, 40)
+
+	// Moving the pin to the branch picks up the newer commit
+	result, err = syncer.Sync(config.SchemeRepoConfig{URL: "file://" + bare, Ref: "main", Path: "schemes"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"extra", "team"}, result.Repo.Schemes)
+	assert.True(t, result.Updated)
+	assert.NotEqual(t, result.PreviousCommit, result.Repo.Commit)
+
+	// Syncing again without changes is a no-op
+	result, err = syncer.Sync(config.SchemeRepoConfig{URL: "file://" + bare, Ref: "main", Path: "schemes"})
+	require.NoError(t, err)
+	assert.False(t, result.Updated)
+}
+
+func TestRepoSyncerUnknownRef(t *testing.T) {
+	bare := createSchemeRepo(t)
+	syncer := NewRepoSyncer(filepath.Join(t.TempDir(), "repos"))
+
+	_, err := syncer.Sync(config.SchemeRepoConfig{URL: "file://" + bare, Ref: "does-not-exist"})
+	assert.Error(t, err)
+}
+
+func TestRepoSyncerKeepsCheckoutWhenOffline(t *testing.T) {
+	bare := createSchemeRepo(t)
+	syncer := NewRepoSyncer(filepath.Join(t.TempDir(), "repos"))
+	repo := config.SchemeRepoConfig{Name: "team", URL: "file://" + bare, Path: "schemes"}
+
+	first, err := syncer.Sync(repo)
+	require.NoError(t, err)
+
+	// Simulate an unreachable remote
+	require.NoError(t, os.RemoveAll(bare))
+
+	second, err := syncer.Sync(repo)
+	require.NoError(t, err)
+	assert.True(t, second.Stale)
+	assert.NotEmpty(t, second.Warning)
+	assert.Equal(t, first.Repo.Commit, second.Repo.Commit)
+}
+
+func TestRepoSyncerChecksOutChangedRefWhenOffline(t *testing.T) {
+	bare := createSchemeRepo(t)
+	syncer := NewRepoSyncer(filepath.Join(t.TempDir(), "repos"))
+	repo := config.SchemeRepoConfig{Name: "team", URL: "file://" + bare, Ref: "main", Path: "schemes"}
+
+	_, err := syncer.Sync(repo)
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(bare))
+
+	// The tag was fetched before, so the new pin applies offline
+	repo.Ref = "v1"
+	result, err := syncer.Sync(repo)
+	require.NoError(t, err)
+	assert.True(t, result.Stale)
+	assert.True(t, result.Updated)
+	assert.Equal(t, []string{"team"}, result.Repo.Schemes)
+
+	// A ref that was never fetched cannot be checked out
+	repo.Ref = "v2"
+	_, err = syncer.Sync(repo)
+	assert.Error(t, err)
+}
+
+func TestRepoSyncerPath(t *testing.T) {
+	bare := createSchemeRepo(t)
+	syncer := NewRepoSyncer(filepath.Join(t.TempDir(), "repos"))
+
+	// The repository root is a valid path
+	result, err := syncer.Sync(config.SchemeRepoConfig{Name: "root", URL: "file://" + bare, Path: "."})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(syncer.baseDir, "root"), result.Repo.SchemesDir)
+
+	_, err = syncer.Sync(config.SchemeRepoConfig{Name: "root", URL: "file://" + bare, Path: "../other"})
+	assert.Error(t, err)
+}
+
+func TestSyncAllManifestAndManagerSource(t *testing.T) {
+	bare := createSchemeRepo(t)
+	tempDir := t.TempDir()
+	repoDir := filepath.Join(tempDir, "repos")
+	syncer := NewRepoSyncer(repoDir)
+
+	// A leftover checkout that is no longer configured
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, "old"), 0755))
+
+	results, errs := syncer.SyncAll([]config.SchemeRepoConfig{
+		{Name: "team", URL: "file://" + bare, Path: "schemes"},
+		{Name: "broken", URL: "file://" + filepath.Join(tempDir, "missing.git")},
+	}, true)
+	require.Len(t, results, 1)
+	require.Len(t, errs, 1)
+	assert.NoDirExists(t, filepath.Join(repoDir, "old"))
+
+	manifest, err := ReadSyncManifest(repoDir)
+	require.NoError(t, err)
+	require.Len(t, manifest.Repos, 1)
+	assert.Equal(t, "team", manifest.Repos[0].Name)
+
+	manager := &Manager{
+		schemesDir: filepath.Join(tempDir, "schemes"),
+		stateDir:   filepath.Join(tempDir, "state"),
+		repoDir:    repoDir,
+	}
+
+	loaded, err := manager.LoadScheme("team", "default", "dark")
+	require.NoError(t, err)
+	assert.Equal(t, SourceSynced, loaded.Source)
+	assert.Equal(t, SourceSynced, manager.GetSchemeSource("extra"))
+
+	schemes, err := manager.ListSchemes()
+	require.NoError(t, err)
+	assert.Contains(t, schemes, "team")
+	assert.Contains(t, schemes, "extra")
+}
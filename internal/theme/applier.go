@@ -76,6 +76,18 @@ func (a *Applier) ApplyTheme(app string, colors map[string]string, mode string)
 		}
 	}
 
+	// Add template values such as the wallpaper path to the colors
+	if values := appthemes.GetValues(app); len(values) > 0 {
+		merged := make(map[string]string, len(colors)+len(values))
+		for key, value := range colors {
+			merged[key] = value
+		}
+		for key, value := range values {
+			merged[key] = value
+		}
+		colors = merged
+	}
+
 	// Render the template using simple string replacement
 	rendered, err := a.replacer.ReplaceTemplate(templateContent, colors)
 	if err != nil {
@@ -266,6 +278,9 @@ func (a *Applier) ApplyAllThemesParallel(ctx context.Context, colors map[string]
 		{"nvim", func() error {
 			return a.ApplyTheme("nvim", colors, mode)
 		}},
+		{"hyprlock", func() error {
+			return a.ApplyTheme("hyprlock", colors, mode)
+		}},
 	}
 
 	// Launch workers for each task
@@ -324,6 +339,11 @@ func (a *Applier) ApplyAllThemesParallel(ctx context.Context, colors map[string]
 
 // ApplyThemeWithCache applies a theme using cached templates
 func (a *Applier) ApplyThemeWithCache(app string, colors map[string]string, mode string) error {
+	// Template values like file paths are not part of the cache key
+	if len(appthemes.GetValues(app)) > 0 {
+		return a.ApplyTheme(app, colors, mode)
+	}
+
 	// Generate cache key
 	cacheKey := CacheKey(app, mode, colors)
 
@@ -0,0 +1,78 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+)
+
+func TestApplyThemeWithCacheHyprlock(t *testing.T) {
+	cfg := config.Get()
+	if cfg == nil {
+		t.Skip("no configuration")
+	}
+	dir := t.TempDir()
+
+	link, output, derive := paths.WallpaperLinkPath, cfg.Theme.Paths.Hyprlock, cfg.Wallpaper.Derive
+	t.Cleanup(func() {
+		paths.WallpaperLinkPath = link
+		cfg.Theme.Paths.Hyprlock = output
+		cfg.Wallpaper.Derive = derive
+	})
+	paths.WallpaperLinkPath = filepath.Join(dir, "current")
+	cfg.Theme.Paths.Hyprlock = filepath.Join(dir, "hypr", "hyprlock.conf")
+	cfg.Wallpaper.Derive = []string{wallpaper.DeriveBlur}
+
+	// A scheme without the optional keys falls back to the base colors
+	colors := map[string]string{
+		"background": "#1e1e2e",
+		"foreground": "#cdd6f4",
+		"primary":    "#89b4fa",
+		"colour1":    "#f38ba8",
+		"colour3":    "#f9e2af",
+		"colour5":    "#cba6f7",
+	}
+	applier := NewApplier(dir, dir)
+	render := func() string {
+		t.Helper()
+		if err := applier.ApplyThemeWithCache("hyprlock", colors, "dark"); err != nil {
+			t.Fatalf("ApplyThemeWithCache() error = %v", err)
+		}
+		data, err := os.ReadFile(cfg.Theme.Paths.Hyprlock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	rendered := render()
+	if strings.Contains(rendered, "{{") {
+		t.Errorf("unresolved placeholders in:\n%s", rendered)
+	}
+	for _, want := range []string{"$wallpaper = \n", "$text = rgb(cdd6f4)", "$input_check = rgb(f9e2af)"} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("rendered theme lacks %q", want)
+		}
+	}
+
+	// The wallpaper path is not cached with the colors
+	if err := os.WriteFile(paths.WallpaperLinkPath, []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if rendered := render(); !strings.Contains(rendered, "$wallpaper = "+paths.WallpaperLinkPath+"\n") {
+		t.Errorf("rendered theme lacks the wallpaper link:\n%s", rendered)
+	}
+
+	blurred := wallpaper.DerivedLinkPath(wallpaper.DeriveBlur)
+	if err := os.WriteFile(blurred, []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if rendered := render(); !strings.Contains(rendered, "$wallpaper = "+blurred+"\n") {
+		t.Errorf("rendered theme lacks the blurred wallpaper:\n%s", rendered)
+	}
+}
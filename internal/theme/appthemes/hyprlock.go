@@ -0,0 +1,82 @@
+package appthemes
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+)
+
+func init() {
+	Register(&Template{
+		Name:        "hyprlock",
+		Description: "Hyprlock lock screen colors and wallpaper",
+		GetOutputPath: func() string {
+			cfg := config.Get()
+			if cfg != nil && cfg.Theme.Paths.Hyprlock != "" {
+				return cfg.Theme.Paths.Hyprlock
+			}
+			home, _ := os.UserHomeDir()
+			return filepath.Join(home, ".config", "hypr", "heimdall", "hyprlock.conf")
+		},
+		Values: func() map[string]string {
+			return map[string]string{"wallpaper": hyprlockWallpaper()}
+		},
+		Content: `
+# Heimdall theme for hyprlock
+# Generated automatically, include it with:
+#   source = ~/.config/hypr/heimdall/hyprlock.conf
+
+$wallpaper = {{wallpaper}}
+
+$background = rgb({{background.raw}})
+$foreground = rgb({{foreground.raw}})
+$primary = rgb({{primary.raw}})
+$text = rgb({{onSurface.raw|default:foreground}})
+$text_dim = rgb({{onSurfaceVariant.raw|default:foreground}})
+
+$input_outer = rgb({{primary.raw}})
+$input_inner = rgba({{surfaceContainer.raw|default:background}}e6)
+$input_font = rgb({{onSurface.raw|default:foreground}})
+$input_check = rgb({{warning.raw|default:colour3}})
+$input_fail = rgb({{error.raw|default:colour1}})
+$input_capslock = rgb({{tertiary.raw|default:colour5}})
+
+background {
+    monitor =
+    path = $wallpaper
+    color = $background
+}
+
+input-field {
+    monitor =
+    outer_color = $input_outer
+    inner_color = $input_inner
+    font_color = $input_font
+    check_color = $input_check
+    fail_color = $input_fail
+    capslock_color = $input_capslock
+}
+`,
+	})
+}
+
+// hyprlockWallpaper returns the blurred wallpaper link when blur images are
+// derived, otherwise the current wallpaper link. Without a wallpaper it is
+// empty, so hyprlock falls back to the background color.
+func hyprlockWallpaper() string {
+	cfg := config.Get()
+	if cfg != nil && slices.Contains(cfg.Wallpaper.Derive, wallpaper.DeriveBlur) {
+		blurred := wallpaper.DerivedLinkPath(wallpaper.DeriveBlur)
+		if _, err := os.Stat(blurred); err == nil {
+			return blurred
+		}
+	}
+	if _, err := os.Stat(paths.WallpaperLinkPath); err != nil {
+		return ""
+	}
+	return paths.WallpaperLinkPath
+}
@@ -0,0 +1,70 @@
+package appthemes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/wallpaper"
+)
+
+// useWallpaperLink points the wallpaper link into a temporary directory and
+// sets the derived images of the configuration for the test
+func useWallpaperLink(t *testing.T, derive []string) string {
+	t.Helper()
+	cfg := config.Get()
+	if cfg == nil {
+		t.Skip("no configuration")
+	}
+
+	link, previousDerive := paths.WallpaperLinkPath, cfg.Wallpaper.Derive
+	t.Cleanup(func() {
+		paths.WallpaperLinkPath = link
+		cfg.Wallpaper.Derive = previousDerive
+	})
+	paths.WallpaperLinkPath = filepath.Join(t.TempDir(), "current")
+	cfg.Wallpaper.Derive = derive
+	return paths.WallpaperLinkPath
+}
+
+func TestHyprlockWallpaper(t *testing.T) {
+	link := useWallpaperLink(t, []string{wallpaper.DeriveBlur})
+
+	// Without a wallpaper the value is empty and hyprlock uses the color
+	if got := GetValues("hyprlock")["wallpaper"]; got != "" {
+		t.Errorf("wallpaper without a link = %q, want empty", got)
+	}
+
+	if err := os.WriteFile(link, []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetValues("hyprlock")["wallpaper"]; got != link {
+		t.Errorf("wallpaper = %q, want the current link %q", got, link)
+	}
+
+	// The blurred image is preferred once it exists
+	blurred := wallpaper.DerivedLinkPath(wallpaper.DeriveBlur)
+	if err := os.WriteFile(blurred, []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetValues("hyprlock")["wallpaper"]; got != blurred {
+		t.Errorf("wallpaper = %q, want the blurred link %q", got, blurred)
+	}
+
+	// Unless blur images are no longer derived
+	config.Get().Wallpaper.Derive = []string{wallpaper.DeriveDim}
+	if got := GetValues("hyprlock")["wallpaper"]; got != link {
+		t.Errorf("wallpaper without blur = %q, want %q", got, link)
+	}
+}
+
+func TestGetValues(t *testing.T) {
+	if values := GetValues("kitty"); values != nil {
+		t.Errorf("GetValues(kitty) = %v, want nil for a template without values", values)
+	}
+	if values := GetValues("no-such-app"); values != nil {
+		t.Errorf("GetValues(no-such-app) = %v, want nil", values)
+	}
+}
@@ -24,6 +24,11 @@ type Template struct {
 	// If nil, uses the default logic from config
 	GetOutputPath func() string
 
+	// Values returns extra placeholder values, such as file paths, that are
+	// rendered alongside the scheme colors
+	// If nil, only the colors are available
+	Values func() map[string]string
+
 	// CustomApply is an optional custom application function
 	// If nil, uses the standard template replacement logic
 	CustomApply func(colors map[string]string, mode string) error
@@ -119,6 +124,17 @@ func GetOutputPath(name string) (string, error) {
 	return "", fmt.Errorf("no output path defined for %s", name)
 }
 
+// GetValues returns the extra placeholder values for a given template
+func GetValues(name string) map[string]string {
+	globalRegistry.mu.RLock()
+	defer globalRegistry.mu.RUnlock()
+
+	if template, ok := globalRegistry.templates[name]; ok && template.Values != nil {
+		return template.Values()
+	}
+	return nil
+}
+
 // HasCustomApply checks if a template has a custom apply function
 func HasCustomApply(name string) bool {
 	globalRegistry.mu.RLock()
@@ -9,6 +9,7 @@ import (
 	"text/template"
 	"time"
 
+	colorutil "github.com/arthur404dev/heimdall-cli/internal/utils/color"
 	"github.com/arthur404dev/heimdall-cli/internal/utils/logger"
 )
 
@@ -56,6 +57,8 @@ func NewEngine() *Engine {
 		"hsla":    e.toHSLA,
 		"lighten": e.lighten,
 		"darken":  e.darken,
+		"mix":     e.mix,
+		"oklch":   e.toOKLCH,
 		"alpha":   e.alpha,
 
 		// String manipulation
@@ -501,18 +504,42 @@ func (e *Engine) toHSLA(color interface{}, alpha float64) string {
 	return fmt.Sprintf("hsla(0, 0%%, 0%%, %.2f)", alpha)
 }
 
-// lighten lightens a color by a percentage
+// lighten raises the perceived (OKLCH) lightness of a color by percent points
 func (e *Engine) lighten(color interface{}, percent float64) string {
-	// Implementation would lighten the color
-	// For now, return the original color
-	return e.toHex(color)
+	c, err := colorutil.Parse(e.toHex(color))
+	if err != nil {
+		return e.toHex(color)
+	}
+	return strings.ToLower(c.LightenPerceptual(percent).Hex)
 }
 
-// darken darkens a color by a percentage
+// darken lowers the perceived (OKLCH) lightness of a color by percent points
 func (e *Engine) darken(color interface{}, percent float64) string {
-	// Implementation would darken the color
-	// For now, return the original color
-	return e.toHex(color)
+	c, err := colorutil.Parse(e.toHex(color))
+	if err != nil {
+		return e.toHex(color)
+	}
+	return strings.ToLower(c.DarkenPerceptual(percent).Hex)
+}
+
+// mix mixes two colors in OKLab; amount is the share of the second (0-1)
+func (e *Engine) mix(color, other interface{}, amount float64) string {
+	c1, err1 := colorutil.Parse(e.toHex(color))
+	c2, err2 := colorutil.Parse(e.toHex(other))
+	if err1 != nil || err2 != nil {
+		return e.toHex(color)
+	}
+	return strings.ToLower(colorutil.Mix(c1, c2, amount).Hex)
+}
+
+// toOKLCH converts a color to CSS oklch() format
+func (e *Engine) toOKLCH(color interface{}) string {
+	c, err := colorutil.Parse(e.toHex(color))
+	if err != nil {
+		return e.toHex(color)
+	}
+	value, _ := c.Format(colorutil.FormatOKLCH)
+	return value
 }
 
 // alpha adds alpha channel to a color
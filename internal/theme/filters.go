@@ -0,0 +1,190 @@
+package theme
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/color"
+)
+
+// placeholderKey matches the color key part of a placeholder
+var placeholderKey = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// colorFilters are the filters a placeholder may chain after its key
+var colorFilters = map[string]bool{
+	"default": true, // default:key - used when the key is missing
+	"lighten": true, // lighten[:percent] - raise OKLCH lightness, default 10
+	"darken":  true, // darken[:percent] - lower OKLCH lightness, default 10
+	"mix":     true, // mix:other[:amount] - mix in OKLab, default 0.5
+	"oklch":   true, // oklch - CSS oklch() output
+	"raw":     true, // raw - hex without #
+}
+
+// formatRaw is hex without the # prefix
+const formatRaw = "raw"
+
+// colorFilter is one filter of a placeholder, e.g. mix:surface:0.3
+type colorFilter struct {
+	name string
+	args []string
+}
+
+// placeholder is a parsed simple replacer placeholder of the form
+// key[.raw][|filter[:arg...]]...
+type placeholder struct {
+	key      string
+	raw      bool
+	fallback string
+	filters  []colorFilter
+}
+
+// parsePlaceholder parses the inside of {{...}}. ok is false for anything
+// that is not a simple placeholder, such as Go template actions.
+func parsePlaceholder(inner string) (placeholder, bool) {
+	parts := strings.Split(strings.TrimSpace(inner), "|")
+
+	p := placeholder{key: parts[0]}
+	if strings.HasSuffix(p.key, ".raw") {
+		p.key = strings.TrimSuffix(p.key, ".raw")
+		p.raw = true
+	}
+	if !placeholderKey.MatchString(p.key) {
+		return placeholder{}, false
+	}
+
+	for _, part := range parts[1:] {
+		fields := strings.Split(part, ":")
+		f := colorFilter{name: fields[0], args: fields[1:]}
+		if !colorFilters[f.name] {
+			return placeholder{}, false
+		}
+
+		if f.name == "default" {
+			if len(f.args) != 1 || !placeholderKey.MatchString(f.args[0]) {
+				return placeholder{}, false
+			}
+			p.fallback = f.args[0]
+			continue
+		}
+		p.filters = append(p.filters, f)
+	}
+	return p, true
+}
+
+// mixReferences returns the arguments of mix filters that may name color
+// keys rather than literal hex colors
+func (p placeholder) mixReferences() []string {
+	var refs []string
+	for _, f := range p.filters {
+		if f.name == "mix" && len(f.args) > 0 && placeholderKey.MatchString(f.args[0]) && !isHexColor(f.args[0]) {
+			refs = append(refs, f.args[0])
+		}
+	}
+	return refs
+}
+
+// resolve renders the placeholder with colors, reporting false when a key
+// is missing or a filter cannot be applied
+func (p placeholder) resolve(colors map[string]string) (string, bool) {
+	value, ok := colors[p.key]
+	if !ok && p.fallback != "" {
+		value, ok = colors[p.fallback]
+	}
+	if !ok {
+		return "", false
+	}
+
+	if len(p.filters) == 0 {
+		if len(value) == 6 && isHexColor(value) {
+			value = "#" + value
+		}
+		if p.raw {
+			value = strings.TrimPrefix(value, "#")
+		}
+		return value, true
+	}
+
+	c, err := color.Parse(value)
+	if err != nil {
+		return "", false
+	}
+
+	format := color.FormatHex
+	if p.raw {
+		format = formatRaw
+	}
+	for _, f := range p.filters {
+		switch f.name {
+		case "lighten", "darken":
+			percent, ok := filterNumber(f.args, 0, 10)
+			if !ok {
+				return "", false
+			}
+			if f.name == "lighten" {
+				c = c.LightenPerceptual(percent)
+			} else {
+				c = c.DarkenPerceptual(percent)
+			}
+		case "mix":
+			if len(f.args) == 0 {
+				return "", false
+			}
+			other, ok := lookupColor(f.args[0], colors)
+			if !ok {
+				return "", false
+			}
+			amount, ok := filterNumber(f.args, 1, 0.5)
+			if !ok {
+				return "", false
+			}
+			// mix:other:30 reads as 30%
+			if amount > 1 {
+				amount /= 100
+			}
+			c = color.Mix(c, other, amount)
+		case "oklch":
+			format = color.FormatOKLCH
+		case "raw":
+			format = formatRaw
+		}
+	}
+
+	return formatColor(c, format), true
+}
+
+// formatColor renders a filtered color in lowercase hex, raw hex or a CSS
+// color function
+func formatColor(c *color.Color, format string) string {
+	switch format {
+	case formatRaw:
+		return strings.ToLower(strings.TrimPrefix(c.Hex, "#"))
+	case color.FormatHex:
+		return strings.ToLower(c.Hex)
+	}
+	value, err := c.Format(format)
+	if err != nil {
+		return strings.ToLower(c.Hex)
+	}
+	return value
+}
+
+// lookupColor resolves a filter argument as a color key, falling back to a
+// literal color
+func lookupColor(ref string, colors map[string]string) (*color.Color, bool) {
+	if value, ok := colors[ref]; ok {
+		ref = value
+	}
+	c, err := color.Parse(ref)
+	return c, err == nil
+}
+
+// filterNumber parses the i-th filter argument, or returns def when it is
+// absent
+func filterNumber(args []string, i int, def float64) (float64, bool) {
+	if i >= len(args) {
+		return def, true
+	}
+	v, err := strconv.ParseFloat(args[i], 64)
+	return v, err == nil
+}
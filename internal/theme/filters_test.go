@@ -0,0 +1,58 @@
+package theme
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplaceStringFilters(t *testing.T) {
+	replacer := NewSimpleReplacer()
+	colors := map[string]string{
+		"background": "000000",
+		"foreground": "ffffff",
+		"primary":    "ff0000",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"oklch output", "{{primary|oklch}}", "oklch(62.8% 0.2577 29.23)"},
+		{"mix in oklab", "{{background|mix:foreground}}", "#636363"},
+		{"mix with percentage", "{{background|mix:foreground:100}}", "#ffffff"},
+		{"mix with literal", "{{background|mix:ffffff:0}}", "#000000"},
+		{"lighten", "{{background|lighten:50}}", "#636363"},
+		{"darken", "{{foreground|darken:100}}", "#000000"},
+		{"chained", "{{background|lighten:50|raw}}", "636363"},
+		{"raw key with filter", "{{primary.raw|darken:0}}", "ff0000"},
+		{"default then filter", "{{accent|default:primary|oklch}}", "oklch(62.8% 0.2577 29.23)"},
+		{"default only", "{{accent|default:primary}}", "#ff0000"},
+		{"unknown filter", "{{primary|blur:2}}", "{{primary|blur:2}}"},
+		{"missing mix key", "{{primary|mix:nothere}}", "{{primary|mix:nothere}}"},
+		{"go template action", "{{if .dark}}x{{end}}", "{{if .dark}}x{{end}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replacer.ReplaceString(tt.template, colors); got != tt.expected {
+				t.Errorf("ReplaceString(%q) = %q, want %q", tt.template, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTemplateKeysWithFilters(t *testing.T) {
+	content := "{{surface|mix:primary:0.1}} {{outline|default:onSurface|oklch}} {{base|mix:ffffff}} {{if .dark}}"
+
+	want := []string{"base", "onSurface", "outline", "primary", "surface"}
+	if got := TemplateKeys(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("TemplateKeys() = %v, want %v", got, want)
+	}
+
+	colors := map[string]string{"surface": "111111", "onSurface": "eeeeee"}
+	want = []string{"base", "primary"}
+	if got := MissingTemplateKeys(content, colors); !reflect.DeepEqual(got, want) {
+		t.Errorf("MissingTemplateKeys() = %v, want %v", got, want)
+	}
+}
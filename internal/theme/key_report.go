@@ -0,0 +1,126 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/theme/appthemes"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/color"
+)
+
+// placeholderPattern matches anything in double braces; parsePlaceholder
+// keeps simple replacer placeholders such as {{primary}}, {{primary.raw}},
+// {{cursor|default:foreground}} and {{surface|mix:primary:0.1}} and drops
+// advanced Go template actions ({{if ...}}, {{.Colors.x}})
+var placeholderPattern = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
+// TemplateKeyUsage lists the color keys a template references but a scheme lacks
+type TemplateKeyUsage struct {
+	Template string   `json:"template"`
+	Missing  []string `json:"missing"`
+}
+
+// TemplateKeys returns the sorted, de-duplicated color keys a template references.
+// For placeholders with a default, both the key and its default are returned,
+// as are keys named by mix filters.
+func TemplateKeys(content string) []string {
+	seen := make(map[string]bool)
+	for _, match := range placeholderPattern.FindAllStringSubmatch(content, -1) {
+		p, ok := parsePlaceholder(match[1])
+		if !ok {
+			continue
+		}
+		seen[p.key] = true
+		if p.fallback != "" {
+			seen[p.fallback] = true
+		}
+		for _, key := range p.mixReferences() {
+			seen[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MissingTemplateKeys returns the keys a template references that the colors
+// cannot resolve, taking replacer aliases and |default: fallbacks into account
+func MissingTemplateKeys(content string, colors map[string]string) []string {
+	available := expandColorAliases(colors)
+
+	missing := make(map[string]bool)
+	for _, match := range placeholderPattern.FindAllStringSubmatch(content, -1) {
+		p, ok := parsePlaceholder(match[1])
+		if !ok {
+			continue
+		}
+
+		_, hasKey := available[p.key]
+		if !hasKey && p.fallback != "" {
+			_, hasKey = available[p.fallback]
+		}
+		if !hasKey {
+			missing[p.key] = true
+		}
+
+		// Mix arguments may also be CSS color names
+		for _, ref := range p.mixReferences() {
+			if _, ok := available[ref]; ok {
+				continue
+			}
+			if _, err := color.Parse(ref); err != nil {
+				missing[ref] = true
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(missing))
+	for key := range missing {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TemplateKeyReport checks every registered application template, plus any
+// custom *.tmpl files in customDir, against the colors and returns the
+// templates that reference missing keys, sorted by template name
+func TemplateKeyReport(colors map[string]string, customDir string) []TemplateKeyUsage {
+	templates := make(map[string]string)
+	for _, name := range appthemes.List() {
+		if content, err := appthemes.Get(name); err == nil {
+			templates[name] = content
+		}
+	}
+
+	if customDir != "" {
+		matches, _ := filepath.Glob(filepath.Join(customDir, "*.tmpl"))
+		for _, path := range matches {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			name := "custom:" + strings.TrimSuffix(filepath.Base(path), ".tmpl")
+			templates[name] = string(content)
+		}
+	}
+
+	var report []TemplateKeyUsage
+	for name, content := range templates {
+		if missing := MissingTemplateKeys(content, colors); len(missing) > 0 {
+			report = append(report, TemplateKeyUsage{Template: name, Missing: missing})
+		}
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Template < report[j].Template
+	})
+	return report
+}
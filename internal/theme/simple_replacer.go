@@ -27,12 +27,9 @@ func isHexColor(s string) bool {
 	return true
 }
 
-// ReplaceString performs simple string replacement on template content
-// Replaces patterns like {{colour0}}, {{colour1}}, etc. with actual color values
-func (r *SimpleReplacer) ReplaceString(templateStr string, colors map[string]string) string {
-	result := templateStr
-
-	// Create extended color map with aliases for compatibility
+// expandColorAliases returns a copy of colors extended with the aliases that
+// templates may reference (term/color/colour numbering, text/foreground, cursor)
+func expandColorAliases(colors map[string]string) map[string]string {
 	extendedColors := make(map[string]string)
 	for key, value := range colors {
 		extendedColors[key] = value
@@ -67,8 +64,18 @@ func (r *SimpleReplacer) ReplaceString(templateStr string, colors map[string]str
 		}
 	}
 
-	// First handle placeholders with default values like {{cursor|default:foreground}}
-	// Process all occurrences
+	return extendedColors
+}
+
+// ReplaceString performs simple string replacement on template content
+// Replaces patterns like {{colour0}}, {{colour1}}, etc. with actual color values
+func (r *SimpleReplacer) ReplaceString(templateStr string, colors map[string]string) string {
+	result := templateStr
+
+	extendedColors := expandColorAliases(colors)
+
+	// First handle placeholders with filters like {{cursor|default:foreground}}
+	// or {{surface|lighten:5|oklch}}
 	startPos := 0
 	for startPos < len(result) {
 		start := strings.Index(result[startPos:], "{{")
@@ -83,41 +90,26 @@ func (r *SimpleReplacer) ReplaceString(templateStr string, colors map[string]str
 		}
 		end += start + 2
 
-		placeholder := result[start:end]
-		inner := strings.Trim(placeholder, "{}")
-
-		// Check for default value syntax
-		if strings.Contains(inner, "|default:") {
-			parts := strings.Split(inner, "|default:")
-			if len(parts) == 2 {
-				key := parts[0]
-				defaultKey := parts[1]
-
-				// Try to get the value for the key
-				if val, ok := extendedColors[key]; ok {
-					// Ensure color has # prefix if it's a hex color
-					if len(val) == 6 && isHexColor(val) {
-						val = "#" + val
-					}
-					result = result[:start] + val + result[end:]
-					startPos = start + len(val)
-				} else if defaultVal, ok := extendedColors[defaultKey]; ok {
-					// Use the default value
-					if len(defaultVal) == 6 && isHexColor(defaultVal) {
-						defaultVal = "#" + defaultVal
-					}
-					result = result[:start] + defaultVal + result[end:]
-					startPos = start + len(defaultVal)
-				} else {
-					// Skip this placeholder if we can't resolve it
-					startPos = end
-				}
-			} else {
-				startPos = end
-			}
-		} else {
+		inner := strings.Trim(result[start:end], "{}")
+		if !strings.Contains(inner, "|") {
+			startPos = end
+			continue
+		}
+
+		p, ok := parsePlaceholder(inner)
+		if !ok {
+			startPos = end
+			continue
+		}
+
+		val, ok := p.resolve(extendedColors)
+		if !ok {
+			// Skip this placeholder if we can't resolve it
 			startPos = end
+			continue
 		}
+		result = result[:start] + val + result[end:]
+		startPos = start + len(val)
 	}
 
 	// Replace all remaining color variables with their values
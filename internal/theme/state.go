@@ -20,11 +20,14 @@ type StateManager struct {
 
 // ThemeState represents the current theme state
 type ThemeState struct {
-	Current     CurrentTheme    `json:"current"`
-	History     []ThemeHistory  `json:"history"`
-	Generated   GeneratedInfo   `json:"generated"`
-	Preferences UserPreferences `json:"preferences"`
-	Version     string          `json:"version"`
+	Current     CurrentTheme               `json:"current"`
+	History     []ThemeHistory             `json:"history"`
+	Generated   GeneratedInfo              `json:"generated"`
+	Preferences UserPreferences            `json:"preferences"`
+	AutoMode    AutoModeInfo               `json:"auto_mode"`
+	Rotation    RotationInfo               `json:"rotation"`
+	Schemes     map[string]scheme.Metadata `json:"schemes,omitempty"`
+	Version     string                     `json:"version"`
 }
 
 // CurrentTheme represents the currently active theme
@@ -57,6 +60,26 @@ type GeneratedInfo struct {
 	Metadata         map[string]string `json:"metadata,omitempty"`
 }
 
+// AutoModeInfo tracks the automatic light/dark switching daemon
+type AutoModeInfo struct {
+	Active         bool      `json:"active"`
+	Trigger        string    `json:"trigger,omitempty"`
+	Schedule       string    `json:"schedule,omitempty"`
+	CurrentMode    string    `json:"current_mode,omitempty"`
+	NextMode       string    `json:"next_mode,omitempty"`
+	NextTransition time.Time `json:"next_transition,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at,omitempty"`
+}
+
+// RotationInfo tracks the scheme playlist rotation
+type RotationInfo struct {
+	Active       bool                  `json:"active"`
+	Playlist     string                `json:"playlist,omitempty"`
+	Cursor       scheme.PlaylistCursor `json:"cursor"`
+	LastRotation time.Time             `json:"last_rotation,omitempty"`
+	NextRotation time.Time             `json:"next_rotation,omitempty"`
+}
+
 // UserPreferences stores user preferences for theme behavior
 type UserPreferences struct {
 	AutoApplyGenerated bool   `json:"auto_apply_generated"`
@@ -173,6 +196,14 @@ func (sm *StateManager) SetCurrent(theme CurrentTheme) error {
 	return sm.Save()
 }
 
+// RecordUsage counts an explicit selection of a scheme
+func (sm *StateManager) RecordUsage(name string) error {
+	return sm.UpdateSchemeMetadata(name, func(meta *scheme.Metadata) {
+		meta.LastUsed = time.Now()
+		meta.UsageCount++
+	})
+}
+
 // GetHistory returns the theme history
 func (sm *StateManager) GetHistory() []ThemeHistory {
 	if sm.state == nil {
@@ -263,6 +294,75 @@ func (sm *StateManager) UpdatePreferences(prefs UserPreferences) error {
 	return sm.Save()
 }
 
+// GetAutoMode returns the auto mode daemon information
+func (sm *StateManager) GetAutoMode() AutoModeInfo {
+	if sm.state == nil {
+		sm.state = sm.getDefaultState()
+	}
+	return sm.state.AutoMode
+}
+
+// SetAutoMode records the auto mode daemon information
+func (sm *StateManager) SetAutoMode(info AutoModeInfo) error {
+	if sm.state == nil {
+		sm.state = sm.getDefaultState()
+	}
+
+	info.UpdatedAt = time.Now()
+	sm.state.AutoMode = info
+	return sm.Save()
+}
+
+// GetRotation returns the playlist rotation information
+func (sm *StateManager) GetRotation() RotationInfo {
+	if sm.state == nil {
+		sm.state = sm.getDefaultState()
+	}
+	return sm.state.Rotation
+}
+
+// SetRotation records the playlist rotation information
+func (sm *StateManager) SetRotation(info RotationInfo) error {
+	if sm.state == nil {
+		sm.state = sm.getDefaultState()
+	}
+
+	sm.state.Rotation = info
+	return sm.Save()
+}
+
+// GetSchemeMetadata returns the metadata recorded for a scheme
+func (sm *StateManager) GetSchemeMetadata(name string) scheme.Metadata {
+	if sm.state == nil {
+		sm.state = sm.getDefaultState()
+	}
+	if sm.state.Schemes == nil {
+		sm.state.Schemes = make(map[string]scheme.Metadata)
+	}
+	return sm.state.Schemes[name]
+}
+
+// GetAllSchemeMetadata returns the metadata of every scheme with any recorded
+func (sm *StateManager) GetAllSchemeMetadata() map[string]scheme.Metadata {
+	if sm.state == nil {
+		sm.state = sm.getDefaultState()
+	}
+	return sm.state.Schemes
+}
+
+// UpdateSchemeMetadata modifies the metadata of a scheme and saves the state
+func (sm *StateManager) UpdateSchemeMetadata(name string, update func(*scheme.Metadata)) error {
+	meta := sm.GetSchemeMetadata(name)
+	update(&meta)
+
+	if meta.IsZero() {
+		delete(sm.state.Schemes, name)
+	} else {
+		sm.state.Schemes[name] = meta
+	}
+	return sm.Save()
+}
+
 // ShouldAutoApply checks if a theme from the given source should be auto-applied
 func (sm *StateManager) ShouldAutoApply(source scheme.SchemeSource) bool {
 	prefs := sm.GetPreferences()
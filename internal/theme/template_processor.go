@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"strings"
 	"text/template"
+
+	colorutil "github.com/arthur404dev/heimdall-cli/internal/utils/color"
 )
 
 // templateProcessor implements the TemplateProcessor interface
@@ -28,6 +30,8 @@ func NewTemplateProcessor() TemplateProcessor {
 		// Color manipulation
 		"darken":  tp.darkenColor,
 		"lighten": tp.lightenColor,
+		"mix":     tp.mixColors,
+		"oklch":   tp.toOKLCH,
 		"alpha":   tp.addAlpha,
 		"hex":     tp.toHex,
 		"rgb":     tp.toRGB,
@@ -138,6 +142,8 @@ func (tp *templateProcessor) processProperty(color, property string) string {
 		return tp.toRGB(color)
 	case "rgba":
 		return tp.toRGBA(color, 1.0)
+	case "oklch":
+		return tp.toOKLCH(color)
 	case "noHash":
 		return tp.removeHash(color)
 	default:
@@ -172,6 +178,19 @@ func (tp *templateProcessor) processFunction(color, function string) string {
 			return tp.addAlpha(color, alpha)
 		}
 		return tp.addAlpha(color, 0.8)
+	case "mix":
+		// mix:#rrggbb[:amount]
+		if len(parts) == 2 {
+			args := strings.SplitN(parts[1], ":", 2)
+			amount := 0.5
+			if len(args) == 2 {
+				fmt.Sscanf(args[1], "%f", &amount)
+			}
+			return tp.mixColors(color, args[0], amount)
+		}
+		return color
+	case "oklch":
+		return tp.toOKLCH(color)
 	case "hex":
 		return tp.toHex(color)
 	case "rgb":
@@ -242,69 +261,43 @@ func (tp *templateProcessor) addAlpha(color interface{}, alpha float64) string {
 	return tp.toRGBA(color, alpha)
 }
 
+// darkenColor lowers perceived lightness (OKLCH) by percent points, so the
+// hue does not drift the way it does when scaling RGB channels
 func (tp *templateProcessor) darkenColor(color interface{}, percent float64) string {
-	hex := tp.toHex(color)
-	hex = strings.TrimPrefix(hex, "#")
-
-	if len(hex) != 6 {
-		return "#" + hex
-	}
-
-	var r, g, b int64
-	r, _ = strconv.ParseInt(hex[0:2], 16, 64)
-	g, _ = strconv.ParseInt(hex[2:4], 16, 64)
-	b, _ = strconv.ParseInt(hex[4:6], 16, 64)
-
-	factor := 1.0 - (percent / 100.0)
-	r = int64(float64(r) * factor)
-	g = int64(float64(g) * factor)
-	b = int64(float64(b) * factor)
-
-	// Clamp values
-	if r < 0 {
-		r = 0
-	}
-	if g < 0 {
-		g = 0
-	}
-	if b < 0 {
-		b = 0
+	c, err := colorutil.Parse(tp.toHex(color))
+	if err != nil {
+		return tp.toHex(color)
 	}
-
-	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	return strings.ToLower(c.DarkenPerceptual(percent).Hex)
 }
 
+// lightenColor raises perceived lightness (OKLCH) by percent points
 func (tp *templateProcessor) lightenColor(color interface{}, percent float64) string {
-	hex := tp.toHex(color)
-	hex = strings.TrimPrefix(hex, "#")
-
-	if len(hex) != 6 {
-		return "#" + hex
+	c, err := colorutil.Parse(tp.toHex(color))
+	if err != nil {
+		return tp.toHex(color)
 	}
+	return strings.ToLower(c.LightenPerceptual(percent).Hex)
+}
 
-	var r, g, b int64
-	r, _ = strconv.ParseInt(hex[0:2], 16, 64)
-	g, _ = strconv.ParseInt(hex[2:4], 16, 64)
-	b, _ = strconv.ParseInt(hex[4:6], 16, 64)
-
-	// Calculate lightening
-	factor := percent / 100.0
-	r = r + int64(float64(255-r)*factor)
-	g = g + int64(float64(255-g)*factor)
-	b = b + int64(float64(255-b)*factor)
-
-	// Clamp values
-	if r > 255 {
-		r = 255
-	}
-	if g > 255 {
-		g = 255
-	}
-	if b > 255 {
-		b = 255
+// mixColors mixes two colors in OKLab; amount is the share of the second
+func (tp *templateProcessor) mixColors(color, other interface{}, amount float64) string {
+	c1, err1 := colorutil.Parse(tp.toHex(color))
+	c2, err2 := colorutil.Parse(tp.toHex(other))
+	if err1 != nil || err2 != nil {
+		return tp.toHex(color)
 	}
+	return strings.ToLower(colorutil.Mix(c1, c2, amount).Hex)
+}
 
-	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+// toOKLCH renders a color as CSS oklch(), for GTK4 and web targets
+func (tp *templateProcessor) toOKLCH(color interface{}) string {
+	c, err := colorutil.Parse(tp.toHex(color))
+	if err != nil {
+		return tp.toHex(color)
+	}
+	value, _ := c.Format(colorutil.FormatOKLCH)
+	return value
 }
 
 func (tp *templateProcessor) isDark(color interface{}) bool {
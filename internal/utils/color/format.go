@@ -0,0 +1,168 @@
+package color
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Output formats understood by Format
+const (
+	FormatHex   = "hex"
+	FormatRGB   = "rgb"
+	FormatHSL   = "hsl"
+	FormatOKLCH = "oklch"
+)
+
+// Formats lists the output formats in display order
+var Formats = []string{FormatHex, FormatRGB, FormatHSL, FormatOKLCH}
+
+// Parse reads a color written as hex (#rgb, #rrggbb, with or without #),
+// CSS rgb(), hsl() or oklch(), or a CSS color name
+func Parse(s string) (*Color, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+
+	if fn, args, ok := splitFunction(s); ok {
+		switch fn {
+		case "rgb", "rgba":
+			return parseRGBFunction(s, args)
+		case "hsl", "hsla":
+			return parseHSLFunction(s, args)
+		case "oklch":
+			return parseOKLCHFunction(s, args)
+		}
+		return nil, fmt.Errorf("unsupported color function: %s", fn)
+	}
+
+	if hex, ok := NamedColors[s]; ok {
+		return NewFromHex(hex)
+	}
+
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	return NewFromHex(hex)
+}
+
+// Format writes the color in one of the output formats, using CSS syntax
+// for everything but hex
+func (c *Color) Format(format string) (string, error) {
+	switch format {
+	case FormatHex:
+		return c.Hex, nil
+	case FormatRGB:
+		return fmt.Sprintf("rgb(%d, %d, %d)", c.RGB.R, c.RGB.G, c.RGB.B), nil
+	case FormatHSL:
+		return fmt.Sprintf("hsl(%s, %s%%, %s%%)",
+			formatNumber(c.HSL.H, 1), formatNumber(c.HSL.S, 1), formatNumber(c.HSL.L, 1)), nil
+	case FormatOKLCH:
+		lch := c.OKLCH()
+		return fmt.Sprintf("oklch(%s%% %s %s)",
+			formatNumber(lch.L*100, 2), formatNumber(lch.C, 4), formatNumber(lch.H, 2)), nil
+	}
+	return "", fmt.Errorf("unknown color format: %s (must be one of %s)", format, strings.Join(Formats, ", "))
+}
+
+// formatNumber rounds to at most decimals places and drops trailing zeros
+func formatNumber(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+// splitFunction splits "name(a, b, c)" or "name(a b c)" into its name and
+// arguments
+func splitFunction(s string) (string, []string, bool) {
+	open := strings.IndexByte(s, '(')
+	if open <= 0 || !strings.HasSuffix(s, ")") {
+		return "", nil, false
+	}
+
+	inner := strings.NewReplacer(",", " ", "/", " ").Replace(s[open+1 : len(s)-1])
+	return strings.TrimSpace(s[:open]), strings.Fields(inner), true
+}
+
+// parseRGBFunction parses rgb() arguments as 0-255 numbers or percentages
+func parseRGBFunction(s string, args []string) (*Color, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("invalid rgb color: %s", s)
+	}
+
+	var channels [3]uint8
+	for i := range channels {
+		v, percent, err := parseNumber(args[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid rgb color: %s", s)
+		}
+		if percent {
+			v = v * 255 / 100
+		}
+		channels[i] = uint8(math.Round(math.Max(0, math.Min(255, v))))
+	}
+	return NewFromRGB(channels[0], channels[1], channels[2]), nil
+}
+
+// parseHSLFunction parses hsl() arguments: hue in degrees, saturation and
+// lightness in percent
+func parseHSLFunction(s string, args []string) (*Color, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("invalid hsl color: %s", s)
+	}
+
+	values := make([]float64, 3)
+	for i := range values {
+		v, _, err := parseNumber(strings.TrimSuffix(args[i], "deg"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hsl color: %s", s)
+		}
+		values[i] = v
+	}
+
+	h := math.Mod(values[0], 360)
+	if h < 0 {
+		h += 360
+	}
+	return NewFromHSL(h, math.Max(0, math.Min(100, values[1])), math.Max(0, math.Min(100, values[2]))), nil
+}
+
+// parseOKLCHFunction parses oklch() arguments: lightness as 0-1 or a
+// percentage, chroma and hue in degrees
+func parseOKLCHFunction(s string, args []string) (*Color, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("invalid oklch color: %s", s)
+	}
+
+	l, percent, err := parseNumber(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid oklch color: %s", s)
+	}
+	if percent {
+		l /= 100
+	}
+
+	c, _, err := parseNumber(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid oklch color: %s", s)
+	}
+
+	h, _, err := parseNumber(strings.TrimSuffix(args[2], "deg"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid oklch color: %s", s)
+	}
+
+	return NewFromOKLCH(l, c, h), nil
+}
+
+// parseNumber parses a number with an optional percent sign
+func parseNumber(s string) (float64, bool, error) {
+	percent := strings.HasSuffix(s, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	return v, percent, err
+}
@@ -0,0 +1,176 @@
+package color
+
+import (
+	"math"
+)
+
+// OKLab represents a color in the OKLab perceptual color space
+type OKLab struct {
+	L float64 `json:"l"` // 0-1
+	A float64 `json:"a"` // about -0.4 to 0.4
+	B float64 `json:"b"` // about -0.4 to 0.4
+}
+
+// OKLCH represents a color in OKLCH, the polar form of OKLab
+type OKLCH struct {
+	L float64 `json:"l"` // 0-1
+	C float64 `json:"c"` // 0 to about 0.37 inside sRGB
+	H float64 `json:"h"` // 0-360
+}
+
+// NewFromOKLCH creates a Color from OKLCH values, reducing chroma until the
+// color fits in sRGB
+func NewFromOKLCH(l, c, h float64) *Color {
+	rgb := OKLCH{L: l, C: c, H: h}.ToRGB()
+	return NewFromRGB(rgb.R, rgb.G, rgb.B)
+}
+
+// OKLab returns the color in OKLab space
+func (c *Color) OKLab() OKLab {
+	return c.RGB.ToOKLab()
+}
+
+// OKLCH returns the color in OKLCH space
+func (c *Color) OKLCH() OKLCH {
+	return c.RGB.ToOKLab().ToOKLCH()
+}
+
+// ToOKLab converts RGB to OKLab
+func (rgb RGB) ToOKLab() OKLab {
+	r := srgbToLinear(float64(rgb.R) / 255.0)
+	g := srgbToLinear(float64(rgb.G) / 255.0)
+	b := srgbToLinear(float64(rgb.B) / 255.0)
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	return OKLab{
+		L: 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		A: 1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		B: 0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// ToOKLCH converts OKLab to OKLCH
+func (lab OKLab) ToOKLCH() OKLCH {
+	c := math.Hypot(lab.A, lab.B)
+	// Grays have no meaningful hue, only rounding noise
+	if c < 1e-4 {
+		return OKLCH{L: lab.L}
+	}
+
+	h := math.Atan2(lab.B, lab.A) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return OKLCH{L: lab.L, C: c, H: h}
+}
+
+// ToRGB converts OKLab to RGB, clipping channels outside sRGB
+func (lab OKLab) ToRGB() RGB {
+	r, g, b := lab.linearRGB()
+	return RGB{R: linearToByte(r), G: linearToByte(g), B: linearToByte(b)}
+}
+
+// inGamut reports whether the color fits in sRGB
+func (lab OKLab) inGamut() bool {
+	const epsilon = 1e-4
+	r, g, b := lab.linearRGB()
+	for _, v := range []float64{r, g, b} {
+		if v < -epsilon || v > 1+epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// linearRGB converts OKLab to linear sRGB without clipping
+func (lab OKLab) linearRGB() (r, g, b float64) {
+	l := lab.L + 0.3963377774*lab.A + 0.2158037573*lab.B
+	m := lab.L - 0.1055613458*lab.A - 0.0638541728*lab.B
+	s := lab.L - 0.0894841775*lab.A - 1.2914855480*lab.B
+
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	r = 4.0767416621*l - 3.3077115913*m + 0.2309699292*s
+	g = -1.2684380046*l + 2.6097574011*m - 0.3413193965*s
+	b = -0.0041960863*l - 0.7034186147*m + 1.7076147010*s
+	return r, g, b
+}
+
+// ToOKLab converts OKLCH to OKLab
+func (lch OKLCH) ToOKLab() OKLab {
+	h := lch.H * math.Pi / 180
+	return OKLab{L: lch.L, A: lch.C * math.Cos(h), B: lch.C * math.Sin(h)}
+}
+
+// ToRGB converts OKLCH to RGB. Colors outside sRGB keep their lightness and
+// hue and lose chroma until they fit, which keeps the perceived color
+// closer than clipping each channel.
+func (lch OKLCH) ToRGB() RGB {
+	lch.L = math.Max(0, math.Min(1, lch.L))
+	if lch.ToOKLab().inGamut() {
+		return lch.ToOKLab().ToRGB()
+	}
+
+	low, high := 0.0, lch.C
+	for high-low > 1e-4 {
+		lch.C = (low + high) / 2
+		if lch.ToOKLab().inGamut() {
+			low = lch.C
+		} else {
+			high = lch.C
+		}
+	}
+	lch.C = low
+	return lch.ToOKLab().ToRGB()
+}
+
+// srgbToLinear removes the sRGB transfer function from a 0-1 channel
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToByte applies the sRGB transfer function and scales to 0-255
+func linearToByte(v float64) uint8 {
+	v = math.Max(0, math.Min(1, v))
+	if v <= 0.0031308 {
+		v *= 12.92
+	} else {
+		v = 1.055*math.Pow(v, 1/2.4) - 0.055
+	}
+	return uint8(math.Round(v * 255))
+}
+
+// Mix interpolates between two colors in OKLab, where t is the amount of c2
+// (0-1). Unlike Blend, midpoints keep their perceived lightness and do not
+// turn muddy between complementary hues.
+func Mix(c1, c2 *Color, t float64) *Color {
+	t = math.Max(0, math.Min(1, t))
+	a, b := c1.OKLab(), c2.OKLab()
+
+	rgb := OKLab{
+		L: a.L + (b.L-a.L)*t,
+		A: a.A + (b.A-a.A)*t,
+		B: a.B + (b.B-a.B)*t,
+	}.ToRGB()
+	return NewFromRGB(rgb.R, rgb.G, rgb.B)
+}
+
+// LightenPerceptual raises OKLCH lightness by percent points (0-100),
+// keeping hue and chroma where sRGB allows
+func (c *Color) LightenPerceptual(percent float64) *Color {
+	lch := c.OKLCH()
+	return NewFromOKLCH(math.Min(1, lch.L+percent/100), lch.C, lch.H)
+}
+
+// DarkenPerceptual lowers OKLCH lightness by percent points (0-100),
+// keeping hue and chroma where sRGB allows
+func (c *Color) DarkenPerceptual(percent float64) *Color {
+	lch := c.OKLCH()
+	return NewFromOKLCH(math.Max(0, lch.L-percent/100), lch.C, lch.H)
+}
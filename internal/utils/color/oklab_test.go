@@ -0,0 +1,179 @@
+package color
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOKLCH(t *testing.T) {
+	tests := []struct {
+		name    string
+		hex     string
+		l, c, h float64
+	}{
+		// Reference values from the CSS Color 4 specification
+		{"Red", "#FF0000", 0.62796, 0.25768, 29.2339},
+		{"Green", "#00FF00", 0.86644, 0.29483, 142.4953},
+		{"Blue", "#0000FF", 0.45201, 0.31321, 264.052},
+		{"White", "#FFFFFF", 1, 0, 0},
+		{"Black", "#000000", 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromHex(tt.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lch := c.OKLCH()
+			if math.Abs(lch.L-tt.l) > 0.001 || math.Abs(lch.C-tt.c) > 0.001 || math.Abs(lch.H-tt.h) > 0.1 {
+				t.Errorf("OKLCH() = %+v, want {L:%g C:%g H:%g}", lch, tt.l, tt.c, tt.h)
+			}
+		})
+	}
+}
+
+func TestOKLabRoundTrip(t *testing.T) {
+	for r := 0; r < 256; r += 15 {
+		for g := 0; g < 256; g += 15 {
+			for b := 0; b < 256; b += 15 {
+				rgb := RGB{R: uint8(r), G: uint8(g), B: uint8(b)}
+				if got := rgb.ToOKLab().ToOKLCH().ToRGB(); got != rgb {
+					t.Fatalf("round trip of %v = %v", rgb, got)
+				}
+			}
+		}
+	}
+}
+
+func TestOKLCHGamutMapping(t *testing.T) {
+	// Far more chroma than sRGB can show at this lightness and hue
+	c := NewFromOKLCH(0.7, 0.4, 145)
+	lch := c.OKLCH()
+
+	if math.Abs(lch.L-0.7) > 0.01 {
+		t.Errorf("lightness = %g, want about 0.7", lch.L)
+	}
+	if math.Abs(lch.H-145) > 2 {
+		t.Errorf("hue = %g, want about 145", lch.H)
+	}
+	if lch.C >= 0.4 {
+		t.Errorf("chroma = %g, want it reduced to fit sRGB", lch.C)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"#7aa2f7", "#7AA2F7", false},
+		{"7aa2f7", "#7AA2F7", false},
+		{"#fff", "#FFFFFF", false},
+		{"rgb(255, 0, 0)", "#FF0000", false},
+		{"rgb(255 128 0 / 50%)", "#FF8000", false},
+		{"rgb(100%, 0%, 0%)", "#FF0000", false},
+		{"hsl(120, 100%, 50%)", "#00FF00", false},
+		{"hsl(480deg 100% 50%)", "#00FF00", false},
+		{"oklch(62.8% 0.2577 29.23)", "#FF0000", false},
+		{"oklch(0.628 0.2577 29.23)", "#FF0000", false},
+		{"RebeccaPurple", "#663399", false},
+		{"rgb(1, 2)", "", true},
+		{"lab(50 20 20)", "", true},
+		{"notacolor", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			c, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && c.Hex != tt.want {
+				t.Errorf("Parse() = %s, want %s", c.Hex, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	c := NewFromRGB(255, 0, 0)
+
+	tests := map[string]string{
+		FormatHex:   "#FF0000",
+		FormatRGB:   "rgb(255, 0, 0)",
+		FormatHSL:   "hsl(0, 100%, 50%)",
+		FormatOKLCH: "oklch(62.8% 0.2577 29.23)",
+	}
+	for format, want := range tests {
+		got, err := c.Format(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("Format(%s) = %s, want %s", format, got, want)
+		}
+
+		// Every format parses back to the same color
+		parsed, err := Parse(got)
+		if err != nil || parsed.Hex != c.Hex {
+			t.Errorf("Parse(%s) = %v, %v", got, parsed, err)
+		}
+	}
+
+	if _, err := c.Format("cmyk"); err == nil {
+		t.Error("Format(cmyk) should fail")
+	}
+}
+
+func TestNearestName(t *testing.T) {
+	name, distance := NearestName(NewFromRGB(0x66, 0x33, 0x99))
+	if name != "rebeccapurple" || distance != 0 {
+		t.Errorf("NearestName() = %s, %g", name, distance)
+	}
+
+	name, _ = NearestName(NewFromRGB(0xFE, 0x01, 0x02))
+	if name != "red" {
+		t.Errorf("NearestName() = %s, want red", name)
+	}
+}
+
+func TestMix(t *testing.T) {
+	black := NewFromRGB(0, 0, 0)
+	white := NewFromRGB(255, 255, 255)
+
+	if got := Mix(black, white, 0).Hex; got != "#000000" {
+		t.Errorf("Mix(t=0) = %s", got)
+	}
+	if got := Mix(black, white, 1).Hex; got != "#FFFFFF" {
+		t.Errorf("Mix(t=1) = %s", got)
+	}
+
+	// The OKLab midpoint sits at half perceived lightness
+	mid := Mix(black, white, 0.5).OKLCH()
+	if math.Abs(mid.L-0.5) > 0.01 {
+		t.Errorf("Mix(t=0.5) lightness = %g, want 0.5", mid.L)
+	}
+}
+
+func TestPerceptualLightness(t *testing.T) {
+	c, _ := NewFromHex("#3D5AFE")
+	before := c.OKLCH()
+
+	lighter := c.LightenPerceptual(10).OKLCH()
+	if math.Abs(lighter.L-(before.L+0.1)) > 0.01 {
+		t.Errorf("lightened L = %g, want %g", lighter.L, before.L+0.1)
+	}
+	if math.Abs(lighter.H-before.H) > 3 {
+		t.Errorf("lightened hue = %g, want about %g", lighter.H, before.H)
+	}
+
+	darker := c.DarkenPerceptual(10).OKLCH()
+	if math.Abs(darker.L-(before.L-0.1)) > 0.01 {
+		t.Errorf("darkened L = %g, want %g", darker.L, before.L-0.1)
+	}
+	if math.Abs(darker.H-before.H) > 3 {
+		t.Errorf("darkened hue = %g, want about %g", darker.H, before.H)
+	}
+}
@@ -0,0 +1,181 @@
+// Package daemon starts background processes detached from the terminal
+// and tracks them with PID files, so commands can stop them later.
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// stopTimeout is how long Stop waits for a process to exit after SIGTERM
+// before killing it
+const stopTimeout = 3 * time.Second
+
+// Process is a background process
+type Process struct {
+	Name    string   // Shown in messages, e.g. "PIP daemon"
+	PIDFile string   // Records the running process; empty for none
+	LogFile string   // Receives the output; empty discards it
+	Command string   // Program to run; empty re-executes heimdall
+	Args    []string // Arguments; nil re-executes the current command without -d
+	Env     []string // Added to the inherited environment
+}
+
+// ForegroundArgs returns the arguments of the current command without the
+// -d and --daemon flags
+func ForegroundArgs() []string {
+	var args []string
+	for _, arg := range os.Args[1:] {
+		if arg == "-d" || arg == "--daemon" {
+			continue
+		}
+		args = append(args, arg)
+	}
+	return args
+}
+
+// PID returns the PID of the running process, or 0
+func (p Process) PID() int {
+	if p.PIDFile == "" {
+		return 0
+	}
+	data, err := os.ReadFile(p.PIDFile)
+	if err != nil {
+		return 0
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || !alive(pid) {
+		return 0
+	}
+	return pid
+}
+
+// Running reports whether the process recorded in the PID file is alive
+func (p Process) Running() bool {
+	return p.PID() != 0
+}
+
+// Start runs the process in a new session and records its PID
+func (p Process) Start() (int, error) {
+	if pid := p.PID(); pid != 0 {
+		return 0, fmt.Errorf("%s already running (PID: %d)", p.Name, pid)
+	}
+
+	command, args := p.Command, p.Args
+	if command == "" {
+		command = os.Args[0]
+		if args == nil {
+			args = ForegroundArgs()
+		}
+	}
+
+	cmd := exec.Command(command, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setsid: true,
+	}
+	if len(p.Env) > 0 {
+		cmd.Env = append(os.Environ(), p.Env...)
+	}
+
+	if p.LogFile != "" {
+		output, err := os.OpenFile(p.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return 0, fmt.Errorf("failed to open log file: %w", err)
+		}
+		defer output.Close()
+		cmd.Stdout = output
+		cmd.Stderr = output
+	}
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start %s: %w", p.Name, err)
+	}
+
+	pid := cmd.Process.Pid
+	if p.PIDFile != "" {
+		if err := os.WriteFile(p.PIDFile, []byte(strconv.Itoa(pid)), 0644); err != nil {
+			// A process nobody can find again must not keep running
+			cmd.Process.Kill()
+			return 0, fmt.Errorf("failed to write PID file: %w", err)
+		}
+	}
+
+	cmd.Process.Release()
+	return pid, nil
+}
+
+// Stop asks the process to exit, kills it when it does not exit in time
+// and returns its PID
+func (p Process) Stop() (int, error) {
+	pid, proc, err := p.find()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := proc.Signal(syscall.SIGTERM); err != nil {
+		return 0, fmt.Errorf("failed to stop %s: %w", p.Name, err)
+	}
+	for deadline := time.Now().Add(stopTimeout); alive(pid) && time.Now().Before(deadline); {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if alive(pid) {
+		if err := proc.Kill(); err != nil {
+			return 0, fmt.Errorf("failed to kill %s: %w", p.Name, err)
+		}
+	}
+
+	p.RemovePID()
+	return pid, nil
+}
+
+// Kill terminates the process immediately and returns its PID
+func (p Process) Kill() (int, error) {
+	pid, proc, err := p.find()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := proc.Kill(); err != nil {
+		return 0, fmt.Errorf("failed to kill %s: %w", p.Name, err)
+	}
+
+	p.RemovePID()
+	return pid, nil
+}
+
+// RemovePID removes the PID file, for processes that exit on their own
+func (p Process) RemovePID() {
+	if p.PIDFile != "" {
+		os.Remove(p.PIDFile)
+	}
+}
+
+// find returns the running process, removing a stale PID file
+func (p Process) find() (int, *os.Process, error) {
+	pid := p.PID()
+	if pid == 0 {
+		p.RemovePID()
+		return 0, nil, fmt.Errorf("%s is not running", p.Name)
+	}
+
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to find %s process: %w", p.Name, err)
+	}
+	return pid, proc, nil
+}
+
+// alive reports whether a process with pid exists
+func alive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
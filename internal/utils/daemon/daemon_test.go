@@ -0,0 +1,90 @@
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestProcessPID(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "test.pid")
+	p := Process{Name: "test daemon", PIDFile: pidFile}
+
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"missing file", "", 0},
+		{"invalid content", "invalid", 0},
+		{"dead process", "999999", 0},
+		{"running process", strconv.Itoa(os.Getpid()), os.Getpid()},
+	}
+	for _, tt := range tests {
+		os.Remove(pidFile)
+		if tt.content != "" {
+			if err := os.WriteFile(pidFile, []byte(tt.content+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := p.PID(); got != tt.want {
+			t.Errorf("%s: PID() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := p.Start(); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("Start() error = %v, want already running", err)
+	}
+}
+
+func TestProcessStartStop(t *testing.T) {
+	dir := t.TempDir()
+	p := Process{
+		Name:    "test daemon",
+		PIDFile: filepath.Join(dir, "test.pid"),
+		LogFile: filepath.Join(dir, "test.log"),
+		Command: "sh",
+		Args:    []string{"-c", "echo $DAEMON_TEST"},
+		Env:     []string{"DAEMON_TEST=started"},
+	}
+
+	pid, err := p.Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if data, _ := os.ReadFile(p.PIDFile); string(data) != strconv.Itoa(pid) {
+		t.Errorf("PID file = %q, want %d", data, pid)
+	}
+
+	// The released child is reaped once it exits, so wait for its output
+	proc, _ := os.FindProcess(pid)
+	proc.Wait()
+	if data, _ := os.ReadFile(p.LogFile); strings.TrimSpace(string(data)) != "started" {
+		t.Errorf("log = %q, want the environment to be passed on", data)
+	}
+
+	// A stale PID file is removed
+	if _, err := p.Stop(); err == nil || !strings.Contains(err.Error(), "not running") {
+		t.Errorf("Stop() error = %v, want not running", err)
+	}
+	if _, err := os.Stat(p.PIDFile); !os.IsNotExist(err) {
+		t.Error("stale PID file was not removed")
+	}
+
+	// A running process is stopped
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("sleep not available: %v", err)
+	}
+	go cmd.Wait()
+	os.WriteFile(p.PIDFile, []byte(strconv.Itoa(cmd.Process.Pid)), 0644)
+	if stopped, err := p.Stop(); err != nil || stopped != cmd.Process.Pid {
+		t.Errorf("Stop() = %d, %v, want %d", stopped, err, cmd.Process.Pid)
+	}
+	if p.Running() {
+		t.Error("process still running after Stop()")
+	}
+}
@@ -0,0 +1,283 @@
+package imageio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"os"
+
+	"golang.org/x/image/riff"
+	"golang.org/x/image/webp"
+)
+
+// MaxSampledFrames is how many frames of an animation are used for color
+// extraction
+const MaxSampledFrames = 8
+
+// frame is one frame of an animation, positioned on the canvas
+type frame struct {
+	image   image.Image
+	bounds  image.Rectangle
+	dispose int  // gif.DisposalNone, DisposalBackground or DisposalPrevious
+	replace bool // Draw over the canvas without alpha blending
+}
+
+// sampleIndices returns up to n evenly spaced frame indices, taken from the
+// middle of each stretch so a fade-in first frame does not dominate
+func sampleIndices(total, n int) []int {
+	if total <= n {
+		n = total
+	}
+	indices := make([]int, n)
+	for k := range indices {
+		indices[k] = (2*k + 1) * total / (2 * n)
+	}
+	return indices
+}
+
+// sampleAnimation composites frames onto a width×height canvas and lays the
+// sampled ones out side by side. The strip keeps the color distribution of
+// the whole animation, so every analysis pass can treat it as one image.
+func sampleAnimation(width, height, total int, frameAt func(i int) (frame, error)) (image.Image, error) {
+	if total == 0 {
+		return nil, fmt.Errorf("animation has no frames")
+	}
+
+	indices := sampleIndices(total, MaxSampledFrames)
+	strip := image.NewRGBA(image.Rect(0, 0, width*len(indices), height))
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	var previous *image.RGBA
+
+	next := 0
+	for i := 0; i <= indices[len(indices)-1]; i++ {
+		f, err := frameAt(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode frame %d: %w", i, err)
+		}
+
+		if f.dispose == gif.DisposalPrevious {
+			previous = cloneRGBA(canvas)
+		}
+
+		op := draw.Over
+		if f.replace {
+			op = draw.Src
+		}
+		draw.Draw(canvas, f.bounds, f.image, f.image.Bounds().Min, op)
+
+		if i == indices[next] {
+			offset := image.Pt(width*next, 0)
+			draw.Draw(strip, canvas.Bounds().Add(offset), canvas, image.Point{}, draw.Src)
+			next++
+		}
+
+		switch f.dispose {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, f.bounds, image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			if previous != nil {
+				canvas = previous
+			}
+		}
+	}
+
+	return strip, nil
+}
+
+// cloneRGBA copies an RGBA image
+func cloneRGBA(img *image.RGBA) *image.RGBA {
+	clone := image.NewRGBA(img.Bounds())
+	copy(clone.Pix, img.Pix)
+	return clone
+}
+
+// gifDecoder samples the frames of animated GIFs
+type gifDecoder struct{}
+
+func (d *gifDecoder) Name() string { return "gif" }
+
+func (d *gifDecoder) Extensions() []string { return []string{".gif"} }
+
+func (d *gifDecoder) Match(ext string, header []byte) bool {
+	return bytes.HasPrefix(header, []byte("GIF8"))
+}
+
+func (d *gifDecoder) Decode(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	g, err := gif.DecodeAll(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.Image) == 1 {
+		return g.Image[0], nil
+	}
+
+	return sampleAnimation(g.Config.Width, g.Config.Height, len(g.Image), func(i int) (frame, error) {
+		f := frame{image: g.Image[i], bounds: g.Image[i].Bounds()}
+		if i < len(g.Disposal) {
+			f.dispose = int(g.Disposal[i])
+		}
+		return f, nil
+	})
+}
+
+func (d *gifDecoder) DecodeConfig(path string) (image.Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer file.Close()
+
+	return gif.DecodeConfig(file)
+}
+
+// animatedWebPDecoder samples the frames of animated WebP files, which
+// x/image/webp does not decode. Each ANMF frame is rewrapped as a still
+// WebP and decoded on its own.
+type animatedWebPDecoder struct{}
+
+// WebP VP8X flags
+const (
+	webpAnimationFlag = 1 << 1
+	webpAlphaFlag     = 1 << 4
+)
+
+func (d *animatedWebPDecoder) Name() string { return "webp-animated" }
+
+func (d *animatedWebPDecoder) Extensions() []string { return []string{".webp"} }
+
+func (d *animatedWebPDecoder) Match(ext string, header []byte) bool {
+	return isWebP(header) && len(header) > 20 && string(header[12:16]) == "VP8X" && header[20]&webpAnimationFlag != 0
+}
+
+func (d *animatedWebPDecoder) Decode(path string) (image.Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height, frames, err := parseAnimatedWebP(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return sampleAnimation(width, height, len(frames), func(i int) (frame, error) {
+		return decodeWebPFrame(frames[i])
+	})
+}
+
+func (d *animatedWebPDecoder) DecodeConfig(path string) (image.Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer file.Close()
+
+	return webp.DecodeConfig(file)
+}
+
+// parseAnimatedWebP returns the canvas size and the raw ANMF chunks of an
+// animated WebP
+func parseAnimatedWebP(data []byte) (width, height int, frames [][]byte, err error) {
+	formType, reader, err := riff.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if formType != riff.FourCC([4]byte{'W', 'E', 'B', 'P'}) {
+		return 0, 0, nil, fmt.Errorf("not a WebP file")
+	}
+
+	for {
+		id, _, chunk, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, 0, nil, err
+		}
+
+		payload, err := io.ReadAll(chunk)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+
+		switch string(id[:]) {
+		case "VP8X":
+			if len(payload) < 10 {
+				return 0, 0, nil, fmt.Errorf("invalid VP8X chunk")
+			}
+			width = int(uint24(payload[4:])) + 1
+			height = int(uint24(payload[7:])) + 1
+		case "ANMF":
+			if len(payload) < 16 {
+				return 0, 0, nil, fmt.Errorf("invalid ANMF chunk")
+			}
+			frames = append(frames, payload)
+		}
+	}
+
+	if width == 0 || height == 0 {
+		return 0, 0, nil, fmt.Errorf("missing VP8X chunk")
+	}
+	return width, height, frames, nil
+}
+
+// decodeWebPFrame decodes an ANMF payload by wrapping its frame data in a
+// still WebP container
+func decodeWebPFrame(payload []byte) (frame, error) {
+	x := int(uint24(payload[0:])) * 2
+	y := int(uint24(payload[3:])) * 2
+	w := uint24(payload[6:]) + 1
+	h := uint24(payload[9:]) + 1
+	flags := payload[15]
+	data := payload[16:]
+
+	var vp8x [10]byte
+	if bytes.HasPrefix(data, []byte("ALPH")) {
+		vp8x[0] = webpAlphaFlag
+	}
+	putUint24(vp8x[4:], w-1)
+	putUint24(vp8x[7:], h-1)
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+8+len(vp8x)+len(data)))
+	buf.WriteString("WEBPVP8X")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(vp8x)))
+	buf.Write(vp8x[:])
+	buf.Write(data)
+
+	img, err := webp.Decode(&buf)
+	if err != nil {
+		return frame{}, err
+	}
+
+	f := frame{
+		image:   img,
+		bounds:  image.Rect(x, y, x+int(w), y+int(h)),
+		replace: flags&0x02 != 0,
+	}
+	if flags&0x01 != 0 {
+		f.dispose = gif.DisposalBackground
+	}
+	return f, nil
+}
+
+// uint24 reads a little-endian 24-bit value
+func uint24(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+}
+
+// putUint24 writes a little-endian 24-bit value
+func putUint24(b []byte, v uint32) {
+	b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16)
+}
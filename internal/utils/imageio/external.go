@@ -0,0 +1,106 @@
+package imageio
+
+import (
+	"context"
+	"fmt"
+	"image"
+	_ "image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// convertTimeout bounds a single external conversion
+const convertTimeout = 30 * time.Second
+
+// ExternalDecoder converts images to PNG with an external tool, for formats
+// without a pure-Go decoder such as AVIF, HEIC and JPEG XL. The tool is
+// called as "tool INPUT OUTPUT.png", which ImageMagick, heif-convert,
+// avifdec and djxl all accept.
+type ExternalDecoder struct {
+	command string
+}
+
+// NewExternalDecoder returns a decoder using command, or nil when command is
+// empty or not installed
+func NewExternalDecoder(command string) *ExternalDecoder {
+	if command == "" {
+		return nil
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		return nil
+	}
+	return &ExternalDecoder{command: command}
+}
+
+func (d *ExternalDecoder) Name() string { return filepath.Base(d.command) }
+
+func (d *ExternalDecoder) Extensions() []string {
+	return []string{".avif", ".heic", ".heif", ".jxl"}
+}
+
+// Match accepts every file, so the converter is the fallback for anything
+// the pure-Go decoders cannot read
+func (d *ExternalDecoder) Match(ext string, header []byte) bool {
+	return true
+}
+
+func (d *ExternalDecoder) Decode(path string) (image.Image, error) {
+	var img image.Image
+	err := d.convert(path, func(file *os.File) error {
+		var err error
+		img, _, err = image.Decode(file)
+		return err
+	})
+	return img, err
+}
+
+func (d *ExternalDecoder) DecodeConfig(path string) (image.Config, error) {
+	var cfg image.Config
+	err := d.convert(path, func(file *os.File) error {
+		var err error
+		cfg, _, err = image.DecodeConfig(file)
+		return err
+	})
+	return cfg, err
+}
+
+// convert writes path as a temporary PNG and passes it to read
+func (d *ExternalDecoder) convert(path string, read func(*os.File) error) error {
+	tmp, err := os.CreateTemp("", "heimdall-*.png")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	// An absolute path is never taken for an option, even when the file
+	// name starts with a dash
+	input, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	switch filepath.Base(d.command) {
+	case "magick", "convert":
+		// Only the first frame, or ImageMagick writes one file per frame
+		input += "[0]"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), convertTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, d.command, input, tmp.Name()).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("conversion failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	file, err := os.Open(tmp.Name())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return read(file)
+}
@@ -0,0 +1,261 @@
+package imageio
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+
+	"github.com/arthur404dev/heimdall-cli/internal/config"
+)
+
+// headerSize is how many leading bytes decoders get to sniff the format
+const headerSize = 32
+
+// Decoder decodes one or more image formats
+type Decoder interface {
+	// Name identifies the decoder in errors
+	Name() string
+	// Extensions lists the file extensions the decoder handles
+	Extensions() []string
+	// Match reports whether the decoder should try a file, given its
+	// lowercased extension and first bytes
+	Match(ext string, header []byte) bool
+	// Decode decodes the image at path
+	Decode(path string) (image.Image, error)
+	// DecodeConfig returns the dimensions of the image at path
+	DecodeConfig(path string) (image.Config, error)
+}
+
+// Registry tries decoders in registration order until one succeeds
+type Registry struct {
+	decoders []Decoder
+}
+
+// NewRegistry creates a registry with the given decoders
+func NewRegistry(decoders ...Decoder) *Registry {
+	return &Registry{decoders: decoders}
+}
+
+// Register appends a decoder, to be tried after the existing ones
+func (r *Registry) Register(d Decoder) {
+	r.decoders = append(r.decoders, d)
+}
+
+// Decode decodes the image at path with the first matching decoder that
+// succeeds
+func (r *Registry) Decode(path string) (image.Image, error) {
+	var img image.Image
+	err := r.try(path, func(d Decoder) error {
+		var err error
+		img, err = d.Decode(path)
+		return err
+	})
+	return img, err
+}
+
+// DecodeConfig returns the dimensions of the image at path without
+// decoding it where the format allows
+func (r *Registry) DecodeConfig(path string) (image.Config, error) {
+	var cfg image.Config
+	err := r.try(path, func(d Decoder) error {
+		var err error
+		cfg, err = d.DecodeConfig(path)
+		return err
+	})
+	return cfg, err
+}
+
+// Extensions returns the sorted file extensions any decoder handles
+func (r *Registry) Extensions() []string {
+	seen := make(map[string]bool)
+	var exts []string
+	for _, d := range r.decoders {
+		for _, ext := range d.Extensions() {
+			if !seen[ext] {
+				seen[ext] = true
+				exts = append(exts, ext)
+			}
+		}
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+// Supports reports whether a file extension is handled by any decoder
+func (r *Registry) Supports(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, d := range r.decoders {
+		for _, e := range d.Extensions() {
+			if e == ext {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// try runs fn with each matching decoder, returning the first decoder's
+// error when none succeeds
+func (r *Registry) try(path string, fn func(Decoder) error) error {
+	header, err := readHeader(path)
+	if err != nil {
+		return err
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+
+	var firstErr error
+	for _, d := range r.decoders {
+		if !d.Match(ext, header) {
+			continue
+		}
+		err := fn(d)
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", d.Name(), err)
+		}
+	}
+
+	if firstErr != nil {
+		return fmt.Errorf("failed to decode image %s: %w", path, firstErr)
+	}
+	return fmt.Errorf("failed to decode image %s: unsupported format", path)
+}
+
+// readHeader returns the first bytes of a file
+func readHeader(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, headerSize)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	return header[:n], nil
+}
+
+var (
+	defaultRegistry *Registry
+	defaultOnce     sync.Once
+)
+
+// Default returns the registry used by wallpaper analysis and scheme
+// generation: the pure-Go decoders, followed by the external converter
+// from the config when it is installed
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		defaultRegistry = NewRegistry(NativeDecoders()...)
+
+		converter := "magick"
+		if cfg := config.Get(); cfg != nil {
+			converter = cfg.External.ImageConverter
+		}
+		if external := NewExternalDecoder(converter); external != nil {
+			defaultRegistry.Register(external)
+		}
+	})
+	return defaultRegistry
+}
+
+// Decode decodes an image with the default registry
+func Decode(path string) (image.Image, error) {
+	return Default().Decode(path)
+}
+
+// DecodeConfig returns image dimensions with the default registry
+func DecodeConfig(path string) (image.Config, error) {
+	return Default().DecodeConfig(path)
+}
+
+// Extensions returns the extensions the default registry can decode
+func Extensions() []string {
+	return Default().Extensions()
+}
+
+// Supports reports whether the default registry handles a file extension
+func Supports(path string) bool {
+	return Default().Supports(path)
+}
+
+// NativeDecoders returns the pure-Go decoders: animated GIF and WebP
+// first, so they are sampled across frames, then the still formats
+func NativeDecoders() []Decoder {
+	return []Decoder{
+		&gifDecoder{},
+		&animatedWebPDecoder{},
+		&stdDecoder{},
+	}
+}
+
+// stdDecoder decodes the still formats registered with the image package
+type stdDecoder struct{}
+
+// stdMagic maps format signatures to what the image package registers
+var stdMagic = [][]byte{
+	[]byte("\xff\xd8"),    // JPEG
+	[]byte("\x89PNG\r\n"), // PNG
+	[]byte("GIF8"),        // GIF
+	[]byte("BM"),          // BMP
+	[]byte("II*\x00"),     // TIFF, little endian
+	[]byte("MM\x00*"),     // TIFF, big endian
+	[]byte("RIFF"),        // WebP, checked further in Match
+}
+
+func (d *stdDecoder) Name() string { return "builtin" }
+
+func (d *stdDecoder) Extensions() []string {
+	return []string{".jpg", ".jpeg", ".png", ".gif", ".bmp", ".tif", ".tiff", ".webp"}
+}
+
+func (d *stdDecoder) Match(ext string, header []byte) bool {
+	for _, magic := range stdMagic {
+		if bytes.HasPrefix(header, magic) {
+			return !bytes.Equal(magic, []byte("RIFF")) || isWebP(header)
+		}
+	}
+	return false
+}
+
+func (d *stdDecoder) Decode(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	return img, err
+}
+
+func (d *stdDecoder) DecodeConfig(path string) (image.Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer file.Close()
+
+	cfg, _, err := image.DecodeConfig(file)
+	return cfg, err
+}
+
+// isWebP reports whether a header starts a RIFF WebP container
+func isWebP(header []byte) bool {
+	return len(header) >= 12 && string(header[:4]) == "RIFF" && string(header[8:12]) == "WEBP"
+}
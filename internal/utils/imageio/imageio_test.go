@@ -0,0 +1,156 @@
+package imageio
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePNG writes a solid PNG to dir/name
+func writePNG(t *testing.T, dir, name string, c color.Color) string {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 8, 6))
+	for i := 0; i < len(img.Pix); i += 4 {
+		r, g, b, a := c.RGBA()
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = uint8(r>>8), uint8(g>>8), uint8(b>>8), uint8(a>>8)
+	}
+
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+	require.NoError(t, png.Encode(file, img))
+	return path
+}
+
+// writeAnimatedGIF writes a GIF with one solid frame per color
+func writeAnimatedGIF(t *testing.T, path string, colors []color.RGBA) {
+	t.Helper()
+
+	anim := &gif.GIF{}
+	for _, c := range colors {
+		frame := image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{c})
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, 10)
+		anim.Disposal = append(anim.Disposal, gif.DisposalNone)
+	}
+
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+	require.NoError(t, gif.EncodeAll(file, anim))
+}
+
+func TestSampleIndices(t *testing.T) {
+	assert.Equal(t, []int{0, 1, 2}, sampleIndices(3, 8))
+	assert.Equal(t, []int{2, 7, 12, 17}, sampleIndices(20, 4))
+}
+
+func TestAnimatedGIFSamplesFrames(t *testing.T) {
+	var colors []color.RGBA
+	for i := 0; i < 16; i++ {
+		colors = append(colors, color.RGBA{R: uint8(i * 16), G: 0, B: 255 - uint8(i*16), A: 255})
+	}
+	path := filepath.Join(t.TempDir(), "anim.gif")
+	writeAnimatedGIF(t, path, colors)
+
+	registry := NewRegistry(NativeDecoders()...)
+	img, err := registry.Decode(path)
+	require.NoError(t, err)
+
+	// The sampled frames are laid out side by side
+	assert.Equal(t, image.Rect(0, 0, 4*MaxSampledFrames, 4), img.Bounds())
+	for k, index := range sampleIndices(len(colors), MaxSampledFrames) {
+		r, g, b, _ := img.At(k*4+1, 1).RGBA()
+		want := colors[index]
+		assert.Equal(t, [3]uint8{want.R, want.G, want.B}, [3]uint8{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8)}, "frame %d", index)
+	}
+
+	cfg, err := registry.DecodeConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, 4, cfg.Width)
+	assert.Equal(t, 4, cfg.Height)
+}
+
+// failingDecoder matches every file and always fails
+type failingDecoder struct{}
+
+func (d failingDecoder) Name() string                         { return "failing" }
+func (d failingDecoder) Extensions() []string                 { return []string{".fail"} }
+func (d failingDecoder) Match(ext string, header []byte) bool { return true }
+func (d failingDecoder) Decode(path string) (image.Image, error) {
+	return nil, errors.New("broken")
+}
+func (d failingDecoder) DecodeConfig(path string) (image.Config, error) {
+	return image.Config{}, errors.New("broken")
+}
+
+func TestRegistryFallsBack(t *testing.T) {
+	path := writePNG(t, t.TempDir(), "solid.png", color.RGBA{R: 200, A: 255})
+
+	registry := NewRegistry(failingDecoder{}, &stdDecoder{})
+	img, err := registry.Decode(path)
+	require.NoError(t, err)
+	assert.Equal(t, 8, img.Bounds().Dx())
+
+	registry = NewRegistry(failingDecoder{})
+	_, err = registry.Decode(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failing: broken")
+
+	assert.Equal(t, []string{".fail"}, registry.Extensions())
+	assert.True(t, registry.Supports("wall.FAIL"))
+}
+
+func TestRegistryRejectsUnknownFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wall.avif")
+	require.NoError(t, os.WriteFile(path, []byte("\x00\x00\x00\x1cftypavif"), 0644))
+
+	_, err := NewRegistry(NativeDecoders()...).Decode(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported format")
+}
+
+func TestExternalDecoder(t *testing.T) {
+	// cp stands in for a converter: it is called as "tool INPUT OUTPUT.png"
+	decoder := NewExternalDecoder("cp")
+	require.NotNil(t, decoder)
+	assert.Nil(t, NewExternalDecoder(""))
+	assert.Nil(t, NewExternalDecoder("heimdall-no-such-converter"))
+
+	dir := t.TempDir()
+	path := writePNG(t, dir, "wall.png", color.RGBA{G: 200, A: 255})
+	avif := filepath.Join(dir, "wall.avif")
+	require.NoError(t, os.Rename(path, avif))
+
+	registry := NewRegistry(NativeDecoders()...)
+	registry.Register(decoder)
+
+	// The PNG signature is found, so the builtin decoder handles it
+	img, err := registry.Decode(avif)
+	require.NoError(t, err)
+	assert.Equal(t, 8, img.Bounds().Dx())
+
+	cfg, err := decoder.DecodeConfig(avif)
+	require.NoError(t, err)
+	assert.Equal(t, 6, cfg.Height)
+
+	assert.Contains(t, registry.Extensions(), ".avif")
+
+	// A relative name starting with a dash is not taken for an option
+	dashed := filepath.Join(dir, "-wall.avif")
+	require.NoError(t, os.Rename(avif, dashed))
+	t.Chdir(dir)
+	cfg, err = decoder.DecodeConfig("-wall.avif")
+	require.NoError(t, err)
+	assert.Equal(t, 6, cfg.Height)
+}
@@ -0,0 +1,71 @@
+package material
+
+import "math"
+
+// maxHarmonizeRotation caps how far harmonization rotates a hue, in degrees
+const maxHarmonizeRotation = 15.0
+
+// Harmonize shifts the hue of designColor toward sourceColor by half their
+// hue difference, at most 15 degrees, keeping its chroma and tone. Fixed
+// colors such as success green stay recognisable while fitting the scheme.
+func Harmonize(designColor, sourceColor uint32) uint32 {
+	from := HctFromARGB(designColor)
+	to := HctFromARGB(sourceColor)
+
+	rotation := math.Min(differenceDegrees(from.Hue, to.Hue)*0.5, maxHarmonizeRotation)
+	hue := sanitizeDegrees(from.Hue + rotation*rotationDirection(from.Hue, to.Hue))
+	return NewHct(hue, from.Chroma, from.Tone).ARGB()
+}
+
+// rotationDirection returns 1 when the shortest way from one hue to the
+// other is increasing, -1 otherwise
+func rotationDirection(from, to float64) float64 {
+	if sanitizeDegrees(to-from) <= 180 {
+		return 1
+	}
+	return -1
+}
+
+// CustomColor is a fixed color added to a scheme, such as success green or
+// a brand color
+type CustomColor struct {
+	Name  string // Scheme key, e.g. "success"
+	Value uint32 // ARGB color
+	Blend bool   // Harmonize the hue toward the seed
+}
+
+// CustomColorGroup holds the roles generated for a custom color
+type CustomColorGroup struct {
+	Color            uint32
+	OnColor          uint32
+	ColorContainer   uint32
+	OnColorContainer uint32
+}
+
+// CustomColorRoles builds the color, on-color and container roles of a
+// custom color for a scheme, harmonizing it toward seed when requested
+func CustomColorRoles(custom CustomColor, seed uint32, isDark bool) CustomColorGroup {
+	value := custom.Value
+	if custom.Blend {
+		value = Harmonize(value, seed)
+	}
+
+	// Like the primary palette, keep enough chroma for a clear accent
+	hct := HctFromARGB(value)
+	palette := NewTonalPalette(hct.Hue, math.Max(48, hct.Chroma))
+
+	if isDark {
+		return CustomColorGroup{
+			Color:            palette.Tone(80),
+			OnColor:          palette.Tone(20),
+			ColorContainer:   palette.Tone(30),
+			OnColorContainer: palette.Tone(90),
+		}
+	}
+	return CustomColorGroup{
+		Color:            palette.Tone(40),
+		OnColor:          palette.Tone(100),
+		ColorContainer:   palette.Tone(90),
+		OnColorContainer: palette.Tone(10),
+	}
+}
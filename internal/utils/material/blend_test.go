@@ -0,0 +1,62 @@
+package material
+
+import "testing"
+
+func TestHarmonize(t *testing.T) {
+	const (
+		red    uint32 = 0xffff0000
+		green  uint32 = 0xff00ff00
+		blue   uint32 = 0xff0000ff
+		yellow uint32 = 0xffffff00
+	)
+
+	// Reference values from material-color-utilities
+	tests := []struct {
+		name           string
+		design, source uint32
+		want           uint32
+	}{
+		{"red to blue", red, blue, 0xfffb0057},
+		{"red to green", red, green, 0xffd85600},
+		{"red to yellow", red, yellow, 0xffd85600},
+		{"blue to green", blue, green, 0xff0047a3},
+		{"blue to red", blue, red, 0xff5700dc},
+		{"blue to yellow", blue, yellow, 0xff0047a3},
+		{"green to blue", green, blue, 0xff00fc94},
+		{"green to red", green, red, 0xffb1f000},
+		{"green to yellow", green, yellow, 0xffb1f000},
+		{"yellow to blue", yellow, blue, 0xffebffba},
+		{"yellow to green", yellow, green, 0xffebffba},
+		{"yellow to red", yellow, red, 0xfffff6e3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Harmonize(tt.design, tt.source); got != tt.want {
+				t.Errorf("Harmonize(%08x, %08x) = %08x, want %08x", tt.design, tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomColorRoles(t *testing.T) {
+	green := CustomColor{Name: "success", Value: 0xff4caf50, Blend: true}
+	seed := uint32(0xff4285f4)
+
+	for _, isDark := range []bool{false, true} {
+		roles := CustomColorRoles(green, seed, isDark)
+		if ratio := ratioOfTones(HctFromARGB(roles.Color).Tone, HctFromARGB(roles.OnColor).Tone); ratio < 4.5 {
+			t.Errorf("dark=%v: on-color contrast %.2f, want >= 4.5", isDark, ratio)
+		}
+		if ratio := ratioOfTones(HctFromARGB(roles.ColorContainer).Tone, HctFromARGB(roles.OnColorContainer).Tone); ratio < 4.5 {
+			t.Errorf("dark=%v: on-container contrast %.2f, want >= 4.5", isDark, ratio)
+		}
+	}
+
+	// Harmonizing moves the hue at most 15 degrees toward the seed
+	fixed := HctFromARGB(CustomColorRoles(CustomColor{Value: green.Value}, seed, false).Color)
+	blended := HctFromARGB(CustomColorRoles(green, seed, false).Color)
+	if shift := differenceDegrees(fixed.Hue, blended.Hue); shift < 1 || shift > 15.5 {
+		t.Errorf("hue shift = %.1f, want between 1 and 15", shift)
+	}
+}
@@ -0,0 +1,327 @@
+package material
+
+import "math"
+
+// sRGB to XYZ (D65) matrix used by CAM16 and HCT
+var srgbToXYZ = [3][3]float64{
+	{0.41233895, 0.35762064, 0.18051042},
+	{0.2126, 0.7152, 0.0722},
+	{0.01932141, 0.11916382, 0.95034478},
+}
+
+// XYZ (D65) to sRGB matrix, the inverse of srgbToXYZ
+var xyzToSRGB = [3][3]float64{
+	{3.2413774792388685, -1.5376652402851851, -0.49885366846268053},
+	{-0.9691452513005321, 1.8758853451067872, 0.04156585616912061},
+	{0.05562093689691305, -0.20395524564742123, 1.0571799111220335},
+}
+
+// XYZ to CAM16 cone response matrix
+var xyzToCAM16RGB = [3][3]float64{
+	{0.401288, 0.650173, -0.051461},
+	{-0.250268, 1.204414, 0.045854},
+	{-0.002079, 0.048952, 0.953127},
+}
+
+// whitePointD65 is the standard white point, with Y normalized to 100
+var whitePointD65 = [3]float64{95.047, 100.0, 108.883}
+
+// ViewingConditions are the environment parameters CAM16 depends on
+type ViewingConditions struct {
+	N      float64
+	Aw     float64
+	Nbb    float64
+	Ncb    float64
+	C      float64
+	Nc     float64
+	RgbD   [3]float64
+	Fl     float64
+	FlRoot float64
+	Z      float64
+}
+
+// DefaultViewingConditions match the sRGB standard viewing environment: a
+// D65 white point, a mid-gray background and an average surround
+var DefaultViewingConditions = NewViewingConditions(
+	whitePointD65,
+	200.0/math.Pi*yFromLstar(50.0)/100.0,
+	50.0,
+	2.0,
+	false,
+)
+
+// NewViewingConditions derives CAM16 viewing conditions. adaptingLuminance
+// is in cd/m², backgroundLstar is the L* of the background and surround
+// ranges from 0 (dark) to 2 (average).
+func NewViewingConditions(whitePoint [3]float64, adaptingLuminance, backgroundLstar, surround float64, discountingIlluminant bool) *ViewingConditions {
+	backgroundLstar = math.Max(0.1, backgroundLstar)
+
+	cone := matrixMultiply(whitePoint, xyzToCAM16RGB)
+	rW, gW, bW := cone[0], cone[1], cone[2]
+
+	f := 0.8 + surround/10.0
+	var c float64
+	if f >= 0.9 {
+		c = lerp(0.59, 0.69, (f-0.9)*10.0)
+	} else {
+		c = lerp(0.525, 0.59, (f-0.8)*10.0)
+	}
+
+	d := 1.0
+	if !discountingIlluminant {
+		d = f * (1.0 - (1.0/3.6)*math.Exp((-adaptingLuminance-42.0)/92.0))
+	}
+	d = math.Max(0, math.Min(1, d))
+
+	rgbD := [3]float64{
+		d*(100.0/rW) + 1.0 - d,
+		d*(100.0/gW) + 1.0 - d,
+		d*(100.0/bW) + 1.0 - d,
+	}
+
+	k := 1.0 / (5.0*adaptingLuminance + 1.0)
+	k4 := k * k * k * k
+	k4F := 1.0 - k4
+	fl := k4*adaptingLuminance + 0.1*k4F*k4F*math.Cbrt(5.0*adaptingLuminance)
+
+	n := yFromLstar(backgroundLstar) / whitePoint[1]
+	z := 1.48 + math.Sqrt(n)
+	nbb := 0.725 / math.Pow(n, 0.2)
+
+	var rgbA [3]float64
+	for i, component := range [3]float64{rW, gW, bW} {
+		factor := math.Pow(fl*rgbD[i]*component/100.0, 0.42)
+		rgbA[i] = 400.0 * factor / (factor + 27.13)
+	}
+	aw := (2.0*rgbA[0] + rgbA[1] + 0.05*rgbA[2]) * nbb
+
+	return &ViewingConditions{
+		N:      n,
+		Aw:     aw,
+		Nbb:    nbb,
+		Ncb:    nbb,
+		C:      c,
+		Nc:     f,
+		RgbD:   rgbD,
+		Fl:     fl,
+		FlRoot: math.Pow(fl, 0.25),
+		Z:      z,
+	}
+}
+
+// Cam16 is a color in the CAM16 color appearance model
+type Cam16 struct {
+	Hue    float64 // Hue angle in degrees
+	Chroma float64 // Colorfulness relative to a white of the same brightness
+	J      float64 // Lightness
+	Q      float64 // Brightness
+	M      float64 // Colorfulness
+	S      float64 // Saturation
+	JStar  float64 // CAM16-UCS lightness
+	AStar  float64 // CAM16-UCS a*
+	BStar  float64 // CAM16-UCS b*
+}
+
+// Cam16FromARGB converts an ARGB color under the default viewing conditions
+func Cam16FromARGB(argb uint32) Cam16 {
+	return Cam16FromARGBInViewingConditions(argb, DefaultViewingConditions)
+}
+
+// Cam16FromARGBInViewingConditions converts an ARGB color under the given
+// viewing conditions
+func Cam16FromARGBInViewingConditions(argb uint32, vc *ViewingConditions) Cam16 {
+	xyz := matrixMultiply(linrgbFromARGB(argb), srgbToXYZ)
+	cone := matrixMultiply(xyz, xyzToCAM16RGB)
+
+	var adapted [3]float64
+	for i := range cone {
+		discounted := vc.RgbD[i] * cone[i]
+		factor := math.Pow(vc.Fl*math.Abs(discounted)/100.0, 0.42)
+		adapted[i] = signum(discounted) * 400.0 * factor / (factor + 27.13)
+	}
+	rA, gA, bA := adapted[0], adapted[1], adapted[2]
+
+	// Redness-greenness and yellowness-blueness
+	a := (11.0*rA + -12.0*gA + bA) / 11.0
+	b := (rA + gA - 2.0*bA) / 9.0
+
+	u := (20.0*rA + 20.0*gA + 21.0*bA) / 20.0
+	p2 := (40.0*rA + 20.0*gA + bA) / 20.0
+
+	hue := sanitizeDegrees(math.Atan2(b, a) * 180.0 / math.Pi)
+	hueRadians := hue * math.Pi / 180.0
+
+	ac := p2 * vc.Nbb
+	j := 100.0 * math.Pow(ac/vc.Aw, vc.C*vc.Z)
+	q := (4.0 / vc.C) * math.Sqrt(j/100.0) * (vc.Aw + 4.0) * vc.FlRoot
+
+	huePrime := hue
+	if hue < 20.14 {
+		huePrime = hue + 360
+	}
+	eHue := 0.25 * (math.Cos(huePrime*math.Pi/180.0+2.0) + 3.8)
+	p1 := 50000.0 / 13.0 * eHue * vc.Nc * vc.Ncb
+	t := p1 * math.Hypot(a, b) / (u + 0.305)
+	alpha := math.Pow(1.64-math.Pow(0.29, vc.N), 0.73) * math.Pow(t, 0.9)
+
+	chroma := alpha * math.Sqrt(j/100.0)
+	m := chroma * vc.FlRoot
+	s := 50.0 * math.Sqrt((alpha*vc.C)/(vc.Aw+4.0))
+
+	jStar := (1.0 + 100.0*0.007) * j / (1.0 + 0.007*j)
+	mStar := 1.0 / 0.0228 * math.Log1p(0.0228*m)
+
+	return Cam16{
+		Hue:    hue,
+		Chroma: chroma,
+		J:      j,
+		Q:      q,
+		M:      m,
+		S:      s,
+		JStar:  jStar,
+		AStar:  mStar * math.Cos(hueRadians),
+		BStar:  mStar * math.Sin(hueRadians),
+	}
+}
+
+// Distance returns the CAM16-UCS distance between two colors
+func (c Cam16) Distance(other Cam16) float64 {
+	dJ := c.JStar - other.JStar
+	dA := c.AStar - other.AStar
+	dB := c.BStar - other.BStar
+	return 1.41 * math.Pow(math.Sqrt(dJ*dJ+dA*dA+dB*dB), 0.63)
+}
+
+// linearized converts an sRGB channel (0-255) to linear RGB (0-100)
+func linearized(component uint8) float64 {
+	normalized := float64(component) / 255.0
+	if normalized <= 0.040449936 {
+		return normalized / 12.92 * 100.0
+	}
+	return math.Pow((normalized+0.055)/1.055, 2.4) * 100.0
+}
+
+// delinearized converts linear RGB (0-100) to an sRGB channel (0-255)
+func delinearized(component float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, math.Round(trueDelinearized(component)))))
+}
+
+// trueDelinearized converts linear RGB (0-100) to unrounded sRGB (0-255)
+func trueDelinearized(component float64) float64 {
+	normalized := component / 100.0
+	var value float64
+	if normalized <= 0.0031308 {
+		value = normalized * 12.92
+	} else {
+		value = 1.055*math.Pow(normalized, 1.0/2.4) - 0.055
+	}
+	return value * 255.0
+}
+
+// linrgbFromARGB returns the linear RGB components (0-100) of a color
+func linrgbFromARGB(argb uint32) [3]float64 {
+	return [3]float64{
+		linearized(uint8(argb >> 16)),
+		linearized(uint8(argb >> 8)),
+		linearized(uint8(argb)),
+	}
+}
+
+// argbFromLinrgb converts linear RGB (0-100) to an opaque ARGB color
+func argbFromLinrgb(linrgb [3]float64) uint32 {
+	return argbFromRGB(delinearized(linrgb[0]), delinearized(linrgb[1]), delinearized(linrgb[2]))
+}
+
+// argbFromXYZ converts XYZ (Y 0-100) to an opaque ARGB color
+func argbFromXYZ(x, y, z float64) uint32 {
+	return argbFromLinrgb(matrixMultiply([3]float64{x, y, z}, xyzToSRGB))
+}
+
+// argbFromRGB packs sRGB channels into an opaque ARGB color
+func argbFromRGB(r, g, b uint8) uint32 {
+	return 0xFF000000 | uint32(r)<<16 | uint32(g)<<8 | uint32(b)
+}
+
+// yFromLstar converts L* to relative luminance Y (0-100)
+func yFromLstar(lstar float64) float64 {
+	return 100.0 * labInvf((lstar+16.0)/116.0)
+}
+
+// lstarFromY converts relative luminance Y (0-100) to L*
+func lstarFromY(y float64) float64 {
+	return labF(y/100.0)*116.0 - 16.0
+}
+
+// LstarFromARGB returns the L* (perceptual lightness, the HCT tone) of a color
+func LstarFromARGB(argb uint32) float64 {
+	linrgb := linrgbFromARGB(argb)
+	y := srgbToXYZ[1][0]*linrgb[0] + srgbToXYZ[1][1]*linrgb[1] + srgbToXYZ[1][2]*linrgb[2]
+	return lstarFromY(y)
+}
+
+// labFromARGB converts a color to L*a*b* using the same sRGB and white
+// point definitions as CAM16
+func labFromARGB(argb uint32) LAB {
+	xyz := matrixMultiply(linrgbFromARGB(argb), srgbToXYZ)
+	fx := labF(xyz[0] / whitePointD65[0])
+	fy := labF(xyz[1] / whitePointD65[1])
+	fz := labF(xyz[2] / whitePointD65[2])
+	return LAB{L: 116.0*fy - 16.0, A: 500.0 * (fx - fy), B: 200.0 * (fy - fz)}
+}
+
+// argbFromLstar returns the gray with the given L*
+func argbFromLstar(lstar float64) uint32 {
+	component := delinearized(yFromLstar(lstar))
+	return argbFromRGB(component, component, component)
+}
+
+// labInvf is the inverse of labF
+func labInvf(ft float64) float64 {
+	const e = 216.0 / 24389.0
+	const kappa = 24389.0 / 27.0
+	ft3 := ft * ft * ft
+	if ft3 > e {
+		return ft3
+	}
+	return (116.0*ft - 16.0) / kappa
+}
+
+// matrixMultiply multiplies a row vector by the transpose of a matrix
+func matrixMultiply(row [3]float64, matrix [3][3]float64) [3]float64 {
+	return [3]float64{
+		row[0]*matrix[0][0] + row[1]*matrix[0][1] + row[2]*matrix[0][2],
+		row[0]*matrix[1][0] + row[1]*matrix[1][1] + row[2]*matrix[1][2],
+		row[0]*matrix[2][0] + row[1]*matrix[2][1] + row[2]*matrix[2][2],
+	}
+}
+
+// sanitizeDegrees wraps an angle into [0, 360)
+func sanitizeDegrees(degrees float64) float64 {
+	degrees = math.Mod(degrees, 360.0)
+	if degrees < 0 {
+		degrees += 360.0
+	}
+	return degrees
+}
+
+// differenceDegrees returns the shortest angular distance between two hues
+func differenceDegrees(a, b float64) float64 {
+	return 180.0 - math.Abs(math.Abs(a-b)-180.0)
+}
+
+// lerp linearly interpolates between start and stop
+func lerp(start, stop, amount float64) float64 {
+	return (1.0-amount)*start + amount*stop
+}
+
+// signum returns -1, 0 or 1 depending on the sign of x
+func signum(x float64) float64 {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	default:
+		return 0
+	}
+}
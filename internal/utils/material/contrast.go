@@ -0,0 +1,98 @@
+package material
+
+import "math"
+
+// ratioOfTones returns the WCAG contrast ratio between two tones (L*)
+func ratioOfTones(toneA, toneB float64) float64 {
+	toneA = math.Max(0, math.Min(100, toneA))
+	toneB = math.Max(0, math.Min(100, toneB))
+	return ratioOfYs(yFromLstar(toneA), yFromLstar(toneB))
+}
+
+// ratioOfYs returns the contrast ratio between two luminances (0-100)
+func ratioOfYs(y1, y2 float64) float64 {
+	lighter := math.Max(y1, y2)
+	darker := math.Min(y1, y2)
+	return (lighter + 5.0) / (darker + 5.0)
+}
+
+// lighterTone returns the darkest tone lighter than tone that reaches the
+// contrast ratio, or -1 when none exists
+func lighterTone(tone, ratio float64) float64 {
+	if tone < 0 || tone > 100 {
+		return -1
+	}
+
+	darkY := yFromLstar(tone)
+	lightY := ratio*(darkY+5.0) - 5.0
+	realContrast := ratioOfYs(lightY, darkY)
+	if realContrast < ratio && math.Abs(realContrast-ratio) > 0.04 {
+		return -1
+	}
+
+	// Rounding to sRGB can lower contrast slightly, so aim a little higher
+	value := lstarFromY(lightY) + 0.4
+	if value < 0 || value > 100 {
+		return -1
+	}
+	return value
+}
+
+// darkerTone returns the lightest tone darker than tone that reaches the
+// contrast ratio, or -1 when none exists
+func darkerTone(tone, ratio float64) float64 {
+	if tone < 0 || tone > 100 {
+		return -1
+	}
+
+	lightY := yFromLstar(tone)
+	darkY := (lightY+5.0)/ratio - 5.0
+	realContrast := ratioOfYs(lightY, darkY)
+	if realContrast < ratio && math.Abs(realContrast-ratio) > 0.04 {
+		return -1
+	}
+
+	value := lstarFromY(darkY) - 0.4
+	if value < 0 || value > 100 {
+		return -1
+	}
+	return value
+}
+
+// foregroundTone picks a tone with the given contrast against bgTone,
+// preferring lighter foregrounds on dark backgrounds. When the ratio cannot
+// be reached it returns the side with more contrast.
+func foregroundTone(bgTone, ratio float64) float64 {
+	lighter := lighterTone(bgTone, ratio)
+	if lighter < 0 {
+		lighter = 100
+	}
+	darker := darkerTone(bgTone, ratio)
+	if darker < 0 {
+		darker = 0
+	}
+
+	lighterRatio := ratioOfTones(lighter, bgTone)
+	darkerRatio := ratioOfTones(darker, bgTone)
+
+	if math.Round(bgTone) < 60 {
+		negligible := math.Abs(lighterRatio-darkerRatio) < 0.1 && lighterRatio < ratio && darkerRatio < ratio
+		if lighterRatio >= ratio || lighterRatio >= darkerRatio || negligible {
+			return lighter
+		}
+		return darker
+	}
+
+	if darkerRatio >= ratio || darkerRatio >= lighterRatio {
+		return darker
+	}
+	return lighter
+}
+
+// ensureContrast moves tone to reach the ratio against bgTone when needed
+func ensureContrast(tone, bgTone, ratio float64) float64 {
+	if ratioOfTones(bgTone, tone) >= ratio {
+		return tone
+	}
+	return foregroundTone(bgTone, ratio)
+}
@@ -0,0 +1,395 @@
+package material
+
+import (
+	"fmt"
+	"math"
+)
+
+// Variant selects how a dynamic scheme derives its palettes from the
+// source color
+type Variant string
+
+const (
+	VariantTonalSpot  Variant = "tonalspot"  // Calm, low chroma; the Android default
+	VariantNeutral    Variant = "neutral"    // Nearly grayscale
+	VariantVibrant    Variant = "vibrant"    // Maximum chroma primary, hue-rotated accents
+	VariantExpressive Variant = "expressive" // Primary hue rotated away from the source
+	VariantFidelity   Variant = "fidelity"   // Keeps the source color, complementary tertiary
+	VariantContent    Variant = "content"    // Keeps the source color, analogous tertiary
+	VariantRainbow    Variant = "rainbow"    // Colorful accents on grayscale neutrals
+	VariantFruitSalad Variant = "fruitsalad" // Playful, primary hue shifted by -50°
+	VariantMonochrome Variant = "monochrome" // Grayscale only
+)
+
+// Variants lists every supported variant
+var Variants = []Variant{
+	VariantTonalSpot, VariantNeutral, VariantVibrant, VariantExpressive, VariantFidelity,
+	VariantContent, VariantRainbow, VariantFruitSalad, VariantMonochrome,
+}
+
+// ParseVariant parses a variant name, accepting "tonal" and "fruit_salad"
+// as aliases
+func ParseVariant(name string) (Variant, error) {
+	switch name {
+	case "tonal", "tonal_spot":
+		return VariantTonalSpot, nil
+	case "fruit_salad":
+		return VariantFruitSalad, nil
+	}
+	for _, variant := range Variants {
+		if string(variant) == name {
+			return variant, nil
+		}
+	}
+	return "", fmt.Errorf("unknown variant %q", name)
+}
+
+// Hue rotations applied to the source hue by the vibrant and expressive
+// variants; hue ranges start at each entry of rotationHues
+var (
+	rotationHues                = []float64{0, 41, 61, 101, 131, 181, 251, 301, 360}
+	vibrantSecondaryRotations   = []float64{18, 15, 10, 12, 15, 18, 15, 12, 12}
+	vibrantTertiaryRotations    = []float64{35, 30, 20, 25, 30, 35, 30, 25, 25}
+	expressiveHues              = []float64{0, 21, 51, 121, 151, 191, 271, 321, 360}
+	expressiveSecondaryRotation = []float64{45, 95, 45, 20, 45, 90, 45, 45, 45}
+	expressiveTertiaryRotation  = []float64{120, 120, 20, 45, 20, 15, 20, 120, 120}
+)
+
+// DynamicScheme holds the tonal palettes a variant derives from a source
+// color and maps them to color roles for light or dark mode
+type DynamicScheme struct {
+	Source         Hct
+	Variant        Variant
+	IsDark         bool
+	Primary        TonalPalette
+	Secondary      TonalPalette
+	Tertiary       TonalPalette
+	Neutral        TonalPalette
+	NeutralVariant TonalPalette
+	Error          TonalPalette
+}
+
+// NewDynamicScheme builds the palettes of a variant from a source color
+func NewDynamicScheme(sourceARGB uint32, variant Variant, isDark bool) *DynamicScheme {
+	source := HctFromARGB(sourceARGB)
+	hue, chroma := source.Hue, source.Chroma
+
+	s := &DynamicScheme{
+		Source:  source,
+		Variant: variant,
+		IsDark:  isDark,
+		Error:   NewTonalPalette(25.0, 84.0),
+	}
+
+	switch variant {
+	case VariantNeutral:
+		s.Primary = NewTonalPalette(hue, 12.0)
+		s.Secondary = NewTonalPalette(hue, 8.0)
+		s.Tertiary = NewTonalPalette(hue, 16.0)
+		s.Neutral = NewTonalPalette(hue, 2.0)
+		s.NeutralVariant = NewTonalPalette(hue, 2.0)
+
+	case VariantVibrant:
+		s.Primary = NewTonalPalette(hue, 200.0)
+		s.Secondary = NewTonalPalette(rotatedHue(hue, rotationHues, vibrantSecondaryRotations), 24.0)
+		s.Tertiary = NewTonalPalette(rotatedHue(hue, rotationHues, vibrantTertiaryRotations), 32.0)
+		s.Neutral = NewTonalPalette(hue, 10.0)
+		s.NeutralVariant = NewTonalPalette(hue, 12.0)
+
+	case VariantExpressive:
+		s.Primary = NewTonalPalette(sanitizeDegrees(hue+240.0), 40.0)
+		s.Secondary = NewTonalPalette(rotatedHue(hue, expressiveHues, expressiveSecondaryRotation), 24.0)
+		s.Tertiary = NewTonalPalette(rotatedHue(hue, expressiveHues, expressiveTertiaryRotation), 32.0)
+		s.Neutral = NewTonalPalette(sanitizeDegrees(hue+15.0), 8.0)
+		s.NeutralVariant = NewTonalPalette(sanitizeDegrees(hue+15.0), 12.0)
+
+	case VariantFidelity, VariantContent:
+		s.Primary = NewTonalPalette(hue, chroma)
+		s.Secondary = NewTonalPalette(hue, math.Max(chroma-32.0, chroma*0.5))
+		var tertiary Hct
+		if variant == VariantFidelity {
+			tertiary = newTemperatureCache(source).complement()
+		} else {
+			tertiary = newTemperatureCache(source).analogous(3, 6)[2]
+		}
+		s.Tertiary = TonalPaletteFromARGB(fixIfDisliked(tertiary).ARGB())
+		s.Neutral = NewTonalPalette(hue, chroma/8.0)
+		s.NeutralVariant = NewTonalPalette(hue, chroma/8.0+4.0)
+
+	case VariantRainbow:
+		s.Primary = NewTonalPalette(hue, 48.0)
+		s.Secondary = NewTonalPalette(hue, 16.0)
+		s.Tertiary = NewTonalPalette(sanitizeDegrees(hue+60.0), 24.0)
+		s.Neutral = NewTonalPalette(hue, 0.0)
+		s.NeutralVariant = NewTonalPalette(hue, 0.0)
+
+	case VariantFruitSalad:
+		s.Primary = NewTonalPalette(sanitizeDegrees(hue-50.0), 48.0)
+		s.Secondary = NewTonalPalette(sanitizeDegrees(hue-50.0), 36.0)
+		s.Tertiary = NewTonalPalette(hue, 36.0)
+		s.Neutral = NewTonalPalette(hue, 10.0)
+		s.NeutralVariant = NewTonalPalette(hue, 16.0)
+
+	case VariantMonochrome:
+		s.Primary = NewTonalPalette(hue, 0.0)
+		s.Secondary = NewTonalPalette(hue, 0.0)
+		s.Tertiary = NewTonalPalette(hue, 0.0)
+		s.Neutral = NewTonalPalette(hue, 0.0)
+		s.NeutralVariant = NewTonalPalette(hue, 0.0)
+
+	default:
+		s.Variant = VariantTonalSpot
+		s.Primary = NewTonalPalette(hue, 36.0)
+		s.Secondary = NewTonalPalette(hue, 16.0)
+		s.Tertiary = NewTonalPalette(sanitizeDegrees(hue+60.0), 24.0)
+		s.Neutral = NewTonalPalette(hue, 6.0)
+		s.NeutralVariant = NewTonalPalette(hue, 8.0)
+	}
+
+	return s
+}
+
+// Palette returns the tonal palettes of the scheme
+func (s *DynamicScheme) Palette() *Palette {
+	return &Palette{
+		Seed:           s.Source.ARGB(),
+		Primary:        s.Primary,
+		Secondary:      s.Secondary,
+		Tertiary:       s.Tertiary,
+		Neutral:        s.Neutral,
+		NeutralVariant: s.NeutralVariant,
+		Error:          s.Error,
+	}
+}
+
+// Scheme maps the palettes to Material color roles at standard contrast
+func (s *DynamicScheme) Scheme() *Scheme {
+	fidelity := s.Variant == VariantFidelity || s.Variant == VariantContent
+	monochrome := s.Variant == VariantMonochrome
+
+	scheme := &Scheme{
+		Seed:    s.Source.ARGB(),
+		IsDark:  s.IsDark,
+		Palette: s.Palette(),
+	}
+
+	// Surfaces
+	scheme.Background = s.Neutral.Tone(s.pick(6, 98))
+	scheme.OnBackground = s.Neutral.Tone(s.pick(90, 10))
+	scheme.Surface = s.Neutral.Tone(s.pick(6, 98))
+	scheme.SurfaceDim = s.Neutral.Tone(s.pick(6, 87))
+	scheme.SurfaceBright = s.Neutral.Tone(s.pick(24, 98))
+	scheme.SurfaceContainerLowest = s.Neutral.Tone(s.pick(4, 100))
+	scheme.SurfaceContainerLow = s.Neutral.Tone(s.pick(10, 96))
+	scheme.SurfaceContainer = s.Neutral.Tone(s.pick(12, 94))
+	scheme.SurfaceContainerHigh = s.Neutral.Tone(s.pick(17, 92))
+	scheme.SurfaceContainerHighest = s.Neutral.Tone(s.pick(22, 90))
+	scheme.OnSurface = s.Neutral.Tone(s.pick(90, 10))
+	scheme.SurfaceVariant = s.NeutralVariant.Tone(s.pick(30, 90))
+	scheme.OnSurfaceVariant = s.NeutralVariant.Tone(s.pick(80, 30))
+	scheme.InverseSurface = s.Neutral.Tone(s.pick(90, 20))
+	scheme.InverseOnSurface = s.Neutral.Tone(s.pick(20, 95))
+	scheme.Outline = s.NeutralVariant.Tone(s.pick(60, 50))
+	scheme.OutlineVariant = s.NeutralVariant.Tone(s.pick(30, 80))
+	scheme.Shadow = s.Neutral.Tone(0)
+	scheme.Scrim = s.Neutral.Tone(0)
+	scheme.SurfaceTint = s.Primary.Tone(s.pick(80, 40))
+
+	// Accents are checked against the most contrasting surface they may sit on
+	highestSurface := float64(s.pick(24, 87))
+
+	// Primary
+	primaryTone := float64(s.pick(80, 40))
+	primaryContainerTone := float64(s.pick(30, 90))
+	onPrimaryTone := float64(s.pick(20, 100))
+	onPrimaryContainerTone := float64(s.pick(90, 10))
+	if monochrome {
+		primaryTone = float64(s.pick(100, 0))
+		primaryContainerTone = float64(s.pick(85, 25))
+		onPrimaryTone = float64(s.pick(10, 90))
+		onPrimaryContainerTone = float64(s.pick(0, 100))
+	}
+	if fidelity {
+		primaryContainerTone = s.Source.Tone
+		onPrimaryContainerTone = foregroundTone(primaryContainerTone, 4.5)
+	}
+	primaryTone = ensureContrast(primaryTone, highestSurface, 4.5)
+	primaryContainerTone, primaryTone = s.tonePair(primaryContainerTone, primaryTone, 10)
+
+	scheme.Primary = s.Primary.argbAt(primaryTone)
+	scheme.OnPrimary = s.Primary.argbAt(ensureContrast(onPrimaryTone, primaryTone, 7))
+	scheme.PrimaryContainer = s.Primary.argbAt(primaryContainerTone)
+	scheme.OnPrimaryContainer = s.Primary.argbAt(ensureContrast(onPrimaryContainerTone, primaryContainerTone, 7))
+	scheme.InversePrimary = s.Primary.Tone(s.pick(40, 80))
+
+	// Secondary
+	secondaryTone := float64(s.pick(80, 40))
+	secondaryContainerTone := float64(s.pick(30, 90))
+	onSecondaryTone := float64(s.pick(20, 100))
+	onSecondaryContainerTone := float64(s.pick(90, 10))
+	if monochrome {
+		secondaryContainerTone = float64(s.pick(30, 85))
+		onSecondaryTone = float64(s.pick(10, 100))
+	}
+	if fidelity {
+		secondaryContainerTone = findDesiredChromaByTone(s.Secondary.Hue, s.Secondary.Chroma, secondaryContainerTone, !s.IsDark)
+		onSecondaryContainerTone = foregroundTone(secondaryContainerTone, 4.5)
+	}
+	secondaryTone = ensureContrast(secondaryTone, highestSurface, 4.5)
+	secondaryContainerTone, secondaryTone = s.tonePair(secondaryContainerTone, secondaryTone, 10)
+
+	scheme.Secondary = s.Secondary.argbAt(secondaryTone)
+	scheme.OnSecondary = s.Secondary.argbAt(ensureContrast(onSecondaryTone, secondaryTone, 7))
+	scheme.SecondaryContainer = s.Secondary.argbAt(secondaryContainerTone)
+	scheme.OnSecondaryContainer = s.Secondary.argbAt(ensureContrast(onSecondaryContainerTone, secondaryContainerTone, 7))
+
+	// Tertiary
+	tertiaryTone := float64(s.pick(80, 40))
+	tertiaryContainerTone := float64(s.pick(30, 90))
+	onTertiaryTone := float64(s.pick(20, 100))
+	onTertiaryContainerTone := float64(s.pick(90, 10))
+	if monochrome {
+		tertiaryTone = float64(s.pick(90, 25))
+		tertiaryContainerTone = float64(s.pick(60, 49))
+		onTertiaryTone = float64(s.pick(10, 90))
+		onTertiaryContainerTone = float64(s.pick(0, 100))
+	}
+	if fidelity {
+		tertiaryContainerTone = fixIfDisliked(s.Tertiary.Hct(s.Source.Tone)).Tone
+		onTertiaryContainerTone = foregroundTone(tertiaryContainerTone, 4.5)
+	}
+	tertiaryTone = ensureContrast(tertiaryTone, highestSurface, 4.5)
+	tertiaryContainerTone, tertiaryTone = s.tonePair(tertiaryContainerTone, tertiaryTone, 10)
+
+	scheme.Tertiary = s.Tertiary.argbAt(tertiaryTone)
+	scheme.OnTertiary = s.Tertiary.argbAt(ensureContrast(onTertiaryTone, tertiaryTone, 7))
+	scheme.TertiaryContainer = s.Tertiary.argbAt(tertiaryContainerTone)
+	scheme.OnTertiaryContainer = s.Tertiary.argbAt(ensureContrast(onTertiaryContainerTone, tertiaryContainerTone, 7))
+
+	// Error
+	scheme.Error = s.Error.Tone(s.pick(80, 40))
+	scheme.OnError = s.Error.Tone(s.pick(20, 100))
+	scheme.ErrorContainer = s.Error.Tone(s.pick(30, 90))
+	scheme.OnErrorContainer = s.Error.Tone(s.pick(90, 10))
+
+	// Fixed colors keep the same tones in light and dark mode
+	if monochrome {
+		scheme.PrimaryFixed, scheme.PrimaryFixedDim = s.Primary.Tone(40), s.Primary.Tone(30)
+		scheme.OnPrimaryFixed, scheme.OnPrimaryFixedVariant = s.Primary.Tone(100), s.Primary.Tone(90)
+		scheme.SecondaryFixed, scheme.SecondaryFixedDim = s.Secondary.Tone(80), s.Secondary.Tone(70)
+		scheme.OnSecondaryFixed, scheme.OnSecondaryFixedVariant = s.Secondary.Tone(10), s.Secondary.Tone(25)
+		scheme.TertiaryFixed, scheme.TertiaryFixedDim = s.Tertiary.Tone(40), s.Tertiary.Tone(30)
+		scheme.OnTertiaryFixed, scheme.OnTertiaryFixedVariant = s.Tertiary.Tone(100), s.Tertiary.Tone(90)
+	} else {
+		scheme.PrimaryFixed, scheme.PrimaryFixedDim = s.Primary.Tone(90), s.Primary.Tone(80)
+		scheme.OnPrimaryFixed, scheme.OnPrimaryFixedVariant = s.Primary.Tone(10), s.Primary.Tone(30)
+		scheme.SecondaryFixed, scheme.SecondaryFixedDim = s.Secondary.Tone(90), s.Secondary.Tone(80)
+		scheme.OnSecondaryFixed, scheme.OnSecondaryFixedVariant = s.Secondary.Tone(10), s.Secondary.Tone(30)
+		scheme.TertiaryFixed, scheme.TertiaryFixedDim = s.Tertiary.Tone(90), s.Tertiary.Tone(80)
+		scheme.OnTertiaryFixed, scheme.OnTertiaryFixedVariant = s.Tertiary.Tone(10), s.Tertiary.Tone(30)
+	}
+
+	// Palette key colors
+	scheme.PrimaryPaletteKeyColor = s.Primary.KeyColor.ARGB()
+	scheme.SecondaryPaletteKeyColor = s.Secondary.KeyColor.ARGB()
+	scheme.TertiaryPaletteKeyColor = s.Tertiary.KeyColor.ARGB()
+	scheme.NeutralPaletteKeyColor = s.Neutral.KeyColor.ARGB()
+	scheme.NeutralVariantPaletteKeyColor = s.NeutralVariant.KeyColor.ARGB()
+
+	return scheme
+}
+
+// pick returns the dark or light mode tone
+func (s *DynamicScheme) pick(dark, light int) int {
+	if s.IsDark {
+		return dark
+	}
+	return light
+}
+
+// tonePair keeps a container (nearer to the background) and its accent at
+// least delta apart, and moves tones out of the 50-59 range where neither
+// black nor white text has enough contrast
+func (s *DynamicScheme) tonePair(nearer, farther, delta float64) (float64, float64) {
+	direction := -1.0
+	if s.IsDark {
+		direction = 1.0
+	}
+
+	if (farther-nearer)*direction < delta {
+		farther = math.Max(0, math.Min(100, nearer+delta*direction))
+		if (farther-nearer)*direction < delta {
+			nearer = math.Max(0, math.Min(100, farther-delta*direction))
+		}
+	}
+
+	if nearer >= 50 && nearer < 60 {
+		if direction > 0 {
+			nearer = 60
+			farther = math.Max(farther, nearer+delta*direction)
+		} else {
+			nearer = 49
+			farther = math.Min(farther, nearer+delta*direction)
+		}
+	} else if farther >= 50 && farther < 60 {
+		if direction > 0 {
+			farther = 60
+		} else {
+			farther = 49
+		}
+	}
+
+	return nearer, farther
+}
+
+// argbAt returns the palette color at a fractional tone
+func (tp *TonalPalette) argbAt(tone float64) uint32 {
+	if tone == math.Trunc(tone) {
+		return tp.Tone(int(tone))
+	}
+	return tp.Hct(tone).ARGB()
+}
+
+// rotatedHue rotates the source hue by the rotation of the range it falls in
+func rotatedHue(sourceHue float64, hues, rotations []float64) float64 {
+	for i := 0; i < len(hues)-1; i++ {
+		if hues[i] < sourceHue && sourceHue < hues[i+1] {
+			return sanitizeDegrees(sourceHue + rotations[i])
+		}
+	}
+	return sourceHue
+}
+
+// findDesiredChromaByTone moves away from tone until the palette reaches
+// the requested chroma, stopping at the chroma peak
+func findDesiredChromaByTone(hue, chroma, tone float64, byDecreasingTone bool) float64 {
+	answer := tone
+	closest := NewHct(hue, chroma, tone)
+	if closest.Chroma >= chroma {
+		return answer
+	}
+
+	chromaPeak := closest.Chroma
+	for closest.Chroma < chroma {
+		if byDecreasingTone {
+			answer--
+		} else {
+			answer++
+		}
+
+		candidate := NewHct(hue, chroma, answer)
+		if chromaPeak > candidate.Chroma {
+			break
+		}
+		if math.Abs(candidate.Chroma-chroma) < 0.4 {
+			break
+		}
+
+		if math.Abs(candidate.Chroma-chroma) < math.Abs(closest.Chroma-chroma) {
+			closest = candidate
+		}
+		chromaPeak = math.Max(chromaPeak, candidate.Chroma)
+	}
+
+	return answer
+}
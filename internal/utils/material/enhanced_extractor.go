@@ -9,18 +9,31 @@ import (
 
 // EnhancedExtractor provides improved color extraction for wallpapers
 type EnhancedExtractor struct {
-	quantizer *Quantizer
-	scorer    *Scorer
+	quantizer   *Quantizer
+	scorer      *Scorer
+	pixelBudget int
 }
 
 // NewEnhancedExtractor creates a new enhanced color extractor
 func NewEnhancedExtractor() *EnhancedExtractor {
 	return &EnhancedExtractor{
-		quantizer: NewQuantizer(256), // More colors for better analysis
-		scorer:    NewScorer(),
+		quantizer:   NewQuantizer(256), // More colors for better analysis
+		scorer:      NewScorer(),
+		pixelBudget: DefaultPixelBudget,
 	}
 }
 
+// SetPixelBudget sets the pixel count images are downsampled to before
+// extraction; 0 extracts from the full resolution
+func (e *EnhancedExtractor) SetPixelBudget(budget int) {
+	e.pixelBudget = budget
+}
+
+// SetRefine enables k-means refinement of the dominant colors in L*a*b*
+func (e *EnhancedExtractor) SetRefine(refine bool) {
+	e.quantizer.SetRefine(refine)
+}
+
 // ColorInfo contains detailed information about an extracted color
 type ColorInfo struct {
 	Color        uint32
@@ -38,39 +51,129 @@ func (e *EnhancedExtractor) ExtractColors(img image.Image) (*ExtractedColors, er
 		return nil, fmt.Errorf("image is nil")
 	}
 
+	// Downsample and histogram once for all passes
+	prepared := Prepare(img, e.pixelBudget)
+
 	// Pass 1: Dominant colors by volume
-	dominantColors := e.extractDominantColors(img)
+	dominantColors := e.extractDominantColors(prepared)
 
 	// Pass 2: Vibrant accent colors
-	accentColors := e.extractVibrantColors(img)
+	accentColors := e.extractVibrantColors(prepared)
 
 	// Pass 3: Background color detection
-	backgroundColor := e.extractBackgroundColor(img)
+	backgroundColor := e.extractBackgroundColor(prepared)
 
 	// Pass 4: Edge/contrast colors for UI elements
-	edgeColors := e.extractEdgeColors(img)
+	edgeColors := e.extractEdgeColors(prepared)
 
 	// Analyze overall luminance
-	avgLuminance := e.analyzeLuminance(img)
-	isDark := avgLuminance < 0.5
+	avgLuminance := e.analyzeLuminance(prepared)
 
+	return e.assemble(dominantColors, accentColors, backgroundColor, edgeColors, avgLuminance), nil
+}
+
+// assemble combines the extraction passes and ranks seed candidates
+func (e *EnhancedExtractor) assemble(dominantColors, accentColors []ColorInfo, backgroundColor ColorInfo, edgeColors []ColorInfo, avgLuminance float64) *ExtractedColors {
 	// Combine and deduplicate colors
 	allColors := e.combineColors(dominantColors, accentColors, edgeColors)
 
-	return &ExtractedColors{
+	extracted := &ExtractedColors{
 		Dominant:     dominantColors,
 		Accents:      accentColors,
 		Background:   backgroundColor,
 		EdgeColors:   edgeColors,
 		AllColors:    allColors,
-		IsDark:       isDark,
+		IsDark:       avgLuminance < 0.5,
 		AvgLuminance: avgLuminance,
-	}, nil
+	}
+
+	// Rank seed candidates from every pass
+	extracted.Candidates = e.rankCandidates(extracted.GetBestSeedColor(), []candidatePass{
+		{"dominant", dominantColors},
+		{"accent", accentColors},
+		{"edge", edgeColors},
+	})
+
+	return extracted
+}
+
+// candidatePass is the output of one extraction pass
+type candidatePass struct {
+	name   string
+	colors []ColorInfo
+}
+
+// rankCandidates scores the colors of each pass, merges them and keeps the
+// best color per hue. The automatic seed is always kept.
+func (e *EnhancedExtractor) rankCandidates(auto uint32, passes []candidatePass) []SeedCandidate {
+	byColor := make(map[uint32]*SeedCandidate)
+	var order []uint32
+
+	for _, pass := range passes {
+		counts := make(map[uint32]int, len(pass.colors))
+		for _, info := range pass.colors {
+			counts[info.Color] += info.Population
+		}
+
+		for _, score := range e.scorer.ScoreColors(counts) {
+			candidate, ok := byColor[score.Color]
+			if !ok {
+				hct := HctFromARGB(score.Color)
+				candidate = &SeedCandidate{Color: score.Color, Hue: hct.Hue, Chroma: hct.Chroma, Tone: hct.Tone}
+				byColor[score.Color] = candidate
+				order = append(order, score.Color)
+			}
+			if score.Score > candidate.Score {
+				candidate.Score = score.Score
+				candidate.PopulationScore = score.PopulationScore
+				candidate.ChromaScore = score.ChromaScore
+			}
+			candidate.Sources = append(candidate.Sources, pass.name)
+		}
+	}
+
+	if _, ok := byColor[auto]; !ok {
+		hct := HctFromARGB(auto)
+		byColor[auto] = &SeedCandidate{Color: auto, Hue: hct.Hue, Chroma: hct.Chroma, Tone: hct.Tone}
+		order = append(order, auto)
+	}
+	byColor[auto].Auto = true
+
+	ranked := make([]SeedCandidate, 0, len(order))
+	for _, color := range order {
+		ranked = append(ranked, *byColor[color])
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+
+	// Colors of the same hue produce near-identical schemes
+	kept := []SeedCandidate{*byColor[auto]}
+	for _, candidate := range ranked {
+		if len(kept) >= maxSeedCandidates {
+			break
+		}
+		similar := false
+		for _, k := range kept {
+			if differenceDegrees(candidate.Hue, k.Hue) < minCandidateHueDistance {
+				similar = true
+				break
+			}
+		}
+		if !similar {
+			kept = append(kept, candidate)
+		}
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].Score > kept[j].Score
+	})
+	return kept
 }
 
 // extractDominantColors finds the most prominent colors by volume
-func (e *EnhancedExtractor) extractDominantColors(img image.Image) []ColorInfo {
-	quantResult := e.quantizer.Quantize(img)
+func (e *EnhancedExtractor) extractDominantColors(prepared *PreparedImage) []ColorInfo {
+	quantResult := e.quantizer.QuantizeHistogram(prepared.Histogram)
 
 	colors := make([]ColorInfo, 0)
 	for argb, count := range quantResult.Colors {
@@ -79,8 +182,8 @@ func (e *EnhancedExtractor) extractDominantColors(img image.Image) []ColorInfo {
 	}
 
 	// Sort by population
-	sort.Slice(colors, func(i, j int) bool {
-		return colors[i].Population > colors[j].Population
+	sortColorInfos(colors, func(info ColorInfo) float64 {
+		return float64(info.Population)
 	})
 
 	// Return top 10 dominant colors
@@ -92,37 +195,20 @@ func (e *EnhancedExtractor) extractDominantColors(img image.Image) []ColorInfo {
 }
 
 // extractVibrantColors finds high saturation accent colors
-func (e *EnhancedExtractor) extractVibrantColors(img image.Image) []ColorInfo {
-	bounds := img.Bounds()
-	vibrantMap := make(map[uint32]int)
-
-	// Sample image for vibrant colors
-	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
-		for x := bounds.Min.X; x < bounds.Max.X; x += 2 {
-			c := img.At(x, y)
-			argb := colorToARGB(c)
-
-			// Check if color is vibrant
-			info := e.analyzeColor(argb, 1)
-			if info.Vibrancy > 0.6 && info.Saturation > 0.5 {
-				vibrantMap[argb]++
-			}
-		}
-	}
-
-	// Convert to ColorInfo slice
+func (e *EnhancedExtractor) extractVibrantColors(prepared *PreparedImage) []ColorInfo {
+	// Analyze each distinct color once instead of every pixel
 	colors := make([]ColorInfo, 0)
-	for argb, count := range vibrantMap {
+	for argb, count := range prepared.Histogram {
 		info := e.analyzeColor(argb, count)
-		info.IsAccent = true
-		colors = append(colors, info)
+		if info.Vibrancy > 0.6 && info.Saturation > 0.5 {
+			info.IsAccent = true
+			colors = append(colors, info)
+		}
 	}
 
 	// Sort by vibrancy * population
-	sort.Slice(colors, func(i, j int) bool {
-		scoreI := colors[i].Vibrancy * float64(colors[i].Population)
-		scoreJ := colors[j].Vibrancy * float64(colors[j].Population)
-		return scoreI > scoreJ
+	sortColorInfos(colors, func(info ColorInfo) float64 {
+		return info.Vibrancy * float64(info.Population)
 	})
 
 	// Return top 5 vibrant colors
@@ -134,49 +220,25 @@ func (e *EnhancedExtractor) extractVibrantColors(img image.Image) []ColorInfo {
 }
 
 // extractBackgroundColor finds the most likely background color
-func (e *EnhancedExtractor) extractBackgroundColor(img image.Image) ColorInfo {
+func (e *EnhancedExtractor) extractBackgroundColor(prepared *PreparedImage) ColorInfo {
+	img := prepared.Image
 	bounds := img.Bounds()
 	cornerColors := make(map[uint32]int)
 
-	// Sample corners and edges
-	sampleSize := bounds.Dx() / 10
-	if sampleSize > 50 {
-		sampleSize = 50
-	}
+	// Sample corners, scaling the 50 pixel cap to the prepared size
+	sampleSize := min(bounds.Dx()/10, max(1, int(50/prepared.Scale)))
 
-	// Top-left corner
-	for y := bounds.Min.Y; y < bounds.Min.Y+sampleSize && y < bounds.Max.Y; y++ {
-		for x := bounds.Min.X; x < bounds.Min.X+sampleSize && x < bounds.Max.X; x++ {
-			c := img.At(x, y)
-			argb := colorToARGB(c)
-			cornerColors[argb]++
-		}
-	}
-
-	// Top-right corner
-	for y := bounds.Min.Y; y < bounds.Min.Y+sampleSize && y < bounds.Max.Y; y++ {
-		for x := bounds.Max.X - sampleSize; x < bounds.Max.X && x >= bounds.Min.X; x++ {
-			c := img.At(x, y)
-			argb := colorToARGB(c)
-			cornerColors[argb]++
-		}
+	corners := []image.Point{
+		{bounds.Min.X, bounds.Min.Y},
+		{bounds.Max.X - sampleSize, bounds.Min.Y},
+		{bounds.Min.X, bounds.Max.Y - sampleSize},
+		{bounds.Max.X - sampleSize, bounds.Max.Y - sampleSize},
 	}
-
-	// Bottom-left corner
-	for y := bounds.Max.Y - sampleSize; y < bounds.Max.Y && y >= bounds.Min.Y; y++ {
-		for x := bounds.Min.X; x < bounds.Min.X+sampleSize && x < bounds.Max.X; x++ {
-			c := img.At(x, y)
-			argb := colorToARGB(c)
-			cornerColors[argb]++
-		}
-	}
-
-	// Bottom-right corner
-	for y := bounds.Max.Y - sampleSize; y < bounds.Max.Y && y >= bounds.Min.Y; y++ {
-		for x := bounds.Max.X - sampleSize; x < bounds.Max.X && x >= bounds.Min.X; x++ {
-			c := img.At(x, y)
-			argb := colorToARGB(c)
-			cornerColors[argb]++
+	for _, corner := range corners {
+		for y := max(corner.Y, bounds.Min.Y); y < corner.Y+sampleSize && y < bounds.Max.Y; y++ {
+			for x := max(corner.X, bounds.Min.X); x < corner.X+sampleSize && x < bounds.Max.X; x++ {
+				cornerColors[argbAt(img, x, y)]++
+			}
 		}
 	}
 
@@ -184,7 +246,7 @@ func (e *EnhancedExtractor) extractBackgroundColor(img image.Image) ColorInfo {
 	var bgColor uint32
 	maxCount := 0
 	for argb, count := range cornerColors {
-		if count > maxCount {
+		if count > maxCount || (count == maxCount && argb < bgColor) {
 			maxCount = count
 			bgColor = argb
 		}
@@ -196,15 +258,18 @@ func (e *EnhancedExtractor) extractBackgroundColor(img image.Image) ColorInfo {
 }
 
 // extractEdgeColors finds colors from high-contrast edges
-func (e *EnhancedExtractor) extractEdgeColors(img image.Image) []ColorInfo {
+func (e *EnhancedExtractor) extractEdgeColors(prepared *PreparedImage) []ColorInfo {
+	img := prepared.Image
 	bounds := img.Bounds()
 	edgeColors := make(map[uint32]int)
 
+	// Sample every 3rd source pixel, which is every pixel once downsampled
+	step := max(1, int(math.Round(3/prepared.Scale)))
+
 	// Simple edge detection using color differences
-	for y := bounds.Min.Y + 1; y < bounds.Max.Y-1; y += 3 {
-		for x := bounds.Min.X + 1; x < bounds.Max.X-1; x += 3 {
-			c := img.At(x, y)
-			argb := colorToARGB(c)
+	for y := bounds.Min.Y + 1; y < bounds.Max.Y-1; y += step {
+		for x := bounds.Min.X + 1; x < bounds.Max.X-1; x += step {
+			argb := argbAt(img, x, y)
 
 			// Check neighbors for contrast
 			neighbors := []image.Point{
@@ -213,8 +278,7 @@ func (e *EnhancedExtractor) extractEdgeColors(img image.Image) []ColorInfo {
 
 			hasHighContrast := false
 			for _, n := range neighbors {
-				nc := img.At(n.X, n.Y)
-				nargb := colorToARGB(nc)
+				nargb := argbAt(img, n.X, n.Y)
 
 				if colorDistance(argb, nargb) > 50 {
 					hasHighContrast = true
@@ -236,8 +300,8 @@ func (e *EnhancedExtractor) extractEdgeColors(img image.Image) []ColorInfo {
 	}
 
 	// Sort by population
-	sort.Slice(colors, func(i, j int) bool {
-		return colors[i].Population > colors[j].Population
+	sortColorInfos(colors, func(info ColorInfo) float64 {
+		return float64(info.Population)
 	})
 
 	// Return top 5 edge colors
@@ -249,27 +313,19 @@ func (e *EnhancedExtractor) extractEdgeColors(img image.Image) []ColorInfo {
 }
 
 // analyzeLuminance calculates the average luminance of the image
-func (e *EnhancedExtractor) analyzeLuminance(img image.Image) float64 {
-	bounds := img.Bounds()
+func (e *EnhancedExtractor) analyzeLuminance(prepared *PreparedImage) float64 {
 	totalLuminance := 0.0
 	pixelCount := 0
 
-	// Sample every 4th pixel for performance
-	for y := bounds.Min.Y; y < bounds.Max.Y; y += 4 {
-		for x := bounds.Min.X; x < bounds.Max.X; x += 4 {
-			c := img.At(x, y)
-			r, g, b, _ := c.RGBA()
-
-			// Convert to 0-255 range
-			r8 := float64(r >> 8)
-			g8 := float64(g >> 8)
-			b8 := float64(b >> 8)
-
-			// Calculate relative luminance
-			luminance := (0.2126*r8 + 0.7152*g8 + 0.0722*b8) / 255.0
-			totalLuminance += luminance
-			pixelCount++
-		}
+	for argb, count := range prepared.Histogram {
+		r8 := float64((argb >> 16) & 0xFF)
+		g8 := float64((argb >> 8) & 0xFF)
+		b8 := float64(argb & 0xFF)
+
+		// Calculate relative luminance
+		luminance := (0.2126*r8 + 0.7152*g8 + 0.0722*b8) / 255.0
+		totalLuminance += luminance * float64(count)
+		pixelCount += count
 	}
 
 	if pixelCount == 0 {
@@ -319,6 +375,18 @@ func (e *EnhancedExtractor) analyzeColor(argb uint32, population int) ColorInfo
 	}
 }
 
+// sortColorInfos sorts colors by descending score, breaking ties by color
+// so results do not depend on map iteration order
+func sortColorInfos(colors []ColorInfo, score func(ColorInfo) float64) {
+	sort.Slice(colors, func(i, j int) bool {
+		si, sj := score(colors[i]), score(colors[j])
+		if si != sj {
+			return si > sj
+		}
+		return colors[i].Color < colors[j].Color
+	})
+}
+
 // combineColors merges and deduplicates color lists
 func (e *EnhancedExtractor) combineColors(colorLists ...[]ColorInfo) []ColorInfo {
 	colorMap := make(map[uint32]ColorInfo)
@@ -366,10 +434,29 @@ type ExtractedColors struct {
 	Background   ColorInfo
 	EdgeColors   []ColorInfo
 	AllColors    []ColorInfo
+	Candidates   []SeedCandidate // Ranked seed candidates, best first
 	IsDark       bool
 	AvgLuminance float64
 }
 
+const (
+	maxSeedCandidates       = 8
+	minCandidateHueDistance = 15.0
+)
+
+// SeedCandidate is a possible seed color with the scores it was ranked by
+type SeedCandidate struct {
+	Color           uint32
+	Score           float64
+	PopulationScore float64  // Share of its extraction pass, 0-100
+	ChromaScore     float64  // Closeness to the target chroma, 0-100
+	Hue             float64  // HCT hue
+	Chroma          float64  // HCT chroma
+	Tone            float64  // HCT tone
+	Sources         []string // Extraction passes that found the color
+	Auto            bool     // Whether this is the automatically chosen seed
+}
+
 // GetBestSeedColor finds the best seed color for theme generation
 func (ec *ExtractedColors) GetBestSeedColor() uint32 {
 	// Prefer vibrant accent colors if available
@@ -390,3 +477,11 @@ func (ec *ExtractedColors) GetBestSeedColor() uint32 {
 
 	return bestColor
 }
+
+// SeedCandidate returns the candidate at a 1-based rank
+func (ec *ExtractedColors) SeedCandidate(rank int) (SeedCandidate, error) {
+	if rank < 1 || rank > len(ec.Candidates) {
+		return SeedCandidate{}, fmt.Errorf("seed index %d out of range (1-%d)", rank, len(ec.Candidates))
+	}
+	return ec.Candidates[rank-1], nil
+}
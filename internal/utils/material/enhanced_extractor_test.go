@@ -0,0 +1,74 @@
+package material
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// quadrantImage paints four solid color quadrants
+func quadrantImage(colors [4]color.RGBA) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 80, 60))
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 80; x++ {
+			quadrant := 0
+			if x >= 40 {
+				quadrant++
+			}
+			if y >= 30 {
+				quadrant += 2
+			}
+			img.Set(x, y, colors[quadrant])
+		}
+	}
+	return img
+}
+
+func TestSeedCandidates(t *testing.T) {
+	img := quadrantImage([4]color.RGBA{
+		{40, 60, 120, 255},
+		{220, 170, 140, 255},
+		{30, 160, 90, 255},
+		{200, 40, 60, 255},
+	})
+
+	extracted, err := NewEnhancedExtractor().ExtractColors(img)
+	if err != nil {
+		t.Fatalf("ExtractColors() error = %v", err)
+	}
+
+	if len(extracted.Candidates) == 0 {
+		t.Fatal("expected seed candidates")
+	}
+
+	autoCount := 0
+	for i, candidate := range extracted.Candidates {
+		if candidate.Auto {
+			autoCount++
+			if candidate.Color != extracted.GetBestSeedColor() {
+				t.Errorf("auto candidate %08x is not the best seed %08x", candidate.Color, extracted.GetBestSeedColor())
+			}
+		}
+		if i > 0 && candidate.Score > extracted.Candidates[i-1].Score {
+			t.Errorf("candidate %d ranked above a higher score", i)
+		}
+		for _, other := range extracted.Candidates[:i] {
+			if differenceDegrees(candidate.Hue, other.Hue) < minCandidateHueDistance {
+				t.Errorf("candidates %08x and %08x share a hue", candidate.Color, other.Color)
+			}
+		}
+	}
+	if autoCount != 1 {
+		t.Errorf("expected exactly one auto candidate, got %d", autoCount)
+	}
+
+	if _, err := extracted.SeedCandidate(1); err != nil {
+		t.Errorf("SeedCandidate(1) error = %v", err)
+	}
+	if _, err := extracted.SeedCandidate(0); err == nil {
+		t.Error("SeedCandidate(0) should fail")
+	}
+	if _, err := extracted.SeedCandidate(len(extracted.Candidates) + 1); err == nil {
+		t.Error("SeedCandidate past the end should fail")
+	}
+}
@@ -0,0 +1,44 @@
+package material
+
+// Hct is a color in the HCT color space: CAM16 hue and chroma combined with
+// L* tone. Tone maps directly to contrast, which makes HCT suitable for
+// building accessible color schemes.
+type Hct struct {
+	Hue    float64 // CAM16 hue, 0-360
+	Chroma float64 // CAM16 chroma, 0 to a hue/tone dependent maximum
+	Tone   float64 // L*, 0-100
+	argb   uint32
+}
+
+// HctFromARGB converts an ARGB color to HCT
+func HctFromARGB(argb uint32) Hct {
+	cam := Cam16FromARGB(argb)
+	return Hct{
+		Hue:    cam.Hue,
+		Chroma: cam.Chroma,
+		Tone:   LstarFromARGB(argb),
+		argb:   argb,
+	}
+}
+
+// NewHct returns the in-gamut color closest to the given hue, chroma and
+// tone. Tone is preserved; chroma is reduced to the maximum available for
+// that hue and tone when necessary.
+func NewHct(hue, chroma, tone float64) Hct {
+	return HctFromARGB(solveHct(hue, chroma, tone))
+}
+
+// ARGB returns the color as an opaque ARGB value
+func (h Hct) ARGB() uint32 {
+	return h.argb
+}
+
+// WithTone returns the color with the same hue and chroma at another tone
+func (h Hct) WithTone(tone float64) Hct {
+	return NewHct(h.Hue, h.Chroma, tone)
+}
+
+// WithChroma returns the color with the same hue and tone at another chroma
+func (h Hct) WithChroma(chroma float64) Hct {
+	return NewHct(h.Hue, chroma, h.Tone)
+}
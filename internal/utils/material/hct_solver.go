@@ -0,0 +1,322 @@
+package material
+
+import "math"
+
+// The HCT solver finds the sRGB color with a given CAM16 hue and chroma and
+// L* tone. It first solves for CAM16 lightness J with Newton's method; when
+// the requested chroma is out of gamut it bisects along the edges of the
+// RGB cube, which lie on planes of constant Y, to find the most chromatic
+// color with the requested hue and tone.
+
+var (
+	// scaledDiscountFromLinrgb maps linear RGB to cone responses scaled by
+	// the default viewing conditions' discount and luminance adaptation
+	scaledDiscountFromLinrgb [3][3]float64
+
+	// linrgbFromScaledDiscount is the inverse of scaledDiscountFromLinrgb
+	linrgbFromScaledDiscount [3][3]float64
+
+	// yFromLinrgb is the luminance row of srgbToXYZ
+	yFromLinrgb = srgbToXYZ[1]
+
+	// criticalPlanes holds the linear RGB values midway between adjacent
+	// sRGB channel values, where rounding changes the resulting color
+	criticalPlanes [255]float64
+)
+
+func init() {
+	vc := DefaultViewingConditions
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			var sum float64
+			for k := 0; k < 3; k++ {
+				sum += xyzToCAM16RGB[i][k] * srgbToXYZ[k][j]
+			}
+			scaledDiscountFromLinrgb[i][j] = sum * vc.RgbD[i] * vc.Fl / 100.0
+		}
+	}
+	linrgbFromScaledDiscount = invert3x3(scaledDiscountFromLinrgb)
+
+	for i := range criticalPlanes {
+		normalized := (float64(i) + 0.5) / 255.0
+		if normalized <= 0.040449936 {
+			criticalPlanes[i] = normalized / 12.92 * 100.0
+		} else {
+			criticalPlanes[i] = math.Pow((normalized+0.055)/1.055, 2.4) * 100.0
+		}
+	}
+}
+
+// solveHct returns the ARGB color closest to the given hue (degrees),
+// chroma and tone (L*). Tone is always matched; chroma is reduced when the
+// requested one is out of gamut.
+func solveHct(hue, chroma, tone float64) uint32 {
+	if chroma < 0.0001 || tone < 0.0001 || tone > 99.9999 {
+		return argbFromLstar(tone)
+	}
+
+	hueRadians := sanitizeDegrees(hue) / 180.0 * math.Pi
+	y := yFromLstar(tone)
+
+	if exact, ok := findResultByJ(hueRadians, chroma, y); ok {
+		return exact
+	}
+	return argbFromLinrgb(bisectToLimit(y, hueRadians))
+}
+
+// findResultByJ solves for the color with the given hue, chroma and
+// luminance by iterating on CAM16 lightness J
+func findResultByJ(hueRadians, chroma, y float64) (uint32, bool) {
+	vc := DefaultViewingConditions
+
+	// Initial estimate of J
+	j := math.Sqrt(y) * 11.0
+
+	tInnerCoeff := 1.0 / math.Pow(1.64-math.Pow(0.29, vc.N), 0.73)
+	eHue := 0.25 * (math.Cos(hueRadians+2.0) + 3.8)
+	p1 := eHue * (50000.0 / 13.0) * vc.Nc * vc.Ncb
+	hSin, hCos := math.Sin(hueRadians), math.Cos(hueRadians)
+
+	for round := 0; round < 5; round++ {
+		jNormalized := j / 100.0
+		alpha := 0.0
+		if chroma != 0 && j != 0 {
+			alpha = chroma / math.Sqrt(jNormalized)
+		}
+		t := math.Pow(alpha*tInnerCoeff, 1.0/0.9)
+		ac := vc.Aw * math.Pow(jNormalized, 1.0/vc.C/vc.Z)
+		p2 := ac / vc.Nbb
+		gamma := 23.0 * (p2 + 0.305) * t / (23.0*p1 + 11*t*hCos + 108.0*t*hSin)
+		a := gamma * hCos
+		b := gamma * hSin
+
+		rA := (460.0*p2 + 451.0*a + 288.0*b) / 1403.0
+		gA := (460.0*p2 - 891.0*a - 261.0*b) / 1403.0
+		bA := (460.0*p2 - 220.0*a - 6300.0*b) / 1403.0
+
+		scaled := [3]float64{
+			inverseChromaticAdaptation(rA),
+			inverseChromaticAdaptation(gA),
+			inverseChromaticAdaptation(bA),
+		}
+		linrgb := matrixMultiply(scaled, linrgbFromScaledDiscount)
+
+		if linrgb[0] < 0 || linrgb[1] < 0 || linrgb[2] < 0 {
+			return 0, false
+		}
+
+		fnj := yFromLinrgb[0]*linrgb[0] + yFromLinrgb[1]*linrgb[1] + yFromLinrgb[2]*linrgb[2]
+		if fnj <= 0 {
+			return 0, false
+		}
+
+		if round == 4 || math.Abs(fnj-y) < 0.002 {
+			if linrgb[0] > 100.01 || linrgb[1] > 100.01 || linrgb[2] > 100.01 {
+				return 0, false
+			}
+			return argbFromLinrgb(linrgb), true
+		}
+
+		// Newton step, using 2 * fn(j) / j as the derivative
+		j = j - (fnj-y)*j/(2*fnj)
+	}
+
+	return 0, false
+}
+
+// bisectToLimit finds the most chromatic in-gamut color with the given
+// luminance and hue, in linear RGB
+func bisectToLimit(y, targetHue float64) [3]float64 {
+	left, right := bisectToSegment(y, targetHue)
+	leftHue := hueOf(left)
+
+	for axis := 0; axis < 3; axis++ {
+		if left[axis] == right[axis] {
+			continue
+		}
+
+		var lPlane, rPlane int
+		if left[axis] < right[axis] {
+			lPlane = criticalPlaneBelow(trueDelinearized(left[axis]))
+			rPlane = criticalPlaneAbove(trueDelinearized(right[axis]))
+		} else {
+			lPlane = criticalPlaneAbove(trueDelinearized(left[axis]))
+			rPlane = criticalPlaneBelow(trueDelinearized(right[axis]))
+		}
+
+		for i := 0; i < 8; i++ {
+			if abs(rPlane-lPlane) <= 1 {
+				break
+			}
+
+			mPlane := int(math.Floor(float64(lPlane+rPlane) / 2.0))
+			mid := setCoordinate(left, criticalPlanes[mPlane], right, axis)
+			midHue := hueOf(mid)
+
+			if areInCyclicOrder(leftHue, targetHue, midHue) {
+				right = mid
+				rPlane = mPlane
+			} else {
+				left = mid
+				leftHue = midHue
+				lPlane = mPlane
+			}
+		}
+	}
+
+	return [3]float64{
+		(left[0] + right[0]) / 2,
+		(left[1] + right[1]) / 2,
+		(left[2] + right[2]) / 2,
+	}
+}
+
+// bisectToSegment finds the edge segment of the RGB cube, within the plane
+// of constant luminance y, that contains the target hue
+func bisectToSegment(y, targetHue float64) (left, right [3]float64) {
+	var leftHue, rightHue float64
+	initialized := false
+	uncut := true
+
+	for n := 0; n < 12; n++ {
+		mid, ok := nthVertex(y, n)
+		if !ok {
+			continue
+		}
+		midHue := hueOf(mid)
+
+		if !initialized {
+			left, right = mid, mid
+			leftHue, rightHue = midHue, midHue
+			initialized = true
+			continue
+		}
+
+		if uncut || areInCyclicOrder(leftHue, midHue, rightHue) {
+			uncut = false
+			if areInCyclicOrder(leftHue, targetHue, midHue) {
+				right = mid
+				rightHue = midHue
+			} else {
+				left = mid
+				leftHue = midHue
+			}
+		}
+	}
+
+	return left, right
+}
+
+// nthVertex returns the nth intersection of the plane of luminance y with
+// the edges of the RGB cube, if it lies on the cube
+func nthVertex(y float64, n int) ([3]float64, bool) {
+	kR, kG, kB := yFromLinrgb[0], yFromLinrgb[1], yFromLinrgb[2]
+
+	coordA := 100.0
+	if n%4 <= 1 {
+		coordA = 0
+	}
+	coordB := 100.0
+	if n%2 == 0 {
+		coordB = 0
+	}
+
+	var vertex [3]float64
+	var free float64
+	switch {
+	case n < 4:
+		g, b := coordA, coordB
+		free = (y - g*kG - b*kB) / kR
+		vertex = [3]float64{free, g, b}
+	case n < 8:
+		b, r := coordA, coordB
+		free = (y - r*kR - b*kB) / kG
+		vertex = [3]float64{r, free, b}
+	default:
+		r, g := coordA, coordB
+		free = (y - r*kR - g*kG) / kB
+		vertex = [3]float64{r, g, free}
+	}
+
+	return vertex, free >= 0 && free <= 100
+}
+
+// hueOf returns the CAM16 hue, in radians, of a linear RGB color
+func hueOf(linrgb [3]float64) float64 {
+	scaled := matrixMultiply(linrgb, scaledDiscountFromLinrgb)
+	rA := chromaticAdaptation(scaled[0])
+	gA := chromaticAdaptation(scaled[1])
+	bA := chromaticAdaptation(scaled[2])
+
+	a := (11.0*rA + -12.0*gA + bA) / 11.0
+	b := (rA + gA - 2.0*bA) / 9.0
+	return math.Atan2(b, a)
+}
+
+// chromaticAdaptation applies the CAM16 post-adaptation compression
+func chromaticAdaptation(component float64) float64 {
+	af := math.Pow(math.Abs(component), 0.42)
+	return signum(component) * 400.0 * af / (af + 27.13)
+}
+
+// inverseChromaticAdaptation undoes chromaticAdaptation
+func inverseChromaticAdaptation(adapted float64) float64 {
+	adaptedAbs := math.Abs(adapted)
+	base := math.Max(0, 27.13*adaptedAbs/(400.0-adaptedAbs))
+	return signum(adapted) * math.Pow(base, 1.0/0.42)
+}
+
+// areInCyclicOrder reports whether a, b and c are in counterclockwise
+// order on the hue circle (radians)
+func areInCyclicOrder(a, b, c float64) bool {
+	return sanitizeRadians(b-a) < sanitizeRadians(c-a)
+}
+
+// sanitizeRadians wraps an angle into [0, 2π)
+func sanitizeRadians(angle float64) float64 {
+	return math.Mod(angle+math.Pi*8, math.Pi*2)
+}
+
+// setCoordinate returns the point on the segment from source to target
+// whose coordinate on axis equals coordinate
+func setCoordinate(source [3]float64, coordinate float64, target [3]float64, axis int) [3]float64 {
+	t := (coordinate - source[axis]) / (target[axis] - source[axis])
+	return [3]float64{
+		source[0] + (target[0]-source[0])*t,
+		source[1] + (target[1]-source[1])*t,
+		source[2] + (target[2]-source[2])*t,
+	}
+}
+
+func criticalPlaneBelow(x float64) int {
+	return int(math.Floor(x - 0.5))
+}
+
+func criticalPlaneAbove(x float64) int {
+	return int(math.Ceil(x - 0.5))
+}
+
+// invert3x3 returns the inverse of a 3x3 matrix
+func invert3x3(m [3][3]float64) [3][3]float64 {
+	det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+
+	return [3][3]float64{
+		{
+			(m[1][1]*m[2][2] - m[1][2]*m[2][1]) / det,
+			(m[0][2]*m[2][1] - m[0][1]*m[2][2]) / det,
+			(m[0][1]*m[1][2] - m[0][2]*m[1][1]) / det,
+		},
+		{
+			(m[1][2]*m[2][0] - m[1][0]*m[2][2]) / det,
+			(m[0][0]*m[2][2] - m[0][2]*m[2][0]) / det,
+			(m[0][2]*m[1][0] - m[0][0]*m[1][2]) / det,
+		},
+		{
+			(m[1][0]*m[2][1] - m[1][1]*m[2][0]) / det,
+			(m[0][1]*m[2][0] - m[0][0]*m[2][1]) / det,
+			(m[0][0]*m[1][1] - m[0][1]*m[1][0]) / det,
+		},
+	}
+}
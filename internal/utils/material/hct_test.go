@@ -0,0 +1,153 @@
+package material
+
+import (
+	"math"
+	"testing"
+)
+
+// Reference values from the Material Color Utilities test suite
+
+func TestCam16FromARGB(t *testing.T) {
+	tests := []struct {
+		name   string
+		argb   uint32
+		j      float64
+		chroma float64
+		hue    float64
+	}{
+		{"red", 0xffff0000, 46.445, 113.358, 27.408},
+		{"green", 0xff00ff00, 79.332, 108.410, 142.140},
+		{"blue", 0xff0000ff, 25.466, 87.231, 282.788},
+		{"white", 0xffffffff, 100.0, 2.869, 209.492},
+		{"black", 0xff000000, 0.0, 0.0, 0.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cam := Cam16FromARGB(tt.argb)
+			if math.Abs(cam.J-tt.j) > 0.01 {
+				t.Errorf("J = %.3f, want %.3f", cam.J, tt.j)
+			}
+			if math.Abs(cam.Chroma-tt.chroma) > 0.01 {
+				t.Errorf("Chroma = %.3f, want %.3f", cam.Chroma, tt.chroma)
+			}
+			if tt.chroma > 0 && math.Abs(cam.Hue-tt.hue) > 0.01 {
+				t.Errorf("Hue = %.3f, want %.3f", cam.Hue, tt.hue)
+			}
+		})
+	}
+}
+
+func TestHctRoundTrip(t *testing.T) {
+	for _, argb := range []uint32{0xffff0000, 0xff00ff00, 0xff0000ff, 0xff6750a4, 0xff808080, 0xffffffff} {
+		hct := HctFromARGB(argb)
+		if got := NewHct(hct.Hue, hct.Chroma, hct.Tone).ARGB(); got != argb {
+			t.Errorf("round trip of %08x = %08x", argb, got)
+		}
+	}
+}
+
+func TestNewHctOutOfGamut(t *testing.T) {
+	for _, tone := range []float64{10, 30, 50, 70, 90} {
+		hct := NewHct(282.788, 200, tone)
+		if math.Abs(hct.Tone-tone) > 0.5 {
+			t.Errorf("tone %.0f: got tone %.2f", tone, hct.Tone)
+		}
+		if hct.Chroma >= 200 {
+			t.Errorf("tone %.0f: chroma %.2f not reduced into gamut", tone, hct.Chroma)
+		}
+	}
+}
+
+func TestTonalPaletteTones(t *testing.T) {
+	blue := TonalPaletteFromARGB(0xff0000ff)
+
+	want := map[int]uint32{
+		100: 0xffffffff,
+		95:  0xfff1efff,
+		90:  0xffe0e0ff,
+		80:  0xffbec2ff,
+		70:  0xff9da3ff,
+		60:  0xff7c84ff,
+		50:  0xff5a64ff,
+		40:  0xff343dff,
+		30:  0xff0000ef,
+		20:  0xff0001ac,
+		10:  0xff00006e,
+		0:   0xff000000,
+	}
+
+	for tone, argb := range want {
+		if got := blue.Tone(tone); got != argb {
+			t.Errorf("Tone(%d) = %08x, want %08x", tone, got, argb)
+		}
+	}
+}
+
+func TestTonalSpotScheme(t *testing.T) {
+	light := NewDynamicScheme(0xff0000ff, VariantTonalSpot, false).Scheme()
+	dark := NewDynamicScheme(0xff0000ff, VariantTonalSpot, true).Scheme()
+
+	tests := []struct {
+		name string
+		got  uint32
+		want uint32
+	}{
+		{"light primary", light.Primary, 0xff555992},
+		{"light primaryContainer", light.PrimaryContainer, 0xffe0e0ff},
+		{"light surface", light.Surface, 0xfffbf8ff},
+		{"dark primary", dark.Primary, 0xffbec2ff},
+		{"dark primaryContainer", dark.PrimaryContainer, 0xff3e4278},
+		{"dark surface", dark.Surface, 0xff131318},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %08x, want %08x", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestDynamicSchemeContrast(t *testing.T) {
+	for _, variant := range Variants {
+		for _, isDark := range []bool{false, true} {
+			s := NewDynamicScheme(0xff4285f4, variant, isDark).Scheme()
+
+			pairs := []struct {
+				name   string
+				fg, bg uint32
+				ratio  float64
+			}{
+				{"onPrimary", s.OnPrimary, s.Primary, 4.5},
+				{"onPrimaryContainer", s.OnPrimaryContainer, s.PrimaryContainer, 4.5},
+				{"onSecondaryContainer", s.OnSecondaryContainer, s.SecondaryContainer, 4.5},
+				{"onTertiaryContainer", s.OnTertiaryContainer, s.TertiaryContainer, 4.5},
+				{"onSurface", s.OnSurface, s.Surface, 4.5},
+			}
+
+			for _, p := range pairs {
+				if got := ratioOfTones(LstarFromARGB(p.fg), LstarFromARGB(p.bg)); got < p.ratio {
+					t.Errorf("%s dark=%v: %s contrast %.2f below %.1f", variant, isDark, p.name, got, p.ratio)
+				}
+			}
+		}
+	}
+}
+
+func TestParseVariant(t *testing.T) {
+	for name, want := range map[string]Variant{
+		"tonal":       VariantTonalSpot,
+		"tonalspot":   VariantTonalSpot,
+		"fruit_salad": VariantFruitSalad,
+		"monochrome":  VariantMonochrome,
+	} {
+		got, err := ParseVariant(name)
+		if err != nil || got != want {
+			t.Errorf("ParseVariant(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+
+	if _, err := ParseVariant("bogus"); err == nil {
+		t.Error("ParseVariant(bogus) should fail")
+	}
+}
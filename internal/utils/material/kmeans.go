@@ -0,0 +1,111 @@
+package material
+
+const (
+	// maxKMeansIterations bounds the refinement of quantized clusters
+	maxKMeansIterations = 10
+	// minKMeansMovement is the squared Lab distance below which a center is
+	// considered settled
+	minKMeansMovement = 0.01
+)
+
+// refineKMeans refines quantized cluster centers with weighted k-means in
+// L*a*b*, where distances follow perceived color differences more closely
+// than in RGB. Each histogram color is assigned to its nearest center and
+// centers move to the weighted mean of their colors.
+func refineKMeans(histogram map[uint32]int, clusters *QuantizerResult) *QuantizerResult {
+	if len(clusters.Colors) == 0 {
+		return clusters
+	}
+
+	points := make([]LAB, 0, len(histogram))
+	weights := make([]float64, 0, len(histogram))
+	for argb, count := range histogram {
+		points = append(points, labFromARGB(argb))
+		weights = append(weights, float64(count))
+	}
+
+	centers := make([]LAB, 0, len(clusters.Colors))
+	for argb := range clusters.Colors {
+		centers = append(centers, labFromARGB(argb))
+	}
+
+	assignments := make([]int, len(points))
+	for iteration := 0; iteration < maxKMeansIterations; iteration++ {
+		assignNearest(points, centers, assignments)
+
+		sums := make([]LAB, len(centers))
+		totals := make([]float64, len(centers))
+		for i, point := range points {
+			c, w := assignments[i], weights[i]
+			sums[c].L += point.L * w
+			sums[c].A += point.A * w
+			sums[c].B += point.B * w
+			totals[c] += w
+		}
+
+		moved := false
+		for c := range centers {
+			if totals[c] == 0 {
+				continue
+			}
+			next := LAB{L: sums[c].L / totals[c], A: sums[c].A / totals[c], B: sums[c].B / totals[c]}
+			if labDistanceSquared(next, centers[c]) > minKMeansMovement {
+				moved = true
+			}
+			centers[c] = next
+		}
+		if !moved {
+			break
+		}
+	}
+
+	assignNearest(points, centers, assignments)
+
+	result := &QuantizerResult{Colors: make(map[uint32]int, len(centers))}
+	populations := make([]int, len(centers))
+	for i := range points {
+		populations[assignments[i]] += int(weights[i])
+	}
+	for c, center := range centers {
+		if populations[c] > 0 {
+			result.Colors[argbFromLab(center)] += populations[c]
+		}
+	}
+	return result
+}
+
+// assignNearest stores the index of the nearest center of every point,
+// splitting the points across CPUs
+func assignNearest(points, centers []LAB, assignments []int) {
+	parallelChunks(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			nearest, best := 0, labDistanceSquared(points[i], centers[0])
+			for c := 1; c < len(centers); c++ {
+				if d := labDistanceSquared(points[i], centers[c]); d < best {
+					nearest, best = c, d
+				}
+			}
+			assignments[i] = nearest
+		}
+	})
+}
+
+// labDistanceSquared returns the squared Euclidean distance between two
+// L*a*b* colors
+func labDistanceSquared(a, b LAB) float64 {
+	dl, da, db := a.L-b.L, a.A-b.A, a.B-b.B
+	return dl*dl + da*da + db*db
+}
+
+// argbFromLab converts L*a*b* to an opaque ARGB color, the inverse of
+// labFromARGB
+func argbFromLab(lab LAB) uint32 {
+	fy := (lab.L + 16.0) / 116.0
+	fx := lab.A/500.0 + fy
+	fz := fy - lab.B/200.0
+	return argbFromXYZ(
+		labInvf(fx)*whitePointD65[0],
+		labInvf(fy)*whitePointD65[1],
+		labInvf(fz)*whitePointD65[2],
+	)
+}
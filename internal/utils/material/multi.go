@@ -0,0 +1,189 @@
+package material
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+)
+
+// WeightedImage is one image of a multi-image extraction, such as the
+// wallpaper of one monitor, with its relative weight
+type WeightedImage struct {
+	Image  image.Image
+	Weight float64
+}
+
+// histogramScale is the total population merged histograms are scaled to
+const histogramScale = 1000000
+
+// normalizeWeights returns the images with positive weights, weights
+// scaled to sum to 1
+func normalizeWeights(images []WeightedImage) ([]WeightedImage, error) {
+	total := 0.0
+	for _, wi := range images {
+		if wi.Image == nil {
+			return nil, fmt.Errorf("image is nil")
+		}
+		if wi.Weight > 0 {
+			total += wi.Weight
+		}
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("no image has a positive weight")
+	}
+
+	normalized := make([]WeightedImage, 0, len(images))
+	for _, wi := range images {
+		if wi.Weight > 0 {
+			normalized = append(normalized, WeightedImage{Image: wi.Image, Weight: wi.Weight / total})
+		}
+	}
+	return normalized, nil
+}
+
+// MergeQuantized merges quantized histograms, giving each its weight's
+// share of the population regardless of image resolution
+func MergeQuantized(results []*QuantizerResult, weights []float64) *QuantizerResult {
+	merged := &QuantizerResult{Colors: make(map[uint32]int)}
+
+	for i, result := range results {
+		total := 0
+		for _, count := range result.Colors {
+			total += count
+		}
+		if total == 0 {
+			continue
+		}
+
+		for color, count := range result.Colors {
+			share := float64(count) / float64(total) * weights[i]
+			merged.Colors[color] += int(math.Round(share * histogramScale))
+		}
+	}
+
+	return merged
+}
+
+// QuantizeImages quantizes each image and merges the histograms by weight
+func (q *Quantizer) QuantizeImages(images []WeightedImage) (*QuantizerResult, error) {
+	normalized, err := normalizeWeights(images)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*QuantizerResult, len(normalized))
+	weights := make([]float64, len(normalized))
+	for i, wi := range normalized {
+		results[i] = q.Quantize(wi.Image)
+		weights[i] = wi.Weight
+	}
+
+	return MergeQuantized(results, weights), nil
+}
+
+// GenerateFromImages creates a Material You palette from several weighted
+// images, scoring their merged histogram
+func (g *Generator) GenerateFromImages(images []WeightedImage) (*Palette, error) {
+	quantResult, err := g.quantizer.QuantizeImages(images)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.GenerateFromColor(FindSeedColor(quantResult))
+}
+
+// ExtractColorsWeighted runs the extraction passes on each image and merges
+// every pass by weight, producing one set of colors for all images
+func (e *EnhancedExtractor) ExtractColorsWeighted(images []WeightedImage) (*ExtractedColors, error) {
+	normalized, err := normalizeWeights(images)
+	if err != nil {
+		return nil, err
+	}
+	if len(normalized) == 1 {
+		return e.ExtractColors(normalized[0].Image)
+	}
+
+	var (
+		dominant, accents, edges [][]ColorInfo
+		weights                  []float64
+		background               ColorInfo
+		heaviest                 float64
+		avgLuminance             float64
+	)
+
+	for _, wi := range normalized {
+		extracted, err := e.ExtractColors(wi.Image)
+		if err != nil {
+			return nil, err
+		}
+
+		dominant = append(dominant, extracted.Dominant)
+		accents = append(accents, extracted.Accents)
+		edges = append(edges, extracted.EdgeColors)
+		weights = append(weights, wi.Weight)
+		avgLuminance += extracted.AvgLuminance * wi.Weight
+
+		// The background of the most prominent image wins
+		if wi.Weight > heaviest {
+			heaviest = wi.Weight
+			background = extracted.Background
+		}
+	}
+
+	byPopulation := func(a, b ColorInfo) bool { return a.Population > b.Population }
+	byVibrancy := func(a, b ColorInfo) bool {
+		return a.Vibrancy*float64(a.Population) > b.Vibrancy*float64(b.Population)
+	}
+
+	return e.assemble(
+		mergeColorInfos(dominant, weights, 10, byPopulation),
+		mergeColorInfos(accents, weights, 5, byVibrancy),
+		background,
+		mergeColorInfos(edges, weights, 5, byPopulation),
+		avgLuminance,
+	), nil
+}
+
+// mergeColorInfos merges one extraction pass across images, scaling each
+// image's populations to its weight, and keeps the top limit colors
+func mergeColorInfos(lists [][]ColorInfo, weights []float64, limit int, less func(a, b ColorInfo) bool) []ColorInfo {
+	byColor := make(map[uint32]*ColorInfo)
+	var order []uint32
+
+	for i, list := range lists {
+		total := 0
+		for _, info := range list {
+			total += info.Population
+		}
+		if total == 0 {
+			continue
+		}
+
+		for _, info := range list {
+			population := int(math.Round(float64(info.Population) / float64(total) * weights[i] * histogramScale))
+			if existing, ok := byColor[info.Color]; ok {
+				existing.Population += population
+				continue
+			}
+
+			merged := info
+			merged.Population = population
+			byColor[info.Color] = &merged
+			order = append(order, info.Color)
+		}
+	}
+
+	merged := make([]ColorInfo, 0, len(order))
+	for _, color := range order {
+		merged = append(merged, *byColor[color])
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return less(merged[i], merged[j])
+	})
+
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged
+}
@@ -0,0 +1,58 @@
+package material
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// solidImage paints a single color
+func solidImage(width, height int, c color.RGBA) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestMergeQuantizedWeights(t *testing.T) {
+	red, blue := uint32(0xFFFF0000), uint32(0xFF0000FF)
+	results := []*QuantizerResult{
+		{Colors: map[uint32]int{red: 10}},
+		{Colors: map[uint32]int{blue: 1000}},
+	}
+
+	merged := MergeQuantized(results, []float64{0.75, 0.25})
+
+	// Populations follow the weights, not the pixel counts
+	if merged.Colors[red] != 750000 || merged.Colors[blue] != 250000 {
+		t.Errorf("merged = %v, want red 750000 and blue 250000", merged.Colors)
+	}
+}
+
+func TestExtractColorsWeighted(t *testing.T) {
+	// A small primary monitor outweighs a large, lower resolution one
+	blue := solidImage(40, 30, color.RGBA{40, 60, 200, 255})
+	red := solidImage(80, 60, color.RGBA{200, 40, 40, 255})
+
+	extracted, err := NewEnhancedExtractor().ExtractColorsWeighted([]WeightedImage{
+		{Image: blue, Weight: 3},
+		{Image: red, Weight: 1},
+	})
+	if err != nil {
+		t.Fatalf("ExtractColorsWeighted() error = %v", err)
+	}
+
+	if len(extracted.Dominant) < 2 {
+		t.Fatalf("expected colors from both images, got %d", len(extracted.Dominant))
+	}
+	if hue := HctFromARGB(extracted.Dominant[0].Color).Hue; hue < 240 || hue > 300 {
+		t.Errorf("dominant hue = %.1f, want the heavier blue image", hue)
+	}
+
+	if _, err := NewEnhancedExtractor().ExtractColorsWeighted([]WeightedImage{{Image: blue}}); err == nil {
+		t.Error("expected an error without positive weights")
+	}
+}
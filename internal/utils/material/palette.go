@@ -1,10 +1,43 @@
 package material
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
-// TonalPalette represents a range of tones for a single color
+// standardTones are the tones precomputed for every tonal palette
+var standardTones = []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 95, 99, 100}
+
+// TonalPalette represents a range of tones for a single color. Palettes
+// created from a hue and chroma compute any tone exactly in HCT; palettes
+// built from a bare Tones map fall back to the closest stored tone.
 type TonalPalette struct {
-	Tones map[int]uint32 // Map of tone (0-100) to ARGB color
+	Hue      float64        // HCT hue shared by all tones
+	Chroma   float64        // Requested HCT chroma, reduced per tone when out of gamut
+	KeyColor Hct            // The tone closest to 50 that reaches the chroma
+	Tones    map[int]uint32 // Map of tone (0-100) to ARGB color
+	exact    bool
+}
+
+// NewTonalPalette creates a palette of the given hue and chroma
+func NewTonalPalette(hue, chroma float64) TonalPalette {
+	palette := TonalPalette{
+		Hue:      hue,
+		Chroma:   chroma,
+		KeyColor: keyColorFor(hue, chroma),
+		Tones:    make(map[int]uint32, len(standardTones)),
+		exact:    true,
+	}
+	for _, tone := range standardTones {
+		palette.Tone(tone)
+	}
+	return palette
+}
+
+// TonalPaletteFromARGB creates a palette with the hue and chroma of a color
+func TonalPaletteFromARGB(argb uint32) TonalPalette {
+	hct := HctFromARGB(argb)
+	return NewTonalPalette(hct.Hue, hct.Chroma)
 }
 
 // Tone returns the color at the specified tone level
@@ -13,6 +46,15 @@ func (tp *TonalPalette) Tone(tone int) uint32 {
 		return color
 	}
 
+	if tp.exact {
+		color := solveHct(tp.Hue, tp.Chroma, float64(tone))
+		if tp.Tones == nil {
+			tp.Tones = make(map[int]uint32)
+		}
+		tp.Tones[tone] = color
+		return color
+	}
+
 	// If exact tone not found, find closest
 	closestTone := 0
 	minDiff := 100
@@ -28,6 +70,37 @@ func (tp *TonalPalette) Tone(tone int) uint32 {
 	return tp.Tones[closestTone]
 }
 
+// Hct returns the palette color at a fractional tone
+func (tp *TonalPalette) Hct(tone float64) Hct {
+	if tp.exact {
+		return NewHct(tp.Hue, tp.Chroma, tone)
+	}
+	return HctFromARGB(tp.Tone(int(math.Round(tone))))
+}
+
+// keyColorFor finds the tone closest to 50 at which the palette reaches the
+// requested chroma, or the most chromatic tone when none does
+func keyColorFor(hue, chroma float64) Hct {
+	const startTone = 50.0
+	best := NewHct(hue, chroma, startTone)
+	bestDelta := math.Abs(best.Chroma - chroma)
+
+	for delta := 1.0; delta < 50.0; delta++ {
+		if math.Round(chroma) == math.Round(best.Chroma) {
+			return best
+		}
+
+		for _, tone := range []float64{startTone + delta, startTone - delta} {
+			candidate := NewHct(hue, chroma, tone)
+			if candidateDelta := math.Abs(candidate.Chroma - chroma); candidateDelta < bestDelta {
+				best, bestDelta = candidate, candidateDelta
+			}
+		}
+	}
+
+	return best
+}
+
 // Palette represents a complete Material You color palette
 type Palette struct {
 	Seed           uint32       // Seed color used to generate the palette
@@ -74,10 +147,18 @@ type Scheme struct {
 	OnBackground uint32
 
 	// Surface colors
-	Surface          uint32
-	OnSurface        uint32
-	SurfaceVariant   uint32
-	OnSurfaceVariant uint32
+	Surface                 uint32
+	OnSurface               uint32
+	SurfaceVariant          uint32
+	OnSurfaceVariant        uint32
+	SurfaceDim              uint32
+	SurfaceBright           uint32
+	SurfaceContainerLowest  uint32
+	SurfaceContainerLow     uint32
+	SurfaceContainer        uint32
+	SurfaceContainerHigh    uint32
+	SurfaceContainerHighest uint32
+	SurfaceTint             uint32
 
 	// Outline colors
 	Outline        uint32
@@ -89,6 +170,27 @@ type Scheme struct {
 	InverseSurface   uint32
 	InverseOnSurface uint32
 	InversePrimary   uint32
+
+	// Fixed colors, identical in light and dark mode
+	PrimaryFixed            uint32
+	PrimaryFixedDim         uint32
+	OnPrimaryFixed          uint32
+	OnPrimaryFixedVariant   uint32
+	SecondaryFixed          uint32
+	SecondaryFixedDim       uint32
+	OnSecondaryFixed        uint32
+	OnSecondaryFixedVariant uint32
+	TertiaryFixed           uint32
+	TertiaryFixedDim        uint32
+	OnTertiaryFixed         uint32
+	OnTertiaryFixedVariant  uint32
+
+	// Palette key colors
+	PrimaryPaletteKeyColor        uint32
+	SecondaryPaletteKeyColor      uint32
+	TertiaryPaletteKeyColor       uint32
+	NeutralPaletteKeyColor        uint32
+	NeutralVariantPaletteKeyColor uint32
 }
 
 // ToMap converts the scheme to a map for template rendering
@@ -122,6 +224,15 @@ func (s *Scheme) ToMap() map[string]string {
 		"surface_variant":    argbToHex(s.SurfaceVariant),
 		"on_surface_variant": argbToHex(s.OnSurfaceVariant),
 
+		"surface_dim":               argbToHex(s.SurfaceDim),
+		"surface_bright":            argbToHex(s.SurfaceBright),
+		"surface_container_lowest":  argbToHex(s.SurfaceContainerLowest),
+		"surface_container_low":     argbToHex(s.SurfaceContainerLow),
+		"surface_container":         argbToHex(s.SurfaceContainer),
+		"surface_container_high":    argbToHex(s.SurfaceContainerHigh),
+		"surface_container_highest": argbToHex(s.SurfaceContainerHighest),
+		"surface_tint":              argbToHex(s.SurfaceTint),
+
 		"outline":         argbToHex(s.Outline),
 		"outline_variant": argbToHex(s.OutlineVariant),
 
@@ -130,6 +241,25 @@ func (s *Scheme) ToMap() map[string]string {
 		"inverse_surface":    argbToHex(s.InverseSurface),
 		"inverse_on_surface": argbToHex(s.InverseOnSurface),
 		"inverse_primary":    argbToHex(s.InversePrimary),
+
+		"primary_fixed":              argbToHex(s.PrimaryFixed),
+		"primary_fixed_dim":          argbToHex(s.PrimaryFixedDim),
+		"on_primary_fixed":           argbToHex(s.OnPrimaryFixed),
+		"on_primary_fixed_variant":   argbToHex(s.OnPrimaryFixedVariant),
+		"secondary_fixed":            argbToHex(s.SecondaryFixed),
+		"secondary_fixed_dim":        argbToHex(s.SecondaryFixedDim),
+		"on_secondary_fixed":         argbToHex(s.OnSecondaryFixed),
+		"on_secondary_fixed_variant": argbToHex(s.OnSecondaryFixedVariant),
+		"tertiary_fixed":             argbToHex(s.TertiaryFixed),
+		"tertiary_fixed_dim":         argbToHex(s.TertiaryFixedDim),
+		"on_tertiary_fixed":          argbToHex(s.OnTertiaryFixed),
+		"on_tertiary_fixed_variant":  argbToHex(s.OnTertiaryFixedVariant),
+
+		"primary_paletteKeyColor":         argbToHex(s.PrimaryPaletteKeyColor),
+		"secondary_paletteKeyColor":       argbToHex(s.SecondaryPaletteKeyColor),
+		"tertiary_paletteKeyColor":        argbToHex(s.TertiaryPaletteKeyColor),
+		"neutral_paletteKeyColor":         argbToHex(s.NeutralPaletteKeyColor),
+		"neutral_variant_paletteKeyColor": argbToHex(s.NeutralVariantPaletteKeyColor),
 	}
 }
 
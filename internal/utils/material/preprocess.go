@@ -0,0 +1,190 @@
+package material
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// DefaultPixelBudget is the pixel count images are downsampled to before
+// quantization. Wallpapers keep their color distribution at this size while
+// 5K and 8K images are processed in a fraction of the time.
+const DefaultPixelBudget = 256 * 256
+
+// PreparedImage is an image downsampled to a pixel budget and its color
+// histogram, computed once and shared by every extraction pass
+type PreparedImage struct {
+	Image     *image.RGBA
+	Histogram map[uint32]int // Map of ARGB colors to their pixel counts
+	Scale     float64        // Source pixels per prepared pixel along each axis
+}
+
+// Prepare downsamples img by area averaging to at most budget pixels and
+// builds its histogram. A budget of 0 keeps the full resolution.
+func Prepare(img image.Image, budget int) *PreparedImage {
+	bounds := img.Bounds()
+	pixels := bounds.Dx() * bounds.Dy()
+
+	scale := 1.0
+	if budget > 0 && pixels > budget {
+		scale = math.Sqrt(float64(pixels) / float64(budget))
+	}
+
+	prepared := downsample(img, scale)
+	return &PreparedImage{
+		Image:     prepared,
+		Histogram: histogramOf(prepared),
+		Scale:     scale,
+	}
+}
+
+// downsample averages the source pixels that fall into each destination
+// pixel. A scale of 1 copies the image into RGBA form.
+func downsample(img image.Image, scale float64) *image.RGBA {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dstWidth := max(1, int(float64(width)/scale))
+	dstHeight := max(1, int(float64(height)/scale))
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	if width == 0 || height == 0 {
+		return dst
+	}
+
+	pixelAt := pixelReader(img)
+
+	// columns maps each source column to its destination column
+	columns := make([]int, width)
+	for sx := range columns {
+		columns[sx] = sx * dstWidth / width
+	}
+
+	// firstRow returns the first source row of a destination row
+	firstRow := func(dy int) int {
+		return (dy*height + dstHeight - 1) / dstHeight
+	}
+
+	parallelChunks(dstHeight, func(start, end int) {
+		sums := make([][4]uint32, dstWidth)
+		counts := make([]uint32, dstWidth)
+
+		for dy := start; dy < end; dy++ {
+			clear(sums)
+			clear(counts)
+
+			for sy := firstRow(dy); sy < firstRow(dy+1); sy++ {
+				for sx, dx := range columns {
+					r, g, b, a := pixelAt(bounds.Min.X+sx, bounds.Min.Y+sy)
+					sums[dx][0] += uint32(r)
+					sums[dx][1] += uint32(g)
+					sums[dx][2] += uint32(b)
+					sums[dx][3] += uint32(a)
+					counts[dx]++
+				}
+			}
+
+			row := dst.Pix[dy*dst.Stride:]
+			for dx := 0; dx < dstWidth; dx++ {
+				n := counts[dx]
+				if n == 0 {
+					continue
+				}
+				for c := 0; c < 4; c++ {
+					row[dx*4+c] = uint8((sums[dx][c] + n/2) / n)
+				}
+			}
+		}
+	})
+
+	return dst
+}
+
+// pixelReader returns a function reading 8-bit premultiplied RGBA pixels,
+// with fast paths for the image types decoders produce
+func pixelReader(img image.Image) func(x, y int) (r, g, b, a uint8) {
+	switch src := img.(type) {
+	case *image.RGBA:
+		return func(x, y int) (uint8, uint8, uint8, uint8) {
+			p := src.Pix[src.PixOffset(x, y):]
+			return p[0], p[1], p[2], p[3]
+		}
+	case *image.NRGBA:
+		return func(x, y int) (uint8, uint8, uint8, uint8) {
+			p := src.Pix[src.PixOffset(x, y):]
+			if p[3] == 0xFF {
+				return p[0], p[1], p[2], p[3]
+			}
+			a := uint32(p[3])
+			return uint8(uint32(p[0]) * a / 0xFF), uint8(uint32(p[1]) * a / 0xFF), uint8(uint32(p[2]) * a / 0xFF), p[3]
+		}
+	case *image.YCbCr:
+		return func(x, y int) (uint8, uint8, uint8, uint8) {
+			yi, ci := src.YOffset(x, y), src.COffset(x, y)
+			r, g, b := color.YCbCrToRGB(src.Y[yi], src.Cb[ci], src.Cr[ci])
+			return r, g, b, 0xFF
+		}
+	default:
+		return func(x, y int) (uint8, uint8, uint8, uint8) {
+			argb := colorToARGB(img.At(x, y))
+			return uint8(argb >> 16), uint8(argb >> 8), uint8(argb), uint8(argb >> 24)
+		}
+	}
+}
+
+// histogramOf counts the colors of an image, one partial histogram per
+// worker merged at the end
+func histogramOf(img *image.RGBA) map[uint32]int {
+	bounds := img.Bounds()
+
+	var (
+		mu     sync.Mutex
+		merged = make(map[uint32]int)
+	)
+
+	parallelChunks(bounds.Dy(), func(start, end int) {
+		partial := make(map[uint32]int)
+		for y := start; y < end; y++ {
+			row := img.Pix[y*img.Stride:]
+			for x := 0; x < bounds.Dx(); x++ {
+				p := row[x*4 : x*4+4]
+				partial[uint32(p[3])<<24|uint32(p[0])<<16|uint32(p[1])<<8|uint32(p[2])]++
+			}
+		}
+
+		mu.Lock()
+		for argb, count := range partial {
+			merged[argb] += count
+		}
+		mu.Unlock()
+	})
+
+	return merged
+}
+
+// argbAt returns the color of a prepared image pixel
+func argbAt(img *image.RGBA, x, y int) uint32 {
+	p := img.Pix[img.PixOffset(x, y):]
+	return uint32(p[3])<<24 | uint32(p[0])<<16 | uint32(p[1])<<8 | uint32(p[2])
+}
+
+// parallelChunks splits n items, such as image rows, into one contiguous
+// chunk per CPU and runs fn on the chunks concurrently
+func parallelChunks(n int, fn func(start, end int)) {
+	workers := min(runtime.GOMAXPROCS(0), n)
+	if workers <= 1 {
+		fn(0, n)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		start, end := n*i/workers, n*(i+1)/workers
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(start, end)
+		}()
+	}
+	wg.Wait()
+}
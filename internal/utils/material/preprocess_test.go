@@ -0,0 +1,213 @@
+package material
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// syntheticWallpaper paints a noisy landscape: a blue to orange sky, a red
+// sun and green hills
+func syntheticWallpaper(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	rng := rand.New(rand.NewSource(1))
+
+	sunX, sunY, sunR := width*2/3, height/3, height/8
+	for y := 0; y < height; y++ {
+		t := float64(y) / float64(height)
+		for x := 0; x < width; x++ {
+			var r, g, b float64
+			dx, dy := x-sunX, y-sunY
+			switch {
+			case dx*dx+dy*dy < sunR*sunR:
+				r, g, b = 230, 70, 40
+			case y > height*2/3+(x%(width/3+1))/8:
+				r, g, b = 40, 120+40*t, 60
+			default:
+				r, g, b = 40+200*t, 90+60*t, 200-120*t
+			}
+
+			noise := rng.Float64()*16 - 8
+			i := img.PixOffset(x, y)
+			img.Pix[i] = clampChannel(r + noise)
+			img.Pix[i+1] = clampChannel(g + noise)
+			img.Pix[i+2] = clampChannel(b + noise)
+			img.Pix[i+3] = 0xFF
+		}
+	}
+	return img
+}
+
+func clampChannel(v float64) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
+
+func TestDownsampleAveragesArea(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			c := color.RGBA{0, 0, 0, 255}
+			if x%2 == 0 {
+				c = color.RGBA{200, 100, 50, 255}
+			}
+			if y >= 2 {
+				c.B = 250
+			}
+			img.Set(x, y, c)
+		}
+	}
+
+	prepared := Prepare(img, 4)
+	if got := prepared.Image.Bounds().Size(); got != image.Pt(2, 2) {
+		t.Fatalf("size = %v, want 2x2", got)
+	}
+	if prepared.Scale != 2 {
+		t.Errorf("scale = %v, want 2", prepared.Scale)
+	}
+
+	// Each 2x2 block averages one colored and one black column
+	if got := argbAt(prepared.Image, 0, 0); got != 0xFF643219 {
+		t.Errorf("top-left = %08x, want ff643219", got)
+	}
+	if got := argbAt(prepared.Image, 1, 1); got != 0xFF6432FA {
+		t.Errorf("bottom-right = %08x, want ff6432fa", got)
+	}
+
+	total := 0
+	for _, count := range prepared.Histogram {
+		total += count
+	}
+	if total != 4 {
+		t.Errorf("histogram counts %d pixels, want 4", total)
+	}
+}
+
+func TestPrepareKeepsSmallImages(t *testing.T) {
+	img := syntheticWallpaper(64, 48)
+	prepared := Prepare(img, DefaultPixelBudget)
+
+	if prepared.Scale != 1 || prepared.Image.Bounds() != img.Bounds() {
+		t.Fatalf("small image was resized to %v", prepared.Image.Bounds())
+	}
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			if argbAt(prepared.Image, x, y) != colorToARGB(img.At(x, y)) {
+				t.Fatalf("pixel %d,%d changed", x, y)
+			}
+		}
+	}
+}
+
+// TestSeedStability checks that downsampling and refinement pick nearly the
+// same seed as quantizing every pixel
+func TestSeedStability(t *testing.T) {
+	img := syntheticWallpaper(1920, 1080)
+
+	fullGen := NewGenerator()
+	fullGen.quantizer.SetPixelBudget(0)
+	fullSeed := FindSeedColor(fullGen.quantizer.Quantize(img))
+
+	fastSeed := FindSeedColor(NewGenerator().quantizer.Quantize(img))
+
+	refined := NewQuantizer(128)
+	refined.SetRefine(true)
+	refinedSeed := FindSeedColor(refined.Quantize(img))
+
+	fullExtractor := NewEnhancedExtractor()
+	fullExtractor.SetPixelBudget(0)
+	fullExtracted, err := fullExtractor.ExtractColors(img)
+	if err != nil {
+		t.Fatalf("ExtractColors() error = %v", err)
+	}
+	fastExtracted, err := NewEnhancedExtractor().ExtractColors(img)
+	if err != nil {
+		t.Fatalf("ExtractColors() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		full, fast uint32
+	}{
+		{"quantizer", fullSeed, fastSeed},
+		{"refined quantizer", fullSeed, refinedSeed},
+		{"extractor", fullExtracted.GetBestSeedColor(), fastExtracted.GetBestSeedColor()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			full, fast := HctFromARGB(tt.full), HctFromARGB(tt.fast)
+			if diff := differenceDegrees(full.Hue, fast.Hue); diff > 10 {
+				t.Errorf("seed %08x (hue %.1f) drifted from full resolution %08x (hue %.1f)",
+					tt.fast, fast.Hue, tt.full, full.Hue)
+			}
+		})
+	}
+}
+
+var (
+	benchImageOnce sync.Once
+	benchImage     *image.RGBA
+)
+
+// benchWallpaper returns a 5K wallpaper shared by the benchmarks
+func benchWallpaper() *image.RGBA {
+	benchImageOnce.Do(func() {
+		benchImage = syntheticWallpaper(5120, 2880)
+	})
+	return benchImage
+}
+
+func BenchmarkQuantize(b *testing.B) {
+	img := benchWallpaper()
+
+	for _, bench := range []struct {
+		name   string
+		budget int
+		refine bool
+	}{
+		{"full", 0, false},
+		{"downsampled", DefaultPixelBudget, false},
+		{"downsampled-refined", DefaultPixelBudget, true},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			q := NewQuantizer(128)
+			q.SetPixelBudget(bench.budget)
+			q.SetRefine(bench.refine)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				q.Quantize(img)
+			}
+		})
+	}
+}
+
+func BenchmarkExtractColors(b *testing.B) {
+	img := benchWallpaper()
+
+	for _, bench := range []struct {
+		name   string
+		budget int
+	}{
+		{"full", 0},
+		{"downsampled", DefaultPixelBudget},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			e := NewEnhancedExtractor()
+			e.SetPixelBudget(bench.budget)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := e.ExtractColors(img); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
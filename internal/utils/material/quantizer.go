@@ -14,7 +14,9 @@ type QuantizerResult struct {
 
 // Quantizer performs color quantization on images
 type Quantizer struct {
-	maxColors int
+	maxColors   int
+	pixelBudget int
+	refine      bool
 }
 
 // NewQuantizer creates a new quantizer with the specified maximum colors
@@ -22,37 +24,43 @@ func NewQuantizer(maxColors int) *Quantizer {
 	if maxColors <= 0 {
 		maxColors = 128
 	}
-	return &Quantizer{maxColors: maxColors}
+	return &Quantizer{maxColors: maxColors, pixelBudget: DefaultPixelBudget}
+}
+
+// SetPixelBudget sets the pixel count images are downsampled to before
+// quantization; 0 quantizes the full resolution
+func (q *Quantizer) SetPixelBudget(budget int) {
+	q.pixelBudget = budget
+}
+
+// SetRefine enables k-means refinement of Wu's clusters in L*a*b*
+func (q *Quantizer) SetRefine(refine bool) {
+	q.refine = refine
 }
 
 // Quantize performs Wu's color quantization algorithm on an image
 func (q *Quantizer) Quantize(img image.Image) *QuantizerResult {
-	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
-
-	// Build color histogram
-	histogram := make(map[uint32]int)
-
-	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			c := img.At(x, y)
-			argb := colorToARGB(c)
-			histogram[argb]++
-		}
-	}
+	return q.QuantizeHistogram(Prepare(img, q.pixelBudget).Histogram)
+}
 
+// QuantizeHistogram quantizes a color histogram, such as the one of a
+// prepared image
+func (q *Quantizer) QuantizeHistogram(histogram map[uint32]int) *QuantizerResult {
 	// If we have fewer unique colors than maxColors, return them all
 	if len(histogram) <= q.maxColors {
 		return &QuantizerResult{Colors: histogram}
 	}
 
 	// Perform Wu's quantization
-	return q.wuQuantize(histogram, width*height)
+	result := q.wuQuantize(histogram)
+	if q.refine {
+		result = refineKMeans(histogram, result)
+	}
+	return result
 }
 
 // wuQuantize implements Wu's color quantization algorithm
-func (q *Quantizer) wuQuantize(histogram map[uint32]int, totalPixels int) *QuantizerResult {
+func (q *Quantizer) wuQuantize(histogram map[uint32]int) *QuantizerResult {
 	// Create color cube
 	cube := newColorCube()
 
@@ -7,8 +7,10 @@ import (
 
 // Score represents a color's suitability score for theming
 type Score struct {
-	Color uint32
-	Score float64
+	Color           uint32
+	Score           float64
+	PopulationScore float64 // Share of the image, 0-100
+	ChromaScore     float64 // Closeness to the target chroma, 0-100
 }
 
 // Scorer calculates scores for colors based on Material You criteria
@@ -62,8 +64,10 @@ func (s *Scorer) ScoreColors(colors map[uint32]int) []Score {
 		finalScore := s.chromaWeight*chromaScore + s.populationWeight*populationScore
 
 		scores = append(scores, Score{
-			Color: color,
-			Score: finalScore,
+			Color:           color,
+			Score:           finalScore,
+			PopulationScore: populationScore,
+			ChromaScore:     chromaScore,
 		})
 	}
 
@@ -0,0 +1,192 @@
+package material
+
+import (
+	"math"
+	"sort"
+)
+
+// temperatureCache finds complementary and analogous colors by color
+// temperature, which follows human perception better than hue rotation
+type temperatureCache struct {
+	input      Hct
+	hctsByHue  []Hct // One color per integer hue 0-360, at the input's chroma and tone
+	hctsByTemp []Hct // hctsByHue plus the input, coldest first
+	temps      map[uint32]float64
+}
+
+// newTemperatureCache precomputes the hue circle at the input's chroma and tone
+func newTemperatureCache(input Hct) *temperatureCache {
+	cache := &temperatureCache{
+		input: input,
+		temps: make(map[uint32]float64),
+	}
+
+	for hue := 0; hue <= 360; hue++ {
+		hct := NewHct(float64(hue), input.Chroma, input.Tone)
+		cache.hctsByHue = append(cache.hctsByHue, hct)
+		cache.temps[hct.ARGB()] = rawTemperature(hct)
+	}
+	cache.temps[input.ARGB()] = rawTemperature(input)
+
+	cache.hctsByTemp = append(append([]Hct{}, cache.hctsByHue...), input)
+	sort.SliceStable(cache.hctsByTemp, func(i, j int) bool {
+		return cache.temps[cache.hctsByTemp[i].ARGB()] < cache.temps[cache.hctsByTemp[j].ARGB()]
+	})
+
+	return cache
+}
+
+// coldest returns the coldest color on the hue circle
+func (c *temperatureCache) coldest() Hct {
+	return c.hctsByTemp[0]
+}
+
+// warmest returns the warmest color on the hue circle
+func (c *temperatureCache) warmest() Hct {
+	return c.hctsByTemp[len(c.hctsByTemp)-1]
+}
+
+// relativeTemperature places a color between the coldest (0) and warmest (1)
+func (c *temperatureCache) relativeTemperature(hct Hct) float64 {
+	coldest := c.temps[c.coldest().ARGB()]
+	span := c.temps[c.warmest().ARGB()] - coldest
+	if span == 0 {
+		return 0.5
+	}
+	return (c.temps[hct.ARGB()] - coldest) / span
+}
+
+// complement returns the color with the opposite relative temperature,
+// searching from the input's side of the coldest-warmest axis
+func (c *temperatureCache) complement() Hct {
+	coldestHue := c.coldest().Hue
+	coldestTemp := c.temps[c.coldest().ARGB()]
+	warmestHue := c.warmest().Hue
+	span := c.temps[c.warmest().ARGB()] - coldestTemp
+
+	startHue, endHue := coldestHue, warmestHue
+	if isBetween(c.input.Hue, coldestHue, warmestHue) {
+		startHue, endHue = warmestHue, coldestHue
+	}
+
+	smallestError := 1000.0
+	answer := c.hctsByHue[int(math.Round(c.input.Hue))]
+	complementTemp := 1.0 - c.relativeTemperature(c.input)
+
+	for addend := 0.0; addend <= 360.0; addend++ {
+		hue := sanitizeDegrees(startHue + addend)
+		if !isBetween(hue, startHue, endHue) {
+			continue
+		}
+
+		candidate := c.hctsByHue[int(math.Round(hue))]
+		relativeTemp := (c.temps[candidate.ARGB()] - coldestTemp) / span
+		if err := math.Abs(complementTemp - relativeTemp); err < smallestError {
+			smallestError = err
+			answer = candidate
+		}
+	}
+
+	return answer
+}
+
+// analogous returns count colors around the input, spaced evenly by
+// temperature across divisions of the hue circle
+func (c *temperatureCache) analogous(count, divisions int) []Hct {
+	startHue := int(math.Round(c.input.Hue))
+	startHct := c.hctsByHue[startHue]
+	lastTemp := c.relativeTemperature(startHct)
+
+	allColors := []Hct{startHct}
+
+	absoluteTotalTempDelta := 0.0
+	for i := 0; i < 360; i++ {
+		hct := c.hctsByHue[sanitizeHue(startHue+i)]
+		temp := c.relativeTemperature(hct)
+		absoluteTotalTempDelta += math.Abs(temp - lastTemp)
+		lastTemp = temp
+	}
+
+	tempStep := absoluteTotalTempDelta / float64(divisions)
+	totalTempDelta := 0.0
+	lastTemp = c.relativeTemperature(startHct)
+
+	for hueAddend := 1; len(allColors) < divisions; hueAddend++ {
+		hct := c.hctsByHue[sanitizeHue(startHue+hueAddend)]
+		temp := c.relativeTemperature(hct)
+		totalTempDelta += math.Abs(temp - lastTemp)
+
+		desired := float64(len(allColors)) * tempStep
+		satisfied := totalTempDelta >= desired
+		indexAddend := 1
+		for satisfied && len(allColors) < divisions {
+			allColors = append(allColors, hct)
+			desired = float64(len(allColors)+indexAddend) * tempStep
+			satisfied = totalTempDelta >= desired
+			indexAddend++
+		}
+		lastTemp = temp
+
+		if hueAddend >= 360 {
+			for len(allColors) < divisions {
+				allColors = append(allColors, hct)
+			}
+		}
+	}
+
+	answers := []Hct{c.input}
+
+	ccwCount := (count - 1) / 2
+	for i := 1; i <= ccwCount; i++ {
+		index := ((-i % len(allColors)) + len(allColors)) % len(allColors)
+		answers = append([]Hct{allColors[index]}, answers...)
+	}
+
+	cwCount := count - ccwCount - 1
+	for i := 1; i <= cwCount; i++ {
+		answers = append(answers, allColors[i%len(allColors)])
+	}
+
+	return answers
+}
+
+// rawTemperature estimates color temperature from L*a*b* hue and chroma,
+// per Ou, Woodcock and Wright (2004); warm colors are positive
+func rawTemperature(hct Hct) float64 {
+	lab := labFromARGB(hct.ARGB())
+	hue := sanitizeDegrees(math.Atan2(lab.B, lab.A) * 180.0 / math.Pi)
+	chroma := math.Hypot(lab.A, lab.B)
+	return -0.5 + 0.02*math.Pow(chroma, 1.07)*math.Cos(sanitizeDegrees(hue-50.0)*math.Pi/180.0)
+}
+
+// isBetween reports whether angle lies on the arc from a to b, clockwise
+func isBetween(angle, a, b float64) bool {
+	if a < b {
+		return a <= angle && angle <= b
+	}
+	return a <= angle || angle <= b
+}
+
+// sanitizeHue wraps an integer hue into [0, 360)
+func sanitizeHue(hue int) int {
+	hue %= 360
+	if hue < 0 {
+		hue += 360
+	}
+	return hue
+}
+
+// isDisliked reports whether a color falls in the dark yellow-green range
+// that people consistently find unpleasant
+func isDisliked(hct Hct) bool {
+	hue := math.Round(hct.Hue)
+	return hue >= 90.0 && hue <= 111.0 && math.Round(hct.Chroma) > 16.0 && math.Round(hct.Tone) < 65.0
+}
+
+// fixIfDisliked lightens disliked colors, keeping their hue and chroma
+func fixIfDisliked(hct Hct) Hct {
+	if isDisliked(hct) {
+		return NewHct(hct.Hue, hct.Chroma, 70.0)
+	}
+	return hct
+}
@@ -148,8 +148,8 @@ func CreateSymlink(target, link string) error {
 		return fmt.Errorf("failed to create parent directory: %w", err)
 	}
 
-	// Remove existing link if it exists
-	if Exists(link) {
+	// Remove existing link if it exists, even when its target is gone
+	if _, err := os.Lstat(link); err == nil {
 		if err := os.Remove(link); err != nil {
 			return fmt.Errorf("failed to remove existing link: %w", err)
 		}
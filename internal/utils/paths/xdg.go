@@ -36,6 +36,7 @@ var (
 	SchemeDataDir       string
 	SchemeCacheDir      string
 	UserSchemeDir       string
+	SchemeRepoDir       string
 	WallpapersDir       string
 	WallpapersCacheDir  string
 	ScreenshotsDir      string
@@ -79,6 +80,7 @@ func init() {
 	SchemeDataDir = filepath.Join(HeimdallDataDir, "schemes")
 	SchemeCacheDir = filepath.Join(HeimdallCacheDir, "schemes")
 	UserSchemeDir = filepath.Join(HeimdallConfigDir, "schemes")
+	SchemeRepoDir = filepath.Join(HeimdallDataDir, "scheme-repos")
 	WallpapersDir = filepath.Join(PicturesDir, "Wallpapers")
 	WallpapersCacheDir = filepath.Join(HeimdallCacheDir, "wallpapers")
 	ScreenshotsDir = filepath.Join(PicturesDir, "Screenshots")
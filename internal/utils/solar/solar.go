@@ -0,0 +1,211 @@
+// Package solar computes sunrise and sunset times offline using the
+// NOAA/Almanac sunrise equation. Accuracy is within a couple of minutes for
+// non-polar latitudes, which is plenty for switching color schemes.
+package solar
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// zenith is the official sunrise/sunset zenith, accounting for refraction
+// and the apparent radius of the sun
+const zenith = 90.833
+
+var (
+	// ErrSunNeverRises is returned for polar night
+	ErrSunNeverRises = errors.New("sun does not rise on this date")
+	// ErrSunNeverSets is returned for polar day (midnight sun)
+	ErrSunNeverSets = errors.New("sun does not set on this date")
+)
+
+// Times holds the sunrise and sunset for a single day
+type Times struct {
+	Sunrise time.Time
+	Sunset  time.Time
+}
+
+// ValidateCoordinates checks that latitude and longitude are in range
+func ValidateCoordinates(latitude, longitude float64) error {
+	if latitude < -90 || latitude > 90 {
+		return fmt.Errorf("latitude %.4f out of range (-90 to 90)", latitude)
+	}
+	if longitude < -180 || longitude > 180 {
+		return fmt.Errorf("longitude %.4f out of range (-180 to 180)", longitude)
+	}
+	return nil
+}
+
+// SunTimes returns sunrise and sunset for the calendar day of date (in
+// date's location). Results are expressed in date's location. Returns
+// ErrSunNeverRises or ErrSunNeverSets at polar latitudes.
+func SunTimes(date time.Time, latitude, longitude float64) (Times, error) {
+	if err := ValidateCoordinates(latitude, longitude); err != nil {
+		return Times{}, err
+	}
+
+	sunrise, err := event(date, latitude, longitude, true)
+	if err != nil {
+		return Times{}, err
+	}
+	sunset, err := event(date, latitude, longitude, false)
+	if err != nil {
+		return Times{}, err
+	}
+
+	return Times{Sunrise: sunrise, Sunset: sunset}, nil
+}
+
+// event computes a single sunrise (rising=true) or sunset
+func event(date time.Time, latitude, longitude float64, rising bool) (time.Time, error) {
+	dayOfYear := float64(date.YearDay())
+	lngHour := longitude / 15
+
+	// Approximate time of the event in days
+	var t float64
+	if rising {
+		t = dayOfYear + (6-lngHour)/24
+	} else {
+		t = dayOfYear + (18-lngHour)/24
+	}
+
+	// Sun's mean anomaly and true longitude
+	meanAnomaly := 0.9856*t - 3.289
+	trueLongitude := normalize(meanAnomaly+
+		1.916*sinDeg(meanAnomaly)+
+		0.020*sinDeg(2*meanAnomaly)+
+		282.634, 360)
+
+	// Right ascension, moved into the same quadrant as the true longitude
+	rightAscension := normalize(atanDeg(0.91764*tanDeg(trueLongitude)), 360)
+	rightAscension += math.Floor(trueLongitude/90)*90 - math.Floor(rightAscension/90)*90
+	rightAscension /= 15
+
+	// Declination
+	sinDec := 0.39782 * sinDeg(trueLongitude)
+	cosDec := cosDeg(asinDeg(sinDec))
+
+	// Local hour angle
+	cosH := (cosDeg(zenith) - sinDec*sinDeg(latitude)) / (cosDec * cosDeg(latitude))
+	if cosH > 1 {
+		return time.Time{}, ErrSunNeverRises
+	}
+	if cosH < -1 {
+		return time.Time{}, ErrSunNeverSets
+	}
+
+	var hourAngle float64
+	if rising {
+		hourAngle = 360 - acosDeg(cosH)
+	} else {
+		hourAngle = acosDeg(cosH)
+	}
+	hourAngle /= 15
+
+	// Local mean time of the event, then UTC
+	localMean := hourAngle + rightAscension - 0.06571*t - 6.622
+	utcHours := normalize(localMean-lngHour, 24)
+
+	loc := date.Location()
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	utcDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	result := utcDay.Add(time.Duration(utcHours * float64(time.Hour))).In(loc)
+
+	// The UTC calendar day can differ from the local one far from Greenwich
+	for result.Before(dayStart) {
+		result = result.Add(24 * time.Hour)
+	}
+	for !result.Before(dayStart.AddDate(0, 0, 1)) {
+		result = result.Add(-24 * time.Hour)
+	}
+
+	return result, nil
+}
+
+// Position returns the elevation of the sun above the horizon and its
+// azimuth clockwise from north, in degrees, at t. It follows the NOAA solar
+// position equations without atmospheric refraction.
+func Position(t time.Time, latitude, longitude float64) (elevation, azimuth float64) {
+	t = t.UTC()
+	declination, equationOfTime := sunCoordinates(t)
+
+	// Hour angle from the true solar time
+	minutes := float64(t.Hour()*60+t.Minute()) + float64(t.Second())/60 + float64(t.Nanosecond())/float64(time.Minute)
+	hourAngle := normalize(minutes+equationOfTime+4*longitude, 1440)/4 - 180
+
+	cosZenith := sinDeg(latitude)*sinDeg(declination) + cosDeg(latitude)*cosDeg(declination)*cosDeg(hourAngle)
+	zenithAngle := acosDeg(clamp(cosZenith))
+	elevation = 90 - zenithAngle
+
+	sinZenith := sinDeg(zenithAngle)
+	if sinZenith == 0 || cosDeg(latitude) == 0 {
+		return elevation, 180
+	}
+	angle := acosDeg(clamp((sinDeg(latitude)*cosZenith - sinDeg(declination)) / (cosDeg(latitude) * sinZenith)))
+	if hourAngle > 0 {
+		azimuth = normalize(angle+180, 360)
+	} else {
+		azimuth = normalize(540-angle, 360)
+	}
+	return elevation, azimuth
+}
+
+// ElevationRange returns the lowest and highest elevation of the sun on
+// the day of t, reached at solar midnight and solar noon
+func ElevationRange(t time.Time, latitude float64) (lowest, highest float64) {
+	declination, _ := sunCoordinates(t.UTC())
+	return math.Abs(latitude+declination) - 90, 90 - math.Abs(latitude-declination)
+}
+
+// sunCoordinates returns the declination of the sun in degrees and the
+// equation of time in minutes at t
+func sunCoordinates(t time.Time) (declination, equationOfTime float64) {
+	// Julian century since J2000
+	julianDay := float64(t.UnixNano())/float64(24*time.Hour) + 2440587.5
+	century := (julianDay - 2451545) / 36525
+
+	// Sun's geometric mean longitude and anomaly, and the orbit eccentricity
+	meanLongitude := normalize(280.46646+century*(36000.76983+century*0.0003032), 360)
+	meanAnomaly := 357.52911 + century*(35999.05029-0.0001537*century)
+	eccentricity := 0.016708634 - century*(0.000042037+0.0000001267*century)
+
+	// Apparent longitude and declination
+	center := sinDeg(meanAnomaly)*(1.914602-century*(0.004817+0.000014*century)) +
+		sinDeg(2*meanAnomaly)*(0.019993-0.000101*century) +
+		sinDeg(3*meanAnomaly)*0.000289
+	omega := 125.04 - 1934.136*century
+	apparentLongitude := meanLongitude + center - 0.00569 - 0.00478*sinDeg(omega)
+	meanObliquity := 23 + (26+(21.448-century*(46.815+century*(0.00059-century*0.001813)))/60)/60
+	obliquity := meanObliquity + 0.00256*cosDeg(omega)
+	declination = asinDeg(sinDeg(obliquity) * sinDeg(apparentLongitude))
+
+	y := tanDeg(obliquity/2) * tanDeg(obliquity/2)
+	equationOfTime = 4 * (180 / math.Pi) * (y*sinDeg(2*meanLongitude) -
+		2*eccentricity*sinDeg(meanAnomaly) +
+		4*eccentricity*y*sinDeg(meanAnomaly)*cosDeg(2*meanLongitude) -
+		0.5*y*y*sinDeg(4*meanLongitude) -
+		1.25*eccentricity*eccentricity*sinDeg(2*meanAnomaly))
+	return declination, equationOfTime
+}
+
+// clamp limits x to the domain of asin and acos
+func clamp(x float64) float64 {
+	return math.Max(-1, math.Min(1, x))
+}
+
+func normalize(value, max float64) float64 {
+	value = math.Mod(value, max)
+	if value < 0 {
+		value += max
+	}
+	return value
+}
+
+func sinDeg(d float64) float64  { return math.Sin(d * math.Pi / 180) }
+func cosDeg(d float64) float64  { return math.Cos(d * math.Pi / 180) }
+func tanDeg(d float64) float64  { return math.Tan(d * math.Pi / 180) }
+func asinDeg(x float64) float64 { return math.Asin(x) * 180 / math.Pi }
+func acosDeg(x float64) float64 { return math.Acos(x) * 180 / math.Pi }
+func atanDeg(x float64) float64 { return math.Atan(x) * 180 / math.Pi }
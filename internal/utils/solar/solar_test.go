@@ -0,0 +1,160 @@
+package solar
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func assertNear(t *testing.T, label string, got time.Time, wantHour, wantMinute int) {
+	t.Helper()
+	want := time.Date(got.Year(), got.Month(), got.Day(), wantHour, wantMinute, 0, 0, got.Location())
+	diff := got.Sub(want)
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > 5*time.Minute {
+		t.Errorf("%s = %s, want about %02d:%02d", label, got.Format("15:04"), wantHour, wantMinute)
+	}
+}
+
+func TestSunTimes(t *testing.T) {
+	tests := []struct {
+		name      string
+		location  string
+		date      [3]int
+		latitude  float64
+		longitude float64
+		sunrise   [2]int
+		sunset    [2]int
+	}{
+		{
+			name:      "new york summer solstice",
+			location:  "America/New_York",
+			date:      [3]int{2024, 6, 21},
+			latitude:  40.7128,
+			longitude: -74.0060,
+			sunrise:   [2]int{5, 25},
+			sunset:    [2]int{20, 31},
+		},
+		{
+			name:      "london winter solstice",
+			location:  "Europe/London",
+			date:      [3]int{2024, 12, 21},
+			latitude:  51.5074,
+			longitude: -0.1278,
+			sunrise:   [2]int{8, 4},
+			sunset:    [2]int{15, 53},
+		},
+		{
+			name:      "sydney crosses the UTC date line",
+			location:  "Australia/Sydney",
+			date:      [3]int{2024, 3, 20},
+			latitude:  -33.8688,
+			longitude: 151.2093,
+			sunrise:   [2]int{7, 3},
+			sunset:    [2]int{19, 8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := mustLoad(t, tt.location)
+			date := time.Date(tt.date[0], time.Month(tt.date[1]), tt.date[2], 12, 0, 0, 0, loc)
+
+			times, err := SunTimes(date, tt.latitude, tt.longitude)
+			if err != nil {
+				t.Fatalf("SunTimes() error = %v", err)
+			}
+
+			assertNear(t, "sunrise", times.Sunrise, tt.sunrise[0], tt.sunrise[1])
+			assertNear(t, "sunset", times.Sunset, tt.sunset[0], tt.sunset[1])
+
+			if times.Sunrise.Day() != tt.date[2] || times.Sunset.Day() != tt.date[2] {
+				t.Errorf("events not on requested day: %s / %s", times.Sunrise, times.Sunset)
+			}
+		})
+	}
+}
+
+func TestSunTimesPolar(t *testing.T) {
+	// Tromsø, Norway
+	latitude, longitude := 69.6492, 18.9553
+
+	winter := time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC)
+	if _, err := SunTimes(winter, latitude, longitude); !errors.Is(err, ErrSunNeverRises) {
+		t.Errorf("winter error = %v, want ErrSunNeverRises", err)
+	}
+
+	summer := time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC)
+	if _, err := SunTimes(summer, latitude, longitude); !errors.Is(err, ErrSunNeverSets) {
+		t.Errorf("summer error = %v, want ErrSunNeverSets", err)
+	}
+}
+
+func TestValidateCoordinates(t *testing.T) {
+	if err := ValidateCoordinates(91, 0); err == nil {
+		t.Error("expected error for latitude 91")
+	}
+	if err := ValidateCoordinates(0, -181); err == nil {
+		t.Error("expected error for longitude -181")
+	}
+	if err := ValidateCoordinates(-33.8, 151.2); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPosition(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	// Summer solstice at solar noon: 90 - 52.52 + 23.44
+	elevation, azimuth := Position(time.Date(2024, 6, 21, 13, 8, 0, 0, berlin), 52.52, 13.405)
+	if elevation < 60.5 || elevation > 61.3 {
+		t.Errorf("noon elevation = %.2f, want about 60.9", elevation)
+	}
+	if azimuth < 175 || azimuth > 185 {
+		t.Errorf("noon azimuth = %.2f, want about 180", azimuth)
+	}
+
+	// Morning sun is in the east, evening sun in the west
+	_, morning := Position(time.Date(2024, 6, 21, 8, 0, 0, 0, berlin), 52.52, 13.405)
+	_, evening := Position(time.Date(2024, 6, 21, 19, 0, 0, 0, berlin), 52.52, 13.405)
+	if morning >= 180 || evening <= 180 {
+		t.Errorf("azimuths = %.2f and %.2f, want east then west", morning, evening)
+	}
+
+	// The sun is just below the horizon at the computed sunset
+	times, err := SunTimes(time.Date(2024, 6, 21, 12, 0, 0, 0, berlin), 52.52, 13.405)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elevation, _ := Position(times.Sunset, 52.52, 13.405); elevation < -1.5 || elevation > 0 {
+		t.Errorf("elevation at sunset = %.2f, want about -0.83", elevation)
+	}
+}
+
+func TestElevationRange(t *testing.T) {
+	// Berlin: high summer sun, low winter sun
+	lowest, highest := ElevationRange(time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC), 52.52)
+	if highest < 60.5 || highest > 61.3 || lowest < -14.5 || lowest > -13.5 {
+		t.Errorf("summer range = %.2f to %.2f, want about -14.1 to 60.9", lowest, highest)
+	}
+	lowest, highest = ElevationRange(time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC), 52.52)
+	if highest < 13.6 || highest > 14.5 || lowest > -60.5 {
+		t.Errorf("winter range = %.2f to %.2f, want about -60.9 to 14.1", lowest, highest)
+	}
+
+	// Midnight sun in Svalbard
+	if lowest, _ := ElevationRange(time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC), 78.22); lowest <= 0 {
+		t.Errorf("svalbard summer lowest = %.2f, want above the horizon", lowest)
+	}
+}
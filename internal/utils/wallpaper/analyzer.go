@@ -3,10 +3,10 @@ package wallpaper
 import (
 	"fmt"
 	"image"
-	_ "image/jpeg"
-	_ "image/png"
 	"math"
-	"os"
+	"sort"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/imageio"
 )
 
 // Analyzer analyzes wallpaper images for various properties
@@ -24,7 +24,12 @@ func (a *Analyzer) AnalyzeColourfulness(path string) (float64, error) {
 	if err != nil {
 		return 0, err
 	}
+	return colourfulness(img)
+}
 
+// colourfulness calculates the Hasler and Süsstrunk colourfulness of a
+// decoded image
+func colourfulness(img image.Image) (float64, error) {
 	bounds := img.Bounds()
 	width := bounds.Dx()
 	height := bounds.Dy()
@@ -97,7 +102,11 @@ func (a *Analyzer) DetermineMode(path string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return determineMode(img)
+}
 
+// determineMode returns the scheme mode suited to a decoded image
+func determineMode(img image.Image) (string, error) {
 	bounds := img.Bounds()
 	width := bounds.Dx()
 	height := bounds.Dy()
@@ -153,13 +162,12 @@ func (a *Analyzer) DetermineMode(path string) (string, error) {
 
 // GetDimensions returns the width and height of an image
 func (a *Analyzer) GetDimensions(path string) (int, int, error) {
-	img, err := a.loadImage(path)
+	cfg, err := imageio.DecodeConfig(path)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	bounds := img.Bounds()
-	return bounds.Dx(), bounds.Dy(), nil
+	return cfg.Width, cfg.Height, nil
 }
 
 // AnalyzeDominantColors extracts the dominant colors from an image
@@ -168,7 +176,11 @@ func (a *Analyzer) AnalyzeDominantColors(path string, numColors int) ([]uint32,
 	if err != nil {
 		return nil, err
 	}
+	return dominantColors(img, numColors), nil
+}
 
+// dominantColors returns the most frequent colors of a decoded image
+func dominantColors(img image.Image, numColors int) []uint32 {
 	// Build color histogram
 	colorCount := make(map[uint32]int)
 	bounds := img.Bounds()
@@ -210,14 +222,13 @@ func (a *Analyzer) AnalyzeDominantColors(path string, numColors int) ([]uint32,
 		colors = append(colors, colorFreq{color, count})
 	}
 
-	// Sort by count descending
-	for i := 0; i < len(colors)-1; i++ {
-		for j := i + 1; j < len(colors); j++ {
-			if colors[j].count > colors[i].count {
-				colors[i], colors[j] = colors[j], colors[i]
-			}
+	// Sort by count descending, ties by color so results are stable
+	sort.Slice(colors, func(i, j int) bool {
+		if colors[i].count != colors[j].count {
+			return colors[i].count > colors[j].count
 		}
-	}
+		return colors[i].color < colors[j].color
+	})
 
 	// Return top N colors
 	result := make([]uint32, 0, numColors)
@@ -225,7 +236,7 @@ func (a *Analyzer) AnalyzeDominantColors(path string, numColors int) ([]uint32,
 		result = append(result, colors[i].color)
 	}
 
-	return result, nil
+	return result
 }
 
 // CalculateContrast calculates the contrast ratio between two colors
@@ -269,20 +280,10 @@ func (a *Analyzer) gammaCorrect(channel float64) float64 {
 	return math.Pow((channel+0.055)/1.055, 2.4)
 }
 
-// loadImage loads an image from a file path
+// loadImage loads an image from a file path. Animated images come back as
+// a strip of sampled frames.
 func (a *Analyzer) loadImage(path string) (image.Image, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open image: %w", err)
-	}
-	defer file.Close()
-
-	img, _, err := image.Decode(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode image: %w", err)
-	}
-
-	return img, nil
+	return imageio.Decode(path)
 }
 
 // Info represents wallpaper metadata
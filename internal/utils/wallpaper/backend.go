@@ -0,0 +1,213 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+)
+
+// Wallpaper backend names
+const (
+	BackendAuto      = "auto"
+	BackendHyprpaper = "hyprpaper"
+	BackendSwww      = "swww"
+	BackendSwaybg    = "swaybg"
+	BackendMpvpaper  = "mpvpaper"
+)
+
+// BackendNames lists the backends in auto-detection order
+var BackendNames = []string{BackendHyprpaper, BackendSwww, BackendSwaybg, BackendMpvpaper}
+
+// Backend shows wallpapers through a wallpaper daemon or program
+type Backend interface {
+	// Name returns the backend name used in the config
+	Name() string
+	// Available reports whether the backend can be used right now
+	Available() bool
+	// Set shows the wallpaper state holds for monitor, or for every monitor
+	// when monitor is empty. Backends running their own processes update
+	// state.PIDs.
+	Set(state *MonitorState, monitor string) error
+}
+
+// activeLister is implemented by backends that can report what each
+// monitor shows
+type activeLister interface {
+	Active() (map[string]string, error)
+}
+
+// BackendOptions configures the backends
+type BackendOptions struct {
+	Transition         string  // swww transition type
+	TransitionDuration float64 // swww transition duration in seconds
+	TransitionFPS      int     // swww transition frame rate
+}
+
+// NewBackend returns the named backend, detecting one when name is empty
+// or auto
+func NewBackend(name string, opts BackendOptions) (Backend, error) {
+	switch name {
+	case "", BackendAuto:
+		return DetectBackend(opts)
+	case BackendHyprpaper:
+		return &hyprpaperBackend{}, nil
+	case BackendSwww:
+		return &swwwBackend{opts: opts}, nil
+	case BackendSwaybg:
+		return &swaybgBackend{}, nil
+	case BackendMpvpaper:
+		return &mpvpaperBackend{}, nil
+	}
+	return nil, fmt.Errorf("unknown wallpaper backend %q (available: %v)", name, BackendNames)
+}
+
+// DetectBackend returns the first available backend. Running daemons
+// (hyprpaper, swww) are preferred over programs heimdall has to start.
+func DetectBackend(opts BackendOptions) (Backend, error) {
+	for _, name := range BackendNames {
+		backend, err := NewBackend(name, opts)
+		if err != nil {
+			return nil, err
+		}
+		if backend.Available() {
+			return backend, nil
+		}
+	}
+	return nil, fmt.Errorf("no wallpaper backend available (tried %v)", BackendNames)
+}
+
+// SetWallpaper shows path on monitor, or on every monitor when monitor is
+// empty, and records it in the monitor state
+func SetWallpaper(backend Backend, path, monitor string) error {
+	state, err := LoadMonitorState(DefaultMonitorStatePath())
+	if err != nil {
+		return err
+	}
+
+	// Processes of a previous backend would keep drawing over the new one
+	if state.Backend != backend.Name() {
+		stopProcesses(state)
+		state.Backend = backend.Name()
+	}
+
+	state.Record(monitor, path)
+	if err := backend.Set(state, monitor); err != nil {
+		return fmt.Errorf("failed to set wallpaper with %s: %w", backend.Name(), err)
+	}
+	return state.Save()
+}
+
+// ActiveWallpapers returns the wallpaper of each monitor keyed by monitor
+// name, where an empty name is the wallpaper of every other monitor. The
+// backend is asked when it can tell; otherwise the wallpapers heimdall set
+// are used.
+func ActiveWallpapers(backend Backend) (map[string]string, error) {
+	if lister, ok := backend.(activeLister); ok {
+		active, err := lister.Active()
+		if err == nil && len(active) > 0 {
+			return active, nil
+		}
+	}
+
+	state, err := LoadMonitorState(DefaultMonitorStatePath())
+	if err != nil {
+		return nil, err
+	}
+	if len(state.Monitors) == 0 {
+		return nil, fmt.Errorf("no wallpaper set with %s", backend.Name())
+	}
+	return state.Monitors, nil
+}
+
+// MonitorState tracks the wallpaper heimdall set on each monitor and the
+// processes showing them
+type MonitorState struct {
+	path      string
+	Backend   string            `json:"backend"`
+	Monitors  map[string]string `json:"monitors"` // Monitor name to wallpaper, "" for every other monitor
+	PIDs      []int             `json:"pids,omitempty"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// DefaultMonitorStatePath returns the location of the monitor state
+func DefaultMonitorStatePath() string {
+	return filepath.Join(paths.HeimdallStateDir, "wallpaper", "monitors.json")
+}
+
+// LoadMonitorState reads the monitor state, returning an empty state when
+// the file does not exist
+func LoadMonitorState(path string) (*MonitorState, error) {
+	state := &MonitorState{path: path, Monitors: make(map[string]string)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read monitor state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse monitor state: %w", err)
+	}
+	if state.Monitors == nil {
+		state.Monitors = make(map[string]string)
+	}
+	return state, nil
+}
+
+// Record sets the wallpaper of monitor. An empty monitor sets every
+// monitor, dropping the per-monitor wallpapers.
+func (s *MonitorState) Record(monitor, path string) {
+	if monitor == "" {
+		s.Monitors = make(map[string]string)
+	}
+	s.Monitors[monitor] = path
+	s.UpdatedAt = time.Now()
+}
+
+// Wallpaper returns the wallpaper shown on monitor
+func (s *MonitorState) Wallpaper(monitor string) (string, bool) {
+	if path, ok := s.Monitors[monitor]; ok {
+		return path, true
+	}
+	path, ok := s.Monitors[""]
+	return path, ok
+}
+
+// monitorNames returns the monitors with their own wallpaper, sorted
+func (s *MonitorState) monitorNames() []string {
+	names := make([]string, 0, len(s.Monitors))
+	for name := range s.Monitors {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save writes the state atomically
+func (s *MonitorState) Save() error {
+	if err := paths.AtomicWriteJSON(s.path, s); err != nil {
+		return fmt.Errorf("failed to write monitor state: %w", err)
+	}
+	return nil
+}
+
+// processRunning reports whether a process with the exact name is running
+func processRunning(name string) bool {
+	return exec.Command("pgrep", "-x", name).Run() == nil
+}
+
+// commandAvailable reports whether a program is on PATH
+func commandAvailable(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
@@ -0,0 +1,136 @@
+package wallpaper
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMonitorState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monitors.json")
+
+	state, err := LoadMonitorState(path)
+	if err != nil {
+		t.Fatalf("LoadMonitorState() error = %v", err)
+	}
+	if _, ok := state.Wallpaper("DP-1"); ok {
+		t.Fatal("expected no wallpaper in an empty state")
+	}
+
+	state.Record("", "/walls/all.png")
+	state.Record("DP-1", "/walls/dp1.png")
+	state.Backend = BackendSwaybg
+	state.PIDs = []int{42}
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadMonitorState(path)
+	if err != nil {
+		t.Fatalf("LoadMonitorState() error = %v", err)
+	}
+	if got, _ := loaded.Wallpaper("DP-1"); got != "/walls/dp1.png" {
+		t.Errorf("Wallpaper(DP-1) = %q, want /walls/dp1.png", got)
+	}
+	if got, _ := loaded.Wallpaper("HDMI-A-1"); got != "/walls/all.png" {
+		t.Errorf("Wallpaper(HDMI-A-1) = %q, want the wallpaper for every monitor", got)
+	}
+	if loaded.Backend != BackendSwaybg || !reflect.DeepEqual(loaded.PIDs, []int{42}) {
+		t.Errorf("loaded backend %q pids %v", loaded.Backend, loaded.PIDs)
+	}
+
+	loaded.Record("", "/walls/new.png")
+	if want := map[string]string{"": "/walls/new.png"}; !reflect.DeepEqual(loaded.Monitors, want) {
+		t.Errorf("Record() for every monitor = %v, want %v", loaded.Monitors, want)
+	}
+}
+
+func TestNewBackend(t *testing.T) {
+	for _, name := range BackendNames {
+		backend, err := NewBackend(name, BackendOptions{})
+		if err != nil {
+			t.Fatalf("NewBackend(%q) error = %v", name, err)
+		}
+		if backend.Name() != name {
+			t.Errorf("NewBackend(%q).Name() = %q", name, backend.Name())
+		}
+	}
+
+	if _, err := NewBackend("feh", BackendOptions{}); err == nil {
+		t.Error("expected an error for an unknown backend")
+	}
+}
+
+func TestSwwwArgs(t *testing.T) {
+	backend := &swwwBackend{opts: BackendOptions{Transition: "grow", TransitionDuration: 1.5, TransitionFPS: 144}}
+
+	got := backend.args("/walls/a.png", "DP-1")
+	want := []string{"img", "/walls/a.png", "--transition-type", "grow", "--transition-duration", "1.5", "--transition-fps", "144", "--outputs", "DP-1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("args() = %v, want %v", got, want)
+	}
+
+	got = (&swwwBackend{}).args("/walls/a.png", "")
+	if want := []string{"img", "/walls/a.png"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("args() = %v, want %v", got, want)
+	}
+}
+
+func TestParseSwwwQuery(t *testing.T) {
+	output := `: DP-1: 2560x1440, scale: 1, currently displaying: image: /walls/a b.png
+: HDMI-A-1: 1920x1080, scale: 1, currently displaying: color: 000000
+eDP-1: 1920x1200, scale: 1.25, currently displaying: image: /walls/c.jpg
+`
+	want := map[string]string{"DP-1": "/walls/a b.png", "eDP-1": "/walls/c.jpg"}
+	if got := parseSwwwQuery(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSwwwQuery() = %v, want %v", got, want)
+	}
+}
+
+func TestSwaybgArgs(t *testing.T) {
+	state := &MonitorState{Monitors: map[string]string{
+		"":         "/walls/all.png",
+		"HDMI-A-1": "/walls/hdmi.png",
+		"DP-1":     "/walls/dp1.png",
+	}}
+
+	want := []string{
+		"-o", "*", "-i", "/walls/all.png", "-m", "fill",
+		"-o", "DP-1", "-i", "/walls/dp1.png", "-m", "fill",
+		"-o", "HDMI-A-1", "-i", "/walls/hdmi.png", "-m", "fill",
+	}
+	if got := swaybgArgs(state); !reflect.DeepEqual(got, want) {
+		t.Errorf("swaybgArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestMpvpaperOutputs(t *testing.T) {
+	all := &MonitorState{Monitors: map[string]string{"": "/walls/all.mp4"}}
+	if got, want := mpvpaperOutputs(all, []string{"DP-1", "HDMI-A-1"}), [][2]string{{"*", "/walls/all.mp4"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("mpvpaperOutputs() = %v, want %v", got, want)
+	}
+
+	mixed := &MonitorState{Monitors: map[string]string{"": "/walls/all.mp4", "HDMI-A-1": "/walls/hdmi.mp4"}}
+	want := [][2]string{{"DP-1", "/walls/all.mp4"}, {"HDMI-A-1", "/walls/hdmi.mp4"}}
+	if got := mpvpaperOutputs(mixed, []string{"DP-1", "HDMI-A-1"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("mpvpaperOutputs() = %v, want %v", got, want)
+	}
+
+	// Without the monitor list the named output is drawn over *
+	want = [][2]string{{"*", "/walls/all.mp4"}, {"HDMI-A-1", "/walls/hdmi.mp4"}}
+	if got := mpvpaperOutputs(mixed, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("mpvpaperOutputs() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckHyprpaperReply(t *testing.T) {
+	if err := checkHyprpaperReply("preload", "ok"); err != nil {
+		t.Errorf("ok reply: %v", err)
+	}
+	for _, reply := range []string{"", "wallpaper failed (not preloaded)", "invalid hyprpaper request"} {
+		if err := checkHyprpaperReply("wallpaper", reply); err == nil {
+			t.Errorf("reply %q: expected an error", reply)
+		}
+	}
+
+}
@@ -0,0 +1,163 @@
+package wallpaper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+)
+
+// DefaultDuplicateDistance is the number of differing hash bits up to which
+// two wallpapers count as the same image
+const DefaultDuplicateDistance = 8
+
+// DuplicateGroup is a set of near-identical wallpapers and the copy kept
+type DuplicateGroup struct {
+	Keep       *IndexEntry   `json:"keep"`
+	Duplicates []*IndexEntry `json:"duplicates"`
+}
+
+// FindDuplicates groups entries whose perceptual hashes, computed with
+// algorithm, differ in at most maxDistance bits. Near hashes are clustered
+// transitively, then each cluster is split so that every duplicate is
+// within maxDistance of the copy kept: the highest resolution copy, then
+// the largest file. Entries without a hash, not yet analyzed by the current
+// index version, are skipped.
+func FindDuplicates(entries []*IndexEntry, algorithm string, maxDistance int) ([]DuplicateGroup, error) {
+	hashOf := func(entry *IndexEntry) PerceptualHash { return entry.PHash }
+	switch algorithm {
+	case HashPHash:
+	case HashDHash:
+		hashOf = func(entry *IndexEntry) PerceptualHash { return entry.DHash }
+	default:
+		return nil, fmt.Errorf("unknown hash %q (available: %s)", algorithm, strings.Join(HashNames, ", "))
+	}
+
+	hashed := make([]*IndexEntry, 0, len(entries))
+	for _, entry := range entries {
+		if hashOf(entry) != 0 {
+			hashed = append(hashed, entry)
+		}
+	}
+	entries = hashed
+
+	// Union-find over every pair close enough
+	parent := make([]int, len(entries))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range entries {
+		for j := i + 1; j < len(entries); j++ {
+			if hashOf(entries[i]).Distance(hashOf(entries[j])) <= maxDistance {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	clusters := make(map[int][]*IndexEntry)
+	for i, entry := range entries {
+		root := find(i)
+		clusters[root] = append(clusters[root], entry)
+	}
+
+	var groups []DuplicateGroup
+	for _, cluster := range clusters {
+		sort.Slice(cluster, func(i, j int) bool { return betterCopy(cluster[i], cluster[j]) })
+
+		// A chain of near hashes can link images far apart, so only the
+		// copies close to the kept one are its duplicates; the rest form
+		// groups of their own
+		for len(cluster) > 1 {
+			group := DuplicateGroup{Keep: cluster[0]}
+			var rest []*IndexEntry
+			for _, entry := range cluster[1:] {
+				if hashOf(group.Keep).Distance(hashOf(entry)) <= maxDistance {
+					group.Duplicates = append(group.Duplicates, entry)
+				} else {
+					rest = append(rest, entry)
+				}
+			}
+			if len(group.Duplicates) > 0 {
+				groups = append(groups, group)
+			}
+			cluster = rest
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Keep.Path < groups[j].Keep.Path })
+	return groups, nil
+}
+
+// betterCopy reports whether a is a better copy to keep than b
+func betterCopy(a, b *IndexEntry) bool {
+	if pa, pb := a.Width*a.Height, b.Width*b.Height; pa != pb {
+		return pa > pb
+	}
+	if a.Size != b.Size {
+		return a.Size > b.Size
+	}
+	return a.Path < b.Path
+}
+
+// MarkDuplicates excludes the duplicates of each group from queries
+func (idx *Index) MarkDuplicates(groups []DuplicateGroup) {
+	for _, group := range groups {
+		group.Keep.DuplicateOf = ""
+		for _, duplicate := range group.Duplicates {
+			duplicate.DuplicateOf = group.Keep.Path
+		}
+	}
+}
+
+// ClearDuplicates includes the wallpapers below dir that dedupe excluded
+// in queries again, returning how many there were
+func (idx *Index) ClearDuplicates(dir string) int {
+	cleared := 0
+	for _, entry := range idx.Entries {
+		if entry.DuplicateOf != "" && underAny(entry.Path, []string{dir}) {
+			entry.DuplicateOf = ""
+			cleared++
+		}
+	}
+	return cleared
+}
+
+// Quarantine moves path below root into dir, keeping its path relative to
+// root so files of the same name do not collide, and returns the new path
+func Quarantine(path, root, dir string) (string, error) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = filepath.Base(path)
+	}
+
+	target := filepath.Join(dir, rel)
+	ext := filepath.Ext(target)
+	base := strings.TrimSuffix(target, ext)
+	for i := 1; paths.Exists(target); i++ {
+		target = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+
+	if err := paths.EnsureParentDir(target); err != nil {
+		return "", fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+	if err := os.Rename(path, target); err != nil {
+		// The quarantine may be on another filesystem
+		if err := paths.CopyFile(path, target); err != nil {
+			return "", fmt.Errorf("failed to move %s: %w", path, err)
+		}
+		if err := os.Remove(path); err != nil {
+			return "", fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	return target, nil
+}
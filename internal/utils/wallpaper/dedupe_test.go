@@ -0,0 +1,158 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// pattern renders the same scene at any size; flip mirrors it
+func pattern(w, h int, flip bool) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			u, v := float64(x)/float64(w), float64(y)/float64(h)
+			if flip {
+				u = 1 - u
+			}
+			r := 127 + 127*math.Sin(6*u)
+			g := 127 + 127*math.Cos(5*v+u)
+			b := 255 * u * v
+			img.SetRGBA(x, y, color.RGBA{uint8(r), uint8(g), uint8(b), 255})
+		}
+	}
+	return img
+}
+
+func TestPerceptualHashes(t *testing.T) {
+	large, small, other := pattern(256, 144, false), pattern(96, 54, false), pattern(256, 144, true)
+
+	for name, hash := range map[string]func(*image.RGBA) PerceptualHash{"dhash": DHash, "phash": PHash} {
+		if d := hash(large).Distance(hash(small)); d > DefaultDuplicateDistance {
+			t.Errorf("%s: resized copy is %d bits away", name, d)
+		}
+		if d := hash(large).Distance(hash(other)); d <= DefaultDuplicateDistance {
+			t.Errorf("%s: different image is only %d bits away", name, d)
+		}
+	}
+}
+
+func TestPerceptualHashJSON(t *testing.T) {
+	entry := IndexEntry{PHash: PerceptualHash(math.MaxUint64 - 1)}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded IndexEntry
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded.PHash != entry.PHash {
+		t.Errorf("PHash = %s, want %s", decoded.PHash, entry.PHash)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	entries := []*IndexEntry{
+		{Path: "/w/small.jpg", Width: 1280, Height: 720, PHash: 0xFF00},
+		{Path: "/w/large.png", Width: 3840, Height: 2160, PHash: 0xFF01},
+		{Path: "/w/medium.jpg", Width: 1920, Height: 1080, PHash: 0xFF03},
+		{Path: "/w/other.jpg", Width: 3840, Height: 2160, PHash: 0xFFFF0000},
+	}
+
+	groups, err := FindDuplicates(entries, HashPHash, 2)
+	if err != nil {
+		t.Fatalf("FindDuplicates() error = %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	if groups[0].Keep.Path != "/w/large.png" || len(groups[0].Duplicates) != 2 {
+		t.Errorf("kept %s with %d duplicates", groups[0].Keep.Path, len(groups[0].Duplicates))
+	}
+
+	// Chained hashes: far only duplicates the middle one, not the kept copy
+	chain := []*IndexEntry{
+		{Path: "/w/keep.png", Width: 3840, Height: 2160, DHash: 0x0F},
+		{Path: "/w/near.png", Width: 1920, Height: 1080, DHash: 0x3F},
+		{Path: "/w/far.png", Width: 1280, Height: 720, DHash: 0xFF},
+		{Path: "/w/unhashed.png", Width: 1280, Height: 720},
+		{Path: "/w/unhashed.jpg", Width: 1280, Height: 720},
+	}
+	groups, err = FindDuplicates(chain, HashDHash, 2)
+	if err != nil {
+		t.Fatalf("FindDuplicates() error = %v", err)
+	}
+	if len(groups) != 1 || groups[0].Keep.Path != "/w/keep.png" || len(groups[0].Duplicates) != 1 || groups[0].Duplicates[0].Path != "/w/near.png" {
+		t.Errorf("chained groups = %+v, want keep.png with near.png only", groups)
+	}
+
+	if _, err := FindDuplicates(entries, "ahash", 2); err == nil {
+		t.Error("expected an error for an unknown hash")
+	}
+}
+
+func TestMarkDuplicatesExcludesFromQueries(t *testing.T) {
+	index := &Index{Entries: map[string]*IndexEntry{
+		"/w/a.png": {Path: "/w/a.png", Width: 100, Height: 100, DHash: 0xF0F0},
+		"/w/b.png": {Path: "/w/b.png", Width: 50, Height: 50, DHash: 0xF0F0},
+	}}
+	groups, _ := FindDuplicates([]*IndexEntry{index.Entries["/w/a.png"], index.Entries["/w/b.png"]}, HashDHash, 0)
+	index.MarkDuplicates(groups)
+
+	if got := index.Query(IndexQuery{}); len(got) != 1 || got[0].Path != "/w/a.png" {
+		t.Errorf("Query() returned %d entries, want only the kept copy", len(got))
+	}
+	if got := index.Query(IndexQuery{Duplicates: true}); len(got) != 2 {
+		t.Errorf("Query(Duplicates) returned %d entries, want 2", len(got))
+	}
+
+	if cleared := index.ClearDuplicates("/w"); cleared != 1 {
+		t.Errorf("ClearDuplicates() = %d, want 1", cleared)
+	}
+	if got := index.Query(IndexQuery{}); len(got) != 2 {
+		t.Errorf("Query() after clearing returned %d entries, want 2", len(got))
+	}
+}
+
+func TestQuarantine(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "walls")
+	quarantine := filepath.Join(dir, "duplicates")
+
+	for _, name := range []string{"nested/a.png", "b/nested/a.png"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A quarantined file of the same relative path already exists
+	if err := os.MkdirAll(filepath.Join(quarantine, "nested"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(quarantine, "nested", "a.png"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := Quarantine(filepath.Join(root, "nested", "a.png"), root, quarantine)
+	if err != nil {
+		t.Fatalf("Quarantine() error = %v", err)
+	}
+	if moved != filepath.Join(quarantine, "nested", "a-1.png") {
+		t.Errorf("moved to %s", moved)
+	}
+	if _, err := os.Stat(filepath.Join(root, "nested", "a.png")); !os.IsNotExist(err) {
+		t.Error("original file still exists")
+	}
+	if data, _ := os.ReadFile(moved); string(data) != "nested/a.png" {
+		t.Errorf("quarantined file holds %q", data)
+	}
+}
@@ -0,0 +1,364 @@
+package wallpaper
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/image/draw"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/color"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/imageio"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+)
+
+// Derived image variants
+const (
+	DeriveBlur     = "blur"
+	DeriveDim      = "dim"
+	DeriveTint     = "tint"
+	DeriveMonitors = "monitors"
+)
+
+// DeriveNames lists the derived image variants
+var DeriveNames = []string{DeriveBlur, DeriveDim, DeriveTint, DeriveMonitors}
+
+// blurSize is the longer side blurred images are scaled to. Blurring hides
+// the lost detail and keeps large wallpapers fast.
+const blurSize = 1920
+
+// derivedQuality is the JPEG quality of derived images
+const derivedQuality = 90
+
+// DeriveOptions selects and configures the derived images
+type DeriveOptions struct {
+	Variants   []string
+	BlurRadius float64       // Gaussian sigma in pixels of the scaled image
+	DimAmount  float64       // Darkening in dark mode, 0-1; light mode uses half
+	TintAmount float64       // Opacity of the surface color overlay, 0-1
+	Mode       string        // Scheme mode
+	Surface    string        // Scheme surface color the tint overlays
+	Monitors   []MonitorSize // Monitors to crop for
+}
+
+// MonitorSize is the name and resolution of a monitor
+type MonitorSize struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// DerivedImage is a derived image in the cache and its stable link
+type DerivedImage struct {
+	Name string `json:"name"` // Variant, or monitor name for crops
+	Path string `json:"path"`
+	Link string `json:"link"`
+}
+
+// DefaultDerivedCacheDir returns the location of derived images
+func DefaultDerivedCacheDir() string {
+	return filepath.Join(paths.HeimdallCacheDir, "derived")
+}
+
+// DerivedLinkPath returns the stable link to the derived image name of the
+// current wallpaper, next to the current wallpaper link
+func DerivedLinkPath(name string) string {
+	return paths.WallpaperLinkPath + "-" + name
+}
+
+// Derive produces the derived images of the wallpaper at path in cacheDir
+// and points their links at them. Images are named by wallpaper content and
+// settings, so switching back to a wallpaper reuses them.
+func Derive(path, cacheDir string, opts DeriveOptions) ([]DerivedImage, error) {
+	hash, err := HashFile(path)
+	if err != nil {
+		return nil, err
+	}
+	prefix := filepath.Join(cacheDir, hash[:16])
+
+	var img image.Image
+	source := func() (image.Image, error) {
+		if img == nil {
+			if img, err = imageio.Decode(path); err != nil {
+				return nil, err
+			}
+		}
+		return img, nil
+	}
+
+	var derived []DerivedImage
+	produce := func(name, key string, render func(image.Image) (image.Image, error)) error {
+		target := prefix + "-" + key + ".jpg"
+		if paths.Exists(target) {
+			// Keep reused images from being pruned
+			now := time.Now()
+			os.Chtimes(target, now, now)
+		} else {
+			src, err := source()
+			if err != nil {
+				return err
+			}
+			out, err := render(src)
+			if err != nil {
+				return err
+			}
+			if err := writeJPEG(target, out); err != nil {
+				return err
+			}
+		}
+
+		link := DerivedLinkPath(name)
+		if err := paths.CreateSymlink(target, link); err != nil {
+			return fmt.Errorf("failed to link %s: %w", link, err)
+		}
+		derived = append(derived, DerivedImage{Name: name, Path: target, Link: link})
+		return nil
+	}
+
+	var errs []string
+	for _, variant := range opts.Variants {
+		var err error
+		switch variant {
+		case DeriveBlur:
+			err = produce(DeriveBlur, fmt.Sprintf("blur%g", opts.BlurRadius), func(src image.Image) (image.Image, error) {
+				return GaussianBlur(fit(src, blurSize), opts.BlurRadius), nil
+			})
+		case DeriveDim:
+			amount := opts.DimAmount
+			if opts.Mode == "light" {
+				amount /= 2
+			}
+			err = produce(DeriveDim, fmt.Sprintf("dim%g", amount), func(src image.Image) (image.Image, error) {
+				return Dim(toRGBA(src), amount), nil
+			})
+		case DeriveTint:
+			surface, parseErr := color.NewFromHex(opts.Surface)
+			if parseErr != nil {
+				err = fmt.Errorf("tint needs the scheme surface color: %w", parseErr)
+				break
+			}
+			hex := strings.TrimPrefix(surface.Hex, "#")
+			err = produce(DeriveTint, fmt.Sprintf("tint%s-%g", hex, opts.TintAmount), func(src image.Image) (image.Image, error) {
+				return Tint(toRGBA(src), surface.RGB.R, surface.RGB.G, surface.RGB.B, opts.TintAmount), nil
+			})
+		case DeriveMonitors:
+			for _, monitor := range opts.Monitors {
+				monitor := monitor
+				key := fmt.Sprintf("%dx%d", monitor.Width, monitor.Height)
+				if cropErr := produce(monitor.Name, key, func(src image.Image) (image.Image, error) {
+					return CoverCrop(src, monitor.Width, monitor.Height), nil
+				}); cropErr != nil {
+					errs = append(errs, fmt.Sprintf("%s: %v", monitor.Name, cropErr))
+				}
+			}
+		default:
+			err = fmt.Errorf("unknown variant (available: %s)", strings.Join(DeriveNames, ", "))
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", variant, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return derived, fmt.Errorf("failed to derive images: %s", strings.Join(errs, "; "))
+	}
+	return derived, nil
+}
+
+// GaussianBlur approximates a gaussian blur of standard deviation sigma
+// with three box blurs
+func GaussianBlur(img *image.RGBA, sigma float64) *image.RGBA {
+	out := cloneRGBA(img)
+	if sigma <= 0 {
+		return out
+	}
+
+	w, h := out.Bounds().Dx(), out.Bounds().Dy()
+	tmp := make([]uint8, len(out.Pix))
+	for _, size := range boxSizes(sigma, 3) {
+		r := (size - 1) / 2
+		boxBlur(out.Pix, tmp, w, h, 4, 4*w, r)
+		boxBlur(tmp, out.Pix, h, w, 4*w, 4, r)
+	}
+	return out
+}
+
+// boxSizes returns n box widths whose successive blurs approximate a
+// gaussian of sigma
+func boxSizes(sigma float64, n int) []int {
+	ideal := math.Sqrt(12*sigma*sigma/float64(n) + 1)
+	lower := int(math.Floor(ideal))
+	if lower%2 == 0 {
+		lower--
+	}
+	upper := lower + 2
+
+	m := int(math.Round((12*sigma*sigma - float64(n*lower*lower+4*n*lower+3*n)) / float64(-4*lower-4)))
+	sizes := make([]int, n)
+	for i := range sizes {
+		if i < m {
+			sizes[i] = lower
+		} else {
+			sizes[i] = upper
+		}
+	}
+	return sizes
+}
+
+// boxBlur averages each pixel with r neighbours on each side along one
+// axis, clamping at the edges. Lines are count pixels of step bytes apart,
+// and consecutive lines start stride bytes apart.
+func boxBlur(src, dst []uint8, count, lines, step, stride, r int) {
+	n := 2*r + 1
+	for line := 0; line < lines; line++ {
+		base := line * stride
+		at := func(i int) int {
+			return base + min(max(i, 0), count-1)*step
+		}
+
+		for c := 0; c < 4; c++ {
+			sum := 0
+			for i := -r; i <= r; i++ {
+				sum += int(src[at(i)+c])
+			}
+			for i := 0; i < count; i++ {
+				dst[base+i*step+c] = uint8((sum + n/2) / n)
+				sum += int(src[at(i+r+1)+c]) - int(src[at(i-r)+c])
+			}
+		}
+	}
+}
+
+// Dim darkens img by amount, from 0 (unchanged) to 1 (black)
+func Dim(img *image.RGBA, amount float64) *image.RGBA {
+	factor := 1 - math.Max(0, math.Min(1, amount))
+	out := cloneRGBA(img)
+	for i := 0; i < len(out.Pix); i += 4 {
+		for c := 0; c < 3; c++ {
+			out.Pix[i+c] = uint8(float64(out.Pix[i+c])*factor + 0.5)
+		}
+	}
+	return out
+}
+
+// Tint overlays the color r, g, b on img with the given opacity
+func Tint(img *image.RGBA, r, g, b uint8, opacity float64) *image.RGBA {
+	opacity = math.Max(0, math.Min(1, opacity))
+	overlay := [3]float64{float64(r), float64(g), float64(b)}
+	out := cloneRGBA(img)
+	for i := 0; i < len(out.Pix); i += 4 {
+		for c := 0; c < 3; c++ {
+			out.Pix[i+c] = uint8(float64(out.Pix[i+c])*(1-opacity) + overlay[c]*opacity + 0.5)
+		}
+	}
+	return out
+}
+
+// CoverCrop scales img to cover width x height and crops the center, as a
+// wallpaper in fill mode is shown
+func CoverCrop(img image.Image, width, height int) *image.RGBA {
+	bounds := img.Bounds()
+	scale := math.Max(float64(width)/float64(bounds.Dx()), float64(height)/float64(bounds.Dy()))
+	cropWidth := min(bounds.Dx(), int(math.Round(float64(width)/scale)))
+	cropHeight := min(bounds.Dy(), int(math.Round(float64(height)/scale)))
+
+	x := bounds.Min.X + (bounds.Dx()-cropWidth)/2
+	y := bounds.Min.Y + (bounds.Dy()-cropHeight)/2
+	crop := image.Rect(x, y, x+cropWidth, y+cropHeight)
+
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(out, out.Bounds(), img, crop, draw.Src, nil)
+	return out
+}
+
+// fit scales img down so its longer side is at most size
+func fit(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	longer := max(bounds.Dx(), bounds.Dy())
+	if longer <= size {
+		return toRGBA(img)
+	}
+
+	width := max(1, bounds.Dx()*size/longer)
+	height := max(1, bounds.Dy()*size/longer)
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.ApproxBiLinear.Scale(out, out.Bounds(), img, bounds, draw.Src, nil)
+	return out
+}
+
+// toRGBA returns img as an RGBA image starting at the origin
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) && rgba.Stride == 4*rgba.Rect.Dx() {
+		return rgba
+	}
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(out, out.Bounds(), img, bounds.Min, draw.Src)
+	return out
+}
+
+// cloneRGBA returns a copy of img
+func cloneRGBA(img *image.RGBA) *image.RGBA {
+	out := toRGBA(img)
+	if out == img {
+		out = &image.RGBA{Pix: append([]uint8{}, img.Pix...), Stride: img.Stride, Rect: img.Rect}
+	}
+	return out
+}
+
+// writeJPEG encodes img and writes it atomically
+func writeJPEG(path string, img image.Image) error {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: derivedQuality}); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := paths.AtomicWrite(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// PruneDerived removes derived images unused for longer than maxAge, except
+// those the current links point to. A maxAge of 0 removes every image not
+// linked.
+func PruneDerived(dir string, maxAge time.Duration) (removed int, freed int64, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, fmt.Errorf("failed to read derived images: %w", err)
+	}
+
+	linked := make(map[string]bool)
+	links, _ := filepath.Glob(DerivedLinkPath("*"))
+	for _, link := range links {
+		if target, err := os.Readlink(link); err == nil {
+			linked[target] = true
+		}
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || e.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if linked[path] || (maxAge > 0 && info.ModTime().After(cutoff)) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, freed, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		removed++
+		freed += info.Size()
+	}
+	return removed, freed, nil
+}
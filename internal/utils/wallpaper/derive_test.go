@@ -0,0 +1,146 @@
+package wallpaper
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/imageio"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+)
+
+func TestGaussianBlur(t *testing.T) {
+	// A white line on black spreads out but keeps its brightness centered
+	img := image.NewRGBA(image.Rect(0, 0, 41, 5))
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 41; x++ {
+			v := uint8(0)
+			if x == 20 {
+				v = 255
+			}
+			img.SetRGBA(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+
+	blurred := GaussianBlur(img, 3)
+	center, near, far := blurred.RGBAAt(20, 2).R, blurred.RGBAAt(17, 2).R, blurred.RGBAAt(5, 2).R
+	if !(center > near && near > far) || center == 255 || far != 0 {
+		t.Errorf("blur profile center=%d near=%d far=%d", center, near, far)
+	}
+	if img.RGBAAt(20, 2).R != 255 {
+		t.Error("GaussianBlur modified its input")
+	}
+}
+
+func TestDimAndTint(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.SetRGBA(0, 0, color.RGBA{200, 100, 50, 255})
+
+	if got := Dim(img, 0.5).RGBAAt(0, 0); got != (color.RGBA{100, 50, 25, 255}) {
+		t.Errorf("Dim() = %v", got)
+	}
+	if got := Tint(img, 0, 0, 250, 0.2).RGBAAt(0, 0); got != (color.RGBA{160, 80, 90, 255}) {
+		t.Errorf("Tint() = %v", got)
+	}
+}
+
+func TestCoverCrop(t *testing.T) {
+	// A wide image keeps its center columns on a portrait monitor
+	img := image.NewRGBA(image.Rect(0, 0, 300, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 300; x++ {
+			c := color.RGBA{255, 0, 0, 255}
+			if x >= 100 && x < 200 {
+				c = color.RGBA{0, 0, 255, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	out := CoverCrop(img, 50, 100)
+	if out.Bounds().Dx() != 50 || out.Bounds().Dy() != 100 {
+		t.Fatalf("CoverCrop() size = %v", out.Bounds())
+	}
+	if got := out.RGBAAt(25, 50); got.B < 200 || got.R > 50 {
+		t.Errorf("center pixel = %v, want the blue middle", got)
+	}
+}
+
+func TestDerive(t *testing.T) {
+	dir := t.TempDir()
+	oldLink := paths.WallpaperLinkPath
+	paths.WallpaperLinkPath = filepath.Join(dir, "state", "current")
+	defer func() { paths.WallpaperLinkPath = oldLink }()
+
+	source := filepath.Join(dir, "wall.png")
+	writeSolidPNG(t, source, 64, 32, color.RGBA{200, 200, 200, 255})
+	cacheDir := filepath.Join(dir, "derived")
+
+	opts := DeriveOptions{
+		Variants:   []string{DeriveBlur, DeriveDim, DeriveTint, DeriveMonitors},
+		BlurRadius: 4,
+		DimAmount:  0.5,
+		TintAmount: 0.5,
+		Mode:       "dark",
+		Surface:    "#000000",
+		Monitors:   []MonitorSize{{Name: "DP-1", Width: 32, Height: 32}},
+	}
+	derived, err := Derive(source, cacheDir, opts)
+	if err != nil {
+		t.Fatalf("Derive() error = %v", err)
+	}
+	if len(derived) != 4 {
+		t.Fatalf("Derive() produced %d images, want 4", len(derived))
+	}
+
+	for _, d := range derived {
+		target, err := os.Readlink(d.Link)
+		if err != nil || target != d.Path {
+			t.Errorf("%s link points to %q (%v), want %s", d.Name, target, err, d.Path)
+		}
+	}
+
+	dimmed, err := imageio.Decode(DerivedLinkPath(DeriveDim))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, _, _ := dimmed.At(0, 0).RGBA(); r>>8 < 90 || r>>8 > 110 {
+		t.Errorf("dimmed pixel = %d, want about 100", r>>8)
+	}
+	crop, err := imageio.DecodeConfig(DerivedLinkPath("DP-1"))
+	if err != nil || crop.Width != 32 || crop.Height != 32 {
+		t.Errorf("monitor crop is %dx%d (%v)", crop.Width, crop.Height, err)
+	}
+
+	// Light mode dims half as much, in a separate file
+	opts.Variants = []string{DeriveDim}
+	opts.Mode = "light"
+	light, err := Derive(source, cacheDir, opts)
+	if err != nil {
+		t.Fatalf("Derive() error = %v", err)
+	}
+	if light[0].Path == derived[1].Path {
+		t.Error("light mode reused the dark mode image")
+	}
+
+	// Pruning keeps linked images only
+	old := time.Now().Add(-48 * time.Hour)
+	files, _ := filepath.Glob(filepath.Join(cacheDir, "*"))
+	for _, file := range files {
+		os.Chtimes(file, old, old)
+	}
+	removed, _, err := PruneDerived(cacheDir, time.Hour)
+	if err != nil {
+		t.Fatalf("PruneDerived() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("PruneDerived() removed %d images, want the unlinked dark dim only", removed)
+	}
+
+	if _, err := Derive(source, cacheDir, DeriveOptions{Variants: []string{DeriveTint}}); err == nil {
+		t.Error("expected an error for a tint without a surface color")
+	}
+}
@@ -0,0 +1,383 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/imageio"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/solar"
+)
+
+// DynamicManifestName is the manifest of a dynamic wallpaper directory
+const DynamicManifestName = "dynamic.json"
+
+// Sun phases of elevation keyframes
+const (
+	PhaseRise = "rise" // Morning, with the sun in the east
+	PhaseSet  = "set"  // Afternoon and evening, with the sun in the west
+)
+
+// Keyframe kinds of a dynamic wallpaper
+const (
+	KeyframeTime = "time" // Time of day
+	KeyframeSun  = "sun"  // Sun elevation
+)
+
+// DynamicFrame is an image of a dynamic wallpaper and the keyframe it
+// starts at
+type DynamicFrame struct {
+	Path      string   `json:"path"`
+	Time      string   `json:"time,omitempty"`      // Time of day (HH:MM)
+	Elevation *float64 `json:"elevation,omitempty"` // Sun elevation in degrees
+	Phase     string   `json:"phase,omitempty"`     // rise or set; empty matches both
+	Mode      string   `json:"mode,omitempty"`      // Scheme mode, detected from the image when empty
+
+	minute int // Minutes after midnight of Time
+}
+
+// DynamicWallpaper is a set of images shown through the day, keyed by time
+// of day or sun elevation
+type DynamicWallpaper struct {
+	Name   string         `json:"name"`
+	Dir    string         `json:"dir"`
+	Kind   string         `json:"kind"`
+	Frames []DynamicFrame `json:"frames"`
+}
+
+// dynamicManifest is the dynamic.json file: frames with a time or a sun
+// elevation. Frames may also use the field names of HEIC metadata exports
+// (fileName, altitude, azimuth, isForLight, isForDark), and the manifest
+// may be the bare list of frames.
+type dynamicManifest struct {
+	Name   string          `json:"name"`
+	Frames []manifestFrame `json:"frames"`
+}
+
+type manifestFrame struct {
+	File       string   `json:"file"`
+	FileName   string   `json:"fileName"`
+	Time       string   `json:"time"`
+	Elevation  *float64 `json:"elevation"`
+	Altitude   *float64 `json:"altitude"`
+	Azimuth    *float64 `json:"azimuth"`
+	Phase      string   `json:"phase"`
+	Mode       string   `json:"mode"`
+	IsForLight bool     `json:"isForLight"`
+	IsForDark  bool     `json:"isForDark"`
+}
+
+// DynamicSocketPath returns the control socket of the dynamic wallpaper
+// daemon
+func DynamicSocketPath() string {
+	return filepath.Join(filepath.Dir(SlideshowSocketPath()), "heimdall-dynamic.sock")
+}
+
+// LoadDynamicWallpaper reads a dynamic wallpaper from a manifest, or from a
+// directory with a dynamic.json manifest. A directory without a manifest
+// spreads its images evenly over the day in name order, the first at
+// midnight.
+func LoadDynamicWallpaper(path string) (*DynamicWallpaper, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("dynamic wallpaper not found: %w", err)
+	}
+
+	manifest := path
+	if info.IsDir() {
+		manifest = filepath.Join(path, DynamicManifestName)
+		if !paths.Exists(manifest) {
+			return evenlySpaced(path)
+		}
+	} else if !strings.EqualFold(filepath.Ext(path), ".json") {
+		return nil, fmt.Errorf("%s is not a dynamic wallpaper manifest; export the frames and metadata of HEIC dynamic wallpapers into a directory first", path)
+	}
+
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dynamic wallpaper manifest: %w", err)
+	}
+	return ParseDynamicManifest(data, filepath.Dir(manifest))
+}
+
+// ParseDynamicManifest parses a manifest whose frame files are relative to
+// dir
+func ParseDynamicManifest(data []byte, dir string) (*DynamicWallpaper, error) {
+	var manifest dynamicManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		if err := json.Unmarshal(data, &manifest.Frames); err != nil {
+			return nil, fmt.Errorf("failed to parse dynamic wallpaper manifest: %w", err)
+		}
+	}
+	if len(manifest.Frames) == 0 {
+		return nil, fmt.Errorf("dynamic wallpaper manifest has no frames")
+	}
+
+	dw := &DynamicWallpaper{Name: manifest.Name, Dir: dir}
+	if dw.Name == "" {
+		dw.Name = filepath.Base(dir)
+	}
+
+	for i, mf := range manifest.Frames {
+		frame, err := mf.frame(dir)
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: %w", i+1, err)
+		}
+
+		kind := KeyframeTime
+		if frame.Elevation != nil {
+			kind = KeyframeSun
+		}
+		if dw.Kind != "" && dw.Kind != kind {
+			return nil, fmt.Errorf("frame %d: frames must all use a time or all use a sun elevation", i+1)
+		}
+		dw.Kind = kind
+		dw.Frames = append(dw.Frames, frame)
+	}
+
+	if dw.Kind == KeyframeTime {
+		sort.SliceStable(dw.Frames, func(i, j int) bool { return dw.Frames[i].minute < dw.Frames[j].minute })
+	}
+	return dw, nil
+}
+
+// frame validates a manifest frame
+func (mf manifestFrame) frame(dir string) (DynamicFrame, error) {
+	file := mf.File
+	if file == "" {
+		file = mf.FileName
+	}
+	if file == "" {
+		return DynamicFrame{}, fmt.Errorf("file is required")
+	}
+	if strings.HasPrefix(file, "~/") {
+		home, _ := os.UserHomeDir()
+		file = filepath.Join(home, file[2:])
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	if !paths.Exists(file) {
+		return DynamicFrame{}, fmt.Errorf("image not found: %s", file)
+	}
+
+	frame := DynamicFrame{Path: file, Phase: mf.Phase, Mode: mf.Mode}
+	switch {
+	case mf.Mode != "":
+	case mf.IsForDark && !mf.IsForLight:
+		frame.Mode = "dark"
+	case mf.IsForLight && !mf.IsForDark:
+		frame.Mode = "light"
+	}
+	if frame.Mode != "" && frame.Mode != "light" && frame.Mode != "dark" {
+		return DynamicFrame{}, fmt.Errorf("mode must be light or dark, not %q", frame.Mode)
+	}
+
+	frame.Elevation = mf.Elevation
+	if frame.Elevation == nil {
+		frame.Elevation = mf.Altitude
+	}
+	switch {
+	case frame.Elevation != nil && mf.Time != "":
+		return DynamicFrame{}, fmt.Errorf("frame has both a time and a sun elevation")
+	case frame.Elevation != nil:
+		if *frame.Elevation < -90 || *frame.Elevation > 90 {
+			return DynamicFrame{}, fmt.Errorf("elevation %.1f out of range (-90 to 90)", *frame.Elevation)
+		}
+		if frame.Phase == "" && mf.Azimuth != nil {
+			frame.Phase = framePhase(*mf.Azimuth)
+		}
+		if frame.Phase != "" && frame.Phase != PhaseRise && frame.Phase != PhaseSet {
+			return DynamicFrame{}, fmt.Errorf("phase must be rise or set, not %q", frame.Phase)
+		}
+	case mf.Time != "":
+		minute, err := parseTimeOfDay(mf.Time)
+		if err != nil {
+			return DynamicFrame{}, err
+		}
+		frame.minute = minute
+		frame.Time = fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+	default:
+		return DynamicFrame{}, fmt.Errorf("frame needs a time or a sun elevation")
+	}
+	return frame, nil
+}
+
+// evenlySpaced creates a time keyed wallpaper from the images of dir
+func evenlySpaced(dir string) (*DynamicWallpaper, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dynamic wallpaper directory: %w", err)
+	}
+
+	var images []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() && imageio.Supports(path) {
+			images = append(images, path)
+		}
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no images or %s in %s", DynamicManifestName, dir)
+	}
+	sort.Strings(images)
+
+	dw := &DynamicWallpaper{Name: filepath.Base(dir), Dir: dir, Kind: KeyframeTime}
+	for i, image := range images {
+		minute := i * 24 * 60 / len(images)
+		dw.Frames = append(dw.Frames, DynamicFrame{
+			Path:   image,
+			Time:   fmt.Sprintf("%02d:%02d", minute/60, minute%60),
+			minute: minute,
+		})
+	}
+	return dw, nil
+}
+
+// parseTimeOfDay parses HH:MM, or the clock of an RFC 3339 time as written
+// by HEIC metadata exports, into minutes after midnight
+func parseTimeOfDay(value string) (int, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Hour()*60 + t.Minute(), nil
+	}
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// meridianBand is how far from the meridian, in degrees of azimuth, a
+// frame is taken as midday or midnight rather than morning or evening
+const meridianBand = 20
+
+// phaseOf returns the phase of the sun at an azimuth: east of the meridian
+// in the morning
+func phaseOf(azimuth float64) string {
+	if math.Mod(azimuth+360, 360) < 180 {
+		return PhaseRise
+	}
+	return PhaseSet
+}
+
+// framePhase returns the phase of a frame at an azimuth, or none for a
+// frame near the meridian, which fits the sun of either phase
+func framePhase(azimuth float64) string {
+	azimuth = math.Mod(azimuth+360, 360)
+	if math.Abs(azimuth-180) < meridianBand || azimuth < meridianBand || azimuth > 360-meridianBand {
+		return ""
+	}
+	return phaseOf(azimuth)
+}
+
+// FrameAt returns the index of the frame shown at t. Time keyframes are
+// shown from their time until the next one. Sun keyframes are shown while
+// their elevation is the closest to the sun's, among the frames of the
+// current phase; see sunElevation.
+func (dw *DynamicWallpaper) FrameAt(t time.Time, latitude, longitude float64) int {
+	if dw.Kind == KeyframeSun {
+		return dw.sunFrame(t, latitude, longitude)
+	}
+
+	// Before the first keyframe the last one of the previous day is shown
+	minute := t.Hour()*60 + t.Minute()
+	current := len(dw.Frames) - 1
+	for i, frame := range dw.Frames {
+		if frame.minute <= minute {
+			current = i
+		}
+	}
+	return current
+}
+
+// sunFrame returns the frame closest to the sun position at t
+func (dw *DynamicWallpaper) sunFrame(t time.Time, latitude, longitude float64) int {
+	elevation, azimuth := dw.sunElevation(t, latitude, longitude)
+	phase := phaseOf(azimuth)
+
+	best, bestAny := -1, 0
+	for i, frame := range dw.Frames {
+		distance := math.Abs(*frame.Elevation - elevation)
+		if distance < math.Abs(*dw.Frames[bestAny].Elevation-elevation) {
+			bestAny = i
+		}
+		if frame.Phase != "" && frame.Phase != phase {
+			continue
+		}
+		if best < 0 || distance < math.Abs(*dw.Frames[best].Elevation-elevation) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return bestAny
+	}
+	return best
+}
+
+// sunElevation returns the sun elevation at t scaled from the day's range
+// to the range of the frames, and the sun's azimuth. The horizon stays in
+// place, so sunrise and sunset frames show at sunrise and sunset, while the
+// highest frame shows at noon and the lowest at midnight in every season.
+func (dw *DynamicWallpaper) sunElevation(t time.Time, latitude, longitude float64) (float64, float64) {
+	elevation, azimuth := solar.Position(t, latitude, longitude)
+	lowest, highest := solar.ElevationRange(t, latitude)
+
+	frameLowest, frameHighest := 0.0, 0.0
+	for _, frame := range dw.Frames {
+		frameLowest = math.Min(frameLowest, *frame.Elevation)
+		frameHighest = math.Max(frameHighest, *frame.Elevation)
+	}
+
+	switch {
+	case elevation > 0 && highest > 0 && frameHighest > 0:
+		elevation = elevation / highest * frameHighest
+	case elevation < 0 && lowest < 0 && frameLowest < 0:
+		elevation = elevation / lowest * frameLowest
+	}
+	return elevation, azimuth
+}
+
+// NextChange returns when the frame shown at t changes, searching a day
+// ahead by the minute. It returns the zero time when a single frame is
+// shown all day.
+func (dw *DynamicWallpaper) NextChange(t time.Time, latitude, longitude float64) time.Time {
+	current := dw.FrameAt(t, latitude, longitude)
+	next := t.Truncate(time.Minute)
+	for i := 0; i < 24*60; i++ {
+		next = next.Add(time.Minute)
+		if dw.FrameAt(next, latitude, longitude) != current {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// ScheduledFrame is a frame and when it is shown
+type ScheduledFrame struct {
+	Frame int       `json:"frame"`
+	At    time.Time `json:"at"`
+}
+
+// Schedule returns when each frame is first shown on the day of t, as an
+// index into Frames, in order of time. Sun keyframes that are skipped on
+// that day, such as a high noon frame in winter, are left out.
+func (dw *DynamicWallpaper) Schedule(t time.Time, latitude, longitude float64) []ScheduledFrame {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	var schedule []ScheduledFrame
+	previous := -1
+	for at := day; at.Before(day.AddDate(0, 0, 1)); at = at.Add(time.Minute) {
+		frame := dw.FrameAt(at, latitude, longitude)
+		if frame != previous {
+			schedule = append(schedule, ScheduledFrame{Frame: frame, At: at})
+			previous = frame
+		}
+	}
+	return schedule
+}
@@ -0,0 +1,165 @@
+package wallpaper
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// dynamicDir creates a directory with the named frames
+func dynamicDir(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for i, name := range names {
+		writeSolidPNG(t, filepath.Join(dir, name), 8, 8, color.RGBA{uint8(i * 40), 0, 0, 255})
+	}
+	return dir
+}
+
+func TestDynamicTimeFrames(t *testing.T) {
+	dir := dynamicDir(t, "dawn.png", "day.png", "night.png")
+	manifest := `{"name": "Valley", "frames": [
+		{"file": "day.png", "time": "09:00", "mode": "light"},
+		{"file": "night.png", "time": "20:30", "mode": "dark"},
+		{"file": "dawn.png", "time": "2019-06-01T06:00:00Z"}
+	]}`
+
+	dw, err := ParseDynamicManifest([]byte(manifest), dir)
+	if err != nil {
+		t.Fatalf("ParseDynamicManifest() error = %v", err)
+	}
+	if dw.Name != "Valley" || dw.Kind != KeyframeTime || len(dw.Frames) != 3 {
+		t.Fatalf("parsed %+v", dw)
+	}
+	if dw.Frames[0].Time != "06:00" || filepath.Base(dw.Frames[0].Path) != "dawn.png" {
+		t.Errorf("frames are not sorted by time: %+v", dw.Frames)
+	}
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 1, hour, minute, 0, 0, time.Local)
+	}
+	tests := []struct {
+		hour, minute int
+		want         string
+	}{
+		{3, 0, "night.png"}, // Before the first keyframe
+		{6, 0, "dawn.png"},
+		{8, 59, "dawn.png"},
+		{12, 0, "day.png"},
+		{20, 30, "night.png"},
+	}
+	for _, tt := range tests {
+		if got := filepath.Base(dw.Frames[dw.FrameAt(at(tt.hour, tt.minute), 0, 0)].Path); got != tt.want {
+			t.Errorf("FrameAt(%02d:%02d) = %s, want %s", tt.hour, tt.minute, got, tt.want)
+		}
+	}
+
+	if next := dw.NextChange(at(12, 0), 0, 0); !next.Equal(at(20, 30)) {
+		t.Errorf("NextChange(12:00) = %s, want 20:30", next.Format("15:04"))
+	}
+	if schedule := dw.Schedule(at(12, 0), 0, 0); len(schedule) != 4 || schedule[1].Frame != 0 || !schedule[1].At.Equal(at(6, 0)) {
+		t.Errorf("Schedule() = %+v", schedule)
+	}
+}
+
+func TestDynamicSunFrames(t *testing.T) {
+	dir := dynamicDir(t, "1.png", "2.png", "3.png", "4.png")
+	// A HEIC metadata export: altitude and azimuth instead of elevation and phase
+	manifest := `[
+		{"fileName": "1.png", "altitude": -30, "azimuth": 0, "isForDark": true},
+		{"fileName": "2.png", "altitude": 5, "azimuth": 90},
+		{"fileName": "3.png", "altitude": 55, "azimuth": 180, "isForLight": true},
+		{"fileName": "4.png", "altitude": 5, "azimuth": 270}
+	]`
+
+	dw, err := ParseDynamicManifest([]byte(manifest), dir)
+	if err != nil {
+		t.Fatalf("ParseDynamicManifest() error = %v", err)
+	}
+	if dw.Kind != KeyframeSun || dw.Frames[0].Mode != "dark" || dw.Frames[2].Mode != "light" {
+		t.Fatalf("parsed %+v", dw.Frames)
+	}
+	if dw.Frames[1].Phase != PhaseRise || dw.Frames[3].Phase != PhaseSet {
+		t.Errorf("phases = %q and %q, want rise and set", dw.Frames[1].Phase, dw.Frames[3].Phase)
+	}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone not available: %v", err)
+	}
+	latitude, longitude := 52.52, 13.405
+	at := func(hour int) time.Time { return time.Date(2024, 6, 21, hour, 0, 0, 0, berlin) }
+
+	tests := []struct {
+		hour int
+		want string
+	}{
+		{1, "1.png"},  // Night
+		{6, "2.png"},  // Morning, low sun in the east
+		{13, "3.png"}, // Noon
+		{21, "4.png"}, // Evening, low sun in the west
+	}
+	for _, tt := range tests {
+		if got := filepath.Base(dw.Frames[dw.FrameAt(at(tt.hour), latitude, longitude)].Path); got != tt.want {
+			t.Errorf("FrameAt(%02d:00) = %s, want %s", tt.hour, got, tt.want)
+		}
+	}
+
+	// In winter the sun stays low, yet noon still shows the highest frame
+	if got := dw.FrameAt(time.Date(2024, 12, 21, 12, 30, 0, 0, berlin), latitude, longitude); got != 2 {
+		t.Errorf("winter noon frame = %d, want 2", got)
+	}
+
+	schedule := dw.Schedule(at(12), latitude, longitude)
+	if len(schedule) != 5 || schedule[0].Frame != 0 || schedule[4].Frame != 0 {
+		t.Errorf("Schedule() = %+v, want night, morning, noon, evening and night", schedule)
+	}
+}
+
+func TestDynamicManifestErrors(t *testing.T) {
+	dir := dynamicDir(t, "a.png", "b.png")
+
+	tests := map[string]string{
+		"no frames":     `{"frames": []}`,
+		"missing image": `{"frames": [{"file": "missing.png", "time": "10:00"}]}`,
+		"no keyframe":   `{"frames": [{"file": "a.png"}]}`,
+		"mixed kinds":   `{"frames": [{"file": "a.png", "time": "10:00"}, {"file": "b.png", "elevation": 10}]}`,
+		"bad time":      `{"frames": [{"file": "a.png", "time": "25:00"}]}`,
+		"bad phase":     `{"frames": [{"file": "a.png", "elevation": 10, "phase": "noon"}]}`,
+		"bad mode":      `{"frames": [{"file": "a.png", "time": "10:00", "mode": "dim"}]}`,
+	}
+	for name, manifest := range tests {
+		if _, err := ParseDynamicManifest([]byte(manifest), dir); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadDynamicWallpaperDirectory(t *testing.T) {
+	dir := dynamicDir(t, "1.png", "2.png", "3.png", "4.png")
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not an image"), 0644)
+
+	// Without a manifest the frames are spread over the day
+	dw, err := LoadDynamicWallpaper(dir)
+	if err != nil {
+		t.Fatalf("LoadDynamicWallpaper() error = %v", err)
+	}
+	if len(dw.Frames) != 4 || dw.Frames[1].Time != "06:00" || dw.Frames[3].Time != "18:00" {
+		t.Errorf("frames = %+v", dw.Frames)
+	}
+
+	manifest := `{"frames": [{"file": "2.png", "time": "07:00"}, {"file": "3.png", "time": "19:00"}]}`
+	if err := os.WriteFile(filepath.Join(dir, DynamicManifestName), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dw, err = LoadDynamicWallpaper(dir)
+	if err != nil || len(dw.Frames) != 2 || dw.Frames[0].Path != filepath.Join(dir, "2.png") {
+		t.Errorf("LoadDynamicWallpaper() = %+v, %v", dw, err)
+	}
+
+	if _, err := LoadDynamicWallpaper(filepath.Join(dir, "1.png")); err == nil {
+		t.Error("expected an error for an image instead of a manifest")
+	}
+}
@@ -0,0 +1,150 @@
+package wallpaper
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// feedProvider reads images from an RSS or Atom feed
+type feedProvider struct {
+	opts ProviderOptions
+}
+
+// feedDocument holds the items of RSS 2.0, RSS 1.0 (RDF) and Atom feeds
+type feedDocument struct {
+	Channel struct {
+		Items []feedItem `xml:"item"`
+	} `xml:"channel"`
+	Items   []feedItem `xml:"item"`  // RSS 1.0
+	Entries []feedItem `xml:"entry"` // Atom
+}
+
+// feedItem is an RSS item or Atom entry
+type feedItem struct {
+	Title      string          `xml:"title"`
+	Links      []feedLink      `xml:"link"`
+	Enclosures []feedEnclosure `xml:"enclosure"`
+	Media      []feedMedia     `xml:"http://search.yahoo.com/mrss/ content"`
+	Groups     []struct {
+		Media []feedMedia `xml:"http://search.yahoo.com/mrss/ content"`
+	} `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+// feedLink is an RSS link (text) or an Atom link (attributes)
+type feedLink struct {
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+	Href string `xml:"href,attr"`
+	Text string `xml:",chardata"`
+}
+
+// feedEnclosure is an RSS enclosure
+type feedEnclosure struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// feedMedia is a Media RSS content element
+type feedMedia struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Medium string `xml:"medium,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+func (p *feedProvider) Name() string { return ProviderFeed }
+
+// Search returns the largest image of each feed item whose title matches
+// the search terms, in feed order
+func (p *feedProvider) Search(ctx context.Context, q FetchQuery) ([]RemoteWallpaper, error) {
+	body, err := httpGet(ctx, p.opts.Client, p.opts.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return parseFeed(body, p.opts.URL, q)
+}
+
+func (p *feedProvider) Open(ctx context.Context, w RemoteWallpaper) (io.ReadCloser, error) {
+	return httpGet(ctx, p.opts.Client, w.URL, nil)
+}
+
+// parseFeed reads the image of each item of a feed. Relative image URLs are
+// resolved against base.
+func parseFeed(r io.Reader, base string, q FetchQuery) ([]RemoteWallpaper, error) {
+	var doc feedDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+	baseURL, _ := url.Parse(base)
+
+	items := append(append(doc.Channel.Items, doc.Items...), doc.Entries...)
+	var wallpapers []RemoteWallpaper
+	for _, item := range items {
+		if !matchesTerms(item.Title, q.Terms) {
+			continue
+		}
+		w, ok := item.image()
+		if !ok {
+			continue
+		}
+		if baseURL != nil {
+			if ref, err := baseURL.Parse(w.URL); err == nil {
+				w.URL = ref.String()
+			}
+		}
+
+		w.Title = strings.TrimSpace(item.Title)
+		w.ID = path.Base(strings.SplitN(w.URL, "?", 2)[0])
+		wallpapers = append(wallpapers, w)
+	}
+	return wallpapers, nil
+}
+
+// image returns the largest image of the item, preferring Media RSS content
+// that states its size, then image enclosures and links
+func (item feedItem) image() (RemoteWallpaper, bool) {
+	media := item.Media
+	for _, group := range item.Groups {
+		media = append(media, group.Media...)
+	}
+
+	var best RemoteWallpaper
+	for _, m := range media {
+		if m.URL == "" || !(m.Medium == "image" || strings.HasPrefix(m.Type, "image/") || (m.Medium == "" && m.Type == "")) {
+			continue
+		}
+		if best.URL == "" || m.Width*m.Height > best.Width*best.Height {
+			best = RemoteWallpaper{URL: m.URL, Width: m.Width, Height: m.Height}
+		}
+	}
+	if best.URL != "" {
+		return best, true
+	}
+
+	for _, enclosure := range item.Enclosures {
+		if enclosure.URL != "" && strings.HasPrefix(enclosure.Type, "image/") {
+			return RemoteWallpaper{URL: enclosure.URL}, true
+		}
+	}
+	for _, link := range item.Links {
+		href := link.Href
+		if href == "" {
+			href = strings.TrimSpace(link.Text)
+		}
+		if href == "" {
+			continue
+		}
+		if (link.Rel == "enclosure" && strings.HasPrefix(link.Type, "image/")) || imageExtension(href, nil) != "" {
+			return RemoteWallpaper{URL: href}, true
+		}
+	}
+	return RemoteWallpaper{}, false
+}
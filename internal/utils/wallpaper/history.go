@@ -0,0 +1,130 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+)
+
+// DefaultHistorySize is the number of wallpaper changes kept
+const DefaultHistorySize = 50
+
+// HistoryEntry is one wallpaper change
+type HistoryEntry struct {
+	Path    string    `json:"path"`
+	Monitor string    `json:"monitor,omitempty"` // Empty when set on all monitors
+	Scheme  string    `json:"scheme,omitempty"`  // Scheme generated from the wallpaper
+	Variant string    `json:"variant,omitempty"` // Variant of the generated scheme
+	Mode    string    `json:"mode,omitempty"`    // Mode of the generated scheme
+	SetAt   time.Time `json:"set_at"`
+}
+
+// History is the bounded list of wallpaper changes, newest first
+type History struct {
+	path    string
+	Entries []HistoryEntry `json:"entries"`
+}
+
+// DefaultHistoryPath returns the location of the wallpaper history
+func DefaultHistoryPath() string {
+	return filepath.Join(paths.HeimdallStateDir, "wallpaper", "history.json")
+}
+
+// LoadHistory reads the history, returning an empty history when the file
+// does not exist
+func LoadHistory(path string) (*History, error) {
+	history := &History{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, fmt.Errorf("failed to read wallpaper history: %w", err)
+	}
+
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("failed to parse wallpaper history: %w", err)
+	}
+	return history, nil
+}
+
+// Save writes the history atomically
+func (h *History) Save() error {
+	if h.Entries == nil {
+		h.Entries = []HistoryEntry{}
+	}
+	if err := paths.AtomicWriteJSON(h.path, h); err != nil {
+		return fmt.Errorf("failed to write wallpaper history: %w", err)
+	}
+	return nil
+}
+
+// Record adds entry as the newest change and keeps at most size entries.
+// A size of zero or less keeps DefaultHistorySize entries.
+func (h *History) Record(entry HistoryEntry, size int) {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	if entry.SetAt.IsZero() {
+		entry.SetAt = time.Now()
+	}
+
+	h.Entries = append([]HistoryEntry{entry}, h.Entries...)
+	if len(h.Entries) > size {
+		h.Entries = h.Entries[:size]
+	}
+}
+
+// Previous removes the newest change of monitor and returns the change
+// before it, which becomes the newest. Changes of wallpapers that no
+// longer exist or equal the current one are dropped on the way.
+func (h *History) Previous(monitor string) (HistoryEntry, error) {
+	current := -1
+	for i, entry := range h.Entries {
+		if entry.Monitor == monitor {
+			current = i
+			break
+		}
+	}
+	if current < 0 {
+		return HistoryEntry{}, fmt.Errorf("no wallpaper history")
+	}
+
+	drop := map[int]bool{current: true}
+	found := -1
+	for i := current + 1; i < len(h.Entries); i++ {
+		entry := h.Entries[i]
+		if entry.Monitor != monitor {
+			continue
+		}
+		if _, err := os.Stat(entry.Path); err != nil || entry.Path == h.Entries[current].Path {
+			drop[i] = true
+			continue
+		}
+		found = i
+		break
+	}
+	if found < 0 {
+		return HistoryEntry{}, fmt.Errorf("no previous wallpaper in history")
+	}
+
+	previous := h.Entries[found]
+	kept := h.Entries[:0]
+	for i, entry := range h.Entries {
+		if !drop[i] {
+			kept = append(kept, entry)
+		}
+	}
+	h.Entries = kept
+	return previous, nil
+}
+
+// Clear forgets all changes
+func (h *History) Clear() {
+	h.Entries = nil
+}
@@ -0,0 +1,101 @@
+package wallpaper
+
+import (
+	"image/color"
+	"path/filepath"
+	"testing"
+)
+
+func TestHistoryRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	history, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		history.Record(HistoryEntry{Path: "/w/" + name + ".png"}, 3)
+	}
+	if len(history.Entries) != 3 || history.Entries[0].Path != "/w/d.png" || history.Entries[2].Path != "/w/b.png" {
+		t.Fatalf("history = %+v, want d, c, b", history.Entries)
+	}
+	if history.Entries[0].SetAt.IsZero() {
+		t.Error("Record() did not set the time")
+	}
+
+	if err := history.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if len(loaded.Entries) != 3 {
+		t.Errorf("loaded %d entries, want 3", len(loaded.Entries))
+	}
+}
+
+func TestHistoryPrevious(t *testing.T) {
+	dir := t.TempDir()
+	file := func(name string) string {
+		path := filepath.Join(dir, name)
+		writeSolidPNG(t, path, 4, 4, color.RGBA{10, 20, 30, 255})
+		return path
+	}
+	a, b, c := file("a.png"), file("b.png"), file("c.png")
+
+	history := &History{}
+	history.Record(HistoryEntry{Path: a}, 10)
+	history.Record(HistoryEntry{Path: c, Monitor: "DP-1"}, 10)
+	history.Record(HistoryEntry{Path: filepath.Join(dir, "deleted.png")}, 10)
+	history.Record(HistoryEntry{Path: b}, 10)
+	history.Record(HistoryEntry{Path: b}, 10)
+
+	// Skips the repeated current wallpaper, the deleted one and other monitors
+	previous, err := history.Previous("")
+	if err != nil {
+		t.Fatalf("Previous() error = %v", err)
+	}
+	if previous.Path != a {
+		t.Errorf("Previous() = %s, want %s", previous.Path, a)
+	}
+	if len(history.Entries) != 2 || history.Entries[0].Path != c || history.Entries[1].Path != a {
+		t.Errorf("history after Previous() = %+v", history.Entries)
+	}
+
+	if _, err := history.Previous(""); err == nil {
+		t.Error("expected an error with no older wallpaper")
+	}
+	if _, err := history.Previous("HDMI-A-1"); err == nil {
+		t.Error("expected an error for a monitor without history")
+	}
+}
+
+func TestSetFavourite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wall.png")
+	writeSolidPNG(t, path, 16, 16, color.RGBA{200, 40, 40, 255})
+
+	index, err := LoadIndex(filepath.Join(dir, "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := index.SetFavourite(path, false); err == nil {
+		t.Error("expected an error removing a favourite that is not indexed")
+	}
+
+	// Favouriting indexes the wallpaper first
+	if err := index.SetFavourite(path, true); err != nil {
+		t.Fatalf("SetFavourite() error = %v", err)
+	}
+	if got := index.Query(IndexQuery{Tags: []string{FavouriteTag}}); len(got) != 1 || got[0].Width != 16 {
+		t.Fatalf("favourites = %v", got)
+	}
+
+	if err := index.SetFavourite(path, false); err != nil {
+		t.Fatalf("SetFavourite() error = %v", err)
+	}
+	if got := index.Query(IndexQuery{Tags: []string{FavouriteTag}}); len(got) != 0 {
+		t.Errorf("%d favourites left, want 0", len(got))
+	}
+}
@@ -0,0 +1,100 @@
+package wallpaper
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// hyprpaperBackend sets wallpapers through hyprpaper's IPC, preloading
+// new wallpapers and unloading the ones no monitor shows any more
+type hyprpaperBackend struct{}
+
+func (h *hyprpaperBackend) Name() string { return BackendHyprpaper }
+
+func (h *hyprpaperBackend) Available() bool { return processRunning("hyprpaper") }
+
+func (h *hyprpaperBackend) Set(state *MonitorState, monitor string) error {
+	path, ok := state.Wallpaper(monitor)
+	if !ok {
+		return fmt.Errorf("no wallpaper recorded for monitor %q", monitor)
+	}
+
+	loaded, err := h.loaded()
+	if err != nil {
+		return err
+	}
+	if !loaded[path] {
+		if err := hyprpaperCommand("preload", path); err != nil {
+			return err
+		}
+	}
+
+	if err := hyprpaperCommand("wallpaper", monitor+","+path); err != nil {
+		return err
+	}
+
+	// Unloading only frees memory, a failure leaves the wallpaper set
+	hyprpaperCommand("unload", "unused")
+	return nil
+}
+
+// Active returns the wallpaper of each monitor as reported by hyprpaper
+func (h *hyprpaperBackend) Active() (map[string]string, error) {
+	output, err := hyprpaperRequest("listactive")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active wallpapers: %w", err)
+	}
+	return parseListActive(output), nil
+}
+
+// loaded returns the preloaded wallpapers
+func (h *hyprpaperBackend) loaded() (map[string]bool, error) {
+	output, err := hyprpaperRequest("listloaded")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list loaded wallpapers: %w", err)
+	}
+
+	loaded := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			loaded[line] = true
+		}
+	}
+	return loaded, nil
+}
+
+// hyprpaperRequest sends a request to hyprpaper through hyprctl and
+// returns the reply. Query replies list wallpaper paths, so they are not
+// checked for errors.
+func hyprpaperRequest(args ...string) (string, error) {
+	output, err := exec.Command("hyprctl", append([]string{"hyprpaper"}, args...)...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("hyprpaper %s failed: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// hyprpaperCommand sends a preload, wallpaper or unload request. hyprctl
+// exits successfully even when hyprpaper rejects one, so the reply is
+// checked as well.
+func hyprpaperCommand(args ...string) error {
+	reply, err := hyprpaperRequest(args...)
+	if err != nil {
+		return err
+	}
+	return checkHyprpaperReply(args[0], reply)
+}
+
+// checkHyprpaperReply returns the error of a rejected request. hyprpaper
+// answers requests that change its state with "ok", and with the reason
+// otherwise.
+func checkHyprpaperReply(request, reply string) error {
+	if reply == "ok" {
+		return nil
+	}
+	if reply == "" {
+		reply = "no reply"
+	}
+	return fmt.Errorf("hyprpaper %s failed: %s", request, reply)
+}
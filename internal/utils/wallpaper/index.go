@@ -0,0 +1,473 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/color"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/imageio"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/material"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+)
+
+// indexVersion changes when the analysis stored in index entries changes,
+// so older entries are analyzed again. Each entry records the version it
+// was analyzed with, as an update only reaches the entries below its roots.
+const indexVersion = 2
+
+// indexColors is the number of dominant colors kept per wallpaper
+const indexColors = 5
+
+// IndexEntry is the metadata of one wallpaper in the library index
+type IndexEntry struct {
+	Path          string         `json:"path"`
+	Hash          string         `json:"hash"`
+	Size          int64          `json:"size"`
+	ModTime       time.Time      `json:"mod_time"`
+	Width         int            `json:"width"`
+	Height        int            `json:"height"`
+	Aspect        float64        `json:"aspect"`
+	Colourfulness float64        `json:"colourfulness"`
+	Mode          string         `json:"mode"`   // Scheme mode the wallpaper suits, as DetermineMode
+	Colors        []string       `json:"colors"` // Dominant colors, most frequent first
+	DHash         PerceptualHash `json:"dhash"`
+	PHash         PerceptualHash `json:"phash"`
+	Tags          []string       `json:"tags,omitempty"`
+	DuplicateOf   string         `json:"duplicate_of,omitempty"` // Copy kept by dedupe; excluded from queries
+	Source        string         `json:"source,omitempty"`       // URL the wallpaper was fetched from
+	Version       int            `json:"version,omitempty"`      // indexVersion of the analysis
+	IndexedAt     time.Time      `json:"indexed_at"`
+}
+
+// Index is the persistent wallpaper library index, keyed by path
+type Index struct {
+	path    string
+	Version int                    `json:"version"`
+	Entries map[string]*IndexEntry `json:"entries"`
+}
+
+// IndexStats reports what an index update did
+type IndexStats struct {
+	Total     int `json:"total"`
+	Analyzed  int `json:"analyzed"`
+	Unchanged int `json:"unchanged"`
+	Moved     int `json:"moved"`
+	Removed   int `json:"removed"`
+	Failed    int `json:"failed"`
+}
+
+// DefaultIndexPath returns the location of the wallpaper index. It lives
+// with the state rather than the cache because it holds user tags.
+func DefaultIndexPath() string {
+	return filepath.Join(paths.HeimdallStateDir, "wallpaper", "index.json")
+}
+
+// LoadIndex reads the index, returning an empty index when the file does
+// not exist
+func LoadIndex(path string) (*Index, error) {
+	index := &Index{path: path, Version: indexVersion, Entries: make(map[string]*IndexEntry)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return index, nil
+		}
+		return nil, fmt.Errorf("failed to read wallpaper index: %w", err)
+	}
+
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("failed to parse wallpaper index: %w", err)
+	}
+	if index.Entries == nil {
+		index.Entries = make(map[string]*IndexEntry)
+	}
+
+	// Entries written before they carried a version share the index's
+	if index.Version == indexVersion {
+		for _, entry := range index.Entries {
+			if entry.Version == 0 {
+				entry.Version = indexVersion
+			}
+		}
+	}
+	index.Version = indexVersion
+	return index, nil
+}
+
+// Save writes the index atomically
+func (idx *Index) Save() error {
+	if err := paths.AtomicWriteJSON(idx.path, idx); err != nil {
+		return fmt.Errorf("failed to write wallpaper index: %w", err)
+	}
+	return nil
+}
+
+// Update indexes the images with one of extensions below roots. Files whose
+// size and modification time are unchanged are skipped unless their
+// analysis is outdated, moved files keep their analysis and tags, and
+// entries of deleted files are removed. progress, when set, is called after
+// each analyzed file.
+func (idx *Index) Update(roots, extensions []string, progress func(path string, err error)) (*IndexStats, error) {
+	stats := &IndexStats{}
+
+	byHash := make(map[string]*IndexEntry)
+	for _, entry := range idx.Entries {
+		if entry.Version == indexVersion {
+			byHash[entry.Hash] = entry
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, root := range roots {
+		files, err := imageFiles(root, extensions)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			seen[file.path] = true
+
+			existing, ok := idx.Entries[file.path]
+			if ok && existing.Version == indexVersion && existing.Size == file.info.Size() && existing.ModTime.Equal(file.info.ModTime()) {
+				stats.Unchanged++
+				continue
+			}
+
+			hash, err := HashFile(file.path)
+			if err != nil {
+				stats.Failed++
+				if progress != nil {
+					progress(file.path, err)
+				}
+				continue
+			}
+
+			if previous, ok := byHash[hash]; ok {
+				entry := *previous
+				entry.Path = file.path
+				entry.Size = file.info.Size()
+				entry.ModTime = file.info.ModTime()
+				idx.Entries[file.path] = &entry
+				if previous.Path == file.path {
+					stats.Unchanged++
+				} else {
+					stats.Moved++
+				}
+				continue
+			}
+
+			entry, err := AnalyzeEntry(file.path)
+			if err == nil {
+				entry.Hash = hash
+				entry.Size = file.info.Size()
+				entry.ModTime = file.info.ModTime()
+				if existing != nil {
+					entry.Tags = existing.Tags
+					entry.Source = existing.Source
+				}
+				idx.Entries[file.path] = entry
+				byHash[hash] = entry
+				stats.Analyzed++
+			} else {
+				stats.Failed++
+			}
+			if progress != nil {
+				progress(file.path, err)
+			}
+		}
+	}
+
+	// Drop entries of files that are gone from the indexed roots
+	for path := range idx.Entries {
+		if seen[path] || !underAny(path, roots) {
+			continue
+		}
+		delete(idx.Entries, path)
+		stats.Removed++
+	}
+
+	// Duplicates of a removed wallpaper are the remaining copies
+	for _, entry := range idx.Entries {
+		if _, ok := idx.Entries[entry.DuplicateOf]; entry.DuplicateOf != "" && !ok {
+			entry.DuplicateOf = ""
+		}
+	}
+
+	stats.Total = len(idx.Entries)
+	return stats, nil
+}
+
+// AnalyzeEntry decodes a wallpaper once and computes its index metadata
+func AnalyzeEntry(path string) (*IndexEntry, error) {
+	config, err := imageio.DecodeConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	img, err := imageio.Decode(path)
+	if err != nil {
+		return nil, err
+	}
+	prepared := material.Prepare(img, material.DefaultPixelBudget).Image
+
+	entry := &IndexEntry{
+		Path:      path,
+		Width:     config.Width,
+		Height:    config.Height,
+		Version:   indexVersion,
+		IndexedAt: time.Now(),
+	}
+	if config.Height > 0 {
+		entry.Aspect = float64(config.Width) / float64(config.Height)
+	}
+
+	if entry.Colourfulness, err = colourfulness(prepared); err != nil {
+		return nil, fmt.Errorf("failed to analyze colourfulness: %w", err)
+	}
+	if entry.Mode, err = determineMode(prepared); err != nil {
+		return nil, fmt.Errorf("failed to determine mode: %w", err)
+	}
+	for _, argb := range dominantColors(prepared, indexColors) {
+		entry.Colors = append(entry.Colors, fmt.Sprintf("#%06X", argb&0xFFFFFF))
+	}
+	entry.DHash = DHash(prepared)
+	entry.PHash = PHash(prepared)
+	return entry, nil
+}
+
+// SetTags adds tags to, or with remove removes them from, the entry of path
+func (idx *Index) SetTags(path string, tags []string, remove bool) error {
+	entry, ok := idx.Entries[path]
+	if !ok {
+		return fmt.Errorf("wallpaper not indexed: %s", path)
+	}
+
+	set := make(map[string]bool)
+	for _, tag := range entry.Tags {
+		set[tag] = true
+	}
+	for _, tag := range tags {
+		set[strings.ToLower(strings.TrimSpace(tag))] = !remove
+	}
+
+	var kept []string
+	for tag, keep := range set {
+		if keep && tag != "" {
+			kept = append(kept, tag)
+		}
+	}
+	sort.Strings(kept)
+	entry.Tags = kept
+	return nil
+}
+
+// Add indexes a single wallpaper, keeping the tags of an existing entry
+func (idx *Index) Add(path string) (*IndexEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("wallpaper not found: %w", err)
+	}
+	hash, err := HashFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := AnalyzeEntry(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze %s: %w", path, err)
+	}
+	entry.Hash = hash
+	entry.Size = info.Size()
+	entry.ModTime = info.ModTime()
+	if existing, ok := idx.Entries[path]; ok {
+		entry.Tags = existing.Tags
+		entry.Source = existing.Source
+	}
+	idx.Entries[path] = entry
+	return entry, nil
+}
+
+// SetFavourite tags the wallpaper at path as a favourite, indexing it
+// first when needed, or with favourite unset removes the tag
+func (idx *Index) SetFavourite(path string, favourite bool) error {
+	if _, ok := idx.Entries[path]; !ok {
+		if !favourite {
+			return fmt.Errorf("wallpaper not indexed: %s", path)
+		}
+		if _, err := idx.Add(path); err != nil {
+			return err
+		}
+	}
+	return idx.SetTags(path, []string{FavouriteTag}, !favourite)
+}
+
+// IndexQuery selects wallpapers from the index. Zero fields match
+// everything.
+type IndexQuery struct {
+	Dir       string   // Only wallpapers below this directory
+	Mode      string   // Scheme mode the wallpaper suits, dark or light
+	MinWidth  int      // Minimum width in pixels
+	MinHeight int      // Minimum height in pixels
+	Hue       string   // One of HueNames present among the dominant colors
+	Tags      []string // Tags the wallpaper must all have
+
+	Duplicates bool // Include wallpapers dedupe excluded
+}
+
+// Empty reports whether the query only selects by directory
+func (q IndexQuery) Empty() bool {
+	return q.Mode == "" && q.MinWidth == 0 && q.MinHeight == 0 && q.Hue == "" && len(q.Tags) == 0
+}
+
+// Query returns the entries matching q, sorted by path
+func (idx *Index) Query(q IndexQuery) []*IndexEntry {
+	var matches []*IndexEntry
+	for _, entry := range idx.Entries {
+		if q.matches(entry) {
+			matches = append(matches, entry)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Path < matches[j].Path })
+	return matches
+}
+
+// Random returns a random entry matching q whose file still exists
+func (idx *Index) Random(q IndexQuery) (*IndexEntry, error) {
+	return PickRandom(idx.Query(q))
+}
+
+// ExistingEntries returns the entries whose files still exist
+func ExistingEntries(entries []*IndexEntry) []*IndexEntry {
+	var existing []*IndexEntry
+	for _, entry := range entries {
+		if _, err := os.Stat(entry.Path); err == nil {
+			existing = append(existing, entry)
+		}
+	}
+	return existing
+}
+
+// PickRandom returns a random entry whose file still exists
+func PickRandom(entries []*IndexEntry) (*IndexEntry, error) {
+	shuffled := append([]*IndexEntry{}, entries...)
+	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	for _, entry := range shuffled {
+		if _, err := os.Stat(entry.Path); err == nil {
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("no indexed wallpaper matches")
+}
+
+// matches reports whether entry satisfies the query
+func (q IndexQuery) matches(entry *IndexEntry) bool {
+	if q.Dir != "" && !underAny(entry.Path, []string{q.Dir}) {
+		return false
+	}
+	if entry.DuplicateOf != "" && !q.Duplicates {
+		return false
+	}
+	if q.Mode != "" && entry.Mode != q.Mode {
+		return false
+	}
+	if entry.Width < q.MinWidth || entry.Height < q.MinHeight {
+		return false
+	}
+	if q.Hue != "" && !hasHue(entry.Colors, q.Hue) {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !slices.Contains(entry.Tags, strings.ToLower(tag)) {
+			return false
+		}
+	}
+	return true
+}
+
+// hueRanges maps hue names to HSL hue ranges in degrees. Red wraps around
+// 0.
+var hueRanges = map[string][2]float64{
+	"red":    {345, 15},
+	"orange": {15, 45},
+	"yellow": {45, 70},
+	"green":  {70, 165},
+	"cyan":   {165, 200},
+	"blue":   {200, 255},
+	"purple": {255, 290},
+	"pink":   {290, 345},
+}
+
+// HueNames lists the hue names a query accepts
+var HueNames = []string{"red", "orange", "yellow", "green", "cyan", "blue", "purple", "pink"}
+
+// hasHue reports whether one of the colors is saturated enough to read as
+// the named hue
+func hasHue(colors []string, name string) bool {
+	r, ok := hueRanges[name]
+	if !ok {
+		return false
+	}
+
+	for _, hex := range colors {
+		c, err := color.NewFromHex(hex)
+		if err != nil {
+			continue
+		}
+		// Near grays, blacks and whites have no meaningful hue
+		if c.HSL.S < 25 || c.HSL.L < 10 || c.HSL.L > 90 {
+			continue
+		}
+		h := c.HSL.H
+		if r[0] > r[1] {
+			if h >= r[0] || h < r[1] {
+				return true
+			}
+		} else if h >= r[0] && h < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// indexedFile is an image found while walking a root
+type indexedFile struct {
+	path string
+	info os.FileInfo
+}
+
+// imageFiles returns the files with one of extensions below root
+func imageFiles(root string, extensions []string) ([]indexedFile, error) {
+	var files []indexedFile
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if info.IsDir() || !slices.Contains(extensions, strings.ToLower(filepath.Ext(path))) {
+			return nil
+		}
+		files = append(files, indexedFile{path: path, info: info})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan wallpaper directory: %w", err)
+	}
+	return files, nil
+}
+
+// underAny reports whether path is inside one of dirs
+func underAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package wallpaper
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeSolidPNG writes a w x h PNG filled with c
+func writeSolidPNG(t *testing.T, path string, w, h int, c color.RGBA) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIndexUpdateAndQuery(t *testing.T) {
+	dir := t.TempDir()
+	library := filepath.Join(dir, "walls")
+	if err := os.MkdirAll(filepath.Join(library, "nested"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	navy := filepath.Join(library, "navy.png")
+	sun := filepath.Join(library, "nested", "sun.png")
+	writeSolidPNG(t, navy, 64, 32, color.RGBA{20, 40, 140, 255})
+	writeSolidPNG(t, sun, 32, 32, color.RGBA{250, 220, 40, 255})
+	if err := os.WriteFile(filepath.Join(library, "notes.txt"), []byte("skip me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	indexPath := filepath.Join(dir, "index.json")
+	index, err := LoadIndex(indexPath)
+	if err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+
+	extensions := []string{".png"}
+	stats, err := index.Update([]string{library}, extensions, nil)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if stats.Analyzed != 2 || stats.Total != 2 {
+		t.Fatalf("Update() stats = %+v, want 2 analyzed", stats)
+	}
+
+	entry := index.Entries[navy]
+	if entry.Width != 64 || entry.Height != 32 || entry.Aspect != 2 {
+		t.Errorf("navy entry size = %dx%d aspect %v", entry.Width, entry.Height, entry.Aspect)
+	}
+	if entry.Mode != "light" || len(entry.Colors) == 0 || entry.Hash == "" {
+		t.Errorf("navy entry = %+v", entry)
+	}
+
+	tests := []struct {
+		name  string
+		query IndexQuery
+		want  []string
+	}{
+		{"everything", IndexQuery{}, []string{navy, sun}},
+		{"blue hue", IndexQuery{Hue: "blue"}, []string{navy}},
+		{"yellow hue", IndexQuery{Hue: "yellow"}, []string{sun}},
+		{"mode", IndexQuery{Mode: "dark"}, []string{sun}},
+		{"min width", IndexQuery{MinWidth: 48}, []string{navy}},
+		{"directory", IndexQuery{Dir: filepath.Join(library, "nested")}, []string{sun}},
+		{"no match", IndexQuery{Hue: "green"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, entry := range index.Query(tt.query) {
+				got = append(got, entry.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query(%+v) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	if err := index.SetTags(navy, []string{"Ocean", "calm"}, false); err != nil {
+		t.Fatalf("SetTags() error = %v", err)
+	}
+	if got := index.Query(IndexQuery{Tags: []string{"ocean"}}); len(got) != 1 || got[0].Path != navy {
+		t.Errorf("Query by tag = %v", got)
+	}
+	if err := index.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A second run only stats the files
+	index, err = LoadIndex(indexPath)
+	if err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+	stats, err = index.Update([]string{library}, extensions, nil)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if stats.Unchanged != 2 || stats.Analyzed != 0 {
+		t.Errorf("second Update() stats = %+v, want 2 unchanged", stats)
+	}
+
+	// Moved files keep their tags, deleted files are dropped
+	moved := filepath.Join(library, "nested", "deep-navy.png")
+	if err := os.Rename(navy, moved); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(sun); err != nil {
+		t.Fatal(err)
+	}
+	stats, err = index.Update([]string{library}, extensions, nil)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if stats.Moved != 1 || stats.Removed != 2 || stats.Total != 1 {
+		t.Errorf("Update() after move stats = %+v", stats)
+	}
+	if got := index.Entries[moved]; got == nil || !reflect.DeepEqual(got.Tags, []string{"calm", "ocean"}) {
+		t.Errorf("moved entry = %+v, want tags kept", got)
+	}
+
+	if err := index.SetTags(moved, []string{"calm"}, true); err != nil {
+		t.Fatalf("SetTags() remove error = %v", err)
+	}
+	if got := index.Entries[moved].Tags; !reflect.DeepEqual(got, []string{"ocean"}) {
+		t.Errorf("tags after removal = %v", got)
+	}
+	if err := index.SetTags(filepath.Join(library, "missing.png"), []string{"x"}, false); err == nil {
+		t.Error("expected an error tagging a wallpaper that is not indexed")
+	}
+}
+
+func TestIndexRandom(t *testing.T) {
+	dir := t.TempDir()
+	present := filepath.Join(dir, "present.png")
+	writeSolidPNG(t, present, 8, 8, color.RGBA{200, 30, 30, 255})
+
+	index := &Index{Entries: map[string]*IndexEntry{
+		present:                        {Path: present},
+		filepath.Join(dir, "gone.png"): {Path: filepath.Join(dir, "gone.png")},
+	}}
+
+	for i := 0; i < 10; i++ {
+		entry, err := index.Random(IndexQuery{})
+		if err != nil {
+			t.Fatalf("Random() error = %v", err)
+		}
+		if entry.Path != present {
+			t.Fatalf("Random() = %s, want only existing files", entry.Path)
+		}
+	}
+
+	if _, err := index.Random(IndexQuery{MinWidth: 100}); err == nil {
+		t.Error("expected an error when nothing matches")
+	}
+}
+
+func TestIndexUpdateOutdatedEntries(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first"), filepath.Join(dir, "second")
+	for _, root := range []string{first, second} {
+		if err := os.MkdirAll(root, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, b := filepath.Join(first, "a.png"), filepath.Join(second, "b.png")
+	writeSolidPNG(t, a, 16, 16, color.RGBA{20, 40, 140, 255})
+	writeSolidPNG(t, b, 16, 16, color.RGBA{250, 220, 40, 255})
+
+	index, _ := LoadIndex(filepath.Join(dir, "index.json"))
+	if _, err := index.Update([]string{first, second}, []string{".png"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries analyzed by an older version lack the newer analysis
+	for _, entry := range index.Entries {
+		entry.Version, entry.DHash, entry.PHash = indexVersion-1, 0, 0
+	}
+
+	stats, err := index.Update([]string{first}, []string{".png"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Analyzed != 1 || index.Entries[a].Version != indexVersion {
+		t.Errorf("Update(first) stats = %+v, want a.png analyzed again", stats)
+	}
+	if index.Entries[b].Version == indexVersion {
+		t.Error("Update(first) touched an entry of another root")
+	}
+
+	// The other root is analyzed again once it is indexed, even unchanged
+	if stats, _ := index.Update([]string{second}, []string{".png"}, nil); stats.Analyzed != 1 {
+		t.Errorf("Update(second) stats = %+v, want b.png analyzed again", stats)
+	}
+}
@@ -0,0 +1,69 @@
+package wallpaper
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/imageio"
+)
+
+// localProvider copies images from a directory, such as a mounted network
+// share or a synced folder
+type localProvider struct {
+	opts ProviderOptions
+}
+
+func (p *localProvider) Name() string { return ProviderLocal }
+
+// Search returns the images below the directory whose relative path
+// matches the search terms, by path or shuffled with the random order
+func (p *localProvider) Search(ctx context.Context, q FetchQuery) ([]RemoteWallpaper, error) {
+	root := p.opts.URL
+	if strings.HasPrefix(root, "~/") {
+		home, _ := os.UserHomeDir()
+		root = filepath.Join(home, root[2:])
+	}
+
+	extensions := p.opts.Extensions
+	if len(extensions) == 0 {
+		extensions = imageio.Extensions()
+	}
+	images, err := ListImages(root, extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	var wallpapers []RemoteWallpaper
+	for _, image := range images {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		rel, err := filepath.Rel(root, image)
+		if err != nil {
+			rel = filepath.Base(image)
+		}
+		if !matchesTerms(rel, q.Terms) {
+			continue
+		}
+
+		w := RemoteWallpaper{ID: rel, URL: image, Title: rel}
+		if config, err := imageio.DecodeConfig(image); err == nil {
+			w.Width, w.Height = config.Width, config.Height
+		}
+		wallpapers = append(wallpapers, w)
+	}
+
+	if q.Sort == "random" {
+		rand.Shuffle(len(wallpapers), func(i, j int) { wallpapers[i], wallpapers[j] = wallpapers[j], wallpapers[i] })
+	}
+	return wallpapers, nil
+}
+
+func (p *localProvider) Open(ctx context.Context, w RemoteWallpaper) (io.ReadCloser, error) {
+	return os.Open(w.URL)
+}
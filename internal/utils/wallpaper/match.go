@@ -0,0 +1,147 @@
+package wallpaper
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/color"
+)
+
+// Scheme keys wallpapers are matched against. Terminal colors stand in for
+// the accents of schemes without Material roles.
+var (
+	matchSurfaceKeys  = []string{"background", "surface", "surfaceContainer", "surfaceContainerHigh"}
+	matchAccentKeys   = []string{"primary", "secondary", "tertiary"}
+	matchTerminalKeys = []string{"term1", "term2", "term3", "term4", "term5", "term6"}
+)
+
+// SchemePalette is the surfaces and accents of a color scheme in OKLab
+type SchemePalette struct {
+	Surfaces []color.OKLab
+	Accents  []color.OKLab
+}
+
+// NewSchemePalette picks the surfaces and accents from scheme colours
+func NewSchemePalette(colours map[string]string) (*SchemePalette, error) {
+	palette := &SchemePalette{
+		Surfaces: labsOf(colours, matchSurfaceKeys),
+		Accents:  labsOf(colours, matchAccentKeys),
+	}
+	if len(palette.Accents) == 0 {
+		palette.Accents = labsOf(colours, matchTerminalKeys)
+	}
+	if len(palette.Surfaces) == 0 && len(palette.Accents) == 0 {
+		return nil, fmt.Errorf("scheme has no surface or accent colors")
+	}
+	return palette, nil
+}
+
+// Distance measures how far a wallpaper's dominant colors, most frequent
+// first, are from the palette in OKLab (0 is a perfect match, about 100
+// is black against white). It averages how closely the scheme covers the
+// wallpaper, weighting frequent colors higher, with how closely each
+// accent appears in the wallpaper.
+func (p *SchemePalette) Distance(colors []string) float64 {
+	labs := make([]color.OKLab, 0, len(colors))
+	for _, hex := range colors {
+		if c, err := color.NewFromHex(hex); err == nil {
+			labs = append(labs, c.OKLab())
+		}
+	}
+	if len(labs) == 0 {
+		return math.Inf(1)
+	}
+
+	references := append(append([]color.OKLab{}, p.Surfaces...), p.Accents...)
+
+	var covered, weights float64
+	for i, lab := range labs {
+		weight := 1 / float64(i+1)
+		covered += weight * nearest(lab, references)
+		weights += weight
+	}
+	covered /= weights
+
+	if len(p.Accents) == 0 {
+		return covered * 100
+	}
+
+	var present float64
+	for _, accent := range p.Accents {
+		present += nearest(accent, labs)
+	}
+	present /= float64(len(p.Accents))
+
+	return (covered + present) / 2 * 100
+}
+
+// ScoredEntry is an index entry and its distance to a scheme palette
+type ScoredEntry struct {
+	*IndexEntry
+	Distance float64 `json:"distance"`
+}
+
+// RankByPalette sorts entries by their distance to the palette, closest
+// first
+func RankByPalette(entries []*IndexEntry, palette *SchemePalette) []ScoredEntry {
+	scored := make([]ScoredEntry, len(entries))
+	for i, entry := range entries {
+		scored[i] = ScoredEntry{IndexEntry: entry, Distance: palette.Distance(entry.Colors)}
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].Distance < scored[j].Distance })
+	return scored
+}
+
+// ClosestEntries returns the entries closest to the palette. threshold is
+// the strictness from 0 (keep every entry) to 1 (keep only the closest);
+// at least one entry is kept.
+func ClosestEntries(entries []*IndexEntry, palette *SchemePalette, threshold float64) []*IndexEntry {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	threshold = math.Max(0, math.Min(1, threshold))
+	keep := max(1, int(math.Ceil(float64(len(entries))*(1-threshold))))
+
+	ranked := RankByPalette(entries, palette)
+	closest := make([]*IndexEntry, keep)
+	for i := range closest {
+		closest[i] = ranked[i].IndexEntry
+	}
+	return closest
+}
+
+// labsOf returns the OKLab values of the present keys, skipping duplicates
+func labsOf(colours map[string]string, keys []string) []color.OKLab {
+	seen := make(map[string]bool)
+	var labs []color.OKLab
+	for _, key := range keys {
+		value, ok := colours[key]
+		if !ok || seen[value] {
+			continue
+		}
+		c, err := color.NewFromHex(value)
+		if err != nil {
+			continue
+		}
+		seen[value] = true
+		labs = append(labs, c.OKLab())
+	}
+	return labs
+}
+
+// nearest returns the OKLab distance from lab to the closest of others
+func nearest(lab color.OKLab, others []color.OKLab) float64 {
+	best := math.Inf(1)
+	for _, other := range others {
+		d := math.Sqrt(sq(lab.L-other.L) + sq(lab.A-other.A) + sq(lab.B-other.B))
+		best = math.Min(best, d)
+	}
+	return best
+}
+
+// sq returns v squared
+func sq(v float64) float64 {
+	return v * v
+}
@@ -0,0 +1,97 @@
+package wallpaper
+
+import (
+	"testing"
+)
+
+// mocha is a subset of Catppuccin Mocha after key derivation
+var mocha = map[string]string{
+	"background": "1e1e2e",
+	"surface":    "1e1e2e",
+	"primary":    "cba6f7",
+	"secondary":  "94e2d5",
+	"tertiary":   "f5c2e7",
+}
+
+func TestNewSchemePalette(t *testing.T) {
+	palette, err := NewSchemePalette(mocha)
+	if err != nil {
+		t.Fatalf("NewSchemePalette() error = %v", err)
+	}
+	// background and surface share a value
+	if len(palette.Surfaces) != 1 || len(palette.Accents) != 3 {
+		t.Errorf("palette has %d surfaces and %d accents", len(palette.Surfaces), len(palette.Accents))
+	}
+
+	terminal, err := NewSchemePalette(map[string]string{"background": "000000", "term1": "ff0000", "term4": "0000ff"})
+	if err != nil {
+		t.Fatalf("NewSchemePalette() error = %v", err)
+	}
+	if len(terminal.Accents) != 2 {
+		t.Errorf("expected terminal colors as accents, got %d", len(terminal.Accents))
+	}
+
+	if _, err := NewSchemePalette(map[string]string{"foreground": "ffffff"}); err == nil {
+		t.Error("expected an error for a scheme without surfaces or accents")
+	}
+}
+
+func TestPaletteDistance(t *testing.T) {
+	palette, err := NewSchemePalette(mocha)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matching := palette.Distance([]string{"#1E1E2E", "#CBA6F7", "#94E2D5", "#F5C2E7"})
+	if matching > 0.001 {
+		t.Errorf("Distance() of the scheme's own colors = %v, want 0", matching)
+	}
+
+	purple := palette.Distance([]string{"#201830", "#B090E0", "#80C8C0"})
+	orange := palette.Distance([]string{"#F0A020", "#E06010", "#FFE0A0"})
+	if purple >= orange {
+		t.Errorf("purple wallpaper distance %v should be below orange %v", purple, orange)
+	}
+
+	if d := palette.Distance(nil); d < 1e9 {
+		t.Errorf("Distance() without colors = %v, want +Inf", d)
+	}
+}
+
+func TestClosestEntries(t *testing.T) {
+	palette, err := NewSchemePalette(mocha)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []*IndexEntry{
+		{Path: "orange", Colors: []string{"#F0A020", "#E06010"}},
+		{Path: "mocha", Colors: []string{"#1E1E2E", "#CBA6F7"}},
+		{Path: "green", Colors: []string{"#20A040", "#80E080"}},
+		{Path: "violet", Colors: []string{"#302040", "#A080E0"}},
+	}
+
+	ranked := RankByPalette(entries, palette)
+	if ranked[0].Path != "mocha" || ranked[1].Path != "violet" {
+		t.Errorf("RankByPalette() order = %s, %s, %s, %s", ranked[0].Path, ranked[1].Path, ranked[2].Path, ranked[3].Path)
+	}
+
+	tests := []struct {
+		threshold float64
+		want      int
+	}{
+		{0, 4},
+		{0.5, 2},
+		{0.8, 1},
+		{1, 1},
+	}
+	for _, tt := range tests {
+		if got := ClosestEntries(entries, palette, tt.threshold); len(got) != tt.want {
+			t.Errorf("ClosestEntries(threshold %v) kept %d, want %d", tt.threshold, len(got), tt.want)
+		}
+	}
+
+	if got := ClosestEntries(nil, palette, 0.5); got != nil {
+		t.Errorf("ClosestEntries(nil) = %v", got)
+	}
+}
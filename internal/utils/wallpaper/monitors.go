@@ -0,0 +1,117 @@
+package wallpaper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/hypr"
+)
+
+// Monitor weighting modes for multi-monitor scheme generation
+const (
+	WeightingArea    = "area"    // Weight each wallpaper by the area of its monitor
+	WeightingEqual   = "equal"   // Weight every monitor the same
+	WeightingPrimary = "primary" // Use the primary monitor's wallpaper only
+	WeightingOff     = "off"     // Use the last set wallpaper only
+)
+
+// MonitorWallpaper is the wallpaper shown on one monitor and its weight in
+// scheme generation
+type MonitorWallpaper struct {
+	Monitor string
+	Path    string
+	Weight  float64
+}
+
+// parseListActive parses "MONITOR = PATH" lines from hyprpaper. An empty
+// monitor name is the wildcard wallpaper.
+func parseListActive(output string) map[string]string {
+	active := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		monitor, path, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		active[strings.TrimSpace(monitor)] = path
+	}
+	return active
+}
+
+// MonitorWallpapers pairs enabled monitors with their wallpapers and weights
+// them. primary names the primary monitor for WeightingPrimary; the monitor
+// with the lowest ID is used when it is empty. Monitors showing the same
+// wallpaper are merged into one entry.
+func MonitorWallpapers(monitors []hypr.Monitor, active map[string]string, weighting, primary string) ([]MonitorWallpaper, error) {
+	enabled := make([]hypr.Monitor, 0, len(monitors))
+	for _, m := range monitors {
+		if !m.Disabled {
+			enabled = append(enabled, m)
+		}
+	}
+	sort.Slice(enabled, func(i, j int) bool { return enabled[i].ID < enabled[j].ID })
+
+	if weighting == WeightingPrimary {
+		m, err := findPrimary(enabled, primary)
+		if err != nil {
+			return nil, err
+		}
+		enabled = []hypr.Monitor{m}
+	}
+
+	byPath := make(map[string]*MonitorWallpaper)
+	var order []string
+
+	for _, m := range enabled {
+		path, ok := active[m.Name]
+		if !ok {
+			path, ok = active[""]
+		}
+		if !ok {
+			continue
+		}
+
+		weight := 1.0
+		if weighting == WeightingArea {
+			weight = float64(m.Width * m.Height)
+		}
+
+		if existing, ok := byPath[path]; ok {
+			existing.Monitor += "," + m.Name
+			existing.Weight += weight
+			continue
+		}
+		byPath[path] = &MonitorWallpaper{Monitor: m.Name, Path: path, Weight: weight}
+		order = append(order, path)
+	}
+
+	if len(order) == 0 {
+		return nil, fmt.Errorf("no monitor has an active wallpaper")
+	}
+
+	result := make([]MonitorWallpaper, 0, len(order))
+	for _, path := range order {
+		result = append(result, *byPath[path])
+	}
+	return result, nil
+}
+
+// findPrimary returns the named monitor, or the first one when name is empty
+func findPrimary(monitors []hypr.Monitor, name string) (hypr.Monitor, error) {
+	if len(monitors) == 0 {
+		return hypr.Monitor{}, fmt.Errorf("no enabled monitors")
+	}
+	if name == "" {
+		return monitors[0], nil
+	}
+	for _, m := range monitors {
+		if m.Name == name {
+			return m, nil
+		}
+	}
+	return hypr.Monitor{}, fmt.Errorf("primary monitor %q not found", name)
+}
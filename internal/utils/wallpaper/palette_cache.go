@@ -0,0 +1,194 @@
+package wallpaper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/scheme"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/material"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+)
+
+// PaletteCacheVersion identifies the extraction and generation algorithms.
+// Bump it whenever either changes its output so stale entries are ignored.
+const PaletteCacheVersion = "1"
+
+// PaletteEntry is the cached result of extracting colors from a wallpaper,
+// or a set of monitor wallpapers, and generating its schemes
+type PaletteEntry struct {
+	Version   string                    `json:"version"`
+	Key       string                    `json:"key"`
+	Sources   []string                  `json:"sources"`
+	CreatedAt time.Time                 `json:"created_at"`
+	Mode      string                    `json:"mode,omitempty"`
+	Extracted *material.ExtractedColors `json:"extracted"`
+
+	// Schemes are the generated variants keyed by "variant/mode". They
+	// depend on the seed choice and generator settings, recorded in
+	// SchemesKey; a different key regenerates them from Extracted.
+	SchemesKey string                    `json:"schemes_key,omitempty"`
+	Schemes    map[string]*scheme.Scheme `json:"schemes,omitempty"`
+}
+
+// PaletteCacheStats summarizes the palette cache
+type PaletteCacheStats struct {
+	Dir     string    `json:"dir"`
+	Entries int       `json:"entries"`
+	Stale   int       `json:"stale"`
+	Size    int64     `json:"size"`
+	Oldest  time.Time `json:"oldest,omitempty"`
+	Newest  time.Time `json:"newest,omitempty"`
+}
+
+// PaletteCache stores palette entries as JSON files named by key
+type PaletteCache struct {
+	dir string
+}
+
+// DefaultPaletteCacheDir returns the location of the palette cache
+func DefaultPaletteCacheDir() string {
+	return filepath.Join(paths.HeimdallCacheDir, "palettes")
+}
+
+// NewPaletteCache creates a palette cache in dir
+func NewPaletteCache(dir string) *PaletteCache {
+	return &PaletteCache{dir: dir}
+}
+
+// PaletteKey derives a cache key from the content hashes and weights of the
+// source wallpapers and the cache version
+func PaletteKey(sources []MonitorWallpaper) (string, error) {
+	parts := []string{PaletteCacheVersion}
+	for _, source := range sources {
+		hash, err := HashFile(source.Path)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, fmt.Sprintf("%s:%g", hash, source.Weight))
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Get returns the entry for key. Hits refresh the entry's modification
+// time, which pruning uses as its last use.
+func (c *PaletteCache) Get(key string) (*PaletteEntry, bool) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry PaletteEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Version != PaletteCacheVersion || entry.Extracted == nil {
+		return nil, false
+	}
+
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return &entry, true
+}
+
+// Put stores an entry under its key
+func (c *PaletteCache) Put(entry *PaletteEntry) error {
+	entry.Version = PaletteCacheVersion
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if err := paths.AtomicWriteJSON(c.path(entry.Key), entry); err != nil {
+		return fmt.Errorf("failed to write palette cache: %w", err)
+	}
+	return nil
+}
+
+// Stats counts the entries of the cache and their size
+func (c *PaletteCache) Stats() (*PaletteCacheStats, error) {
+	stats := &PaletteCacheStats{Dir: c.dir}
+
+	err := c.walk(func(path string, info os.FileInfo, stale bool) error {
+		stats.Entries++
+		stats.Size += info.Size()
+		if stale {
+			stats.Stale++
+		}
+		if stats.Oldest.IsZero() || info.ModTime().Before(stats.Oldest) {
+			stats.Oldest = info.ModTime()
+		}
+		if info.ModTime().After(stats.Newest) {
+			stats.Newest = info.ModTime()
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Prune removes entries of other cache versions and entries unused for
+// longer than maxAge. A maxAge of 0 removes every entry.
+func (c *PaletteCache) Prune(maxAge time.Duration) (removed int, freed int64, err error) {
+	cutoff := time.Now().Add(-maxAge)
+
+	err = c.walk(func(path string, info os.FileInfo, stale bool) error {
+		if maxAge > 0 && !stale && info.ModTime().After(cutoff) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		removed++
+		freed += info.Size()
+		return nil
+	})
+	return removed, freed, err
+}
+
+// walk calls fn for every cache file, reporting whether it was written by
+// another cache version
+func (c *PaletteCache) walk(fn func(path string, info os.FileInfo, stale bool) error) error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read palette cache: %w", err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+
+		path := filepath.Join(c.dir, e.Name())
+		if err := fn(path, info, !c.current(path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// current reports whether a cache file was written by this cache version
+func (c *PaletteCache) current(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var header struct {
+		Version string `json:"version"`
+	}
+	return json.Unmarshal(data, &header) == nil && header.Version == PaletteCacheVersion
+}
+
+// path returns the file of a cache key
+func (c *PaletteCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
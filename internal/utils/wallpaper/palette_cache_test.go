@@ -0,0 +1,96 @@
+package wallpaper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/material"
+)
+
+func TestPaletteCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewPaletteCache(dir)
+
+	wallpaperPath := filepath.Join(dir, "wall.png")
+	if err := os.WriteFile(wallpaperPath, []byte("not really a png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := PaletteKey([]MonitorWallpaper{{Path: wallpaperPath, Weight: 1}})
+	if err != nil {
+		t.Fatalf("PaletteKey() error = %v", err)
+	}
+	if _, ok := cache.Get(key); ok {
+		t.Fatal("expected a miss on an empty cache")
+	}
+
+	entry := &PaletteEntry{
+		Key:       key,
+		Sources:   []string{wallpaperPath},
+		Mode:      "dark",
+		Extracted: &material.ExtractedColors{Candidates: []material.SeedCandidate{{Color: 0xff4285f4}}},
+	}
+	if err := cache.Put(entry); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	got, ok := cache.Get(key)
+	if !ok {
+		t.Fatal("expected a hit after Put")
+	}
+	if got.Mode != "dark" || len(got.Extracted.Candidates) != 1 || got.Extracted.Candidates[0].Color != 0xff4285f4 {
+		t.Errorf("Get() = %+v, want the stored entry", got)
+	}
+
+	// Same content under another name hits the same entry
+	renamed := filepath.Join(dir, "renamed.png")
+	if err := os.Rename(wallpaperPath, renamed); err != nil {
+		t.Fatal(err)
+	}
+	if renamedKey, _ := PaletteKey([]MonitorWallpaper{{Path: renamed, Weight: 1}}); renamedKey != key {
+		t.Error("renaming a wallpaper changed its cache key")
+	}
+}
+
+func TestPaletteCachePrune(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewPaletteCache(dir)
+
+	for _, key := range []string{"fresh", "old"} {
+		if err := cache.Put(&PaletteEntry{Key: key, Extracted: &material.ExtractedColors{}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "old.json"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "outdated.json"), []byte(`{"version":"0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Entries != 3 || stats.Stale != 1 {
+		t.Errorf("Stats() = %d entries, %d stale; want 3 and 1", stats.Entries, stats.Stale)
+	}
+
+	removed, _, err := cache.Prune(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Prune() removed %d entries, want 2", removed)
+	}
+	if _, ok := cache.Get("fresh"); !ok {
+		t.Error("Prune() removed a fresh entry")
+	}
+
+	if removed, _, _ := cache.Prune(0); removed != 1 {
+		t.Errorf("Prune(0) removed %d entries, want 1", removed)
+	}
+}
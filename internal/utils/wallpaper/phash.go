@@ -0,0 +1,149 @@
+package wallpaper
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+)
+
+// Perceptual hash algorithms
+const (
+	HashDHash = "dhash"
+	HashPHash = "phash"
+)
+
+// HashNames lists the perceptual hash algorithms
+var HashNames = []string{HashPHash, HashDHash}
+
+// phashSize is the side of the grayscale grid the DCT of pHash runs on
+const phashSize = 32
+
+// PerceptualHash is a 64-bit perceptual image hash. Resized and re-encoded
+// copies of an image hash to values a few bits apart.
+type PerceptualHash uint64
+
+// String returns the hash as 16 hex digits
+func (h PerceptualHash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// MarshalText stores hashes as hex, since JSON numbers lose precision
+// beyond 53 bits
+func (h PerceptualHash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText reads a hash stored by MarshalText
+func (h *PerceptualHash) UnmarshalText(text []byte) error {
+	v, err := strconv.ParseUint(string(text), 16, 64)
+	if err != nil {
+		return fmt.Errorf("invalid perceptual hash %q: %w", text, err)
+	}
+	*h = PerceptualHash(v)
+	return nil
+}
+
+// Distance returns the number of bits in which two hashes differ
+func (h PerceptualHash) Distance(other PerceptualHash) int {
+	return bits.OnesCount64(uint64(h ^ other))
+}
+
+// DHash computes the difference hash: each bit tells whether a cell of a
+// 9x8 grayscale grid is brighter than its right neighbour
+func DHash(img *image.RGBA) PerceptualHash {
+	gray := grayGrid(img, 9, 8)
+
+	var hash PerceptualHash
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if gray[y*9+x] > gray[y*9+x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// PHash computes the DCT hash: each bit tells whether one of the 8x8
+// lowest frequencies of a 32x32 grayscale grid is above their median
+func PHash(img *image.RGBA) PerceptualHash {
+	gray := grayGrid(img, phashSize, phashSize)
+
+	// cosines[u][x] is the DCT-II basis of frequency u at position x
+	var cosines [8][phashSize]float64
+	for u := range cosines {
+		for x := range cosines[u] {
+			cosines[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * phashSize))
+		}
+	}
+
+	// Rows first, then columns, keeping only the low frequencies
+	var rows [phashSize][8]float64
+	for y := 0; y < phashSize; y++ {
+		for u := 0; u < 8; u++ {
+			var sum float64
+			for x := 0; x < phashSize; x++ {
+				sum += gray[y*phashSize+x] * cosines[u][x]
+			}
+			rows[y][u] = sum
+		}
+	}
+
+	coefficients := make([]float64, 0, 64)
+	for v := 0; v < 8; v++ {
+		for u := 0; u < 8; u++ {
+			var sum float64
+			for y := 0; y < phashSize; y++ {
+				sum += rows[y][u] * cosines[v][y]
+			}
+			coefficients = append(coefficients, sum)
+		}
+	}
+
+	// The DC term is the mean brightness and would skew the median
+	sorted := append([]float64{}, coefficients[1:]...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash PerceptualHash
+	for _, c := range coefficients {
+		hash <<= 1
+		if c > median {
+			hash |= 1
+		}
+	}
+	return hash
+}
+
+// grayGrid averages the luminance of img over a width x height grid
+func grayGrid(img *image.RGBA, width, height int) []float64 {
+	bounds := img.Bounds()
+	sums := make([]float64, width*height)
+	counts := make([]int, width*height)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		gy := (y - bounds.Min.Y) * height / bounds.Dy()
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gx := (x - bounds.Min.X) * width / bounds.Dx()
+			p := img.Pix[img.PixOffset(x, y):]
+			cell := gy*width + gx
+			sums[cell] += 0.299*float64(p[0]) + 0.587*float64(p[1]) + 0.114*float64(p[2])
+			counts[cell]++
+		}
+	}
+
+	// Images smaller than the grid leave cells empty; repeat their
+	// neighbours instead
+	for i := range sums {
+		if counts[i] > 0 {
+			sums[i] /= float64(counts[i])
+		} else if i > 0 {
+			sums[i] = sums[i-1]
+		}
+	}
+	return sums
+}
@@ -0,0 +1,150 @@
+package wallpaper
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/hypr"
+)
+
+// allOutputs is the output name swaybg and mpvpaper read as every monitor
+const allOutputs = "*"
+
+// swaybgBackend runs one swaybg process showing the wallpaper of every
+// monitor. swaybg has no IPC, so each change restarts it.
+type swaybgBackend struct{}
+
+func (s *swaybgBackend) Name() string { return BackendSwaybg }
+
+func (s *swaybgBackend) Available() bool { return commandAvailable("swaybg") }
+
+func (s *swaybgBackend) Set(state *MonitorState, monitor string) error {
+	stopProcesses(state)
+
+	pid, err := startProcess("swaybg", swaybgArgs(state)...)
+	if err != nil {
+		return err
+	}
+	state.PIDs = []int{pid}
+	return nil
+}
+
+// swaybgArgs returns swaybg arguments for every wallpaper in state. Named
+// outputs take precedence over the * output.
+func swaybgArgs(state *MonitorState) []string {
+	var args []string
+	if path, ok := state.Monitors[""]; ok {
+		args = append(args, "-o", allOutputs, "-i", path, "-m", "fill")
+	}
+	for _, monitor := range state.monitorNames() {
+		args = append(args, "-o", monitor, "-i", state.Monitors[monitor], "-m", "fill")
+	}
+	return args
+}
+
+// mpvpaperBackend runs an mpvpaper process per monitor, which also plays
+// video wallpapers. Like swaybg it is restarted on each change.
+type mpvpaperBackend struct{}
+
+func (m *mpvpaperBackend) Name() string { return BackendMpvpaper }
+
+func (m *mpvpaperBackend) Available() bool { return commandAvailable("mpvpaper") }
+
+func (m *mpvpaperBackend) Set(state *MonitorState, monitor string) error {
+	stopProcesses(state)
+
+	for _, output := range mpvpaperOutputs(state, connectedMonitors()) {
+		pid, err := startProcess("mpvpaper", "-o", "no-audio loop", output[0], output[1])
+		if err != nil {
+			return err
+		}
+		state.PIDs = append(state.PIDs, pid)
+	}
+	return nil
+}
+
+// mpvpaperOutputs pairs outputs with wallpapers. mpvpaper has no
+// precedence between * and named outputs, so once a monitor has its own
+// wallpaper the wallpaper for every monitor is expanded over the connected
+// monitors instead.
+func mpvpaperOutputs(state *MonitorState, connected []string) [][2]string {
+	named := state.monitorNames()
+
+	if len(named) == 0 || len(connected) == 0 {
+		var outputs [][2]string
+		if path, ok := state.Monitors[""]; ok {
+			outputs = append(outputs, [2]string{allOutputs, path})
+		}
+		for _, monitor := range named {
+			outputs = append(outputs, [2]string{monitor, state.Monitors[monitor]})
+		}
+		return outputs
+	}
+
+	seen := make(map[string]bool)
+	var outputs [][2]string
+	for _, monitor := range append(connected, named...) {
+		if seen[monitor] {
+			continue
+		}
+		seen[monitor] = true
+		if path, ok := state.Wallpaper(monitor); ok {
+			outputs = append(outputs, [2]string{monitor, path})
+		}
+	}
+	return outputs
+}
+
+// connectedMonitors returns the names of the enabled Hyprland monitors, or
+// nil outside Hyprland
+func connectedMonitors() []string {
+	if !hypr.IsRunning() {
+		return nil
+	}
+	client, err := hypr.NewClient()
+	if err != nil {
+		return nil
+	}
+	monitors, err := client.GetMonitors()
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, m := range monitors {
+		if !m.Disabled {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
+
+// startProcess starts a wallpaper program in its own session so it
+// outlives heimdall, returning its PID
+func startProcess(name string, args ...string) (int, error) {
+	cmd := exec.Command(name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start %s: %w", name, err)
+	}
+
+	pid := cmd.Process.Pid
+	cmd.Process.Release()
+	return pid, nil
+}
+
+// stopProcesses stops the processes started for the wallpapers of state.
+// PIDs that now belong to another program are left alone.
+func stopProcesses(state *MonitorState) {
+	for _, pid := range state.PIDs {
+		comm, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+		if err != nil || strings.TrimSpace(string(comm)) != state.Backend {
+			continue
+		}
+		syscall.Kill(pid, syscall.SIGTERM)
+	}
+	state.PIDs = nil
+}
@@ -0,0 +1,333 @@
+package wallpaper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/imageio"
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+)
+
+// Wallpaper source providers
+const (
+	ProviderWallhaven = "wallhaven" // Wallhaven-style search API
+	ProviderFeed      = "feed"      // RSS or Atom feed with image enclosures
+	ProviderLocal     = "local"     // Directory, such as a mounted network share
+)
+
+// ProviderNames lists the supported provider kinds
+var ProviderNames = []string{ProviderWallhaven, ProviderFeed, ProviderLocal}
+
+// DefaultWallhavenURL is the API base URL of the wallhaven provider
+const DefaultWallhavenURL = "https://wallhaven.cc"
+
+// FetchQuery selects the wallpapers a provider offers
+type FetchQuery struct {
+	Terms     string // Search terms; feeds and directories match them against titles and names
+	MinWidth  int    // Minimum width in pixels
+	MinHeight int    // Minimum height in pixels
+	Sort      string // Provider specific order, e.g. toplist or random
+	Limit     int    // Number of new wallpapers to fetch
+}
+
+// RemoteWallpaper is a wallpaper a provider offers. Width and Height are
+// zero when the provider does not know them before the download.
+type RemoteWallpaper struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Title  string `json:"title,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// Provider lists and downloads the wallpapers of a source
+type Provider interface {
+	// Name returns the provider kind, one of ProviderNames
+	Name() string
+
+	// Search returns the wallpapers matching q, best first. It may return
+	// more than q.Limit so that duplicates can be skipped.
+	Search(ctx context.Context, q FetchQuery) ([]RemoteWallpaper, error)
+
+	// Open returns the image data of a wallpaper
+	Open(ctx context.Context, w RemoteWallpaper) (io.ReadCloser, error)
+}
+
+// ProviderOptions configure a provider
+type ProviderOptions struct {
+	URL        string       // API base URL, feed URL or directory
+	APIKey     string       // Sent to Wallhaven-style APIs
+	Client     *http.Client // HTTP client of remote providers
+	Extensions []string     // Image extensions of local directories
+}
+
+// NewProvider creates the provider of kind
+func NewProvider(kind string, opts ProviderOptions) (Provider, error) {
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: time.Minute}
+	}
+
+	switch kind {
+	case ProviderWallhaven:
+		if opts.URL == "" {
+			opts.URL = DefaultWallhavenURL
+		}
+		return &wallhavenProvider{opts: opts}, nil
+	case ProviderFeed:
+		if opts.URL == "" {
+			return nil, fmt.Errorf("the feed provider needs a feed URL")
+		}
+		return &feedProvider{opts: opts}, nil
+	case ProviderLocal:
+		if opts.URL == "" {
+			return nil, fmt.Errorf("the local provider needs a directory")
+		}
+		return &localProvider{opts: opts}, nil
+	default:
+		return nil, fmt.Errorf("unknown wallpaper provider %q (must be one of: %s)", kind, strings.Join(ProviderNames, ", "))
+	}
+}
+
+// FetchOptions configure Fetch
+type FetchOptions struct {
+	Dir      string // Directory the wallpapers are saved to
+	Distance int    // Maximum pHash distance to an indexed wallpaper that counts as a duplicate
+}
+
+// SkippedWallpaper is a wallpaper Fetch did not keep and why
+type SkippedWallpaper struct {
+	Wallpaper RemoteWallpaper `json:"wallpaper"`
+	Reason    string          `json:"reason"`
+}
+
+// FetchResult reports what Fetch did with the provider's wallpapers
+type FetchResult struct {
+	Fetched []*IndexEntry      `json:"fetched"`
+	Skipped []SkippedWallpaper `json:"skipped"`
+}
+
+// Fetch downloads up to q.Limit new wallpapers from p into opts.Dir and
+// adds them to the index. Wallpapers below the minimum size, fetched
+// before, or copies or near-duplicates of an indexed wallpaper are
+// skipped. The caller saves the index.
+func Fetch(ctx context.Context, p Provider, q FetchQuery, index *Index, opts FetchOptions) (*FetchResult, error) {
+	candidates, err := p.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]string)
+	for _, entry := range index.Entries {
+		if entry.Source != "" {
+			sources[entry.Source] = entry.Path
+		}
+	}
+
+	result := &FetchResult{}
+	skip := func(w RemoteWallpaper, format string, args ...any) {
+		result.Skipped = append(result.Skipped, SkippedWallpaper{Wallpaper: w, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	for _, w := range candidates {
+		if q.Limit > 0 && len(result.Fetched) >= q.Limit {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if existing, ok := sources[w.URL]; ok {
+			skip(w, "already fetched as %s", existing)
+			continue
+		}
+		if w.Width > 0 && w.Height > 0 && tooSmall(w.Width, w.Height, q) {
+			skip(w, "too small (%dx%d)", w.Width, w.Height)
+			continue
+		}
+
+		entry, err := download(ctx, p, w, opts.Dir)
+		if err != nil {
+			skip(w, "%v", err)
+			continue
+		}
+
+		reason := ""
+		if tooSmall(entry.Width, entry.Height, q) {
+			reason = fmt.Sprintf("too small (%dx%d)", entry.Width, entry.Height)
+		} else if original := findCopy(index, entry, opts.Distance); original != nil {
+			reason = "duplicate of " + original.Path
+		}
+		if reason != "" {
+			os.Remove(entry.Path)
+			skip(w, "%s", reason)
+			continue
+		}
+
+		index.Entries[entry.Path] = entry
+		sources[w.URL] = entry.Path
+		result.Fetched = append(result.Fetched, entry)
+	}
+	return result, nil
+}
+
+// tooSmall reports whether a size is below the minimum of q
+func tooSmall(width, height int, q FetchQuery) bool {
+	return width < q.MinWidth || height < q.MinHeight
+}
+
+// findCopy returns the indexed wallpaper entry is a copy or near-duplicate
+// of, or nil
+func findCopy(index *Index, entry *IndexEntry, distance int) *IndexEntry {
+	for _, existing := range index.Entries {
+		if existing.Hash == entry.Hash {
+			return existing
+		}
+		if distance >= 0 && existing.PHash != 0 && existing.PHash.Distance(entry.PHash) <= distance {
+			return existing
+		}
+	}
+	return nil
+}
+
+// unsafeName matches characters left out of fetched file names
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// download saves a wallpaper in dir as <provider>-<id><ext> and analyzes it
+func download(ctx context.Context, p Provider, w RemoteWallpaper, dir string) (*IndexEntry, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	reader, err := p.Open(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	tmp, err := os.CreateTemp(dir, ".fetch-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(reader, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to download %s: %w", w.URL, err)
+	}
+	header = header[:n]
+	if _, err := tmp.Write(header); err == nil {
+		_, err = io.Copy(tmp, reader)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", w.URL, err)
+	}
+
+	ext := imageExtension(w.URL, header)
+	if ext == "" {
+		return nil, fmt.Errorf("not an image: %s", w.URL)
+	}
+
+	id := strings.Trim(unsafeName.ReplaceAllString(strings.TrimSuffix(w.ID, filepath.Ext(w.ID)), "-"), "-.")
+	if id == "" {
+		id = "wallpaper"
+	}
+	target := filepath.Join(dir, p.Name()+"-"+id+ext)
+	base := strings.TrimSuffix(target, ext)
+	for i := 1; paths.Exists(target); i++ {
+		target = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return nil, fmt.Errorf("failed to save %s: %w", target, err)
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := HashFile(target)
+	if err == nil {
+		var entry *IndexEntry
+		if entry, err = AnalyzeEntry(target); err == nil {
+			entry.Hash = hash
+			entry.Size = info.Size()
+			entry.ModTime = info.ModTime()
+			entry.Source = w.URL
+			return entry, nil
+		}
+	}
+	os.Remove(target)
+	return nil, fmt.Errorf("failed to analyze %s: %w", w.URL, err)
+}
+
+// imageExtension returns the extension of an image, from the name when it
+// is a supported image extension or else from the content
+func imageExtension(name string, header []byte) string {
+	if u, err := url.Parse(name); err == nil && u.Path != "" {
+		name = u.Path
+	}
+	ext := strings.ToLower(path.Ext(name))
+	for _, supported := range imageio.Extensions() {
+		if ext == supported {
+			return ext
+		}
+	}
+
+	switch http.DetectContentType(header) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	}
+	return ""
+}
+
+// matchesTerms reports whether text contains every search term
+func matchesTerms(text, terms string) bool {
+	text = strings.ToLower(text)
+	for _, term := range strings.Fields(strings.ToLower(terms)) {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// httpGet requests a URL and fails on statuses other than 200
+func httpGet(ctx context.Context, client *http.Client, rawURL string, header http.Header) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("User-Agent", "heimdall-cli")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: %s", rawURL, resp.Status)
+	}
+	return resp.Body, nil
+}
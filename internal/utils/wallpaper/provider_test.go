@@ -0,0 +1,230 @@
+package wallpaper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// wallpaperServer is a local stand-in for a Wallhaven-style API and an RSS
+// feed. It serves a new wallpaper, one too small to use, and a resized copy
+// of the pattern(128, 96, false) wallpaper.
+func wallpaperServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	images := map[string][]byte{}
+	encode := func(name string, w, h int, flip bool) {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, pattern(w, h, flip)); err != nil {
+			t.Fatal(err)
+		}
+		images[name] = buf.Bytes()
+	}
+	encode("new.png", 64, 48, true)
+	encode("small.png", 16, 12, true)
+	encode("copy.png", 64, 48, false)
+
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/api/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("atleast") != "32x32" || r.URL.Query().Get("q") != "nature" {
+			http.Error(w, "unexpected query "+r.URL.RawQuery, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"data": [
+			{"id": "copy", "path": "%[1]s/img/copy.png", "dimension_x": 64, "dimension_y": 48},
+			{"id": "small", "path": "%[1]s/img/small.png", "dimension_x": 16, "dimension_y": 12},
+			{"id": "new", "path": "%[1]s/img/new.png", "dimension_x": 64, "dimension_y": 48}
+		], "meta": {"current_page": 1, "last_page": 1}}`, server.URL)
+	})
+	mux.HandleFunc("/img/", func(w http.ResponseWriter, r *http.Request) {
+		data, ok := images[strings.TrimPrefix(r.URL.Path, "/img/")]
+		if !ok || r.Header.Get("X-API-Key") != "" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	})
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <item>
+      <title>Nature at dawn</title>
+      <media:content url="/img/small.png" medium="image" width="16" height="12"/>
+      <media:content url="/img/new.png" medium="image" width="64" height="48"/>
+    </item>
+    <item>
+      <title>City lights</title>
+      <enclosure url="/img/copy.png" type="image/png"/>
+    </item>
+    <item>
+      <title>No image</title>
+      <link>https://example.com/post</link>
+    </item>
+  </channel>
+</rss>`)
+	})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetchWallhaven(t *testing.T) {
+	server := wallpaperServer(t)
+	dir := t.TempDir()
+
+	// The library already has a larger version of copy.png
+	library := filepath.Join(dir, "library")
+	if err := os.MkdirAll(library, 0755); err != nil {
+		t.Fatal(err)
+	}
+	original := filepath.Join(library, "original.png")
+	f, err := os.Create(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, pattern(128, 96, false))
+	f.Close()
+
+	index, _ := LoadIndex(filepath.Join(dir, "index.json"))
+	if _, err := index.Add(original); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := NewProvider(ProviderWallhaven, ProviderOptions{URL: server.URL, APIKey: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := FetchQuery{Terms: "nature", MinWidth: 32, MinHeight: 32, Limit: 5}
+	opts := FetchOptions{Dir: filepath.Join(library, "fetched"), Distance: DefaultDuplicateDistance}
+
+	result, err := Fetch(context.Background(), provider, query, index, opts)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(result.Fetched) != 1 || filepath.Base(result.Fetched[0].Path) != "wallhaven-new.png" {
+		t.Fatalf("fetched %+v, want only wallhaven-new.png", result.Fetched)
+	}
+	if result.Fetched[0].Source != server.URL+"/img/new.png" || index.Entries[result.Fetched[0].Path] == nil {
+		t.Error("fetched wallpaper is not indexed with its source")
+	}
+
+	reasons := map[string]string{}
+	for _, skipped := range result.Skipped {
+		reasons[skipped.Wallpaper.ID] = skipped.Reason
+	}
+	if !strings.HasPrefix(reasons["copy"], "duplicate of "+original) {
+		t.Errorf("copy skipped for %q, want a duplicate", reasons["copy"])
+	}
+	if !strings.HasPrefix(reasons["small"], "too small") {
+		t.Errorf("small skipped for %q", reasons["small"])
+	}
+	if files, _ := os.ReadDir(opts.Dir); len(files) != 1 {
+		t.Errorf("%d files left in the fetch directory, want 1", len(files))
+	}
+
+	// A second fetch recognizes the source
+	again, err := Fetch(context.Background(), provider, query, index, opts)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(again.Fetched) != 0 {
+		t.Errorf("fetched %d wallpapers again", len(again.Fetched))
+	}
+
+	// Without the API key the search fails
+	provider, _ = NewProvider(ProviderWallhaven, ProviderOptions{URL: server.URL})
+	if _, err := Fetch(context.Background(), provider, query, index, opts); err == nil {
+		t.Error("expected an error from the unauthorized search")
+	}
+}
+
+func TestFeedProvider(t *testing.T) {
+	server := wallpaperServer(t)
+	provider, err := NewProvider(ProviderFeed, ProviderOptions{URL: server.URL + "/feed.xml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wallpapers, err := provider.Search(context.Background(), FetchQuery{})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(wallpapers) != 2 {
+		t.Fatalf("Search() returned %d wallpapers, want 2", len(wallpapers))
+	}
+	// The largest media content wins and relative URLs are resolved
+	if wallpapers[0].URL != server.URL+"/img/new.png" || wallpapers[0].Width != 64 || wallpapers[0].Title != "Nature at dawn" {
+		t.Errorf("first wallpaper = %+v", wallpapers[0])
+	}
+	if wallpapers[1].URL != server.URL+"/img/copy.png" || wallpapers[1].ID != "copy.png" {
+		t.Errorf("enclosure wallpaper = %+v", wallpapers[1])
+	}
+
+	filtered, err := provider.Search(context.Background(), FetchQuery{Terms: "city"})
+	if err != nil || len(filtered) != 1 || filtered[0].Title != "City lights" {
+		t.Errorf("Search(city) = %+v, %v", filtered, err)
+	}
+
+	atom := `<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>Peak</title>
+		<link rel="alternate" href="https://example.com/peak"/>
+		<link rel="enclosure" type="image/jpeg" href="https://example.com/peak"/></entry></feed>`
+	entries, err := parseFeed(strings.NewReader(atom), "https://example.com/atom.xml", FetchQuery{})
+	if err != nil || len(entries) != 1 || entries[0].URL != "https://example.com/peak" {
+		t.Errorf("parseFeed(atom) = %+v, %v", entries, err)
+	}
+}
+
+func TestLocalProvider(t *testing.T) {
+	share := t.TempDir()
+	for _, name := range []string{"nature/forest.png", "city/night.png"} {
+		path := filepath.Join(share, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		png.Encode(f, pattern(40, 30, strings.HasPrefix(name, "city")))
+		f.Close()
+	}
+
+	provider, err := NewProvider(ProviderLocal, ProviderOptions{URL: share})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	index, _ := LoadIndex(filepath.Join(dir, "index.json"))
+
+	result, err := Fetch(context.Background(), provider, FetchQuery{Terms: "nature"}, index, FetchOptions{Dir: dir, Distance: -1})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(result.Fetched) != 1 || filepath.Base(result.Fetched[0].Path) != "local-nature-forest.png" || result.Fetched[0].Width != 40 {
+		t.Fatalf("fetched %+v", result.Fetched)
+	}
+	if _, err := os.Stat(filepath.Join(share, "nature", "forest.png")); err != nil {
+		t.Error("the shared file was moved instead of copied")
+	}
+
+	if _, err := NewProvider(ProviderLocal, ProviderOptions{}); err == nil {
+		t.Error("expected an error for a local provider without a directory")
+	}
+	if _, err := NewProvider("ftp", ProviderOptions{}); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
@@ -0,0 +1,125 @@
+package wallpaper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+)
+
+// SeedChoice is a seed color the user picked for a wallpaper, or for the
+// set of wallpapers shown across monitors
+type SeedChoice struct {
+	Color    string    `json:"color"`
+	Path     string    `json:"path"`
+	ChosenAt time.Time `json:"chosen_at"`
+}
+
+// SeedStore remembers seed choices by wallpaper content hash, so renamed
+// or moved wallpapers keep their seed
+type SeedStore struct {
+	path    string
+	Choices map[string]SeedChoice `json:"choices"`
+}
+
+// DefaultSeedStorePath returns the location of the seed store
+func DefaultSeedStorePath() string {
+	return filepath.Join(paths.HeimdallStateDir, "wallpaper", "seeds.json")
+}
+
+// LoadSeedStore reads the seed store, returning an empty store when the
+// file does not exist
+func LoadSeedStore(path string) (*SeedStore, error) {
+	store := &SeedStore{path: path, Choices: make(map[string]SeedChoice)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to read seed store: %w", err)
+	}
+
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse seed store: %w", err)
+	}
+	if store.Choices == nil {
+		store.Choices = make(map[string]SeedChoice)
+	}
+	return store, nil
+}
+
+// Get returns the seed chosen for a wallpaper hash
+func (s *SeedStore) Get(hash string) (SeedChoice, bool) {
+	choice, ok := s.Choices[hash]
+	return choice, ok
+}
+
+// Set records a seed choice and saves the store
+func (s *SeedStore) Set(hash string, choice SeedChoice) error {
+	s.Choices[hash] = choice
+	return s.save()
+}
+
+// Delete forgets the seed choice of a wallpaper hash and saves the store
+func (s *SeedStore) Delete(hash string) error {
+	if _, ok := s.Choices[hash]; !ok {
+		return nil
+	}
+	delete(s.Choices, hash)
+	return s.save()
+}
+
+// save writes the store atomically
+func (s *SeedStore) save() error {
+	if err := paths.AtomicWriteJSON(s.path, s); err != nil {
+		return fmt.Errorf("failed to write seed store: %w", err)
+	}
+	return nil
+}
+
+// HashFile returns the SHA-256 of a file's content
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// HashFiles returns the seed key for the wallpapers shown across monitors:
+// the hash of a single file, or a hash of every file's content hash,
+// monitors and weight plus the primary monitor. The order of files does not
+// matter, but a changed weighting gets its own key.
+func HashFiles(files []MonitorWallpaper, primary string) (string, error) {
+	parts := make([]string, 0, len(files)+1)
+	for _, file := range files {
+		hash, err := HashFile(file.Path)
+		if err != nil {
+			return "", err
+		}
+		if len(files) == 1 {
+			return hash, nil
+		}
+		parts = append(parts, fmt.Sprintf("%s:%s:%g", hash, file.Monitor, file.Weight))
+	}
+
+	sort.Strings(parts)
+	parts = append(parts, "primary:"+primary)
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:]), nil
+}
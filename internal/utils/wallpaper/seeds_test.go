@@ -0,0 +1,54 @@
+package wallpaper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashFiles(t *testing.T) {
+	dir := t.TempDir()
+	left := filepath.Join(dir, "left.png")
+	right := filepath.Join(dir, "right.png")
+	if err := os.WriteFile(left, []byte("left"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(right, []byte("right"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	key := func(files []MonitorWallpaper, primary string) string {
+		t.Helper()
+		hash, err := HashFiles(files, primary)
+		if err != nil {
+			t.Fatalf("HashFiles() error = %v", err)
+		}
+		return hash
+	}
+
+	// A single wallpaper keeps its content hash on any monitor
+	single, _ := HashFile(left)
+	if got := key([]MonitorWallpaper{{Monitor: "DP-1", Path: left, Weight: 2}}, "DP-1"); got != single {
+		t.Errorf("single wallpaper key = %s, want its content hash %s", got, single)
+	}
+
+	base := []MonitorWallpaper{
+		{Monitor: "DP-1", Path: left, Weight: 2},
+		{Monitor: "HDMI-A-1", Path: right, Weight: 1},
+	}
+	want := key(base, "")
+
+	reordered := []MonitorWallpaper{base[1], base[0]}
+	if got := key(reordered, ""); got != want {
+		t.Error("reordering the wallpapers changed the key")
+	}
+
+	reweighted := []MonitorWallpaper{base[0], {Monitor: "HDMI-A-1", Path: right, Weight: 2}}
+	if got := key(reweighted, ""); got == want {
+		t.Error("changing a monitor weight kept the key")
+	}
+
+	if got := key(base, "HDMI-A-1"); got == want {
+		t.Error("changing the primary monitor kept the key")
+	}
+}
@@ -0,0 +1,257 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/arthur404dev/heimdall-cli/internal/utils/paths"
+)
+
+// Slideshow orders
+const (
+	OrderShuffle  = "shuffle"
+	OrderOrdered  = "ordered"
+	OrderWeighted = "weighted"
+)
+
+// SlideshowOrders lists the slideshow orders
+var SlideshowOrders = []string{OrderShuffle, OrderOrdered, OrderWeighted}
+
+// FavouriteTag marks favourite wallpapers in the index
+const FavouriteTag = "favourite"
+
+// FavouriteWeight is how much more often the weighted order shows a
+// favourite than any other wallpaper
+const FavouriteWeight = 4.0
+
+// slideshowHistory is the number of shown wallpapers kept for going back
+const slideshowHistory = 50
+
+// SlideshowState is the position of the slideshow, persisted so a restarted
+// slideshow continues where it stopped
+type SlideshowState struct {
+	path     string
+	Dir      string   `json:"dir"`
+	Order    string   `json:"order"`
+	Queue    []string `json:"queue,omitempty"`   // Wallpapers left in the shuffled pass
+	History  []string `json:"history,omitempty"` // Shown wallpapers, oldest first
+	Position int      `json:"position"`          // Index of the current wallpaper in History
+}
+
+// DefaultSlideshowStatePath returns the location of the slideshow state
+func DefaultSlideshowStatePath() string {
+	return filepath.Join(paths.HeimdallStateDir, "wallpaper", "slideshow.json")
+}
+
+// SlideshowSocketPath returns the control socket of the slideshow daemon
+func SlideshowSocketPath() string {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		runtimeDir = fmt.Sprintf("/run/user/%d", os.Getuid())
+	}
+	return filepath.Join(runtimeDir, "heimdall-slideshow.sock")
+}
+
+// LoadSlideshowState reads the slideshow state for dir and order. A missing
+// file, or a state of another directory or order, starts a new slideshow.
+func LoadSlideshowState(path, dir, order string) (*SlideshowState, error) {
+	state := &SlideshowState{path: path, Dir: dir, Order: order}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read slideshow state: %w", err)
+	}
+
+	var saved SlideshowState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse slideshow state: %w", err)
+	}
+	if saved.Dir != dir || saved.Order != order {
+		return state, nil
+	}
+	saved.path = path
+	return &saved, nil
+}
+
+// Save writes the state atomically
+func (s *SlideshowState) Save() error {
+	if err := paths.AtomicWriteJSON(s.path, s); err != nil {
+		return fmt.Errorf("failed to write slideshow state: %w", err)
+	}
+	return nil
+}
+
+// Current returns the wallpaper shown last, or an empty string
+func (s *SlideshowState) Current() string {
+	if s.Position < 0 || s.Position >= len(s.History) {
+		return ""
+	}
+	return s.History[s.Position]
+}
+
+// Next moves to the next wallpaper among wallpapers. After going back the
+// history is replayed first. weight, used by the weighted order, returns
+// how likely a wallpaper is to be picked.
+func (s *SlideshowState) Next(wallpapers []string, weight func(string) float64) (string, error) {
+	available := make(map[string]bool, len(wallpapers))
+	for _, path := range wallpapers {
+		available[path] = true
+	}
+
+	for s.Position < len(s.History)-1 {
+		s.Position++
+		if available[s.History[s.Position]] {
+			return s.History[s.Position], nil
+		}
+	}
+
+	if len(wallpapers) == 0 {
+		return "", fmt.Errorf("no wallpapers to show")
+	}
+
+	var next string
+	switch s.Order {
+	case OrderOrdered:
+		next = s.nextOrdered(wallpapers)
+	case OrderWeighted:
+		next = s.nextWeighted(wallpapers, weight)
+	default:
+		next = s.nextShuffled(wallpapers, available)
+	}
+
+	s.History = append(s.History, next)
+	if len(s.History) > slideshowHistory {
+		s.History = s.History[len(s.History)-slideshowHistory:]
+	}
+	s.Position = len(s.History) - 1
+	return next, nil
+}
+
+// Previous moves back to the wallpaper shown before the current one that
+// is still among wallpapers
+func (s *SlideshowState) Previous(wallpapers []string) (string, error) {
+	available := make(map[string]bool, len(wallpapers))
+	for _, path := range wallpapers {
+		available[path] = true
+	}
+
+	for position := s.Position - 1; position >= 0; position-- {
+		if available[s.History[position]] {
+			s.Position = position
+			return s.History[position], nil
+		}
+	}
+	return "", fmt.Errorf("no previous wallpaper")
+}
+
+// nextOrdered returns the wallpaper after the current one by path,
+// wrapping around. A removed current wallpaper continues with the path
+// that followed it.
+func (s *SlideshowState) nextOrdered(wallpapers []string) string {
+	sorted := append([]string{}, wallpapers...)
+	sort.Strings(sorted)
+
+	current := s.Current()
+	i := sort.SearchStrings(sorted, current)
+	if i < len(sorted) && sorted[i] == current {
+		i++
+	}
+	return sorted[i%len(sorted)]
+}
+
+// nextShuffled takes the next wallpaper of the shuffled pass, starting a
+// new pass once every wallpaper was shown. A new pass never starts with
+// the wallpaper that ended the previous one.
+func (s *SlideshowState) nextShuffled(wallpapers []string, available map[string]bool) string {
+	queue := s.Queue[:0]
+	for _, path := range s.Queue {
+		if available[path] {
+			queue = append(queue, path)
+		}
+	}
+	s.Queue = queue
+
+	if len(s.Queue) == 0 {
+		s.Queue = append([]string{}, wallpapers...)
+		rand.Shuffle(len(s.Queue), func(i, j int) { s.Queue[i], s.Queue[j] = s.Queue[j], s.Queue[i] })
+		if len(s.Queue) > 1 && s.Queue[0] == s.Current() {
+			last := len(s.Queue) - 1
+			s.Queue[0], s.Queue[last] = s.Queue[last], s.Queue[0]
+		}
+	}
+
+	next := s.Queue[0]
+	s.Queue = s.Queue[1:]
+	return next
+}
+
+// nextWeighted picks a random wallpaper other than the current one, with a
+// probability proportional to its weight
+func (s *SlideshowState) nextWeighted(wallpapers []string, weight func(string) float64) string {
+	current := s.Current()
+
+	var candidates []string
+	var weights []float64
+	var total float64
+	for _, path := range wallpapers {
+		if path == current && len(wallpapers) > 1 {
+			continue
+		}
+		w := 1.0
+		if weight != nil {
+			w = weight(path)
+		}
+		if w <= 0 {
+			continue
+		}
+		candidates = append(candidates, path)
+		weights = append(weights, w)
+		total += w
+	}
+	if len(candidates) == 0 {
+		return wallpapers[rand.Intn(len(wallpapers))]
+	}
+
+	r := rand.Float64() * total
+	for i, w := range weights {
+		if r < w {
+			return candidates[i]
+		}
+		r -= w
+	}
+	return candidates[len(candidates)-1]
+}
+
+// FavouriteWeights returns a weight function favouring the index entries
+// tagged as favourite
+func FavouriteWeights(idx *Index) func(string) float64 {
+	return func(path string) float64 {
+		if entry, ok := idx.Entries[path]; ok && slices.Contains(entry.Tags, FavouriteTag) {
+			return FavouriteWeight
+		}
+		return 1
+	}
+}
+
+// ListImages returns the images with one of extensions below root, sorted
+func ListImages(root string, extensions []string) ([]string, error) {
+	files, err := imageFiles(root, extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]string, len(files))
+	for i, file := range files {
+		images[i] = file.path
+	}
+	sort.Strings(images)
+	return images, nil
+}
@@ -0,0 +1,138 @@
+package wallpaper
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+var slides = []string{"/w/a.png", "/w/b.png", "/w/c.png", "/w/d.png"}
+
+func TestSlideshowShuffleNoRepeats(t *testing.T) {
+	state := &SlideshowState{Order: OrderShuffle}
+
+	for pass := 0; pass < 5; pass++ {
+		seen := make(map[string]bool)
+		for range slides {
+			previous := state.Current()
+			next, err := state.Next(slides, nil)
+			if err != nil {
+				t.Fatalf("Next() error = %v", err)
+			}
+			if seen[next] {
+				t.Fatalf("pass %d repeated %s", pass, next)
+			}
+			if next == previous {
+				t.Fatalf("pass %d showed %s twice in a row", pass, next)
+			}
+			seen[next] = true
+		}
+	}
+}
+
+func TestSlideshowOrdered(t *testing.T) {
+	state := &SlideshowState{Order: OrderOrdered}
+
+	var shown []string
+	for i := 0; i < 5; i++ {
+		next, err := state.Next(slides, nil)
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		shown = append(shown, next)
+	}
+	want := []string{"/w/a.png", "/w/b.png", "/w/c.png", "/w/d.png", "/w/a.png"}
+	for i := range want {
+		if shown[i] != want[i] {
+			t.Fatalf("ordered slideshow showed %v, want %v", shown, want)
+		}
+	}
+
+	// A removed wallpaper continues with the one after it
+	state.History = []string{"/w/b.png"}
+	state.Position = 0
+	next, _ := state.Next([]string{"/w/a.png", "/w/c.png"}, nil)
+	if next != "/w/c.png" {
+		t.Errorf("after removed b got %s, want /w/c.png", next)
+	}
+}
+
+func TestSlideshowWeighted(t *testing.T) {
+	state := &SlideshowState{Order: OrderWeighted}
+	weight := func(path string) float64 {
+		if path == "/w/a.png" {
+			return 0
+		}
+		return 1
+	}
+
+	for i := 0; i < 50; i++ {
+		previous := state.Current()
+		next, err := state.Next(slides, weight)
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if next == "/w/a.png" {
+			t.Fatal("picked a wallpaper with weight 0")
+		}
+		if next == previous {
+			t.Fatalf("showed %s twice in a row", next)
+		}
+	}
+}
+
+func TestSlideshowPreviousReplays(t *testing.T) {
+	state := &SlideshowState{Order: OrderShuffle}
+	var shown []string
+	for range slides {
+		next, _ := state.Next(slides, nil)
+		shown = append(shown, next)
+	}
+
+	previous, err := state.Previous(slides)
+	if err != nil || previous != shown[2] {
+		t.Fatalf("Previous() = %s, %v; want %s", previous, err, shown[2])
+	}
+	// Wallpapers that are gone are skipped
+	previous, err = state.Previous([]string{shown[0], shown[3]})
+	if err != nil || previous != shown[0] {
+		t.Fatalf("Previous() = %s, %v; want %s", previous, err, shown[0])
+	}
+	if _, err := state.Previous(slides); err == nil {
+		t.Error("expected an error before the first wallpaper")
+	}
+
+	// Going forward replays the history
+	next, _ := state.Next(slides, nil)
+	if next != shown[1] {
+		t.Errorf("Next() after going back = %s, want %s", next, shown[1])
+	}
+}
+
+func TestLoadSlideshowState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slideshow.json")
+
+	state, err := LoadSlideshowState(path, "/w", OrderShuffle)
+	if err != nil {
+		t.Fatalf("LoadSlideshowState() error = %v", err)
+	}
+	if _, err := state.Next(slides, nil); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadSlideshowState(path, "/w", OrderShuffle)
+	if err != nil {
+		t.Fatalf("LoadSlideshowState() error = %v", err)
+	}
+	if loaded.Current() != state.Current() || len(loaded.Queue) != len(slides)-1 {
+		t.Errorf("loaded state %+v, saved %+v", loaded, state)
+	}
+
+	// Another order starts over
+	other, _ := LoadSlideshowState(path, "/w", OrderOrdered)
+	if other.Current() != "" {
+		t.Errorf("expected a new slideshow for another order, got %s", other.Current())
+	}
+}
@@ -0,0 +1,79 @@
+package wallpaper
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// swwwBackend sets wallpapers through the swww daemon with animated
+// transitions
+type swwwBackend struct {
+	opts BackendOptions
+}
+
+func (s *swwwBackend) Name() string { return BackendSwww }
+
+func (s *swwwBackend) Available() bool { return exec.Command("swww", "query").Run() == nil }
+
+func (s *swwwBackend) Set(state *MonitorState, monitor string) error {
+	path, ok := state.Wallpaper(monitor)
+	if !ok {
+		return fmt.Errorf("no wallpaper recorded for monitor %q", monitor)
+	}
+
+	if output, err := exec.Command("swww", s.args(path, monitor)...).CombinedOutput(); err != nil {
+		return fmt.Errorf("swww img failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// args returns the swww img arguments showing path on monitor
+func (s *swwwBackend) args(path, monitor string) []string {
+	args := []string{"img", path}
+	if s.opts.Transition != "" {
+		args = append(args, "--transition-type", s.opts.Transition)
+	}
+	if s.opts.TransitionDuration > 0 {
+		args = append(args, "--transition-duration", strconv.FormatFloat(s.opts.TransitionDuration, 'f', -1, 64))
+	}
+	if s.opts.TransitionFPS > 0 {
+		args = append(args, "--transition-fps", strconv.Itoa(s.opts.TransitionFPS))
+	}
+	if monitor != "" {
+		args = append(args, "--outputs", monitor)
+	}
+	return args
+}
+
+// Active returns the wallpaper of each monitor as reported by swww
+func (s *swwwBackend) Active() (map[string]string, error) {
+	output, err := exec.Command("swww", "query").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query swww: %w", err)
+	}
+	return parseSwwwQuery(string(output)), nil
+}
+
+// parseSwwwQuery parses "MONITOR: WxH, scale: S, currently displaying:
+// image: PATH" lines from swww query. Newer versions prefix lines with the
+// namespace followed by ": ". Monitors showing a plain color are skipped.
+func parseSwwwQuery(output string) map[string]string {
+	const marker = "currently displaying: image: "
+
+	active := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), ": ")
+		i := strings.Index(line, marker)
+		if i < 0 {
+			continue
+		}
+		monitor, _, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		active[strings.TrimSpace(monitor)] = strings.TrimSpace(line[i+len(marker):])
+	}
+	return active
+}
@@ -0,0 +1,110 @@
+package wallpaper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// wallhavenMaxPages bounds the result pages read by one search
+const wallhavenMaxPages = 5
+
+// wallhavenProvider searches a Wallhaven-style API
+type wallhavenProvider struct {
+	opts ProviderOptions
+}
+
+// wallhavenResponse is a page of search results
+type wallhavenResponse struct {
+	Data []struct {
+		ID     string `json:"id"`
+		URL    string `json:"url"`  // Page of the wallpaper
+		Path   string `json:"path"` // Full size image
+		Width  int    `json:"dimension_x"`
+		Height int    `json:"dimension_y"`
+	} `json:"data"`
+	Meta struct {
+		CurrentPage int `json:"current_page"`
+		LastPage    int `json:"last_page"`
+	} `json:"meta"`
+}
+
+func (p *wallhavenProvider) Name() string { return ProviderWallhaven }
+
+// Search reads result pages until there are three candidates per wanted
+// wallpaper, leaving room for skipped duplicates
+func (p *wallhavenProvider) Search(ctx context.Context, q FetchQuery) ([]RemoteWallpaper, error) {
+	params := url.Values{}
+	if q.Terms != "" {
+		params.Set("q", q.Terms)
+	}
+	if q.MinWidth > 0 || q.MinHeight > 0 {
+		params.Set("atleast", fmt.Sprintf("%dx%d", q.MinWidth, q.MinHeight))
+	}
+	if q.Sort != "" {
+		params.Set("sorting", q.Sort)
+	}
+	// General, anime and people; safe for work only
+	params.Set("categories", "111")
+	params.Set("purity", "100")
+
+	wanted := max(q.Limit*3, 24)
+	var wallpapers []RemoteWallpaper
+	for page := 1; page <= wallhavenMaxPages && len(wallpapers) < wanted; page++ {
+		params.Set("page", strconv.Itoa(page))
+		result, err := p.search(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range result.Data {
+			wallpapers = append(wallpapers, RemoteWallpaper{
+				ID:     item.ID,
+				URL:    item.Path,
+				Title:  item.URL,
+				Width:  item.Width,
+				Height: item.Height,
+			})
+		}
+		if len(result.Data) == 0 || result.Meta.CurrentPage >= result.Meta.LastPage {
+			break
+		}
+	}
+	return wallpapers, nil
+}
+
+// search requests one page of results
+func (p *wallhavenProvider) search(ctx context.Context, params url.Values) (*wallhavenResponse, error) {
+	endpoint := strings.TrimRight(p.opts.URL, "/") + "/api/v1/search?" + params.Encode()
+	body, err := httpGet(ctx, p.opts.Client, endpoint, p.header())
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var result wallhavenResponse
+	if err := json.NewDecoder(body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse wallhaven results: %w", err)
+	}
+	return &result, nil
+}
+
+// Open downloads the image without the API key, since images are served
+// from another host
+func (p *wallhavenProvider) Open(ctx context.Context, w RemoteWallpaper) (io.ReadCloser, error) {
+	return httpGet(ctx, p.opts.Client, w.URL, nil)
+}
+
+// header carries the API key, when there is one
+func (p *wallhavenProvider) header() http.Header {
+	header := http.Header{}
+	if p.opts.APIKey != "" {
+		header.Set("X-API-Key", p.opts.APIKey)
+	}
+	return header
+}